@@ -39,6 +39,8 @@ import (
 	"github.com/fabric8-services/fabric8-wit/actions/change"
 	"github.com/fabric8-services/fabric8-wit/actions/rules"
 	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 )
 
 // ExecuteActionsByOldNew executes all actions given in the actionConfigList
@@ -62,27 +64,7 @@ func ExecuteActionsByChangeset(ctx context.Context, db application.DB, userID uu
 	var actionChanges change.Set
 	for actionKey := range actionConfigs {
 		var err error
-		actionConfig := actionConfigs[actionKey]
-		switch actionKey {
-		case rules.ActionKeyNil:
-			newContext, actionChanges, err = executeAction(rules.ActionNil{}, actionConfig, newContext, contextChanges, &actionChanges)
-		case rules.ActionKeyFieldSet:
-			newContext, actionChanges, err = executeAction(rules.ActionFieldSet{
-				Db:     db,
-				Ctx:    ctx,
-				UserID: &userID,
-			}, actionConfig, newContext, contextChanges, &actionChanges)
-		/* commented out for now until this rule is added
-		case rules.ActionKeyStateToMetastate:
-			newContext, actionChanges, err = executeAction(rules.ActionStateToMetaState{
-				Db:     db,
-				Ctx:    ctx,
-				UserID: &userID,
-			}, actionConfig, newContext, contextChanges, &actionChanges)
-		*/
-		default:
-			return nil, nil, errs.New("action key " + actionKey + " is unknown")
-		}
+		newContext, actionChanges, err = executeActionByKey(ctx, db, userID, actionKey, actionConfigs[actionKey], newContext, contextChanges, &actionChanges)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -90,6 +72,179 @@ func ExecuteActionsByChangeset(ctx context.Context, db application.DB, userID uu
 	return newContext, actionChanges, nil
 }
 
+// ExecuteActionRules executes the action rules that are configured in the space
+// template of the given work item's space. Only those rules are executed that
+// match one of the changes between the older and the newer version of the
+// work item. The rules are executed in the order in which they are defined in
+// the space template. If older is nil, the newer work item is treated as a
+// newly created one.
+func ExecuteActionRules(ctx context.Context, db application.DB, userID uuid.UUID, older *workitem.WorkItem, newer workitem.WorkItem) (change.Detector, change.Set, error) {
+	var contextChanges change.Set
+	var err error
+	if older == nil {
+		contextChanges, err = newer.ChangeSet(nil)
+	} else {
+		contextChanges, err = newer.ChangeSet(*older)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(contextChanges) == 0 {
+		return newer, nil, nil
+	}
+	actionRules, err := db.ActionRules().ListBySpace(ctx, newer.SpaceID)
+	if err != nil {
+		return nil, nil, errs.Wrapf(err, "failed to load action rules of space %s", newer.SpaceID)
+	}
+	var newContext change.Detector = newer
+	var actionChanges change.Set
+	for _, rule := range actionRules {
+		if !ruleMatches(*rule, newer.Type, contextChanges) {
+			continue
+		}
+		newContext, actionChanges, err = executeActionByKey(ctx, db, userID, rule.ActionKey, rule.ActionConfig, newContext, contextChanges, &actionChanges)
+		if err != nil {
+			return nil, nil, errs.Wrapf(err, "failed to execute action rule %q", rule.Name)
+		}
+	}
+	return newContext, actionChanges, nil
+}
+
+// OnWorkItemChange is an application.WorkItemChangeHook that executes the
+// action rules of the space template for the changes of a work item. The
+// repository runs it in the transaction of the change, so the changes of the
+// rules are stored or undone together with the change itself.
+func OnWorkItemChange(ctx context.Context, db application.DB, modifierID uuid.UUID, older *workitem.WorkItem, newer workitem.WorkItem) (*workitem.WorkItem, error) {
+	afterActions, _, err := ExecuteActionRules(ctx, db, modifierID, older, newer)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to execute action rules for work item %s", newer.ID)
+	}
+	if wi, ok := afterActions.(workitem.WorkItem); ok {
+		return &wi, nil
+	}
+	return &newer, nil
+}
+
+// make sure OnWorkItemChange can be used as a work item change hook.
+var _ application.WorkItemChangeHook = OnWorkItemChange
+
+// HasWorkItemActionRules is an application.WorkItemChangeFilter that lets
+// OnWorkItemChange run only for work items in spaces whose space template has
+// action rules. This spares the repository loading the older version of every
+// changed work item when there is nothing to execute.
+func HasWorkItemActionRules(ctx context.Context, db application.DB, spaceID uuid.UUID) (bool, error) {
+	actionRules, err := db.ActionRules().ListBySpace(ctx, spaceID)
+	if err != nil {
+		return false, errs.Wrapf(err, "failed to load action rules of space %s", spaceID)
+	}
+	return len(actionRules) > 0, nil
+}
+
+// make sure HasWorkItemActionRules can be used as a work item change filter.
+var _ application.WorkItemChangeFilter = HasWorkItemActionRules
+
+// ExecuteIterationActionRules executes the action rules that are configured
+// in the space template of the given iteration's space and that match one of
+// the changes between the older and the newer version of the iteration. appl
+// is the application of the transaction that saved the iteration; the actions
+// make their changes in that transaction, so the caller must roll it back if
+// an error is returned.
+func ExecuteIterationActionRules(ctx context.Context, appl application.Application, userID uuid.UUID, older iteration.Iteration, newer iteration.Iteration) (change.Set, error) {
+	contextChanges, err := newer.ChangeSet(older)
+	if err != nil {
+		return nil, err
+	}
+	if len(contextChanges) == 0 {
+		return nil, nil
+	}
+	actionRules, err := appl.ActionRules().ListBySpace(ctx, newer.SpaceID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load action rules of space %s", newer.SpaceID)
+	}
+	db := inTransaction{appl}
+	var newContext change.Detector = newer
+	var actionChanges change.Set
+	for _, rule := range actionRules {
+		// iteration rules are not restricted to a work item type
+		if !ruleMatches(*rule, uuid.Nil, contextChanges) {
+			continue
+		}
+		newContext, actionChanges, err = executeActionByKey(ctx, db, userID, rule.ActionKey, rule.ActionConfig, newContext, contextChanges, &actionChanges)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to execute action rule %q", rule.Name)
+		}
+	}
+	return actionChanges, nil
+}
+
+// inTransaction makes the application of an open transaction usable by the
+// actions, which begin their own transactions. These transactions are part of
+// the open transaction and are only undone with it.
+type inTransaction struct {
+	application.Application
+}
+
+// BeginTransaction implements application.DB
+func (t inTransaction) BeginTransaction() (application.Transaction, error) {
+	return t, nil
+}
+
+// Commit implements application.Transaction
+func (t inTransaction) Commit() error {
+	return nil
+}
+
+// Rollback implements application.Transaction
+func (t inTransaction) Rollback() error {
+	return nil
+}
+
+// ruleMatches returns true if the given rule is triggered by any of the given
+// changes.
+func ruleMatches(rule workitem.ActionRule, witID uuid.UUID, contextChanges change.Set) bool {
+	for _, c := range contextChanges {
+		if rule.Matches(witID, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// executeActionByKey looks up the action for the given key and executes it
+// with the given configuration.
+func executeActionByKey(ctx context.Context, db application.DB, userID uuid.UUID, actionKey string, actionConfig string, newContext change.Detector, contextChanges change.Set, actionChanges *change.Set) (change.Detector, change.Set, error) {
+	switch actionKey {
+	case rules.ActionKeyNil:
+		return executeAction(rules.ActionNil{}, actionConfig, newContext, contextChanges, actionChanges)
+	case rules.ActionKeyFieldSet:
+		return executeAction(rules.ActionFieldSet{
+			Db:     db,
+			Ctx:    ctx,
+			UserID: &userID,
+		}, actionConfig, newContext, contextChanges, actionChanges)
+	case rules.ActionKeyStateToMetastate:
+		return executeAction(rules.ActionStateToMetaState{
+			Db:     db,
+			Ctx:    ctx,
+			UserID: &userID,
+		}, actionConfig, newContext, contextChanges, actionChanges)
+	case rules.ActionKeyCascade:
+		return executeAction(rules.ActionCascade{
+			Db:     db,
+			Ctx:    ctx,
+			UserID: &userID,
+		}, actionConfig, newContext, contextChanges, actionChanges)
+	case rules.ActionKeyIterationRollover:
+		return executeAction(rules.ActionIterationRollover{
+			Db:     db,
+			Ctx:    ctx,
+			UserID: &userID,
+		}, actionConfig, newContext, contextChanges, actionChanges)
+	default:
+		return nil, nil, errs.New("action key " + actionKey + " is unknown")
+	}
+}
+
 // executeAction executes the action given. The actionChanges contain the changes made by
 // prior action executions. The execution is expected to add/update their changes on this
 // change set.
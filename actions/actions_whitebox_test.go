@@ -1,13 +1,24 @@
 package actions
 
 import (
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/fabric8-services/fabric8-wit/actions/change"
+	"github.com/fabric8-services/fabric8-wit/actions/rules"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/gormapplication"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 
+	errs "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -178,3 +189,292 @@ func (s *ActionSuite) TestActionExecution() {
 		require.Equal(t, workitem.SystemStateResolved, afterActionWI.(workitem.WorkItem).Fields[workitem.SystemState])
 	})
 }
+
+func (s *ActionSuite) TestChangeSetOtherFields() {
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+
+	s.T().Run("title changes", func(t *testing.T) {
+		fxt.WorkItems[0].Fields[workitem.SystemState] = workitem.SystemStateNew
+		fxt.WorkItems[0].Fields[workitem.SystemBoardcolumns] = []interface{}{"bcid0"}
+		wiCopy := createWICopy(*fxt.WorkItems[0], workitem.SystemStateNew, []interface{}{"bcid0"})
+		wiCopy.Fields[workitem.SystemTitle] = "new title"
+		changes, err := wiCopy.ChangeSet(*fxt.WorkItems[0])
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, workitem.SystemTitle, changes[0].AttributeName)
+		require.Equal(t, "new title", changes[0].NewValue)
+		require.Equal(t, fxt.WorkItems[0].Fields[workitem.SystemTitle], changes[0].OldValue)
+	})
+
+	s.T().Run("field removed", func(t *testing.T) {
+		fxt.WorkItems[0].Fields[workitem.SystemState] = workitem.SystemStateNew
+		fxt.WorkItems[0].Fields[workitem.SystemBoardcolumns] = []interface{}{"bcid0"}
+		fxt.WorkItems[0].Fields["custom.field"] = "foo"
+		wiCopy := createWICopy(*fxt.WorkItems[0], workitem.SystemStateNew, []interface{}{"bcid0"})
+		delete(wiCopy.Fields, "custom.field")
+		changes, err := wiCopy.ChangeSet(*fxt.WorkItems[0])
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, "custom.field", changes[0].AttributeName)
+		require.Nil(t, changes[0].NewValue)
+		require.Equal(t, "foo", changes[0].OldValue)
+		delete(fxt.WorkItems[0].Fields, "custom.field")
+	})
+}
+
+func (s *ActionSuite) TestActionRules() {
+	s.T().Run("matching rule is executed", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+		userID := fxt.Identities[0].ID
+		_, err := s.GormDB.ActionRules().Create(s.Ctx, workitem.ActionRule{
+			SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+			Name:            "resolve opened work items",
+			WorkItemTypeID:  &fxt.WorkItemTypes[0].ID,
+			AttributeName:   workitem.SystemState,
+			AttributeValue:  ptr.String(workitem.SystemStateOpen),
+			ActionKey:       rules.ActionKeyFieldSet,
+			ActionConfig:    `{ "system.state": "resolved" }`,
+		})
+		require.NoError(t, err)
+		fxt.WorkItems[0].Fields[workitem.SystemState] = workitem.SystemStateNew
+		fxt.WorkItems[0].Fields[workitem.SystemBoardcolumns] = []interface{}{}
+		newVersion := createWICopy(*fxt.WorkItems[0], workitem.SystemStateOpen, []interface{}{})
+		afterActionWI, changes, err := ExecuteActionRules(s.Ctx, s.GormDB, userID, fxt.WorkItems[0], newVersion)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, workitem.SystemStateResolved, changes[0].NewValue)
+		require.Equal(t, workitem.SystemStateResolved, afterActionWI.(workitem.WorkItem).Fields[workitem.SystemState])
+	})
+
+	s.T().Run("non-matching rule is not executed", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+		userID := fxt.Identities[0].ID
+		_, err := s.GormDB.ActionRules().Create(s.Ctx, workitem.ActionRule{
+			SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+			Name:            "resolve closed work items",
+			AttributeName:   workitem.SystemState,
+			AttributeValue:  ptr.String(workitem.SystemStateClosed),
+			ActionKey:       rules.ActionKeyFieldSet,
+			ActionConfig:    `{ "system.state": "resolved" }`,
+		})
+		require.NoError(t, err)
+		fxt.WorkItems[0].Fields[workitem.SystemState] = workitem.SystemStateNew
+		fxt.WorkItems[0].Fields[workitem.SystemBoardcolumns] = []interface{}{}
+		newVersion := createWICopy(*fxt.WorkItems[0], workitem.SystemStateOpen, []interface{}{})
+		afterActionWI, changes, err := ExecuteActionRules(s.Ctx, s.GormDB, userID, fxt.WorkItems[0], newVersion)
+		require.NoError(t, err)
+		require.Empty(t, changes)
+		require.Equal(t, workitem.SystemStateOpen, afterActionWI.(workitem.WorkItem).Fields[workitem.SystemState])
+	})
+
+	s.T().Run("unknown action key", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+		userID := fxt.Identities[0].ID
+		_, err := s.GormDB.ActionRules().Create(s.Ctx, workitem.ActionRule{
+			SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+			Name:            "unknown",
+			AttributeName:   workitem.SystemState,
+			ActionKey:       "unknownRule",
+		})
+		require.NoError(t, err)
+		fxt.WorkItems[0].Fields[workitem.SystemState] = workitem.SystemStateNew
+		fxt.WorkItems[0].Fields[workitem.SystemBoardcolumns] = []interface{}{}
+		newVersion := createWICopy(*fxt.WorkItems[0], workitem.SystemStateOpen, []interface{}{})
+		_, _, err = ExecuteActionRules(s.Ctx, s.GormDB, userID, fxt.WorkItems[0], newVersion)
+		require.Error(t, err)
+	})
+}
+
+func (s *ActionSuite) TestExecuteIterationActionRules() {
+	// given an iteration with an unfinished work item, the next iteration
+	// and a rule that moves the unfinished work items on close
+	startDates := []time.Time{
+		time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, time.February, 1, 0, 0, 0, 0, time.UTC),
+	}
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.Iterations(3, func(fxt *tf.TestFixture, idx int) error {
+			if idx == 0 {
+				fxt.Iterations[idx].Name = fxt.Spaces[0].Name
+				return nil
+			}
+			fxt.Iterations[idx].MakeChildOf(*fxt.Iterations[0])
+			fxt.Iterations[idx].StartAt = &startDates[idx-1]
+			return nil
+		}),
+		tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[1].ID.String()
+			fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateNew
+			return nil
+		}),
+	)
+	_, err := s.GormDB.ActionRules().Create(s.Ctx, workitem.ActionRule{
+		SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+		Name:            "move unfinished work items",
+		AttributeName:   iteration.AttributeState,
+		AttributeValue:  ptr.String(iteration.StateClose.String()),
+		ActionKey:       rules.ActionKeyIterationRollover,
+	})
+	require.NoError(s.T(), err)
+	older := *fxt.Iterations[1]
+	newer := *fxt.Iterations[1]
+	newer.State = iteration.StateClose
+
+	s.T().Run("changes are undone with the transaction", func(t *testing.T) {
+		var changes change.Set
+		err := application.Transactional(s.GormDB, func(appl application.Application) error {
+			var err error
+			changes, err = ExecuteIterationActionRules(s.Ctx, appl, fxt.Identities[0].ID, older, newer)
+			if err != nil {
+				return err
+			}
+			return errors.New("fail the update")
+		})
+		require.EqualError(t, errs.Cause(err), "fail the update")
+		require.Len(t, changes, 1)
+		wi, err := s.GormDB.WorkItems().LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, fxt.Iterations[1].ID.String(), wi.Fields[workitem.SystemIteration])
+	})
+
+	s.T().Run("matching rule runs in the transaction", func(t *testing.T) {
+		err := application.Transactional(s.GormDB, func(appl application.Application) error {
+			_, err := ExecuteIterationActionRules(s.Ctx, appl, fxt.Identities[0].ID, older, newer)
+			return err
+		})
+		require.NoError(t, err)
+		wi, err := s.GormDB.WorkItems().LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, fxt.Iterations[2].ID.String(), wi.Fields[workitem.SystemIteration])
+	})
+}
+
+func (s *ActionSuite) TestOnWorkItemChange() {
+	// given a DB that runs the action rules on every work item change
+	db := gormapplication.NewGormDB(s.DB)
+	db.SetWorkItemChangeHook(OnWorkItemChange)
+	db.SetWorkItemChangeFilter(HasWorkItemActionRules)
+	newFixture := func(t *testing.T, actionKey string) *tf.TestFixture {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+		_, err := s.GormDB.ActionRules().Create(s.Ctx, workitem.ActionRule{
+			SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+			Name:            "resolve opened work items",
+			AttributeName:   workitem.SystemState,
+			AttributeValue:  ptr.String(workitem.SystemStateOpen),
+			ActionKey:       actionKey,
+			ActionConfig:    `{ "system.state": "resolved" }`,
+		})
+		require.NoError(t, err)
+		return fxt
+	}
+
+	s.T().Run("rules run in the transaction of the save", func(t *testing.T) {
+		fxt := newFixture(t, rules.ActionKeyFieldSet)
+		fxt.WorkItems[0].Fields[workitem.SystemState] = workitem.SystemStateOpen
+		var wi *workitem.WorkItem
+		var rev *workitem.Revision
+		err := application.Transactional(db, func(appl application.Application) error {
+			var err error
+			wi, rev, err = appl.WorkItems().Save(s.Ctx, fxt.Spaces[0].ID, *fxt.WorkItems[0], fxt.Identities[0].ID)
+			return err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, workitem.SystemStateResolved, wi.Fields[workitem.SystemState])
+		// the returned revision is the one of the change made by the rule
+		require.NotNil(t, rev)
+		assert.Equal(t, wi.Version, rev.WorkItemVersion)
+		assert.Equal(t, workitem.SystemStateResolved, rev.WorkItemFields[workitem.SystemState])
+	})
+
+	s.T().Run("rules run on create", func(t *testing.T) {
+		fxt := newFixture(t, rules.ActionKeyFieldSet)
+		var wi *workitem.WorkItem
+		err := application.Transactional(db, func(appl application.Application) error {
+			var err error
+			wi, _, err = appl.WorkItems().Create(s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemTypes[0].ID, map[string]interface{}{
+				workitem.SystemTitle: "created",
+				workitem.SystemState: workitem.SystemStateOpen,
+			}, fxt.Identities[0].ID)
+			return err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, workitem.SystemStateResolved, wi.Fields[workitem.SystemState])
+	})
+
+	s.T().Run("rules run for work items changed by rules", func(t *testing.T) {
+		// given a rule that closes the children of a resolved work item and
+		// a rule that renames closed work items
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.CreateWorkItemEnvironment(),
+			tf.WorkItems(2, tf.SetWorkItemTitles("parent", "child")),
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyTree)),
+			tf.WorkItemLinksCustom(1, tf.BuildLinks(tf.L("parent", "child"))),
+		)
+		_, err := s.GormDB.ActionRules().Create(s.Ctx, workitem.ActionRule{
+			SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+			Name:            "close children of resolved work items",
+			AttributeName:   workitem.SystemState,
+			AttributeValue:  ptr.String(workitem.SystemStateResolved),
+			ActionKey:       rules.ActionKeyCascade,
+			ActionConfig:    `{ "linkType": "` + fxt.WorkItemLinkTypes[0].ID.String() + `", "direction": "children", "fields": { "system.state": "closed" } }`,
+			Position:        0,
+		})
+		require.NoError(t, err)
+		_, err = s.GormDB.ActionRules().Create(s.Ctx, workitem.ActionRule{
+			SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+			Name:            "rename closed work items",
+			AttributeName:   workitem.SystemState,
+			AttributeValue:  ptr.String(workitem.SystemStateClosed),
+			ActionKey:       rules.ActionKeyFieldSet,
+			ActionConfig:    `{ "system.title": "closed" }`,
+			Position:        1,
+		})
+		require.NoError(t, err)
+		parent := *fxt.WorkItemByTitle("parent")
+		parent.Fields[workitem.SystemState] = workitem.SystemStateResolved
+		// when
+		err = application.Transactional(db, func(appl application.Application) error {
+			_, _, err := appl.WorkItems().Save(s.Ctx, fxt.Spaces[0].ID, parent, fxt.Identities[0].ID)
+			return err
+		})
+		// then the rename rule ran for the child closed by the cascade rule
+		require.NoError(t, err)
+		child, err := s.GormDB.WorkItems().LoadByID(s.Ctx, fxt.WorkItemByTitle("child").ID)
+		require.NoError(t, err)
+		assert.Equal(t, workitem.SystemStateClosed, child.Fields[workitem.SystemState])
+		assert.Equal(t, "closed", child.Fields[workitem.SystemTitle])
+		wi, err := s.GormDB.WorkItems().LoadByID(s.Ctx, parent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "parent", wi.Fields[workitem.SystemTitle])
+	})
+
+	s.T().Run("work items of spaces without rules are saved", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+		fxt.WorkItems[0].Fields[workitem.SystemState] = workitem.SystemStateOpen
+		var wi *workitem.WorkItem
+		var rev *workitem.Revision
+		err := application.Transactional(db, func(appl application.Application) error {
+			var err error
+			wi, rev, err = appl.WorkItems().Save(s.Ctx, fxt.Spaces[0].ID, *fxt.WorkItems[0], fxt.Identities[0].ID)
+			return err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, workitem.SystemStateOpen, wi.Fields[workitem.SystemState])
+		require.NotNil(t, rev)
+		assert.Equal(t, wi.Version, rev.WorkItemVersion)
+	})
+
+	s.T().Run("failing rule undoes the save", func(t *testing.T) {
+		fxt := newFixture(t, "unknownRule")
+		oldState := fxt.WorkItems[0].Fields[workitem.SystemState]
+		fxt.WorkItems[0].Fields[workitem.SystemState] = workitem.SystemStateOpen
+		err := application.Transactional(db, func(appl application.Application) error {
+			_, _, err := appl.WorkItems().Save(s.Ctx, fxt.Spaces[0].ID, *fxt.WorkItems[0], fxt.Identities[0].ID)
+			return err
+		})
+		require.Error(t, err)
+		wi, err := s.GormDB.WorkItems().LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, oldState, wi.Fields[workitem.SystemState])
+	})
+}
@@ -1,5 +1,7 @@
 package change
 
+import uuid "github.com/satori/go.uuid"
+
 // Set is a set of changes to an entitiy.
 type Set []Change
 
@@ -11,9 +13,12 @@ type Detector interface {
 }
 
 // Change defines a set of changed values in an entity. It holds
-// the attribute name as the key and old and new values.
+// the attribute name as the key and old and new values. EntityID
+// is only set when the change was done on an entity other than the
+// context entity of an action (e.g. on a linked work item).
 type Change struct {
 	AttributeName string
 	NewValue      interface{}
 	OldValue      interface{}
+	EntityID      uuid.UUID
 }
@@ -26,8 +26,21 @@ For all these automations, the actions system provides a re-usable, flexible and
 later user configurable way of doing that without creating lots of custom code
 and/or custom process implementations that are hardcoded in the WIT.
 
-The current PR provides the basic actions infrastructure and an implementation of
-an example action rules for testing.
+The connections between events and actions are configured per space template
+as "action rules" (see workitem.ActionRule and the "work_item_action_rules"
+section of the space template YAML files). A rule reads like "when attribute X
+of a work item of type Y changes to Z, run action A with configuration C".
+ExecuteActionRules() loads the rules of the space template of a work item and
+executes all rules that match the changes of the work item.
+OnWorkItemChange() is the work item change hook (see
+application.WorkItemChangeHook) that runs them in the transaction of every
+created, saved or reverted work item. Work items changed by the rules run the
+rules as well, e.g. the children closed by a "Cascade" rule are moved to
+their matching board columns, but the rules run at most once per work item
+and change. HasWorkItemActionRules() is the matching
+application.WorkItemChangeFilter that skips spaces without rules. Rules on an
+iteration attribute (e.g. "iteration.state") are run by
+ExecuteIterationActionRules() in the transaction of the iteration update.
 
 This package provides two methods ExecuteActionsByOldNew() and ExecuteActionsByChangeset()
 that can be called by a client (for example the controller on a request) with an entity
@@ -1,6 +1,17 @@
 package rules
 
-import "github.com/fabric8-services/fabric8-wit/actions/change"
+import (
+	"context"
+	"strings"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/fabric8-services/fabric8-wit/actions/change"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+)
 
 const (
 	// ActionKeyNil is the key for the ActionKeyNil action rule.
@@ -9,6 +20,10 @@ const (
 	ActionKeyFieldSet = "FieldSet"
 	// ActionKeyStateToMetastate is the key for the ActionKeyStateToMetastate action rule.
 	ActionKeyStateToMetastate = "BidirectionalStateToColumn"
+	// ActionKeyCascade is the key for the ActionCascade action rule.
+	ActionKeyCascade = "Cascade"
+	// ActionKeyIterationRollover is the key for the ActionIterationRollover action rule.
+	ActionKeyIterationRollover = "IterationRollover"
 
 	// ActionKeyStateToMetastateConfigMetastate is the key for the ActionKeyStateToMetastateConfigMetastate config parameter.
 	ActionKeyStateToMetastateConfigMetastate = "metaState"
@@ -26,3 +41,59 @@ type Action interface {
 	// part of the changeset.
 	OnChange(newContext change.Detector, contextChanges change.Set, configuration string, actionChanges *change.Set) (change.Detector, change.Set, error)
 }
+
+// ValidateConfig checks that the given action key refers to a known action
+// and that the given configuration can be read by that action. It is meant to
+// reject broken action rules before they are stored (e.g. when importing a
+// space template) rather than when they are executed.
+func ValidateConfig(actionKey string, configuration string) error {
+	switch actionKey {
+	case ActionKeyNil, ActionKeyStateToMetastate:
+		// these actions don't read a configuration.
+		return nil
+	case ActionKeyFieldSet:
+		_, err := parseActionFieldSetConfig(configuration)
+		return err
+	case ActionKeyCascade:
+		_, err := parseActionCascadeConfig(configuration)
+		return err
+	case ActionKeyIterationRollover:
+		_, err := parseActionIterationRolloverConfig(configuration)
+		return err
+	default:
+		return errors.NewBadParameterError("action", actionKey).Expected(strings.Join([]string{
+			ActionKeyNil,
+			ActionKeyFieldSet,
+			ActionKeyStateToMetastate,
+			ActionKeyCascade,
+			ActionKeyIterationRollover,
+		}, ", "))
+	}
+}
+
+// storeWorkItem saves the given work item in a new transaction on behalf of
+// the given user and returns the stored work item.
+func storeWorkItem(ctx context.Context, db application.DB, userID *uuid.UUID, wi *workitem.WorkItem) (*workitem.WorkItem, error) {
+	if ctx == nil {
+		return nil, errs.New("context is nil")
+	}
+	if db == nil {
+		return nil, errs.New("database is nil")
+	}
+	if userID == nil {
+		return nil, errs.New("userID is nil")
+	}
+	var storeResultWorkItem *workitem.WorkItem
+	err := application.Transactional(db, func(appl application.Application) error {
+		var err error
+		storeResultWorkItem, _, err = appl.WorkItems().Save(ctx, wi.SpaceID, *wi, *userID)
+		if err != nil {
+			return errs.Wrap(err, "error updating work item")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return storeResultWorkItem, nil
+}
@@ -0,0 +1,217 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/fabric8-services/fabric8-wit/actions/change"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+)
+
+const (
+	// ActionCascadeDirectionChildren makes the cascade follow the links from
+	// their source to their target (e.g. from a parent to its children).
+	ActionCascadeDirectionChildren = "children"
+	// ActionCascadeDirectionParents makes the cascade follow the links from
+	// their target to their source (e.g. from a child to its parent).
+	ActionCascadeDirectionParents = "parents"
+)
+
+// ActionCascadeConfig is the configuration of the ActionCascade action rule.
+// Example:
+//
+//	{
+//	  "linkType": "25c326a7-6d03-4f5a-b23b-86a9ee4171e9",
+//	  "direction": "children",
+//	  "fields": { "system.state": "closed" }
+//	}
+type ActionCascadeConfig struct {
+	// LinkTypeID is the ID of the link type to follow.
+	LinkTypeID uuid.UUID `json:"linkType"`
+	// Direction is either ActionCascadeDirectionChildren or
+	// ActionCascadeDirectionParents. It is ignored for link types with a
+	// network topology as those links have no direction.
+	Direction string `json:"direction"`
+	// Levels limits the number of links to follow from the context work
+	// item. If it is zero, there is no limit.
+	Levels int `json:"levels,omitempty"`
+	// Fields holds the field values to set on the linked work items.
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// parseActionCascadeConfig deserializes and checks the given configuration
+// JSON of an ActionCascade action rule.
+func parseActionCascadeConfig(configuration string) (ActionCascadeConfig, error) {
+	var config ActionCascadeConfig
+	if err := json.Unmarshal([]byte(configuration), &config); err != nil {
+		return config, errs.Wrap(err, "failed to unmarshall from action configuration: "+configuration)
+	}
+	if uuid.Equal(config.LinkTypeID, uuid.Nil) {
+		return config, errs.New("no link type given in action configuration: " + configuration)
+	}
+	if config.Direction != ActionCascadeDirectionChildren && config.Direction != ActionCascadeDirectionParents {
+		return config, errs.Errorf("unknown cascade direction %q, expected %q or %q", config.Direction, ActionCascadeDirectionChildren, ActionCascadeDirectionParents)
+	}
+	if len(config.Fields) == 0 {
+		return config, errs.New("no fields given in action configuration: " + configuration)
+	}
+	return config, nil
+}
+
+// ActionCascade applies a FieldSet-style change to all work items that are
+// reachable from the context work item over links of a given link type. The
+// context work item itself is not modified. Every work item is visited only
+// once, so loops in the link graph are no problem. Linked work items whose
+// type doesn't have one of the fields are left untouched for that field.
+// Note that this only works on WorkItems.
+type ActionCascade struct {
+	Db     application.DB
+	Ctx    context.Context
+	UserID *uuid.UUID
+}
+
+// make sure the rule is implementing the interface.
+var _ Action = ActionCascade{}
+
+// OnChange executes the action rule.
+func (act ActionCascade) OnChange(newContext change.Detector, contextChanges change.Set, configuration string, actionChanges *change.Set) (change.Detector, change.Set, error) {
+	// check if the newContext is a WorkItem, fail otherwise.
+	wiContext, ok := newContext.(workitem.WorkItem)
+	if !ok {
+		return nil, nil, errs.New("given context is not a WorkItem: " + reflect.TypeOf(newContext).String())
+	}
+	if act.Ctx == nil {
+		return nil, nil, errs.New("context is nil")
+	}
+	if act.Db == nil {
+		return nil, nil, errs.New("database is nil")
+	}
+	if act.UserID == nil {
+		return nil, nil, errs.New("userID is nil")
+	}
+	config, err := parseActionCascadeConfig(configuration)
+	if err != nil {
+		return nil, nil, err
+	}
+	// sort the field names to produce a stable order of changes.
+	fieldNames := make([]string, 0, len(config.Fields))
+	for k := range config.Fields {
+		fieldNames = append(fieldNames, k)
+	}
+	sort.Strings(fieldNames)
+	var cascadeChanges change.Set
+	err = application.Transactional(act.Db, func(appl application.Application) error {
+		linkType, err := appl.WorkItemLinkTypes().Load(act.Ctx, config.LinkTypeID)
+		if err != nil {
+			return errs.Wrap(err, "error loading work item link type")
+		}
+		linkedIDs, err := act.walk(appl, *linkType, config, wiContext.ID)
+		if err != nil {
+			return err
+		}
+		wits := map[uuid.UUID]*workitem.WorkItemType{}
+		for _, linkedID := range linkedIDs {
+			wi, err := appl.WorkItems().LoadByID(act.Ctx, linkedID)
+			if err != nil {
+				return errs.Wrapf(err, "error loading linked work item %s", linkedID)
+			}
+			wit, ok := wits[wi.Type]
+			if !ok {
+				wit, err = appl.WorkItemTypes().Load(act.Ctx, wi.Type)
+				if err != nil {
+					return errs.Wrap(err, "error loading work item type")
+				}
+				wits[wi.Type] = wit
+			}
+			var wiChanges change.Set
+			for _, k := range fieldNames {
+				fieldType, ok := wit.Fields[k]
+				if !ok {
+					continue
+				}
+				newValue, err := fieldType.Type.ConvertToModel(config.Fields[k])
+				if err != nil {
+					return errs.Wrapf(err, "error converting new value of field %s for linked work item %s", k, linkedID)
+				}
+				if reflect.DeepEqual(wi.Fields[k], newValue) {
+					continue
+				}
+				wiChanges = append(wiChanges, change.Change{
+					AttributeName: k,
+					NewValue:      newValue,
+					OldValue:      wi.Fields[k],
+					EntityID:      wi.ID,
+				})
+				wi.Fields[k] = newValue
+			}
+			if len(wiChanges) == 0 {
+				continue
+			}
+			if _, _, err := appl.WorkItems().Save(act.Ctx, wi.SpaceID, *wi, *act.UserID); err != nil {
+				return errs.Wrapf(err, "error updating linked work item %s", linkedID)
+			}
+			cascadeChanges = append(cascadeChanges, wiChanges...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	*actionChanges = append(*actionChanges, cascadeChanges...)
+	return wiContext, *actionChanges, nil
+}
+
+// walk returns the IDs of all work items reachable from the given work item
+// over links of the given type in breadth-first order. The given work item
+// itself is never part of the result.
+func (act ActionCascade) walk(appl application.Application, linkType link.WorkItemLinkType, config ActionCascadeConfig, startID uuid.UUID) ([]uuid.UUID, error) {
+	followChildren := config.Direction == ActionCascadeDirectionChildren
+	followParents := config.Direction == ActionCascadeDirectionParents
+	if linkType.Topology == link.TopologyNetwork {
+		// network links have no direction.
+		followChildren = true
+		followParents = true
+	}
+	visited := map[uuid.UUID]struct{}{startID: {}}
+	res := []uuid.UUID{}
+	frontier := []uuid.UUID{startID}
+	for level := 1; len(frontier) > 0 && (config.Levels <= 0 || level <= config.Levels); level++ {
+		var next []uuid.UUID
+		if followChildren {
+			childLinks, err := appl.WorkItemLinks().ListChildLinks(act.Ctx, linkType.ID, frontier...)
+			if err != nil {
+				return nil, errs.Wrap(err, "error loading child links")
+			}
+			for _, l := range childLinks {
+				next = append(next, l.TargetID)
+			}
+		}
+		if followParents {
+			ancestors, err := appl.WorkItemLinks().GetAncestors(act.Ctx, linkType.ID, link.AncestorLevelParent, frontier...)
+			if err != nil {
+				return nil, errs.Wrap(err, "error loading parent links")
+			}
+			for _, a := range ancestors {
+				next = append(next, a.ID)
+			}
+		}
+		frontier = []uuid.UUID{}
+		for _, id := range next {
+			// skip work items that we've seen before; this also breaks loops.
+			if _, ok := visited[id]; ok {
+				continue
+			}
+			visited[id] = struct{}{}
+			res = append(res, id)
+			frontier = append(frontier, id)
+		}
+	}
+	return res, nil
+}
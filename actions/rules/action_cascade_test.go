@@ -0,0 +1,140 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/fabric8-services/fabric8-wit/actions/change"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestSuiteActionCascade(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &ActionCascadeSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+type ActionCascadeSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func (s *ActionCascadeSuite) changedIDs(changes change.Set) []uuid.UUID {
+	res := []uuid.UUID{}
+	for _, c := range changes {
+		res = append(res, c.EntityID)
+	}
+	return res
+}
+
+func (s *ActionCascadeSuite) TestActionExecution() {
+	// A -> B -> C
+	//   \-> D
+	tree := func(t *testing.T, topology link.Topology) *tf.TestFixture {
+		return tf.NewTestFixture(t, s.DB,
+			tf.WorkItems(4, tf.SetWorkItemTitles("A", "B", "C", "D")),
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(topology)),
+			tf.WorkItemLinksCustom(3, tf.BuildLinks(tf.L("A", "B"), tf.L("B", "C"), tf.L("A", "D"))),
+		)
+	}
+
+	s.T().Run("children", func(t *testing.T) {
+		fxt := tree(t, link.TopologyTree)
+		action := ActionCascade{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		config := `{ "linkType": "` + fxt.WorkItemLinkTypes[0].ID.String() + `", "direction": "children", "fields": { "system.state": "closed" } }`
+		var convertChanges change.Set
+		afterActionWI, convertChanges, err := action.OnChange(*fxt.WorkItemByTitle("A"), change.Set{}, config, &convertChanges)
+		require.NoError(t, err)
+		require.Equal(t, *fxt.WorkItemByTitle("A"), afterActionWI)
+		require.Len(t, convertChanges, 3)
+		require.ElementsMatch(t, []uuid.UUID{fxt.WorkItemByTitle("B").ID, fxt.WorkItemByTitle("C").ID, fxt.WorkItemByTitle("D").ID}, s.changedIDs(convertChanges))
+		for _, c := range convertChanges {
+			require.Equal(t, workitem.SystemState, c.AttributeName)
+			require.Equal(t, workitem.SystemStateClosed, c.NewValue)
+			wi, err := s.GormDB.WorkItems().LoadByID(s.Ctx, c.EntityID)
+			require.NoError(t, err)
+			require.Equal(t, workitem.SystemStateClosed, wi.Fields[workitem.SystemState])
+		}
+		// running it again doesn't change anything.
+		convertChanges = change.Set{}
+		_, convertChanges, err = action.OnChange(*fxt.WorkItemByTitle("A"), change.Set{}, config, &convertChanges)
+		require.NoError(t, err)
+		require.Empty(t, convertChanges)
+	})
+
+	s.T().Run("children with level limit", func(t *testing.T) {
+		fxt := tree(t, link.TopologyTree)
+		action := ActionCascade{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		config := `{ "linkType": "` + fxt.WorkItemLinkTypes[0].ID.String() + `", "direction": "children", "levels": 1, "fields": { "system.state": "closed" } }`
+		var convertChanges change.Set
+		_, convertChanges, err := action.OnChange(*fxt.WorkItemByTitle("A"), change.Set{}, config, &convertChanges)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uuid.UUID{fxt.WorkItemByTitle("B").ID, fxt.WorkItemByTitle("D").ID}, s.changedIDs(convertChanges))
+	})
+
+	s.T().Run("parents", func(t *testing.T) {
+		fxt := tree(t, link.TopologyTree)
+		action := ActionCascade{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		config := `{ "linkType": "` + fxt.WorkItemLinkTypes[0].ID.String() + `", "direction": "parents", "fields": { "system.state": "open" } }`
+		var convertChanges change.Set
+		_, convertChanges, err := action.OnChange(*fxt.WorkItemByTitle("C"), change.Set{}, config, &convertChanges)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uuid.UUID{fxt.WorkItemByTitle("B").ID, fxt.WorkItemByTitle("A").ID}, s.changedIDs(convertChanges))
+	})
+
+	s.T().Run("loop in network", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItems(3, tf.SetWorkItemTitles("A", "B", "C")),
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyNetwork)),
+			tf.WorkItemLinksCustom(3, tf.BuildLinks(tf.LinkChain("A", "B", "C", "A")...)),
+		)
+		action := ActionCascade{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		config := `{ "linkType": "` + fxt.WorkItemLinkTypes[0].ID.String() + `", "direction": "children", "fields": { "system.state": "closed" } }`
+		var convertChanges change.Set
+		_, convertChanges, err := action.OnChange(*fxt.WorkItemByTitle("A"), change.Set{}, config, &convertChanges)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uuid.UUID{fxt.WorkItemByTitle("B").ID, fxt.WorkItemByTitle("C").ID}, s.changedIDs(convertChanges))
+	})
+
+	s.T().Run("invalid configuration", func(t *testing.T) {
+		fxt := tree(t, link.TopologyTree)
+		action := ActionCascade{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		for name, config := range map[string]string{
+			"non-json":          "someNonJSON",
+			"unknown direction": `{ "linkType": "` + fxt.WorkItemLinkTypes[0].ID.String() + `", "direction": "sideways", "fields": { "system.state": "closed" } }`,
+			"no fields":         `{ "linkType": "` + fxt.WorkItemLinkTypes[0].ID.String() + `", "direction": "children" }`,
+			"unknown link type": `{ "linkType": "` + uuid.NewV4().String() + `", "direction": "children", "fields": { "system.state": "closed" } }`,
+		} {
+			t.Run(name, func(t *testing.T) {
+				var convertChanges change.Set
+				_, _, err := action.OnChange(*fxt.WorkItemByTitle("A"), change.Set{}, config, &convertChanges)
+				require.Error(t, err)
+			})
+		}
+	})
+}
@@ -26,29 +26,18 @@ type ActionFieldSet struct {
 // make sure the rule is implementing the interface.
 var _ Action = ActionFieldSet{}
 
-func (act ActionFieldSet) storeWorkItem(wi *workitem.WorkItem) (*workitem.WorkItem, error) {
-	if act.Ctx == nil {
-		return nil, errs.New("context is nil")
-	}
-	if act.Db == nil {
-		return nil, errs.New("database is nil")
-	}
-	if act.UserID == nil {
-		return nil, errs.New("userID is nil")
-	}
-	var storeResultWorkItem *workitem.WorkItem
-	err := application.Transactional(act.Db, func(appl application.Application) error {
-		var err error
-		storeResultWorkItem, _, err = appl.WorkItems().Save(act.Ctx, wi.SpaceID, *wi, *act.UserID)
-		if err != nil {
-			return errs.Wrap(err, "error updating work item")
-		}
-		return nil
-	})
-	if err != nil {
-		return nil, err
+// parseActionFieldSetConfig deserializes the given configuration JSON of an
+// ActionFieldSet action rule into a map from field names to values.
+func parseActionFieldSetConfig(configuration string) (map[string]interface{}, error) {
+	var rawType map[string]interface{}
+	if err := json.Unmarshal([]byte(configuration), &rawType); err != nil {
+		return nil, errs.Wrap(err, "failed to unmarshall from action configuration to a map: "+configuration)
 	}
-	return storeResultWorkItem, nil
+	return rawType, nil
+}
+
+func (act ActionFieldSet) storeWorkItem(wi *workitem.WorkItem) (*workitem.WorkItem, error) {
+	return storeWorkItem(act.Ctx, act.Db, act.UserID, wi)
 }
 
 // OnChange executes the action rule.
@@ -58,11 +47,9 @@ func (act ActionFieldSet) OnChange(newContext change.Detector, contextChanges ch
 	if !ok {
 		return nil, nil, errs.New("given context is not a WorkItem: " + reflect.TypeOf(newContext).String())
 	}
-	// deserialize the config JSON.
-	var rawType map[string]interface{}
-	err := json.Unmarshal([]byte(configuration), &rawType)
+	rawType, err := parseActionFieldSetConfig(configuration)
 	if err != nil {
-		return nil, nil, errs.Wrap(err, "failed to unmarshall from action configuration to a map: "+configuration)
+		return nil, nil, err
 	}
 	// load WIT.
 	wit, err := act.Db.WorkItemTypes().Load(act.Ctx, wiContext.Type)
@@ -0,0 +1,232 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/fabric8-services/fabric8-wit/actions/change"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+)
+
+// ActionIterationRolloverConfig is the configuration of the
+// ActionIterationRollover action rule. All values are optional.
+type ActionIterationRolloverConfig struct {
+	// TargetIterationID is the iteration to move the unfinished work items
+	// to. If it is nil, the next iteration by start date is used.
+	TargetIterationID *uuid.UUID `json:"targetIteration,omitempty"`
+	// FinishedMetaStates are the meta-states of work items that are
+	// considered to be finished. Defaults to the closed meta-state only, so
+	// resolved work items that still need to be verified are moved as well.
+	FinishedMetaStates []string `json:"finishedMetaStates,omitempty"`
+}
+
+// parseActionIterationRolloverConfig deserializes the given configuration
+// JSON of an ActionIterationRollover action rule. An empty configuration is
+// allowed.
+func parseActionIterationRolloverConfig(configuration string) (ActionIterationRolloverConfig, error) {
+	config := ActionIterationRolloverConfig{}
+	if configuration != "" {
+		if err := json.Unmarshal([]byte(configuration), &config); err != nil {
+			return config, errs.Wrap(err, "failed to unmarshall from action configuration: "+configuration)
+		}
+	}
+	return config, nil
+}
+
+// ActionIterationRollover moves all unfinished work items of an iteration and
+// of its child iterations to another iteration when the iteration is closed.
+// A work item is considered to be unfinished if its state doesn't map to one
+// of the finished meta-states (see ActionStateToMetaState for the
+// state/meta-state mapping). The target iteration is either configured or the
+// next not yet closed iteration of the space by start date that is not a
+// child of the closed iteration. Note that this only works on Iterations.
+type ActionIterationRollover struct {
+	Db     application.DB
+	Ctx    context.Context
+	UserID *uuid.UUID
+}
+
+// make sure the rule is implementing the interface.
+var _ Action = ActionIterationRollover{}
+
+// OnChange executes the action rule.
+func (act ActionIterationRollover) OnChange(newContext change.Detector, contextChanges change.Set, configuration string, actionChanges *change.Set) (change.Detector, change.Set, error) {
+	// check if the newContext is an Iteration, fail otherwise.
+	itr, ok := newContext.(iteration.Iteration)
+	if !ok {
+		return nil, nil, errs.New("given context is not an Iteration: " + reflect.TypeOf(newContext).String())
+	}
+	if act.Ctx == nil {
+		return nil, nil, errs.New("context is nil")
+	}
+	if act.Db == nil {
+		return nil, nil, errs.New("database is nil")
+	}
+	if act.UserID == nil {
+		return nil, nil, errs.New("userID is nil")
+	}
+	config, err := parseActionIterationRolloverConfig(configuration)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(config.FinishedMetaStates) == 0 {
+		config.FinishedMetaStates = []string{workitem.SystemMetaStateClosed}
+	}
+	closed := false
+	for _, c := range contextChanges {
+		if c.AttributeName == iteration.AttributeState && c.NewValue == iteration.StateClose {
+			closed = true
+		}
+	}
+	if !closed {
+		// nothing to do for us.
+		return newContext, *actionChanges, nil
+	}
+	var rolloverChanges change.Set
+	err = application.Transactional(act.Db, func(appl application.Application) error {
+		target, err := act.targetIteration(appl, itr, config)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			log.Info(act.Ctx, map[string]interface{}{
+				"iteration_id": itr.ID,
+			}, "no iteration found to move unfinished work items to")
+			return nil
+		}
+		children, err := appl.Iterations().LoadChildren(act.Ctx, itr.ID)
+		if err != nil {
+			return errs.Wrapf(err, "error loading child iterations of iteration %s", itr.ID)
+		}
+		var wis []*workitem.WorkItem
+		for _, itrID := range append([]uuid.UUID{itr.ID}, iterationIDs(children)...) {
+			itrWIs, err := appl.WorkItems().LoadByIteration(act.Ctx, itrID)
+			if err != nil {
+				return errs.Wrapf(err, "error loading work items of iteration %s", itrID)
+			}
+			wis = append(wis, itrWIs...)
+		}
+		finished := map[string]struct{}{}
+		for _, metaState := range config.FinishedMetaStates {
+			finished[metaState] = struct{}{}
+		}
+		stateMappings := map[uuid.UUID]workitem.MetaStateMapping{}
+		for _, wi := range wis {
+			stateToMetaState, ok := stateMappings[wi.Type]
+			if !ok {
+				wit, err := appl.WorkItemTypes().Load(act.Ctx, wi.Type)
+				if err != nil {
+					return errs.Wrap(err, "error loading work item type")
+				}
+				stateToMetaState, err = wit.MetaStateMapping()
+				if err != nil {
+					// without a meta-state mapping there is no telling
+					// whether the work items of the type are finished
+					log.Info(act.Ctx, map[string]interface{}{
+						"wit_id": wi.Type,
+						"err":    err,
+					}, "not moving the work items of a type without a meta-state mapping")
+					stateToMetaState = nil
+				}
+				stateMappings[wi.Type] = stateToMetaState
+			}
+			if stateToMetaState == nil {
+				continue
+			}
+			if _, ok := finished[stateToMetaState.MetaState(fmt.Sprintf("%v", wi.Fields[workitem.SystemState]))]; ok {
+				continue
+			}
+			oldValue := wi.Fields[workitem.SystemIteration]
+			if oldValue == target.ID.String() {
+				// a configured target can be one of the child iterations
+				continue
+			}
+			wi.Fields[workitem.SystemIteration] = target.ID.String()
+			// saving the work item also records a revision for it.
+			if _, _, err := appl.WorkItems().Save(act.Ctx, wi.SpaceID, *wi, *act.UserID); err != nil {
+				return errs.Wrapf(err, "error moving work item %s to iteration %s", wi.ID, target.ID)
+			}
+			rolloverChanges = append(rolloverChanges, change.Change{
+				AttributeName: workitem.SystemIteration,
+				NewValue:      target.ID.String(),
+				OldValue:      oldValue,
+				EntityID:      wi.ID,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	*actionChanges = append(*actionChanges, rolloverChanges...)
+	return itr, *actionChanges, nil
+}
+
+// targetIteration returns the iteration to move the unfinished work items of
+// the given iteration to or nil if there is none.
+func (act ActionIterationRollover) targetIteration(appl application.Application, itr iteration.Iteration, config ActionIterationRolloverConfig) (*iteration.Iteration, error) {
+	if config.TargetIterationID != nil {
+		target, err := appl.Iterations().Load(act.Ctx, *config.TargetIterationID)
+		if err != nil {
+			return nil, errs.Wrapf(err, "error loading target iteration %s", *config.TargetIterationID)
+		}
+		if target.SpaceID != itr.SpaceID {
+			return nil, errs.Errorf("target iteration %s is not in the space %s of iteration %s", target.ID, itr.SpaceID, itr.ID)
+		}
+		return target, nil
+	}
+	itrs, err := appl.Iterations().List(act.Ctx, itr.SpaceID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "error loading iterations of space %s", itr.SpaceID)
+	}
+	return nextIteration(itr, itrs), nil
+}
+
+// iterationIDs returns the IDs of the given iterations.
+func iterationIDs(itrs []iteration.Iteration) []uuid.UUID {
+	res := make([]uuid.UUID, len(itrs))
+	for i, itr := range itrs {
+		res[i] = itr.ID
+	}
+	return res
+}
+
+// isDescendant returns true if the given candidate is a child of the given
+// iteration or of one of its children.
+func isDescendant(candidate iteration.Iteration, itr iteration.Iteration) bool {
+	for _, ancestorID := range candidate.Path {
+		if ancestorID == itr.ID && candidate.ID != itr.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// nextIteration returns the iteration from the given list that starts next
+// after the given iteration. Root iterations, iterations without a start
+// date, closed iterations and children of the given iteration are never
+// returned.
+func nextIteration(itr iteration.Iteration, itrs []iteration.Iteration) *iteration.Iteration {
+	var res *iteration.Iteration
+	for i := range itrs {
+		candidate := itrs[i]
+		if candidate.ID == itr.ID || candidate.IsRoot(itr.SpaceID) || candidate.State == iteration.StateClose || candidate.StartAt == nil || isDescendant(candidate, itr) {
+			continue
+		}
+		if itr.StartAt != nil && !candidate.StartAt.After(*itr.StartAt) {
+			continue
+		}
+		if res == nil || candidate.StartAt.Before(*res.StartAt) {
+			res = &itrs[i]
+		}
+	}
+	return res
+}
@@ -0,0 +1,277 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/fabric8-services/fabric8-wit/actions/change"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+)
+
+func TestSuiteActionIterationRollover(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &ActionIterationRolloverSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+type ActionIterationRolloverSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+// createFixture creates a root iteration and these iterations below it:
+//
+//	0: root
+//	1: starts 2018-01-01 (the one to close)
+//	2: starts 2018-01-15 and is already closed
+//	3: starts 2018-02-01
+//	4: starts 2018-01-20
+//
+// and three work items in iteration 1 in the states new, closed and in
+// progress.
+func (s *ActionIterationRolloverSuite) createFixture(t *testing.T) *tf.TestFixture {
+	startDates := []time.Time{
+		time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, time.January, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, time.February, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, time.January, 20, 0, 0, 0, 0, time.UTC),
+	}
+	states := []string{workitem.SystemStateNew, workitem.SystemStateClosed, workitem.SystemStateInProgress}
+	return tf.NewTestFixture(t, s.DB,
+		tf.Iterations(5, func(fxt *tf.TestFixture, idx int) error {
+			if idx == 0 {
+				fxt.Iterations[idx].Name = fxt.Spaces[0].Name
+				return nil
+			}
+			fxt.Iterations[idx].MakeChildOf(*fxt.Iterations[0])
+			fxt.Iterations[idx].StartAt = &startDates[idx-1]
+			if idx == 2 {
+				fxt.Iterations[idx].State = iteration.StateClose
+			}
+			return nil
+		}),
+		tf.WorkItems(3, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[1].ID.String()
+			fxt.WorkItems[idx].Fields[workitem.SystemState] = states[idx]
+			return nil
+		}),
+	)
+}
+
+func (s *ActionIterationRolloverSuite) TestActionExecution() {
+	s.T().Run("move to next iteration", func(t *testing.T) {
+		fxt := s.createFixture(t)
+		newVersion := *fxt.Iterations[1]
+		newVersion.State = iteration.StateClose
+		contextChanges, err := newVersion.ChangeSet(*fxt.Iterations[1])
+		require.NoError(t, err)
+		action := ActionIterationRollover{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		var convertChanges change.Set
+		afterActionItr, convertChanges, err := action.OnChange(newVersion, contextChanges, "", &convertChanges)
+		require.NoError(t, err)
+		require.Equal(t, newVersion, afterActionItr)
+		require.Len(t, convertChanges, 2)
+		for _, c := range convertChanges {
+			require.Equal(t, workitem.SystemIteration, c.AttributeName)
+			require.Equal(t, fxt.Iterations[4].ID.String(), c.NewValue)
+			require.NotEqual(t, fxt.WorkItems[1].ID, c.EntityID)
+		}
+		moved, err := s.GormDB.WorkItems().LoadByIteration(s.Ctx, fxt.Iterations[4].ID)
+		require.NoError(t, err)
+		require.Len(t, moved, 2)
+		remaining, err := s.GormDB.WorkItems().LoadByIteration(s.Ctx, fxt.Iterations[1].ID)
+		require.NoError(t, err)
+		require.Len(t, remaining, 1)
+		require.Equal(t, fxt.WorkItems[1].ID, remaining[0].ID)
+	})
+
+	s.T().Run("move to configured iteration", func(t *testing.T) {
+		fxt := s.createFixture(t)
+		newVersion := *fxt.Iterations[1]
+		newVersion.State = iteration.StateClose
+		contextChanges, err := newVersion.ChangeSet(*fxt.Iterations[1])
+		require.NoError(t, err)
+		action := ActionIterationRollover{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		var convertChanges change.Set
+		_, convertChanges, err = action.OnChange(newVersion, contextChanges, `{ "targetIteration": "`+fxt.Iterations[3].ID.String()+`", "finishedMetaStates": ["mClosed", "mInprogress"] }`, &convertChanges)
+		require.NoError(t, err)
+		require.Len(t, convertChanges, 1)
+		require.Equal(t, fxt.WorkItems[0].ID, convertChanges[0].EntityID)
+		require.Equal(t, fxt.Iterations[3].ID.String(), convertChanges[0].NewValue)
+	})
+
+	s.T().Run("resolved work items are moved by default", func(t *testing.T) {
+		fxt := s.createFixture(t)
+		wi := *fxt.WorkItems[1]
+		wi.Fields[workitem.SystemState] = workitem.SystemStateResolved
+		_, _, err := s.GormDB.WorkItems().Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		newVersion := *fxt.Iterations[1]
+		newVersion.State = iteration.StateClose
+		contextChanges, err := newVersion.ChangeSet(*fxt.Iterations[1])
+		require.NoError(t, err)
+		action := ActionIterationRollover{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		var convertChanges change.Set
+		_, convertChanges, err = action.OnChange(newVersion, contextChanges, "", &convertChanges)
+		require.NoError(t, err)
+		require.Len(t, convertChanges, 3)
+		remaining, err := s.GormDB.WorkItems().LoadByIteration(s.Ctx, fxt.Iterations[1].ID)
+		require.NoError(t, err)
+		require.Empty(t, remaining)
+	})
+
+	s.T().Run("work items of child iterations are moved", func(t *testing.T) {
+		startDates := []time.Time{
+			time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2018, time.January, 8, 0, 0, 0, 0, time.UTC),
+			time.Date(2018, time.February, 1, 0, 0, 0, 0, time.UTC),
+		}
+		// 0: root, 1: the one to close, 2: child of 1, 3: the next one
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.Iterations(4, func(fxt *tf.TestFixture, idx int) error {
+				switch idx {
+				case 0:
+					fxt.Iterations[idx].Name = fxt.Spaces[0].Name
+					return nil
+				case 2:
+					fxt.Iterations[idx].MakeChildOf(*fxt.Iterations[1])
+				default:
+					fxt.Iterations[idx].MakeChildOf(*fxt.Iterations[0])
+				}
+				fxt.Iterations[idx].StartAt = &startDates[idx-1]
+				return nil
+			}),
+			tf.WorkItems(2, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[idx+1].ID.String()
+				fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateOpen
+				return nil
+			}),
+		)
+		newVersion := *fxt.Iterations[1]
+		newVersion.State = iteration.StateClose
+		contextChanges, err := newVersion.ChangeSet(*fxt.Iterations[1])
+		require.NoError(t, err)
+		action := ActionIterationRollover{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		var convertChanges change.Set
+		_, convertChanges, err = action.OnChange(newVersion, contextChanges, "", &convertChanges)
+		require.NoError(t, err)
+		require.Len(t, convertChanges, 2)
+		moved, err := s.GormDB.WorkItems().LoadByIteration(s.Ctx, fxt.Iterations[3].ID)
+		require.NoError(t, err)
+		require.Len(t, moved, 2)
+	})
+
+	s.T().Run("iteration not closed", func(t *testing.T) {
+		fxt := s.createFixture(t)
+		newVersion := *fxt.Iterations[1]
+		newVersion.State = iteration.StateStart
+		contextChanges, err := newVersion.ChangeSet(*fxt.Iterations[1])
+		require.NoError(t, err)
+		action := ActionIterationRollover{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		var convertChanges change.Set
+		_, convertChanges, err = action.OnChange(newVersion, contextChanges, "", &convertChanges)
+		require.NoError(t, err)
+		require.Empty(t, convertChanges)
+		remaining, err := s.GormDB.WorkItems().LoadByIteration(s.Ctx, fxt.Iterations[1].ID)
+		require.NoError(t, err)
+		require.Len(t, remaining, 3)
+	})
+
+	s.T().Run("no next iteration", func(t *testing.T) {
+		fxt := s.createFixture(t)
+		// the last iteration by start date is closed.
+		newVersion := *fxt.Iterations[3]
+		newVersion.State = iteration.StateClose
+		contextChanges, err := newVersion.ChangeSet(*fxt.Iterations[3])
+		require.NoError(t, err)
+		action := ActionIterationRollover{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		var convertChanges change.Set
+		_, convertChanges, err = action.OnChange(newVersion, contextChanges, "", &convertChanges)
+		require.NoError(t, err)
+		require.Empty(t, convertChanges)
+	})
+
+	s.T().Run("work items of types without a meta-state mapping stay", func(t *testing.T) {
+		startDates := []time.Time{
+			time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2018, time.February, 1, 0, 0, 0, 0, time.UTC),
+		}
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				delete(fxt.WorkItemTypes[idx].Fields, workitem.SystemMetaState)
+				return nil
+			}),
+			tf.Iterations(3, func(fxt *tf.TestFixture, idx int) error {
+				if idx == 0 {
+					fxt.Iterations[idx].Name = fxt.Spaces[0].Name
+					return nil
+				}
+				fxt.Iterations[idx].MakeChildOf(*fxt.Iterations[0])
+				fxt.Iterations[idx].StartAt = &startDates[idx-1]
+				return nil
+			}),
+			tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[1].ID.String()
+				fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateNew
+				return nil
+			}),
+		)
+		newVersion := *fxt.Iterations[1]
+		newVersion.State = iteration.StateClose
+		contextChanges, err := newVersion.ChangeSet(*fxt.Iterations[1])
+		require.NoError(t, err)
+		action := ActionIterationRollover{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		var convertChanges change.Set
+		_, convertChanges, err = action.OnChange(newVersion, contextChanges, "", &convertChanges)
+		require.NoError(t, err)
+		require.Empty(t, convertChanges)
+		remaining, err := s.GormDB.WorkItems().LoadByIteration(s.Ctx, fxt.Iterations[1].ID)
+		require.NoError(t, err)
+		require.Len(t, remaining, 1)
+	})
+
+	s.T().Run("non-iteration context", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+		action := ActionIterationRollover{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		var convertChanges change.Set
+		_, _, err := action.OnChange(*fxt.WorkItems[0], change.Set{}, "", &convertChanges)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,247 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/fabric8-services/fabric8-wit/actions/change"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+)
+
+// ActionStateToMetaState implements a bidirectional mapping between the
+// system.state of a work item and the board columns the work item is placed
+// in (system.boardcolumns). The mapping is done via the system.metastate
+// field of the work item type: the n-th value of the system.metastate enum is
+// the meta-state of the n-th value of the system.state enum. Board columns
+// refer to a meta-state in their transition rule argument, e.g.
+// { "metaState": "mOpen" }.
+//
+// Moving a work item into a column sets the work item state to the first
+// state that maps to the column's meta-state. Changing the state of a work
+// item moves it into the first column with the matching meta-state on every
+// board of the space template. Note that this only works on WorkItems.
+type ActionStateToMetaState struct {
+	Db     application.DB
+	Ctx    context.Context
+	UserID *uuid.UUID
+}
+
+// make sure the rule is implementing the interface.
+var _ Action = ActionStateToMetaState{}
+
+func (act ActionStateToMetaState) storeWorkItem(wi *workitem.WorkItem) (*workitem.WorkItem, error) {
+	return storeWorkItem(act.Ctx, act.Db, act.UserID, wi)
+}
+
+// OnChange executes the action rule.
+func (act ActionStateToMetaState) OnChange(newContext change.Detector, contextChanges change.Set, configuration string, actionChanges *change.Set) (change.Detector, change.Set, error) {
+	// check if the newContext is a WorkItem, fail otherwise.
+	wi, ok := newContext.(workitem.WorkItem)
+	if !ok {
+		return nil, nil, errs.New("given context is not a WorkItem: " + reflect.TypeOf(newContext).String())
+	}
+	if act.Db == nil {
+		return nil, nil, errs.New("database is nil")
+	}
+	var stateChange, columnsChange *change.Change
+	for i, c := range contextChanges {
+		switch c.AttributeName {
+		case workitem.SystemState:
+			stateChange = &contextChanges[i]
+		case workitem.SystemBoardcolumns:
+			columnsChange = &contextChanges[i]
+		}
+	}
+	if stateChange == nil && columnsChange == nil {
+		// nothing to do for us.
+		return newContext, *actionChanges, nil
+	}
+	// load the state/meta-state mapping from the WIT.
+	wit, err := act.Db.WorkItemTypes().Load(act.Ctx, wi.Type)
+	if err != nil {
+		return nil, nil, errs.Wrap(err, "error loading work item type")
+	}
+	stateToMetaState, metaStateToStates, err := metaStateMapping(*wit)
+	if err != nil {
+		return nil, nil, err
+	}
+	// load the boards of the space template.
+	s, err := act.Db.Spaces().Load(act.Ctx, wi.SpaceID)
+	if err != nil {
+		return nil, nil, errs.Wrap(err, "error loading space")
+	}
+	boards, err := act.Db.Boards().List(act.Ctx, s.SpaceTemplateID)
+	if err != nil {
+		return nil, nil, errs.Wrap(err, "error loading boards")
+	}
+	oldState, _ := wi.Fields[workitem.SystemState].(string)
+	newState := oldState
+	oldColumns := columnIDs(wi.Fields[workitem.SystemBoardcolumns])
+	// a column move takes precedence over a state change.
+	if columnsChange != nil {
+		for _, columnID := range addedColumnIDs(columnsChange.OldValue, columnsChange.NewValue) {
+			column := findColumn(boards, columnID)
+			if column == nil {
+				continue
+			}
+			metaState := columnMetaState(*column)
+			if metaState == "" || stateToMetaState[newState] == metaState {
+				continue
+			}
+			if states := metaStateToStates[metaState]; len(states) > 0 {
+				newState = states[0]
+			}
+			break
+		}
+	}
+	// move the work item into the matching column on every board.
+	newColumns := make([]string, len(oldColumns))
+	copy(newColumns, oldColumns)
+	if metaState, ok := stateToMetaState[newState]; ok {
+		for _, board := range boards {
+			newColumns = placeOnBoard(newColumns, *board, metaState)
+		}
+	}
+	if newState != oldState {
+		*actionChanges = append(*actionChanges, change.Change{
+			AttributeName: workitem.SystemState,
+			NewValue:      newState,
+			OldValue:      oldState,
+		})
+		wi.Fields[workitem.SystemState] = newState
+	}
+	if !reflect.DeepEqual(oldColumns, newColumns) {
+		newValue := make([]interface{}, len(newColumns))
+		for i, columnID := range newColumns {
+			newValue[i] = columnID
+		}
+		*actionChanges = append(*actionChanges, change.Change{
+			AttributeName: workitem.SystemBoardcolumns,
+			NewValue:      newValue,
+			OldValue:      wi.Fields[workitem.SystemBoardcolumns],
+		})
+		wi.Fields[workitem.SystemBoardcolumns] = newValue
+	}
+	if newState == oldState && reflect.DeepEqual(oldColumns, newColumns) {
+		// the work item is already consistent, no need to store it.
+		return wi, *actionChanges, nil
+	}
+	// store the WorkItem.
+	actionResultContext, err := act.storeWorkItem(&wi)
+	if err != nil {
+		return nil, nil, err
+	}
+	return *actionResultContext, *actionChanges, nil
+}
+
+// metaStateMapping returns the mapping between the values of the system.state
+// and the system.metastate enums of the given work item type (see
+// workitem.WorkItemType.MetaStateMapping) and its reverse, which lists the
+// states of every meta-state in the order of the state enum.
+func metaStateMapping(wit workitem.WorkItemType) (workitem.MetaStateMapping, map[string][]string, error) {
+	stateToMetaState, err := wit.MetaStateMapping()
+	if err != nil {
+		return nil, nil, err
+	}
+	stateValues, err := wit.EnumValues(workitem.SystemState)
+	if err != nil {
+		return nil, nil, err
+	}
+	metaStateToStates := map[string][]string{}
+	for _, state := range stateValues {
+		metaState := stateToMetaState.MetaState(state)
+		metaStateToStates[metaState] = append(metaStateToStates[metaState], state)
+	}
+	return stateToMetaState, metaStateToStates, nil
+}
+
+// columnMetaState returns the meta-state configured in the transition rule
+// argument of the given column or an empty string if there is none.
+func columnMetaState(column workitem.BoardColumn) string {
+	var args map[string]interface{}
+	if err := json.Unmarshal([]byte(column.TransRuleArgument), &args); err != nil {
+		return ""
+	}
+	metaState, _ := args[ActionKeyStateToMetastateConfigMetastate].(string)
+	return metaState
+}
+
+// findColumn returns the column with the given ID from the given boards or
+// nil if no such column exists.
+func findColumn(boards []*workitem.Board, columnID string) *workitem.BoardColumn {
+	for _, board := range boards {
+		for i := range board.Columns {
+			if board.Columns[i].ID.String() == columnID {
+				return &board.Columns[i]
+			}
+		}
+	}
+	return nil
+}
+
+// placeOnBoard makes sure the given column IDs contain a column of the given
+// board that has the given meta-state. An existing column of the board is
+// replaced unless it already has the given meta-state. If the board has no
+// column with the given meta-state, the columns are returned unchanged.
+func placeOnBoard(columns []string, board workitem.Board, metaState string) []string {
+	var target *workitem.BoardColumn
+	for i := range board.Columns {
+		if columnMetaState(board.Columns[i]) == metaState {
+			target = &board.Columns[i]
+			break
+		}
+	}
+	if target == nil {
+		return columns
+	}
+	res := []string{}
+	for _, columnID := range columns {
+		column := findColumn([]*workitem.Board{&board}, columnID)
+		if column == nil {
+			res = append(res, columnID)
+			continue
+		}
+		if columnMetaState(*column) == metaState {
+			// already in a matching column of this board.
+			return columns
+		}
+	}
+	return append(res, target.ID.String())
+}
+
+// columnIDs converts the value of a system.boardcolumns field to a list of
+// column IDs.
+func columnIDs(value interface{}) []string {
+	res := []string{}
+	switch v := value.(type) {
+	case []interface{}:
+		for _, columnID := range v {
+			res = append(res, fmt.Sprintf("%v", columnID))
+		}
+	case []string:
+		res = append(res, v...)
+	}
+	return res
+}
+
+// addedColumnIDs returns the column IDs contained in the new value but not in
+// the old value of a system.boardcolumns field.
+func addedColumnIDs(oldValue, newValue interface{}) []string {
+	old := map[string]struct{}{}
+	for _, columnID := range columnIDs(oldValue) {
+		old[columnID] = struct{}{}
+	}
+	res := []string{}
+	for _, columnID := range columnIDs(newValue) {
+		if _, ok := old[columnID]; !ok {
+			res = append(res, columnID)
+		}
+	}
+	return res
+}
@@ -0,0 +1,128 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/fabric8-services/fabric8-wit/actions/change"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+)
+
+func TestSuiteActionStateToMetaState(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &ActionStateToMetaStateSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+type ActionStateToMetaStateSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func (s *ActionStateToMetaStateSuite) TestActionExecution() {
+	s.T().Run("state change moves to matching columns", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItemTypeGroups(2), tf.WorkItemBoards(2), tf.WorkItems(1))
+		fxt.WorkItems[0].Fields[workitem.SystemState] = workitem.SystemStateNew
+		fxt.WorkItems[0].Fields[workitem.SystemBoardcolumns] = []interface{}{
+			fxt.WorkItemBoards[0].Columns[0].ID.String(),
+			fxt.WorkItemBoards[1].Columns[0].ID.String(),
+		}
+		newVersion := createWICopy(*fxt.WorkItems[0], workitem.SystemStateInProgress, fxt.WorkItems[0].Fields[workitem.SystemBoardcolumns].([]interface{}))
+		contextChanges, err := newVersion.ChangeSet(*fxt.WorkItems[0])
+		require.NoError(t, err)
+		action := ActionStateToMetaState{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		var convertChanges change.Set
+		afterActionWI, convertChanges, err := action.OnChange(newVersion, contextChanges, "", &convertChanges)
+		require.NoError(t, err)
+		require.Len(t, convertChanges, 1)
+		require.Equal(t, workitem.SystemBoardcolumns, convertChanges[0].AttributeName)
+		require.Equal(t, workitem.SystemStateInProgress, afterActionWI.(workitem.WorkItem).Fields[workitem.SystemState])
+		require.ElementsMatch(t, []interface{}{
+			fxt.WorkItemBoards[0].Columns[1].ID.String(),
+			fxt.WorkItemBoards[1].Columns[1].ID.String(),
+		}, afterActionWI.(workitem.WorkItem).Fields[workitem.SystemBoardcolumns])
+	})
+
+	s.T().Run("column move changes state", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItemTypeGroups(2), tf.WorkItemBoards(2), tf.WorkItems(1))
+		fxt.WorkItems[0].Fields[workitem.SystemState] = workitem.SystemStateNew
+		fxt.WorkItems[0].Fields[workitem.SystemBoardcolumns] = []interface{}{
+			fxt.WorkItemBoards[0].Columns[0].ID.String(),
+			fxt.WorkItemBoards[1].Columns[0].ID.String(),
+		}
+		newVersion := createWICopy(*fxt.WorkItems[0], workitem.SystemStateNew, []interface{}{
+			fxt.WorkItemBoards[0].Columns[2].ID.String(),
+			fxt.WorkItemBoards[1].Columns[0].ID.String(),
+		})
+		contextChanges, err := newVersion.ChangeSet(*fxt.WorkItems[0])
+		require.NoError(t, err)
+		action := ActionStateToMetaState{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		var convertChanges change.Set
+		afterActionWI, convertChanges, err := action.OnChange(newVersion, contextChanges, "", &convertChanges)
+		require.NoError(t, err)
+		require.Len(t, convertChanges, 2)
+		require.Equal(t, workitem.SystemState, convertChanges[0].AttributeName)
+		require.Equal(t, workitem.SystemStateNew, convertChanges[0].OldValue)
+		require.Equal(t, workitem.SystemStateResolved, convertChanges[0].NewValue)
+		require.Equal(t, workitem.SystemBoardcolumns, convertChanges[1].AttributeName)
+		require.Equal(t, workitem.SystemStateResolved, afterActionWI.(workitem.WorkItem).Fields[workitem.SystemState])
+		// the work item is moved on the other board as well.
+		require.ElementsMatch(t, []interface{}{
+			fxt.WorkItemBoards[0].Columns[2].ID.String(),
+			fxt.WorkItemBoards[1].Columns[2].ID.String(),
+		}, afterActionWI.(workitem.WorkItem).Fields[workitem.SystemBoardcolumns])
+	})
+
+	s.T().Run("column move keeps matching state", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItemTypeGroups(1), tf.WorkItemBoards(1), tf.WorkItems(1))
+		fxt.WorkItems[0].Fields[workitem.SystemState] = workitem.SystemStateResolved
+		fxt.WorkItems[0].Fields[workitem.SystemBoardcolumns] = []interface{}{fxt.WorkItemBoards[0].Columns[2].ID.String()}
+		// both columns have the same meta-state.
+		newVersion := createWICopy(*fxt.WorkItems[0], workitem.SystemStateResolved, []interface{}{fxt.WorkItemBoards[0].Columns[3].ID.String()})
+		contextChanges, err := newVersion.ChangeSet(*fxt.WorkItems[0])
+		require.NoError(t, err)
+		action := ActionStateToMetaState{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		var convertChanges change.Set
+		afterActionWI, convertChanges, err := action.OnChange(newVersion, contextChanges, "", &convertChanges)
+		require.NoError(t, err)
+		require.Empty(t, convertChanges)
+		require.Equal(t, workitem.SystemStateResolved, afterActionWI.(workitem.WorkItem).Fields[workitem.SystemState])
+		require.Equal(t, []interface{}{fxt.WorkItemBoards[0].Columns[3].ID.String()}, afterActionWI.(workitem.WorkItem).Fields[workitem.SystemBoardcolumns])
+	})
+
+	s.T().Run("unrelated change", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItemTypeGroups(1), tf.WorkItemBoards(1), tf.WorkItems(1))
+		contextChanges := change.Set{
+			{
+				AttributeName: workitem.SystemTitle,
+				NewValue:      "new title",
+				OldValue:      "old title",
+			},
+		}
+		action := ActionStateToMetaState{
+			Db:     s.GormDB,
+			Ctx:    s.Ctx,
+			UserID: &fxt.Identities[0].ID,
+		}
+		var convertChanges change.Set
+		afterActionWI, convertChanges, err := action.OnChange(*fxt.WorkItems[0], contextChanges, "", &convertChanges)
+		require.NoError(t, err)
+		require.Empty(t, convertChanges)
+		require.Equal(t, *fxt.WorkItems[0], afterActionWI)
+	})
+}
@@ -1,19 +1,26 @@
 package application
 
 import (
+	"context"
+
 	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/area"
+	"github.com/fabric8-services/fabric8-wit/attachment"
 	"github.com/fabric8-services/fabric8-wit/codebase"
 	"github.com/fabric8-services/fabric8-wit/comment"
 	"github.com/fabric8-services/fabric8-wit/iteration"
 	"github.com/fabric8-services/fabric8-wit/label"
+	"github.com/fabric8-services/fabric8-wit/notification/outbox"
 	"github.com/fabric8-services/fabric8-wit/query"
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/fabric8-services/fabric8-wit/webhook"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/analytics"
 	"github.com/fabric8-services/fabric8-wit/workitem/event"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	uuid "github.com/satori/go.uuid"
 )
 
 //An Application stands for a particular implementation of the business logic of our application
@@ -22,11 +29,14 @@ type Application interface {
 	WorkItemTypes() workitem.WorkItemTypeRepository
 	Trackers() remoteworkitem.TrackerRepository
 	TrackerQueries() remoteworkitem.TrackerQueryRepository
+	TrackerQueryRuns() remoteworkitem.TrackerQueryRunRepository
 	SearchItems() SearchRepository
 	Identities() account.IdentityRepository
 	WorkItemLinkTypes() link.WorkItemLinkTypeRepository
 	WorkItemLinks() link.WorkItemLinkRepository
 	Comments() comment.Repository
+	CommentMentions() comment.MentionRepository
+	Attachments() attachment.Repository
 	Spaces() space.Repository
 	Iterations() iteration.Repository
 	Users() account.UserRepository
@@ -35,9 +45,13 @@ type Application interface {
 	Labels() label.Repository
 	Queries() query.Repository
 	Events() event.Repository
+	Analytics() analytics.Repository
 	SpaceTemplates() spacetemplate.Repository
 	WorkItemTypeGroups() workitem.WorkItemTypeGroupRepository
 	Boards() workitem.BoardRepository
+	ActionRules() workitem.ActionRuleRepository
+	Webhooks() webhook.Repository
+	Outbox() outbox.Repository
 }
 
 // A Transaction abstracts a database transaction. The repositories created for the transaction object make changes inside the the transaction
@@ -52,3 +66,19 @@ type DB interface {
 	Application
 	BeginTransaction() (Transaction, error)
 }
+
+// WorkItemChangeHook is run by the work item repository of an Application
+// whenever a work item was created, saved or reverted. It runs in the same
+// transaction as the change and the given DB takes part in that transaction,
+// so an error of the hook undoes the change. older is nil for a created work
+// item. The hook returns the work item as it is after the hook ran. Changes
+// that the hook makes to other work items run the hook for those work items
+// as well, but the hook runs at most once per work item and change, so a
+// hook changing a work item it already ran for doesn't run it again.
+type WorkItemChangeHook func(ctx context.Context, db DB, modifierID uuid.UUID, older *workitem.WorkItem, newer workitem.WorkItem) (*workitem.WorkItem, error)
+
+// WorkItemChangeFilter is asked by the work item repository of an Application
+// whether the WorkItemChangeHook has anything to do for a change of a work
+// item in the given space. If it returns false, the repository neither loads
+// the older version of the work item nor runs the hook.
+type WorkItemChangeFilter func(ctx context.Context, db DB, spaceID uuid.UUID) (bool, error)
@@ -0,0 +1,216 @@
+package attachment
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-common/id"
+	"github.com/fabric8-services/fabric8-wit/application/repository"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeAttachment helps to avoid string literal
+const APIStringTypeAttachment = "attachments"
+
+// AttachmentTableName constant that holds table name of Attachments
+const AttachmentTableName = "attachments"
+
+// DefaultContentType is the content type of attachments uploaded without one
+const DefaultContentType = "application/octet-stream"
+
+// Attachment describes a file attached to a work item or to one of its
+// comments. The content is kept in a BlobStore under the BlobKey.
+type Attachment struct {
+	gormsupport.Lifecycle
+	ID         uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	WorkItemID uuid.UUID `sql:"type:uuid"`
+	// CommentID is set if the file is attached to a comment of the work item
+	CommentID   id.NullUUID `sql:"type:uuid"`
+	Name        string
+	ContentType string
+	Size        int64
+	UploaderID  uuid.UUID `sql:"type:uuid"`
+	BlobKey     string
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (a Attachment) TableName() string {
+	return AttachmentTableName
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (a Attachment) GetETagData() []interface{} {
+	return []interface{}{a.ID, strconv.FormatInt(a.UpdatedAt.Unix(), 10)}
+}
+
+// GetLastModified returns the last modification time
+func (a Attachment) GetLastModified() time.Time {
+	return a.UpdatedAt.Truncate(time.Second)
+}
+
+// Repository describes interactions with Attachments.
+type Repository interface {
+	repository.Exister
+	Create(ctx context.Context, a *Attachment) error
+	Load(ctx context.Context, ID uuid.UUID) (*Attachment, error)
+	// List returns the attachments of the given work item and its comments,
+	// the oldest attachment first
+	List(ctx context.Context, workItemID uuid.UUID) ([]Attachment, error)
+	Delete(ctx context.Context, ID uuid.UUID) error
+}
+
+// NewAttachmentRepository creates a new storage type.
+func NewAttachmentRepository(db *gorm.DB) Repository {
+	return &GormAttachmentRepository{db: db}
+}
+
+// GormAttachmentRepository is the implementation of the storage interface for Attachments.
+type GormAttachmentRepository struct {
+	db *gorm.DB
+}
+
+// CheckExists returns nil if the given ID exists otherwise returns an error
+func (r *GormAttachmentRepository) CheckExists(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "exists"}, time.Now())
+	return repository.CheckExists(ctx, r.db, AttachmentTableName, id)
+}
+
+// Create records the given attachment. The ID and the blob key must already
+// be set since the content is stored before the attachment is recorded.
+func (r *GormAttachmentRepository) Create(ctx context.Context, a *Attachment) error {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "create"}, time.Now())
+	if strings.TrimSpace(a.Name) == "" {
+		return errors.NewBadParameterError("name", a.Name).Expected("non empty string")
+	}
+	if a.ContentType == "" {
+		a.ContentType = DefaultContentType
+	}
+	if err := r.db.Create(a).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"workitem_id": a.WorkItemID,
+			"err":         err,
+		}, "unable to create the attachment")
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to create the attachment"))
+	}
+	return nil
+}
+
+// Load returns the attachment with the given ID
+func (r *GormAttachmentRepository) Load(ctx context.Context, ID uuid.UUID) (*Attachment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "show"}, time.Now())
+	a := Attachment{}
+	tx := r.db.Where("id = ?", ID).First(&a)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("attachment", ID.String())
+	}
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":           tx.Error,
+			"attachment_id": ID.String(),
+		}, "unable to load the attachment by ID")
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	return &a, nil
+}
+
+// List returns the attachments of the given work item and its comments
+func (r *GormAttachmentRepository) List(ctx context.Context, workItemID uuid.UUID) ([]Attachment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "list"}, time.Now())
+	var objs []Attachment
+	err := r.db.Where("work_item_id = ?", workItemID).Order("created_at").Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return objs, nil
+}
+
+// Delete deletes the attachment with the given id, returns NotFoundError or
+// InternalError. The content is left to the caller to delete from the blob
+// store.
+func (r *GormAttachmentRepository) Delete(ctx context.Context, ID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "delete"}, time.Now())
+	tx := r.db.Delete(Attachment{ID: ID})
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"attachment_id": ID.String(),
+			"err":           err,
+		}, "unable to delete the attachment")
+		return errors.NewInternalError(ctx, err)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("attachment", ID.String())
+	}
+	return nil
+}
+
+// errTooLarge is returned by the reader of an upload that exceeds the maximum
+// size
+var errTooLarge = errs.New("attachment too large")
+
+// limitedReader reads from r until more than max bytes were read and fails
+// from then on
+type limitedReader struct {
+	r   io.Reader
+	max int64
+	n   int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.n > l.max {
+		return n, errTooLarge
+	}
+	return n, err
+}
+
+// PutContent writes the content read from r to the given blob store and sets
+// the ID, blob key and size of the given attachment without recording it.
+// Returns a BadParameterError if the content is larger than maxSize bytes.
+func PutContent(ctx context.Context, store BlobStore, a *Attachment, r io.Reader, maxSize int64) error {
+	a.ID = uuid.NewV4()
+	a.BlobKey = fmt.Sprintf("%s/%s", a.WorkItemID, a.ID)
+	size, err := store.Put(ctx, a.BlobKey, &limitedReader{r: r, max: maxSize})
+	if errs.Cause(err) == errTooLarge {
+		return errors.NewBadParameterErrorFromString(fmt.Sprintf("the attachment exceeds the maximum size of %d bytes", maxSize))
+	}
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to store the content of attachment %s", a.Name))
+	}
+	a.Size = size
+	return nil
+}
+
+// Store writes the content read from r to the given blob store and records
+// the given attachment with its size. Returns a BadParameterError if the
+// content is larger than maxSize bytes. The content is removed from the blob
+// store again if the attachment cannot be recorded; if the transaction of
+// the given repository is rolled back later on it is up to the caller to
+// remove it.
+func Store(ctx context.Context, repo Repository, store BlobStore, a *Attachment, r io.Reader, maxSize int64) error {
+	if err := PutContent(ctx, store, a, r, maxSize); err != nil {
+		return err
+	}
+	if err := repo.Create(ctx, a); err != nil {
+		if err := store.Delete(ctx, a.BlobKey); err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"attachment_id": a.ID,
+				"blob_key":      a.BlobKey,
+				"err":           err,
+			}, "unable to remove the content of the attachment that could not be recorded")
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package attachment_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-common/id"
+	"github.com/fabric8-services/fabric8-wit/attachment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type attachmentRepoSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestAttachmentRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &attachmentRepoSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *attachmentRepoSuite) TestStore() {
+	// given
+	dir, err := ioutil.TempDir("", "attachments")
+	require.NoError(s.T(), err)
+	defer os.RemoveAll(dir)
+	store, err := attachment.NewFileSystemBlobStore(dir)
+	require.NoError(s.T(), err)
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(1), tf.Comments(1))
+	repo := attachment.NewAttachmentRepository(s.DB)
+
+	var stored attachment.Attachment
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		stored = attachment.Attachment{
+			WorkItemID: fxt.WorkItems[0].ID,
+			CommentID:  id.NullUUID{UUID: fxt.Comments[0].ID, Valid: true},
+			Name:       "trace.txt",
+			UploaderID: fxt.Identities[0].ID,
+		}
+		// when
+		err := attachment.Store(s.Ctx, repo, store, &stored, strings.NewReader("stack trace"), 100)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int64(11), stored.Size)
+		assert.Equal(t, attachment.DefaultContentType, stored.ContentType)
+		loaded, err := repo.Load(s.Ctx, stored.ID)
+		require.NoError(t, err)
+		assert.Equal(t, stored.BlobKey, loaded.BlobKey)
+		assert.Equal(t, fxt.Comments[0].ID, loaded.CommentID.UUID)
+		r, err := store.Get(s.Ctx, loaded.BlobKey)
+		require.NoError(t, err)
+		defer r.Close()
+		content, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "stack trace", string(content))
+	})
+
+	s.T().Run("too large", func(t *testing.T) {
+		// given
+		a := attachment.Attachment{
+			WorkItemID: fxt.WorkItems[0].ID,
+			Name:       "large.bin",
+			UploaderID: fxt.Identities[0].ID,
+		}
+		// when
+		err := attachment.Store(s.Ctx, repo, store, &a, strings.NewReader(strings.Repeat("x", 101)), 100)
+		// then
+		require.Error(t, err)
+		ok, _ := errors.IsBadParameterError(err)
+		assert.True(t, ok)
+		_, err = store.Get(s.Ctx, a.BlobKey)
+		require.IsType(t, errors.NotFoundError{}, err)
+	})
+
+	s.T().Run("missing name", func(t *testing.T) {
+		// given
+		a := attachment.Attachment{
+			WorkItemID: fxt.WorkItems[0].ID,
+			UploaderID: fxt.Identities[0].ID,
+		}
+		// when
+		err := attachment.Store(s.Ctx, repo, store, &a, strings.NewReader("content"), 100)
+		// then
+		require.Error(t, err)
+		_, err = store.Get(s.Ctx, a.BlobKey)
+		require.IsType(t, errors.NotFoundError{}, err, "the content must be removed again")
+	})
+
+	s.T().Run("list and delete", func(t *testing.T) {
+		// when
+		attachments, err := repo.List(s.Ctx, fxt.WorkItems[0].ID)
+		// then
+		require.NoError(t, err)
+		require.Len(t, attachments, 1)
+		assert.Equal(t, stored.ID, attachments[0].ID)
+		require.NoError(t, repo.Delete(s.Ctx, stored.ID))
+		_, err = repo.Load(s.Ctx, stored.ID)
+		require.IsType(t, errors.NotFoundError{}, err)
+		require.IsType(t, errors.NotFoundError{}, repo.Delete(s.Ctx, stored.ID))
+	})
+}
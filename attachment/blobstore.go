@@ -0,0 +1,120 @@
+package attachment
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	errs "github.com/pkg/errors"
+)
+
+// BlobStore keeps the content of the attachments. The content is addressed by
+// a key made of slash separated segments, so that stores with flat
+// namespaces like S3-compatible object stores can use the key as the object
+// name.
+type BlobStore interface {
+	// Put stores the content read from r under the given key and returns its
+	// size. Nothing is stored if r returns an error.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Get returns the content stored under the given key, it is up to the
+	// caller to close it. Returns a NotFoundError if there is no such
+	// content.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the content stored under the given key. Deleting
+	// content that does not exist is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// FileSystemBlobStore is a BlobStore that keeps each blob in a file below a
+// root directory
+type FileSystemBlobStore struct {
+	root string
+}
+
+// Ensure FileSystemBlobStore implements the BlobStore interface
+var _ BlobStore = &FileSystemBlobStore{}
+
+// NewFileSystemBlobStore creates a FileSystemBlobStore that keeps the blobs
+// below the given directory, which is created if it does not exist
+func NewFileSystemBlobStore(root string) (*FileSystemBlobStore, error) {
+	if root == "" {
+		return nil, errs.New("no directory given for the blob store")
+	}
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, errs.Wrapf(err, "failed to create the blob store directory %s", root)
+	}
+	return &FileSystemBlobStore{root: root}, nil
+}
+
+// path returns the path of the file of the given key. Keys that would
+// escape the root directory are rejected.
+func (s *FileSystemBlobStore) path(key string) (string, error) {
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsRune(segment, filepath.Separator) {
+			return "", errors.NewBadParameterError("key", key).Expected("slash separated segments")
+		}
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// Put stores the content read from r in the file of the given key. The
+// content is written to a temporary file first so that a failed upload
+// neither leaves a partial blob nor overwrites an existing one.
+func (s *FileSystemBlobStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return 0, errs.Wrapf(err, "failed to create the directory of blob %s", key)
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(p), ".upload-")
+	if err != nil {
+		return 0, errs.Wrapf(err, "failed to create blob %s", key)
+	}
+	defer os.Remove(tmp.Name())
+	n, err := io.Copy(tmp, r)
+	if err != nil {
+		tmp.Close()
+		return 0, errs.Wrapf(err, "failed to write blob %s", key)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, errs.Wrapf(err, "failed to write blob %s", key)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return 0, errs.Wrapf(err, "failed to store blob %s", key)
+	}
+	return n, nil
+}
+
+// Get opens the file of the given key
+func (s *FileSystemBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if os.IsNotExist(err) {
+		return nil, errors.NewNotFoundError("blob", key)
+	}
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to read blob %s", key)
+	}
+	return f, nil
+}
+
+// Delete removes the file of the given key
+func (s *FileSystemBlobStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return errs.Wrapf(err, "failed to delete blob %s", key)
+	}
+	return nil
+}
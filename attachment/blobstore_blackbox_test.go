@@ -0,0 +1,78 @@
+package attachment_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/fabric8-services/fabric8-wit/attachment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSystemBlobStore(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	// given
+	dir, err := ioutil.TempDir("", "blobstore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	store, err := attachment.NewFileSystemBlobStore(filepath.Join(dir, "blobs"))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("put and get", func(t *testing.T) {
+		// when
+		n, err := store.Put(ctx, "a/b", strings.NewReader("content"))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), n)
+		r, err := store.Get(ctx, "a/b")
+		require.NoError(t, err)
+		defer r.Close()
+		content, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "content", string(content))
+	})
+
+	t.Run("failed put keeps the existing content", func(t *testing.T) {
+		// when
+		_, err := store.Put(ctx, "a/b", iotest.TimeoutReader(strings.NewReader("other content")))
+		// then
+		require.Error(t, err)
+		r, err := store.Get(ctx, "a/b")
+		require.NoError(t, err)
+		defer r.Close()
+		content, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "content", string(content))
+		files, err := ioutil.ReadDir(filepath.Join(dir, "blobs", "a"))
+		require.NoError(t, err)
+		assert.Len(t, files, 1, "no temporary file must be left behind")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		// when
+		err := store.Delete(ctx, "a/b")
+		// then
+		require.NoError(t, err)
+		_, err = store.Get(ctx, "a/b")
+		require.IsType(t, errors.NotFoundError{}, err)
+		// deleting again is fine
+		require.NoError(t, store.Delete(ctx, "a/b"))
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		for _, key := range []string{"", "../a", "a/../../b", "/a", "a//b", "a/./b"} {
+			_, err := store.Put(ctx, key, strings.NewReader("content"))
+			require.Error(t, err, key)
+			ok, _ := errors.IsBadParameterError(err)
+			assert.True(t, ok, key)
+		}
+	})
+}
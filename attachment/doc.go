@@ -0,0 +1,4 @@
+// Package attachment contains the files attached to work items and their
+// comments. The metadata of an attachment is kept in the database while its
+// content is kept in a BlobStore.
+package attachment
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/rest"
+	goaclient "github.com/goadesign/goa/client"
+	errs "github.com/pkg/errors"
+)
+
+// serviceAccountTokenLeeway is how long before its expiry a cached service
+// account token is renewed
+const serviceAccountTokenLeeway = time.Minute
+
+// ServiceAccountConfiguration holds the credentials of the service account
+// used to call other services outside of a user request
+type ServiceAccountConfiguration interface {
+	GetAuthServiceURL() string
+	GetServiceAccountID() string
+	GetServiceAccountSecret() string
+}
+
+// ServiceAccountSigner signs requests with a token of the service account.
+// The token is obtained from the auth service with the client credentials
+// grant and cached until shortly before it expires.
+type ServiceAccountSigner struct {
+	config    ServiceAccountConfiguration
+	client    *http.Client
+	lock      sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// ensure ServiceAccountSigner implements the goa client Signer interface
+var _ goaclient.Signer = &ServiceAccountSigner{}
+
+// NewServiceAccountSigner creates a signer for the configured service account
+func NewServiceAccountSigner(config ServiceAccountConfiguration) *ServiceAccountSigner {
+	return &ServiceAccountSigner{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Sign sets the Authorization header of the request
+func (s *ServiceAccountSigner) Sign(req *http.Request) error {
+	token, err := s.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Token returns a valid access token of the service account
+func (s *ServiceAccountSigner) Token(ctx context.Context) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.token != "" && time.Now().Before(s.expiresAt) {
+		return s.token, nil
+	}
+	tokenURL := strings.TrimSuffix(s.config.GetAuthServiceURL(), "/") + "/api/token"
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {s.config.GetServiceAccountID()},
+		"client_secret": {s.config.GetServiceAccountSecret()},
+	}
+	req, err := http.NewRequest(http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errs.Wrapf(err, "failed to create the token request to %s", tokenURL)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", errs.Wrapf(err, "failed to obtain a service account token from %s", tokenURL)
+	}
+	defer rest.CloseResponse(resp)
+	if resp.StatusCode != http.StatusOK {
+		return "", errs.Errorf("unexpected response code %d from %s: %s", resp.StatusCode, tokenURL, rest.ReadBody(resp.Body))
+	}
+	var token struct {
+		AccessToken string      `json:"access_token"`
+		ExpiresIn   json.Number `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", errs.Wrapf(err, "failed to decode the service account token from %s", tokenURL)
+	}
+	if token.AccessToken == "" {
+		return "", errs.Errorf("no access token in the response from %s", tokenURL)
+	}
+	expiresIn, err := token.ExpiresIn.Int64()
+	if err != nil {
+		// without a known expiry the token is only used for this request
+		expiresIn = 0
+	}
+	s.token = token.AccessToken
+	s.expiresAt = time.Now().Add(time.Duration(expiresIn)*time.Second - serviceAccountTokenLeeway)
+	return s.token, nil
+}
@@ -0,0 +1,66 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/auth"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type serviceAccountConfig struct {
+	authURL string
+}
+
+func (c serviceAccountConfig) GetAuthServiceURL() string       { return c.authURL }
+func (c serviceAccountConfig) GetServiceAccountID() string     { return "wit-id" }
+func (c serviceAccountConfig) GetServiceAccountSecret() string { return "wit-secret" }
+
+func TestServiceAccountSigner(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+
+	t.Run("token is requested once and cached", func(t *testing.T) {
+		// given
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			assert.Equal(t, "/api/token", r.URL.Path)
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "wit-id", r.PostForm.Get("client_id"))
+			assert.Equal(t, "wit-secret", r.PostForm.Get("client_secret"))
+			w.Write([]byte(`{"access_token":"abc","token_type":"bearer","expires_in":"3600"}`))
+		}))
+		defer server.Close()
+		signer := auth.NewServiceAccountSigner(serviceAccountConfig{authURL: server.URL})
+		for i := 0; i < 2; i++ {
+			req, err := http.NewRequest(http.MethodPost, "http://notification/api/notify", nil)
+			require.NoError(t, err)
+			// when
+			err = signer.Sign(req)
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, "Bearer abc", req.Header.Get("Authorization"))
+		}
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("rejected credentials", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+		signer := auth.NewServiceAccountSigner(serviceAccountConfig{authURL: server.URL})
+		req, err := http.NewRequest(http.MethodPost, "http://notification/api/notify", nil)
+		require.NoError(t, err)
+		// when
+		err = signer.Sign(req)
+		// then
+		require.Error(t, err)
+		assert.Empty(t, req.Header.Get("Authorization"))
+	})
+}
@@ -258,3 +258,43 @@ func (s *TestCommentRepository) TestExistsComment() {
 		require.IsType(t, errors.NotFoundError{}, err)
 	})
 }
+
+func (s *TestCommentRepository) TestListThreads() {
+	// given a thread of a comment, a reply and a reply to the reply and a
+	// second thread without replies
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Comments(4, func(fxt *tf.TestFixture, idx int) error {
+		if idx == 1 || idx == 2 {
+			fxt.Comments[idx].ParentCommentID = id.NullUUID{UUID: fxt.Comments[idx-1].ID, Valid: true}
+		}
+		return nil
+	}))
+	s.T().Run("threads", func(t *testing.T) {
+		// when
+		threads, count, err := s.repo.ListThreads(s.Ctx, fxt.WorkItems[0].ID, nil, nil)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), count)
+		require.Len(t, threads, 2)
+		assert.Equal(t, fxt.Comments[3].ID, threads[0].ID)
+		assert.Equal(t, fxt.Comments[0].ID, threads[1].ID)
+	})
+	s.T().Run("replies", func(t *testing.T) {
+		// when
+		replies, err := s.repo.ListReplies(s.Ctx, []uuid.UUID{fxt.Comments[0].ID, fxt.Comments[3].ID})
+		// then
+		require.NoError(t, err)
+		require.Len(t, replies, 2)
+		assert.Equal(t, fxt.Comments[1].ID, replies[0].ID)
+		assert.Equal(t, fxt.Comments[2].ID, replies[1].ID)
+	})
+	s.T().Run("replies to deleted comments", func(t *testing.T) {
+		// given
+		err := s.repo.Delete(s.Ctx, fxt.Comments[1].ID, fxt.Comments[1].Creator)
+		require.NoError(t, err)
+		// when
+		replies, err := s.repo.ListReplies(s.Ctx, []uuid.UUID{fxt.Comments[0].ID})
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, replies)
+	})
+}
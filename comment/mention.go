@@ -0,0 +1,138 @@
+package comment
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Mention is an identity mentioned with "@username" in the body of a comment
+type Mention struct {
+	CommentID  uuid.UUID `sql:"type:uuid" gorm:"primary_key"`
+	IdentityID uuid.UUID `sql:"type:uuid" gorm:"primary_key"`
+	CreatedAt  time.Time
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m Mention) TableName() string {
+	return "comment_mentions"
+}
+
+var (
+	// mentionPattern matches "@username" unless it is part of a word, e.g.
+	// an email address
+	mentionPattern = regexp.MustCompile(`(?:^|[^\w@/.])@([A-Za-z0-9](?:[A-Za-z0-9._-]*[A-Za-z0-9])?)`)
+	// codeBlockPattern matches fenced code blocks and inline code
+	codeBlockPattern = regexp.MustCompile("(?s)```.*?```|~~~.*?~~~|`[^`\n]*`")
+)
+
+// ParseMentions returns the usernames mentioned in the given comment body in
+// order of their first appearance. Only Markdown bodies can contain mentions
+// and mentions in code are ignored.
+func ParseMentions(body string, markup string) []string {
+	if markup != rendering.SystemMarkupMarkdown {
+		return nil
+	}
+	body = codeBlockPattern.ReplaceAllString(body, " ")
+	seen := map[string]bool{}
+	var usernames []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		username := match[1]
+		if seen[strings.ToLower(username)] {
+			continue
+		}
+		seen[strings.ToLower(username)] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
+
+// MentionRepository encapsulates storage & retrieval of the identities
+// mentioned in comments
+type MentionRepository interface {
+	// Set replaces the mentions of the given comment and returns the
+	// identities that were not mentioned before
+	Set(ctx context.Context, commentID uuid.UUID, identityIDs []uuid.UUID) ([]uuid.UUID, error)
+	// List returns the mentioned identities of the given comments
+	List(ctx context.Context, commentIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error)
+}
+
+// NewMentionRepository creates a GormMentionRepository
+func NewMentionRepository(db *gorm.DB) *GormMentionRepository {
+	return &GormMentionRepository{db: db}
+}
+
+// GormMentionRepository implements MentionRepository using gorm
+type GormMentionRepository struct {
+	db *gorm.DB
+}
+
+// Set replaces the mentions of the given comment and returns the identities
+// that were not mentioned before
+func (r *GormMentionRepository) Set(ctx context.Context, commentID uuid.UUID, identityIDs []uuid.UUID) ([]uuid.UUID, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment_mention", "set"}, time.Now())
+	existing, err := r.List(ctx, []uuid.UUID{commentID})
+	if err != nil {
+		return nil, err
+	}
+	mentioned := map[uuid.UUID]bool{}
+	for _, id := range existing[commentID] {
+		mentioned[id] = true
+	}
+	keep := map[uuid.UUID]bool{}
+	var added []uuid.UUID
+	for _, id := range identityIDs {
+		if keep[id] {
+			continue
+		}
+		keep[id] = true
+		if mentioned[id] {
+			continue
+		}
+		if err := r.db.Create(&Mention{CommentID: commentID, IdentityID: id}).Error; err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"comment_id":  commentID,
+				"identity_id": id,
+				"err":         err,
+			}, "unable to store the mention")
+			return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to store the mention of identity %s in comment %s", id, commentID))
+		}
+		added = append(added, id)
+	}
+	for id := range mentioned {
+		if keep[id] {
+			continue
+		}
+		if err := r.db.Where("comment_id = ? AND identity_id = ?", commentID, id).Delete(&Mention{}).Error; err != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to remove the mention of identity %s from comment %s", id, commentID))
+		}
+	}
+	return added, nil
+}
+
+// List returns the mentioned identities of the given comments
+func (r *GormMentionRepository) List(ctx context.Context, commentIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment_mention", "list"}, time.Now())
+	res := map[uuid.UUID][]uuid.UUID{}
+	if len(commentIDs) == 0 {
+		return res, nil
+	}
+	var mentions []Mention
+	if err := r.db.Where("comment_id IN (?)", commentIDs).Order("created_at").Find(&mentions).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to list the mentions of the comments"))
+	}
+	for _, m := range mentions {
+		res[m.CommentID] = append(res[m.CommentID], m.IdentityID)
+	}
+	return res, nil
+}
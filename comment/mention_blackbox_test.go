@@ -0,0 +1,79 @@
+package comment_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestParseMentions(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	for body, expected := range map[string][]string{
+		"no mentions":                                  nil,
+		"@jdoe please have a look":                     {"jdoe"},
+		"thanks @jdoe, @asmith and @JDoe.":             {"jdoe", "asmith"},
+		"(cc @first.last-name)":                        {"first.last-name"},
+		"mail jdoe@example.com or see example.com/@me": nil,
+		"run `@jdoe` or\n```\n@asmith\n```\n@bob":      {"bob"},
+	} {
+		t.Run(body, func(t *testing.T) {
+			assert.Equal(t, expected, comment.ParseMentions(body, rendering.SystemMarkupMarkdown))
+		})
+	}
+	t.Run("plain text", func(t *testing.T) {
+		assert.Nil(t, comment.ParseMentions("@jdoe please have a look", rendering.SystemMarkupPlainText))
+	})
+}
+
+type TestMentionRepository struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunMentionRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestMentionRepository{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *TestMentionRepository) TestSet() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Identities(3), tf.Comments(2))
+	repo := comment.NewMentionRepository(s.DB)
+	c := fxt.Comments[0].ID
+
+	s.T().Run("new mentions", func(t *testing.T) {
+		// when
+		added, err := repo.Set(s.Ctx, c, []uuid.UUID{fxt.Identities[0].ID, fxt.Identities[1].ID, fxt.Identities[0].ID})
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fxt.Identities[0].ID, fxt.Identities[1].ID}, added)
+	})
+	s.T().Run("changed mentions", func(t *testing.T) {
+		// when
+		added, err := repo.Set(s.Ctx, c, []uuid.UUID{fxt.Identities[1].ID, fxt.Identities[2].ID})
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fxt.Identities[2].ID}, added)
+		mentions, err := repo.List(s.Ctx, []uuid.UUID{c, fxt.Comments[1].ID})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{fxt.Identities[1].ID, fxt.Identities[2].ID}, mentions[c])
+		assert.Empty(t, mentions[fxt.Comments[1].ID])
+	})
+	s.T().Run("no mentions", func(t *testing.T) {
+		// when
+		added, err := repo.Set(s.Ctx, c, nil)
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, added)
+		mentions, err := repo.List(s.Ctx, []uuid.UUID{c})
+		require.NoError(t, err)
+		assert.Empty(t, mentions[c])
+	})
+}
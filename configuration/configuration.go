@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -53,8 +54,11 @@ const (
 	varAuthDomainPrefix             = "auth.domain.prefix"
 	varAuthShortServiceHostName     = "auth.servicehostname.short"
 	varAuthURL                      = "auth.url"
+	varServiceAccountID             = "service.account.id"
+	varServiceAccountSecret         = "service.account.secret"
 	varAuthorizationEnabled         = "authz.enabled"
 	varGithubAuthToken              = "github.auth.token"
+	varJiraAuthToken                = "jira.auth.token"
 	varOpenshiftProxyURL            = "osoproxy.url"
 	varKeycloakSecret               = "keycloak.secret"
 	varKeycloakClientID             = "keycloak.client.id"
@@ -107,10 +111,22 @@ const (
 	varLogJSON                  = "log.json"
 	varTenantServiceURL         = "tenant.serviceurl"
 	varNotificationServiceURL   = "notification.serviceurl"
+	varNotificationHTTPTimeout  = "notification.http.timeout"
 	varTogglesServiceURL        = "toggles.serviceurl"
 	varDeploymentsServiceURL    = "deployments.serviceurl"
 	varCodebaseServiceURL       = "codebase.serviceurl"
 	varDeploymentsHTTPTimeout   = "deployments.http.timeout"
+	varWebhookHTTPTimeout       = "webhook.http.timeout"
+	varWebhookPrivateAddresses  = "webhook.private.addresses.enabled"
+	varOutboxPollInterval       = "notification.outbox.poll.interval"
+	varOutboxBatchSize          = "notification.outbox.batch.size"
+	varOutboxMaxAttempts        = "notification.outbox.max.attempts"
+	varOutboxRetryBackoff       = "notification.outbox.retry.backoff"
+	varOutboxLease              = "notification.outbox.lease"
+	varEventStreamPollInterval  = "eventstream.poll.interval"
+	varEventStreamBatchSize     = "eventstream.batch.size"
+	varAttachmentsPath          = "attachments.filesystem.path"
+	varAttachmentsMaxSize       = "attachments.max.size"
 )
 
 // Registry encapsulates the Viper configuration registry which stores the
@@ -255,6 +271,17 @@ func (c *Registry) setConfigDefaults() {
 	c.v.SetDefault(varDeploymentsServiceURL, defaultDeploymentsServiceURL)
 	c.v.SetDefault(varCodebaseServiceURL, defaultCodebaseServiceURL)
 	c.v.SetDefault(varDeploymentsHTTPTimeout, defaultDeploymentsHTTPTimeout)
+	c.v.SetDefault(varNotificationHTTPTimeout, time.Duration(10*time.Second))
+	c.v.SetDefault(varWebhookHTTPTimeout, time.Duration(10*time.Second))
+	c.v.SetDefault(varWebhookPrivateAddresses, false)
+	c.v.SetDefault(varOutboxPollInterval, time.Duration(5*time.Second))
+	c.v.SetDefault(varOutboxBatchSize, 50)
+	c.v.SetDefault(varOutboxMaxAttempts, 10)
+	c.v.SetDefault(varOutboxRetryBackoff, time.Duration(10*time.Second))
+	c.v.SetDefault(varOutboxLease, time.Duration(10*time.Minute))
+	c.v.SetDefault(varEventStreamPollInterval, time.Duration(2*time.Second))
+	c.v.SetDefault(varEventStreamBatchSize, 100)
+	c.v.SetDefault(varAttachmentsMaxSize, 10*1024*1024)
 }
 
 // GetPostgresHost returns the postgres host as set via default, config file, or environment variable
@@ -589,6 +616,18 @@ func (c *Registry) GetAuthServiceURL() string {
 	return c.v.GetString(varAuthURL)
 }
 
+// GetServiceAccountID returns the client ID of the service account used to
+// call other services outside of a user request
+func (c *Registry) GetServiceAccountID() string {
+	return c.v.GetString(varServiceAccountID)
+}
+
+// GetServiceAccountSecret returns the client secret of the service account
+// used to call other services outside of a user request
+func (c *Registry) GetServiceAccountSecret() string {
+	return c.v.GetString(varServiceAccountSecret)
+}
+
 // GetOpenshiftProxyURL returns the Openshift Proxy URL, or "" if no URL
 func (c *Registry) GetOpenshiftProxyURL() string {
 	return c.v.GetString(varOpenshiftProxyURL)
@@ -626,6 +665,12 @@ func (c *Registry) GetGithubAuthToken() string {
 	return c.v.GetString(varGithubAuthToken)
 }
 
+// GetJiraAuthToken returns the Jira access token used to write local changes
+// back to Jira trackers. Importing from public Jira trackers needs no token.
+func (c *Registry) GetJiraAuthToken() string {
+	return c.v.GetString(varJiraAuthToken)
+}
+
 // GetKeycloakSecret returns the keycloak client secret (as set via config file or environment variable)
 // that is used to make authorized Keycloak API Calls.
 func (c *Registry) GetKeycloakSecret() string {
@@ -858,6 +903,86 @@ func (c *Registry) GetNotificationServiceURL() string {
 	return c.v.GetString(varNotificationServiceURL)
 }
 
+// GetNotificationServiceHTTPTimeout returns the timeout of a single request
+// to the Notification service.
+func (c *Registry) GetNotificationServiceHTTPTimeout() time.Duration {
+	return c.v.GetDuration(varNotificationHTTPTimeout)
+}
+
+// GetNotificationOutboxPollInterval returns how often the notification outbox
+// is checked for messages that are due for delivery
+func (c *Registry) GetNotificationOutboxPollInterval() time.Duration {
+	return c.v.GetDuration(varOutboxPollInterval)
+}
+
+// GetNotificationOutboxBatchSize returns the maximum number of messages
+// delivered from the notification outbox in one transaction
+func (c *Registry) GetNotificationOutboxBatchSize() int {
+	return c.v.GetInt(varOutboxBatchSize)
+}
+
+// GetNotificationOutboxMaxAttempts returns the number of delivery attempts
+// after which a message is moved to the dead letters of the outbox
+func (c *Registry) GetNotificationOutboxMaxAttempts() int {
+	return c.v.GetInt(varOutboxMaxAttempts)
+}
+
+// GetNotificationOutboxRetryBackoff returns the delay before the first retry
+// of a failed notification delivery. The delay doubles with every further retry.
+func (c *Registry) GetNotificationOutboxRetryBackoff() time.Duration {
+	return c.v.GetDuration(varOutboxRetryBackoff)
+}
+
+// GetNotificationOutboxLease returns how long the messages claimed by a
+// dispatcher are hidden from the other dispatchers. It must be longer than
+// the delivery of a whole batch takes.
+func (c *Registry) GetNotificationOutboxLease() time.Duration {
+	return c.v.GetDuration(varOutboxLease)
+}
+
+// GetEventStreamPollInterval returns how often the change stream of a space
+// is checked for new changes
+func (c *Registry) GetEventStreamPollInterval() time.Duration {
+	return c.v.GetDuration(varEventStreamPollInterval)
+}
+
+// GetEventStreamBatchSize returns the maximum number of changes loaded at
+// once for the change stream of a space
+func (c *Registry) GetEventStreamBatchSize() int {
+	return c.v.GetInt(varEventStreamBatchSize)
+}
+
+// GetAttachmentsFileSystemPath returns the directory in which the content of
+// the attachments is stored. There is no default outside of developer mode
+// since the content must survive restarts of the host, so an empty string is
+// returned if the path is not set.
+func (c *Registry) GetAttachmentsFileSystemPath() string {
+	if c.v.IsSet(varAttachmentsPath) {
+		return c.v.GetString(varAttachmentsPath)
+	}
+	if c.IsPostgresDeveloperModeEnabled() {
+		return filepath.Join(os.TempDir(), "fabric8-wit-attachments")
+	}
+	return ""
+}
+
+// GetAttachmentMaxSize returns the maximum size of an attachment in bytes
+func (c *Registry) GetAttachmentMaxSize() int64 {
+	return c.v.GetInt64(varAttachmentsMaxSize)
+}
+
+// GetWebhookHTTPTimeout returns the timeout of a single webhook delivery request.
+func (c *Registry) GetWebhookHTTPTimeout() time.Duration {
+	return c.v.GetDuration(varWebhookHTTPTimeout)
+}
+
+// IsWebhookPrivateAddressesEnabled returns true if webhooks may post to
+// private, loopback and link-local addresses. Only enable this for
+// development.
+func (c *Registry) IsWebhookPrivateAddressesEnabled() bool {
+	return c.v.GetBool(varWebhookPrivateAddresses)
+}
+
 // GetTogglesServiceURL returns the URL for the Feature Toggles service used enabling/disabling features per user
 func (c *Registry) GetTogglesServiceURL() string {
 	return c.v.GetString(varTogglesServiceURL)
@@ -203,3 +203,20 @@ func TestOSOProxyURL(t *testing.T) {
 	resource.Require(t, resource.UnitTest)
 	assert.Equal(t, "", config.GetOpenshiftProxyURL())
 }
+
+func TestGetAttachmentsFileSystemPath(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	defer resetConfiguration()
+
+	t.Run("explicit path", func(t *testing.T) {
+		config.v.Set(varAttachmentsPath, "/var/lib/attachments")
+		assert.Equal(t, "/var/lib/attachments", config.GetAttachmentsFileSystemPath())
+		resetConfiguration()
+	})
+
+	t.Run("no default outside of developer mode", func(t *testing.T) {
+		config.v.Set(varDeveloperModeEnabled, false)
+		assert.Equal(t, "", config.GetAttachmentsFileSystemPath())
+		resetConfiguration()
+	})
+}
@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/attachment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space/authz"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+)
+
+// AttachmentsController implements the attachments resource.
+type AttachmentsController struct {
+	*goa.Controller
+	db    application.DB
+	store attachment.BlobStore
+}
+
+// NewAttachmentsController creates an attachments controller.
+func NewAttachmentsController(service *goa.Service, db application.DB, store attachment.BlobStore) *AttachmentsController {
+	return &AttachmentsController{
+		Controller: service.NewController("AttachmentsController"),
+		db:         db,
+		store:      store,
+	}
+}
+
+// Show runs the show action.
+func (c *AttachmentsController) Show(ctx *app.ShowAttachmentsContext) error {
+	var a *attachment.Attachment
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		a, err = appl.Attachments().Load(ctx, ctx.AttachmentID)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.AttachmentSingle{
+		Data: ConvertAttachment(ctx.Request, *a),
+	})
+}
+
+// Download runs the download action.
+func (c *AttachmentsController) Download(ctx *app.DownloadAttachmentsContext) error {
+	var a *attachment.Attachment
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		a, err = appl.Attachments().Load(ctx, ctx.AttachmentID)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	content, err := c.store.Get(ctx, a.BlobKey)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	defer content.Close()
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": a.Name})
+	if disposition == "" {
+		// the name cannot be encoded in the header
+		disposition = "attachment"
+	}
+	ctx.ResponseData.Header().Set("Content-Type", a.ContentType)
+	ctx.ResponseData.Header().Set("Content-Length", strconv.FormatInt(a.Size, 10))
+	ctx.ResponseData.Header().Set("Content-Disposition", disposition)
+	// the content must never be rendered as a page of this service
+	ctx.ResponseData.Header().Set("X-Content-Type-Options", "nosniff")
+	ctx.ResponseData.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(ctx.ResponseData, content); err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"attachment_id": a.ID,
+			"err":           err,
+		}, "unable to send the content of the attachment")
+	}
+	return nil
+}
+
+// Delete runs the delete action.
+func (c *AttachmentsController) Delete(ctx *app.DeleteAttachmentsContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	var a *attachment.Attachment
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		a, err = appl.Attachments().Load(ctx, ctx.AttachmentID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		// the uploader or a space collaborator may delete an attachment
+		if a.UploaderID != *currentUser {
+			if err := authorizeAttachment(ctx, appl, *a); err != nil {
+				return err
+			}
+		}
+		return errs.WithStack(appl.Attachments().Delete(ctx, a.ID))
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if err := c.store.Delete(ctx, a.BlobKey); err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"attachment_id": a.ID,
+			"blob_key":      a.BlobKey,
+			"err":           err,
+		}, "unable to remove the content of the deleted attachment")
+	}
+	return ctx.NoContent()
+}
+
+// authorizeAttachment returns a ForbiddenError if the current user is not a
+// collaborator of the space of the work item of the given attachment
+func authorizeAttachment(ctx context.Context, appl application.Application, a attachment.Attachment) error {
+	wi, err := appl.WorkItems().LoadByID(ctx, a.WorkItemID)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	authorized, err := authz.Authorize(ctx, wi.SpaceID.String())
+	if err != nil {
+		return errors.NewUnauthorizedError(err.Error())
+	}
+	if !authorized {
+		return errors.NewForbiddenError("user is not a space collaborator")
+	}
+	return nil
+}
+
+// ConvertAttachment converts from internal to external REST representation.
+// The related link of the attachment points to its content.
+func ConvertAttachment(request *http.Request, a attachment.Attachment) *app.Attachment {
+	selfURL := rest.AbsoluteURL(request, app.AttachmentsHref(a.ID))
+	contentURL := selfURL + "/content"
+	workItemURL := rest.AbsoluteURL(request, app.WorkitemHref(a.WorkItemID))
+	uploaderURL := rest.AbsoluteURL(request, fmt.Sprintf("%s/%s", usersEndpoint, a.UploaderID))
+	res := &app.Attachment{
+		Type: attachment.APIStringTypeAttachment,
+		ID:   ptr.UUID(a.ID),
+		Attributes: &app.AttachmentAttributes{
+			Name:        ptr.String(a.Name),
+			ContentType: ptr.String(a.ContentType),
+			Size:        ptr.Int(int(a.Size)),
+			CreatedAt:   ptr.Time(a.CreatedAt),
+		},
+		Relationships: &app.AttachmentRelations{
+			Workitem: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: ptr.String(APIStringTypeWorkItem),
+					ID:   ptr.String(a.WorkItemID.String()),
+				},
+				Links: &app.GenericLinks{
+					Self:    &workItemURL,
+					Related: &workItemURL,
+				},
+			},
+			Uploader: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: ptr.String(APIStringTypeUser),
+					ID:   ptr.String(a.UploaderID.String()),
+					Links: &app.GenericLinks{
+						Related: &uploaderURL,
+					},
+				},
+			},
+		},
+		Links: &app.GenericLinks{
+			Self:    &selfURL,
+			Related: &contentURL,
+		},
+	}
+	if a.CommentID.Valid {
+		commentURL := rest.AbsoluteURL(request, app.CommentsHref(a.CommentID.UUID))
+		res.Relationships.Comment = &app.RelationGeneric{
+			Data: &app.GenericData{
+				Type: ptr.String(APIStringTypeComments),
+				ID:   ptr.String(a.CommentID.UUID.String()),
+			},
+			Links: &app.GenericLinks{
+				Self:    &commentURL,
+				Related: &commentURL,
+			},
+		}
+	}
+	return res
+}
+
+// ConvertAttachments from internal to external REST representation
+func ConvertAttachments(request *http.Request, attachments []attachment.Attachment) []*app.Attachment {
+	var res = []*app.Attachment{}
+	for _, a := range attachments {
+		res = append(res, ConvertAttachment(request, a))
+	}
+	return res
+}
@@ -0,0 +1,181 @@
+package controller_test
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	"github.com/fabric8-services/fabric8-wit/attachment"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/goatest"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type attachmentsSuite struct {
+	gormtestsupport.DBTestSuite
+	store    *attachment.FileSystemBlobStore
+	storeDir string
+}
+
+func TestAttachmentsREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &attachmentsSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *attachmentsSuite) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	dir, err := ioutil.TempDir("", "attachments")
+	require.NoError(s.T(), err)
+	s.storeDir = dir
+	s.store, err = attachment.NewFileSystemBlobStore(dir)
+	require.NoError(s.T(), err)
+}
+
+func (s *attachmentsSuite) TearDownTest() {
+	os.RemoveAll(s.storeDir)
+	s.DBTestSuite.TearDownTest()
+}
+
+// attachmentsConfig limits the size of the attachments
+type attachmentsConfig struct {
+	maxSize int64
+}
+
+func (c attachmentsConfig) GetAttachmentMaxSize() int64 {
+	return c.maxSize
+}
+
+// upload calls the upload action with the given content since the generated
+// test helpers don't support a request body
+func (s *attachmentsSuite) upload(t *testing.T, identity account.Identity, wiID uuid.UUID, commentID *uuid.UUID, name, contentType, content string) (*httptest.ResponseRecorder, interface{}) {
+	svc := testsupport.ServiceAsUser("WorkItemAttachments-Service", identity)
+	var resp interface{}
+	var respSetter goatest.ResponseSetterFunc = func(r interface{}) { resp = r }
+	newEncoder := func(io.Writer) goa.Encoder { return respSetter }
+	svc.Encoder = goa.NewHTTPEncoder()
+	svc.Encoder.Register(newEncoder, "*/*")
+	ctrl := NewWorkItemAttachmentsController(svc, s.GormDB, s.store, attachmentsConfig{maxSize: 16})
+	prms := url.Values{}
+	prms["wiID"] = []string{wiID.String()}
+	prms["name"] = []string{name}
+	if commentID != nil {
+		prms["comment"] = []string{commentID.String()}
+	}
+	req, err := http.NewRequest("POST", fmt.Sprintf("/api/workitems/%s/attachments", wiID), strings.NewReader(content))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	rw := httptest.NewRecorder()
+	goaCtx := goa.NewContext(goa.WithAction(svc.Context, "WorkItemAttachmentsTest"), rw, req, prms)
+	uploadCtx, err := app.NewUploadWorkItemAttachmentsContext(goaCtx, req, svc)
+	require.NoError(t, err)
+	require.NoError(t, ctrl.Upload(uploadCtx))
+	return rw, resp
+}
+
+func (s *attachmentsSuite) TestUploadAndDownload() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(2), tf.Comments(1))
+	wi := fxt.WorkItems[0]
+	svc := goa.New("Attachments-Service")
+	ctrl := NewAttachmentsController(svc, s.GormDB, s.store)
+	wiCtrl := NewWorkItemAttachmentsController(svc, s.GormDB, s.store, attachmentsConfig{maxSize: 16})
+
+	// when
+	rw, resp := s.upload(s.T(), *fxt.Identities[0], wi.ID, nil, "logs/build.log", "text/plain", "build failed")
+	// then
+	require.Equal(s.T(), http.StatusCreated, rw.Code)
+	created, ok := resp.(*app.AttachmentSingle)
+	require.True(s.T(), ok)
+	assert.Equal(s.T(), "build.log", *created.Data.Attributes.Name)
+	assert.Equal(s.T(), "text/plain", *created.Data.Attributes.ContentType)
+	assert.Equal(s.T(), 12, *created.Data.Attributes.Size)
+	assert.Equal(s.T(), fxt.Identities[0].ID.String(), *created.Data.Relationships.Uploader.Data.ID)
+	assert.Nil(s.T(), created.Data.Relationships.Comment)
+	attachmentID := *created.Data.ID
+
+	s.T().Run("attach to comment", func(t *testing.T) {
+		rw, resp := s.upload(t, *fxt.Identities[0], wi.ID, &fxt.Comments[0].ID, "screenshot.png", "image/png", "png")
+		require.Equal(t, http.StatusCreated, rw.Code)
+		created, ok := resp.(*app.AttachmentSingle)
+		require.True(t, ok)
+		require.NotNil(t, created.Data.Relationships.Comment)
+		assert.Equal(t, fxt.Comments[0].ID.String(), *created.Data.Relationships.Comment.Data.ID)
+	})
+
+	s.T().Run("comment of other work item", func(t *testing.T) {
+		rw, _ := s.upload(t, *fxt.Identities[0], fxt.WorkItems[1].ID, &fxt.Comments[0].ID, "screenshot.png", "image/png", "png")
+		assert.Equal(t, http.StatusBadRequest, rw.Code)
+	})
+
+	s.T().Run("too large", func(t *testing.T) {
+		rw, _ := s.upload(t, *fxt.Identities[0], wi.ID, nil, "large.bin", "", strings.Repeat("x", 17))
+		assert.Equal(t, http.StatusBadRequest, rw.Code)
+	})
+
+	s.T().Run("list", func(t *testing.T) {
+		_, list := test.ListWorkItemAttachmentsOK(t, svc.Context, svc, wiCtrl, wi.ID)
+		require.Len(t, list.Data, 2)
+		assert.Equal(t, attachmentID, *list.Data[0].ID)
+		assert.Equal(t, 2, list.Meta.TotalCount)
+	})
+
+	s.T().Run("show", func(t *testing.T) {
+		_, shown := test.ShowAttachmentsOK(t, svc.Context, svc, ctrl, attachmentID)
+		assert.Equal(t, "build.log", *shown.Data.Attributes.Name)
+		assert.True(t, strings.HasSuffix(*shown.Data.Links.Related, "/attachments/"+attachmentID.String()+"/content"))
+	})
+
+	s.T().Run("download", func(t *testing.T) {
+		rw := test.DownloadAttachmentsOK(t, svc.Context, svc, ctrl, attachmentID)
+		recorder, ok := rw.(*httptest.ResponseRecorder)
+		require.True(t, ok)
+		assert.Equal(t, "build failed", recorder.Body.String())
+		assert.Equal(t, "text/plain", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename=build.log`, recorder.Header().Get("Content-Disposition"))
+	})
+
+	s.T().Run("unknown attachment", func(t *testing.T) {
+		test.ShowAttachmentsNotFound(t, svc.Context, svc, ctrl, uuid.NewV4())
+		test.DownloadAttachmentsNotFound(t, svc.Context, svc, ctrl, uuid.NewV4())
+	})
+}
+
+func (s *attachmentsSuite) TestDelete() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+	_, resp := s.upload(s.T(), *fxt.Identities[0], fxt.WorkItems[0].ID, nil, "notes.txt", "text/plain", "notes")
+	created, ok := resp.(*app.AttachmentSingle)
+	require.True(s.T(), ok)
+	attachmentID := *created.Data.ID
+
+	s.T().Run("unauthorized", func(t *testing.T) {
+		svc := goa.New("Attachments-Service")
+		ctrl := NewAttachmentsController(svc, s.GormDB, s.store)
+		test.DeleteAttachmentsUnauthorized(t, svc.Context, svc, ctrl, attachmentID)
+	})
+
+	s.T().Run("ok", func(t *testing.T) {
+		svc := testsupport.ServiceAsUser("Attachments-Service", *fxt.Identities[0])
+		ctrl := NewAttachmentsController(svc, s.GormDB, s.store)
+		test.DeleteAttachmentsNoContent(t, svc.Context, svc, ctrl, attachmentID)
+		test.ShowAttachmentsNotFound(t, svc.Context, svc, ctrl, attachmentID)
+		test.DownloadAttachmentsNotFound(t, svc.Context, svc, ctrl, attachmentID)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
 	"github.com/fabric8-services/fabric8-wit/comment"
@@ -18,6 +19,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/space/authz"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -62,9 +64,14 @@ func NewNotifyingCommentsController(service *goa.Service, db application.DB, not
 // Show runs the show action.
 func (c *CommentsController) Show(ctx *app.ShowCommentsContext) error {
 	var cmt *comment.Comment
+	var mentions map[uuid.UUID][]uuid.UUID
 	err := application.Transactional(c.db, func(appl application.Application) error {
 		var err error
 		cmt, err = appl.Comments().Load(ctx, ctx.CommentID)
+		if err != nil {
+			return err
+		}
+		mentions, err = appl.CommentMentions().List(ctx, []uuid.UUID{cmt.ID})
 		return err
 	})
 	if err != nil {
@@ -77,7 +84,8 @@ func (c *CommentsController) Show(ctx *app.ShowCommentsContext) error {
 		res.Data = ConvertComment(
 			ctx.Request,
 			*cmt,
-			includeParentWorkItem)
+			includeParentWorkItem,
+			CommentIncludeMentions(mentions))
 		return ctx.OK(res)
 	})
 }
@@ -102,15 +110,19 @@ func (c *CommentsController) Update(ctx *app.UpdateCommentsContext) error {
 			return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not a space collaborator"))
 		}
 	}
-	err = c.performUpdate(ctx, cm, identityID)
+	msg := notification.NewCommentUpdated(wi.SpaceID, cm.ID.String())
+	mentioned, mentionMsgs, err := c.performUpdate(ctx, cm, wi.SpaceID, identityID, msg)
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
 	// This code should change if others type of parents than WI are allowed
 	res := &app.CommentSingle{
-		Data: ConvertComment(ctx.Request, *cm, CommentIncludeParentWorkItem(ctx, cm)),
+		Data: ConvertComment(ctx.Request, *cm, CommentIncludeParentWorkItem(ctx, cm), CommentIncludeMentions(map[uuid.UUID][]uuid.UUID{cm.ID: mentioned})),
+	}
+	c.notification.Send(ctx, msg)
+	for _, m := range mentionMsgs {
+		c.notification.Send(ctx, m)
 	}
-	c.notification.Send(ctx, notification.NewCommentUpdated(cm.ID.String()))
 	return ctx.OK(res)
 }
 
@@ -121,26 +133,66 @@ func (c *CommentsController) loadComment(ctx context.Context, commentID, identit
 		if err != nil {
 			return err
 		}
-		if identityID == cm.Creator {
-			userIsCreator = true
-			return err
-		}
+		userIsCreator = identityID == cm.Creator
 		wi, err = appl.WorkItems().LoadByID(ctx, cm.ParentID)
-		if err != nil {
-			return err
-		}
-		return nil
+		return err
 	})
 	return // using names returned value
 }
 
-func (c *CommentsController) performUpdate(ctx *app.UpdateCommentsContext, cm *comment.Comment, identityID *uuid.UUID) error {
-	return application.Transactional(c.db, func(appl application.Application) error {
+// performUpdate saves the given comment and its mentions. Returns the
+// mentioned identities and the messages for the newly mentioned ones.
+func (c *CommentsController) performUpdate(ctx *app.UpdateCommentsContext, cm *comment.Comment, spaceID uuid.UUID, identityID *uuid.UUID, msg notification.Message) (mentioned []uuid.UUID, mentionMsgs []notification.Message, err error) {
+	err = application.Transactional(c.db, func(appl application.Application) error {
 		cm.Body = *ctx.Payload.Data.Attributes.Body
 		cm.Markup = rendering.NilSafeGetMarkup(ctx.Payload.Data.Attributes.Markup)
 		err := appl.Comments().Save(ctx.Context, cm, *identityID)
+		if err != nil {
+			return err
+		}
+		if err := notification.Enqueue(ctx, appl.Outbox(), msg); err != nil {
+			return err
+		}
+		mentioned, mentionMsgs, err = storeMentions(ctx, appl, spaceID, *cm, *identityID)
 		return err
 	})
+	return // using names returned value
+}
+
+// storeMentions resolves the usernames mentioned in the body of the given
+// comment and stores the mentions. Unknown usernames are ignored. Returns the
+// mentioned identities and the messages for the identities that were not
+// mentioned before, which are already enqueued in the outbox and only need to
+// be sent once the transaction is committed. Authors mentioning themselves
+// aren't notified.
+func storeMentions(ctx context.Context, appl application.Application, spaceID uuid.UUID, cm comment.Comment, authorID uuid.UUID) ([]uuid.UUID, []notification.Message, error) {
+	mentioned := []uuid.UUID{}
+	for _, username := range comment.ParseMentions(cm.Body, cm.Markup) {
+		identities, err := appl.Identities().Query(account.IdentityFilterByUsername(username), account.IdentityFilterByProviderType(account.KeycloakIDP))
+		if err != nil {
+			return nil, nil, errs.Wrapf(err, "failed to look up the mentioned user %s", username)
+		}
+		if len(identities) == 0 {
+			continue
+		}
+		mentioned = append(mentioned, identities[0].ID)
+	}
+	added, err := appl.CommentMentions().Set(ctx, cm.ID, mentioned)
+	if err != nil {
+		return nil, nil, err
+	}
+	var msgs []notification.Message
+	for _, identityID := range added {
+		if identityID == authorID {
+			continue
+		}
+		msg := notification.NewCommentMentioned(spaceID, cm.ID.String(), identityID)
+		if err := notification.Enqueue(ctx, appl.Outbox(), msg); err != nil {
+			return nil, nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return mentioned, msgs, nil
 }
 
 // Delete does DELETE comment
@@ -259,6 +311,50 @@ func ConvertComment(request *http.Request, comment comment.Comment, additional .
 	return c
 }
 
+// CommentIncludeMentions adds the "mentions" relationship holding the
+// identities mentioned in the comment
+func CommentIncludeMentions(mentions map[uuid.UUID][]uuid.UUID) CommentConvertFunc {
+	return func(request *http.Request, comment *comment.Comment, data *app.Comment) {
+		data.Relationships.Mentions = &app.RelationGenericList{
+			Data: []*app.GenericData{},
+		}
+		for _, identityID := range mentions[comment.ID] {
+			data.Relationships.Mentions.Data = append(data.Relationships.Mentions.Data, &app.GenericData{
+				Type: ptr.String(APIStringTypeUser),
+				ID:   ptr.String(identityID.String()),
+				Links: &app.GenericLinks{
+					Related: ptr.String(rest.AbsoluteURL(request, fmt.Sprintf("%s/%s", usersEndpoint, identityID))),
+				},
+			})
+		}
+	}
+}
+
+// CommentIncludeReplies adds the "replies" relationship holding the direct
+// replies to the comment and their number
+func CommentIncludeReplies(replies []comment.Comment) CommentConvertFunc {
+	return func(request *http.Request, cm *comment.Comment, data *app.Comment) {
+		data.Relationships.Replies = &app.RelationGenericList{
+			Data: []*app.GenericData{},
+		}
+		for _, reply := range replies {
+			if !reply.ParentCommentID.Valid || reply.ParentCommentID.UUID != cm.ID {
+				continue
+			}
+			data.Relationships.Replies.Data = append(data.Relationships.Replies.Data, &app.GenericData{
+				Type: ptr.String(APIStringTypeComments),
+				ID:   ptr.String(reply.ID.String()),
+				Links: &app.GenericLinks{
+					Related: ptr.String(rest.AbsoluteURL(request, app.CommentsHref(reply.ID))),
+				},
+			})
+		}
+		data.Relationships.Replies.Meta = map[string]interface{}{
+			"totalCount": len(data.Relationships.Replies.Data),
+		}
+	}
+}
+
 // HrefFunc generic function to greate a relative Href to a resource
 type HrefFunc func(id interface{}) string
 
@@ -471,6 +471,27 @@ func (s *CommentsSuite) TestNotificationSendOnUpdate() {
 	assert.Equal(s.T(), c.Data.ID.String(), s.notification.Messages[0].TargetID)
 }
 
+func (s *CommentsSuite) TestMentionNotificationSendOnUpdate() {
+	// given a comment mentioning the first of two users
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1), tf.Identities(2, func(fxt *tf.TestFixture, idx int) error {
+		fxt.Identities[idx].Username = uuid.NewV4().String()
+		return nil
+	}))
+	wiID := fxt.WorkItems[0].ID
+	c := s.createWorkItemComment(s.testIdentity, wiID, "@"+fxt.Identities[0].Username, &markdownMarkup, nil)
+	// when both users are mentioned
+	updateCommentPayload := newUpdateCommentsPayload("@"+fxt.Identities[0].Username+" @"+fxt.Identities[1].Username, &markdownMarkup)
+	userSvc, _, _, _, commentsCtrl := s.securedControllers(s.testIdentity)
+	_, result := test.UpdateCommentsOK(s.T(), userSvc.Context, userSvc, commentsCtrl, *c.Data.ID, updateCommentPayload)
+	// then only the newly mentioned user is notified
+	require.Len(s.T(), result.Data.Relationships.Mentions.Data, 2)
+	require.Len(s.T(), s.notification.Messages, 2)
+	assert.Equal(s.T(), "comment.update", s.notification.Messages[0].MessageType)
+	assert.Equal(s.T(), "comment.mention", s.notification.Messages[1].MessageType)
+	assert.Equal(s.T(), c.Data.ID.String(), s.notification.Messages[1].TargetID)
+	assert.Equal(s.T(), fxt.Identities[1].ID, s.notification.Messages[1].Custom["mentioned_identity_id"])
+}
+
 func CreateSecuredSpace(t *testing.T, db application.DB, config SpaceConfiguration, owner account.Identity, userIDs string) app.Space {
 	svc := testsupport.ServiceAsSpaceUser("Collaborators-Service", owner, &TestSpaceAuthzService{owner: owner, userIDs: userIDs})
 	spaceCtrl := NewSpaceController(svc, db, config, &DummyResourceManager{})
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/fabric8-services/fabric8-common/id"
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeDependencies contains the JSON API type for dependencies
+const APIStringTypeDependencies = "dependencies"
+
+// DependenciesController implements the dependencies resource.
+type DependenciesController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewDependenciesController creates a dependencies controller.
+func NewDependenciesController(service *goa.Service, db application.DB) *DependenciesController {
+	return &DependenciesController{
+		Controller: service.NewController("DependenciesController"),
+		db:         db,
+	}
+}
+
+// ShowWorkItem runs the showWorkItem action.
+func (c *DependenciesController) ShowWorkItem(ctx *app.ShowWorkItemDependenciesContext) error {
+	var graph *link.DependencyGraph
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.WorkItems().CheckExists(ctx, ctx.WiID); err != nil {
+			return err
+		}
+		var err error
+		graph, err = appl.WorkItemLinks().GetDependencyGraph(ctx, ctx.WiID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to get the dependencies of work item %s", ctx.WiID)
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.DependenciesSingle{
+		Data: ConvertDependencies(ctx.Request, ctx.WiID, *graph),
+	})
+}
+
+// ShowIteration runs the showIteration action.
+func (c *DependenciesController) ShowIteration(ctx *app.ShowIterationDependenciesContext) error {
+	var graph *link.DependencyGraph
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.Iterations().CheckExists(ctx, ctx.IterationID); err != nil {
+			return err
+		}
+		wis, err := appl.WorkItems().LoadByIteration(ctx, ctx.IterationID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to load the work items of iteration %s", ctx.IterationID)
+		}
+		wiIDs := make([]uuid.UUID, len(wis))
+		for i, wi := range wis {
+			wiIDs[i] = wi.ID
+		}
+		graph, err = appl.WorkItemLinks().GetDependencyGraph(ctx, wiIDs...)
+		if err != nil {
+			return errs.Wrapf(err, "failed to get the dependencies of iteration %s", ctx.IterationID)
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.DependenciesSingle{
+		Data: ConvertDependencies(ctx.Request, ctx.IterationID, *graph),
+	})
+}
+
+// ConvertDependencies converts the given dependency graph of the work item or
+// iteration with the given ID to its REST representation.
+func ConvertDependencies(request *http.Request, ID uuid.UUID, graph link.DependencyGraph) *app.Dependencies {
+	openBlockers := graph.OpenBlockers()
+	return &app.Dependencies{
+		Type: APIStringTypeDependencies,
+		ID:   ID,
+		Attributes: &app.DependenciesAttributes{
+			Blocked: len(openBlockers) > 0,
+		},
+		Relationships: &app.DependenciesRelations{
+			Blockers:     convertWorkItemRelationList(request, graph.Blockers()),
+			OpenBlockers: convertWorkItemRelationList(request, openBlockers),
+			Blocking:     convertWorkItemRelationList(request, graph.Blocking()),
+			CriticalPath: convertWorkItemRelationList(request, graph.CriticalPath()),
+		},
+	}
+}
+
+// convertWorkItemRelationList returns a relationship to the given work items.
+func convertWorkItemRelationList(request *http.Request, wiIDs id.Slice) *app.RelationGenericList {
+	res := &app.RelationGenericList{Data: make([]*app.GenericData, len(wiIDs))}
+	for i, wiID := range wiIDs {
+		res.Data[i] = &app.GenericData{
+			ID:   ptr.String(wiID.String()),
+			Type: ptr.String(APIStringTypeWorkItem),
+			Links: &app.GenericLinks{
+				Self: ptr.String(rest.AbsoluteURL(request, app.WorkitemHref(wiID))),
+			},
+		}
+	}
+	return res
+}
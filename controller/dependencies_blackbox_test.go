@@ -0,0 +1,94 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestDependenciesSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestDependencies(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestDependenciesSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func relationIDs(rel *app.RelationGenericList) []string {
+	res := []string{}
+	for _, d := range rel.Data {
+		res = append(res, *d.ID)
+	}
+	return res
+}
+
+func (s *TestDependenciesSuite) TestShow() {
+	// A -> B -> C with A and B in the first and C in the second iteration
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.Iterations(2),
+		tf.WorkItems(3,
+			tf.SetWorkItemTitles("A", "B", "C"),
+			func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[idx/2].ID.String()
+				return nil
+			},
+		),
+		tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyDependency)),
+		tf.WorkItemLinksCustom(2, tf.BuildLinks(tf.LinkChain("A", "B", "C")...)),
+	)
+	svc := goa.New("Dependencies-Service")
+	ctrl := NewDependenciesController(svc, s.GormDB)
+	A := fxt.WorkItemByTitle("A").ID.String()
+	B := fxt.WorkItemByTitle("B").ID.String()
+	C := fxt.WorkItemByTitle("C").ID.String()
+
+	s.T().Run("work item", func(t *testing.T) {
+		// when
+		_, res := test.ShowWorkItemDependenciesOK(t, svc.Context, svc, ctrl, fxt.WorkItemByTitle("B").ID)
+		// then
+		require.Equal(t, fxt.WorkItemByTitle("B").ID, res.Data.ID)
+		require.True(t, res.Data.Attributes.Blocked)
+		require.Equal(t, []string{A}, relationIDs(res.Data.Relationships.Blockers))
+		require.Equal(t, []string{A}, relationIDs(res.Data.Relationships.OpenBlockers))
+		require.Equal(t, []string{C}, relationIDs(res.Data.Relationships.Blocking))
+		require.Equal(t, []string{A, B}, relationIDs(res.Data.Relationships.CriticalPath))
+	})
+
+	s.T().Run("work item not blocked", func(t *testing.T) {
+		// when
+		_, res := test.ShowWorkItemDependenciesOK(t, svc.Context, svc, ctrl, fxt.WorkItemByTitle("A").ID)
+		// then
+		require.False(t, res.Data.Attributes.Blocked)
+		require.Empty(t, res.Data.Relationships.Blockers.Data)
+		require.ElementsMatch(t, []string{B, C}, relationIDs(res.Data.Relationships.Blocking))
+		require.Equal(t, []string{A}, relationIDs(res.Data.Relationships.CriticalPath))
+	})
+
+	s.T().Run("iteration", func(t *testing.T) {
+		// when
+		_, res := test.ShowIterationDependenciesOK(t, svc.Context, svc, ctrl, fxt.Iterations[1].ID)
+		// then
+		require.Equal(t, fxt.Iterations[1].ID, res.Data.ID)
+		require.True(t, res.Data.Attributes.Blocked)
+		require.ElementsMatch(t, []string{A, B}, relationIDs(res.Data.Relationships.Blockers))
+		require.Empty(t, res.Data.Relationships.Blocking.Data)
+		require.Equal(t, []string{A, B, C}, relationIDs(res.Data.Relationships.CriticalPath))
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		test.ShowWorkItemDependenciesNotFound(t, svc.Context, svc, ctrl, uuid.NewV4())
+		test.ShowIterationDependenciesNotFound(t, svc.Context, svc, ctrl, uuid.NewV4())
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/fabric8-services/fabric8-wit/actions"
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
 	"github.com/fabric8-services/fabric8-wit/errors"
@@ -27,6 +28,10 @@ const (
 	KeyClosedWorkItems = "closed"
 )
 
+// APIStringTypeIterationBurndown is the "type" of the burndown of an iteration
+// in the JSON API
+const APIStringTypeIterationBurndown = "iterationburndowns"
+
 // IterationController implements the iteration resource.
 type IterationController struct {
 	*goa.Controller
@@ -194,6 +199,34 @@ func (c *IterationController) Show(ctx *app.ShowIterationContext) error {
 	})
 }
 
+// Burndown runs the burndown action.
+func (c *IterationController) Burndown(ctx *app.BurndownIterationContext) error {
+	id, err := uuid.FromString(ctx.IterationID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrNotFound(err.Error()))
+	}
+	field := ""
+	if ctx.Field != nil {
+		field = *ctx.Field
+	}
+	var itr *iteration.Iteration
+	var points []workitem.BurndownPoint
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		itr, err = appl.Iterations().Load(ctx, id)
+		if err != nil {
+			return err
+		}
+		points, err = appl.WorkItems().GetBurndownForIteration(ctx, itr, field)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.IterationBurndownSingle{
+		Data: ConvertIterationBurndown(ctx.Request, *itr, ctx.Field, points),
+	})
+}
+
 // Update runs the update action.
 func (c *IterationController) Update(ctx *app.UpdateIterationContext) error {
 	currentUser, err := login.ContextIdentity(ctx)
@@ -237,6 +270,8 @@ func (c *IterationController) Update(ctx *app.UpdateIterationContext) error {
 		// But written following line to make it verbose 401 vs 403
 		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not allowed to create an iteration in this space"))
 	}
+	// keep the iteration as it was before the update for the actions system.
+	oldItr := *itr
 	var iterations []iteration.Iteration
 	var wiCounts map[string]workitem.WICountsPerIteration
 	err = application.Transactional(c.db, func(appl application.Application) error {
@@ -330,6 +365,13 @@ func (c *IterationController) Update(ctx *app.UpdateIterationContext) error {
 				}
 			}
 		}
+		// run the action rules of the space template that are triggered by
+		// the iteration change, e.g. moving all unfinished work items to the
+		// next iteration when the iteration is closed.
+		_, err = actions.ExecuteIterationActionRules(ctx, appl, *currentUser, oldItr, *itr)
+		if err != nil {
+			return err
+		}
 		wiCounts, err = appl.WorkItems().GetCountsForIteration(ctx, itr)
 		if err != nil {
 			return err
@@ -519,6 +561,50 @@ func ConvertIteration(request *http.Request, itr iteration.Iteration, additional
 	return i
 }
 
+// ConvertIterationBurndown converts the burndown of an iteration from internal
+// to external REST representation
+func ConvertIterationBurndown(request *http.Request, itr iteration.Iteration, field *string, points []workitem.BurndownPoint) *app.IterationBurndown {
+	iterationType := iteration.APIStringTypeIteration
+	iterationID := itr.ID.String()
+	selfURL := rest.AbsoluteURL(request, app.IterationHref(itr.ID)+"/burndown")
+	iterationURL := rest.AbsoluteURL(request, app.IterationHref(itr.ID))
+	res := &app.IterationBurndown{
+		Type: APIStringTypeIterationBurndown,
+		ID:   &itr.ID,
+		Attributes: &app.IterationBurndownAttributes{
+			Field:  field,
+			Points: make([]*app.IterationBurndownPoint, len(points)),
+		},
+		Relationships: &app.IterationBurndownRelations{
+			Iteration: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &iterationType,
+					ID:   &iterationID,
+				},
+				Links: &app.GenericLinks{
+					Self:    &iterationURL,
+					Related: &iterationURL,
+				},
+			},
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+	for i, p := range points {
+		res.Attributes.Points[i] = &app.IterationBurndownPoint{
+			Time:      p.Time,
+			Open:      p.Open,
+			Closed:    p.Closed,
+			Remaining: p.Remaining,
+			Completed: p.Completed,
+			Added:     p.Added,
+			Removed:   p.Removed,
+		}
+	}
+	return res
+}
+
 // ConvertIterationSimple converts a simple Iteration ID into a Generic
 // Relationship data+links element
 func ConvertIterationSimple(request *http.Request, id interface{}) (*app.GenericData, *app.GenericLinks) {
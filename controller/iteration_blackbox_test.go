@@ -309,6 +309,61 @@ func (rest *TestIterationREST) TestShow() {
 	})
 }
 
+func (rest *TestIterationREST) TestBurndown() {
+	// given an iteration that started two days ago with one open and one
+	// closed work item
+	fxt := tf.NewTestFixture(rest.T(), rest.DB,
+		tf.CreateWorkItemEnvironment(),
+		tf.Iterations(2,
+			tf.SetIterationNames("current", "undated"),
+			func(fxt *tf.TestFixture, idx int) error {
+				if idx == 0 {
+					fxt.Iterations[idx].StartAt = ptr.Time(time.Now().Add(-48 * time.Hour))
+					fxt.Iterations[idx].EndAt = ptr.Time(time.Now().Add(24 * time.Hour))
+				}
+				return nil
+			}),
+		tf.WorkItems(2, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.IterationByName("current").ID.String()
+			fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateNew
+			if idx == 1 {
+				fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateClosed
+			}
+			return nil
+		}),
+	)
+	svc, ctrl := rest.UnSecuredController()
+	rest.T().Run("ok", func(t *testing.T) {
+		// when
+		_, res := test.BurndownIterationOK(t, svc.Context, svc, ctrl, fxt.IterationByName("current").ID.String(), nil)
+		// then
+		require.NotNil(t, res.Data)
+		assert.Equal(t, APIStringTypeIterationBurndown, res.Data.Type)
+		assert.Equal(t, fxt.IterationByName("current").ID, *res.Data.ID)
+		require.NotNil(t, res.Data.Relationships.Iteration.Data.ID)
+		assert.Equal(t, fxt.IterationByName("current").ID.String(), *res.Data.Relationships.Iteration.Data.ID)
+		assert.Nil(t, res.Data.Attributes.Field)
+		points := res.Data.Attributes.Points
+		require.True(t, len(points) >= 3)
+		// the work items were created after the start of the iteration
+		assert.Equal(t, 0, points[0].Open+points[0].Closed)
+		last := points[len(points)-1]
+		assert.Equal(t, 1, last.Open)
+		assert.Equal(t, 1, last.Closed)
+		added := 0
+		for _, p := range points {
+			added += p.Added
+		}
+		assert.Equal(t, 2, added)
+	})
+	rest.T().Run("iteration without dates", func(t *testing.T) {
+		test.BurndownIterationBadRequest(t, svc.Context, svc, ctrl, fxt.IterationByName("undated").ID.String(), nil)
+	})
+	rest.T().Run("unknown iteration", func(t *testing.T) {
+		test.BurndownIterationNotFound(t, svc.Context, svc, ctrl, uuid.NewV4().String(), nil)
+	})
+}
+
 func (rest *TestIterationREST) TestShowIterationOKUsingExpiredIfModifiedSinceHeader() {
 	// given
 	fxt := tf.NewTestFixture(rest.T(), rest.DB, createSpaceAndRootAreaAndIterations()...)
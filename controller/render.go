@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"strings"
+
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
@@ -29,7 +31,7 @@ func (c *RenderController) Render(ctx *app.RenderRenderContext) error {
 	content := ctx.Payload.Data.Attributes.Content
 	markup := ctx.Payload.Data.Attributes.Markup
 	if !rendering.IsMarkupSupported(markup) {
-		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("Unsupported markup type", markup))
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("Unsupported markup type", markup).Expected(strings.Join(rendering.SupportedMarkups(), ", ")))
 	}
 	htmlResult := rendering.RenderMarkupToHTML(content, markup)
 	res := &app.MarkupRenderingSingle{Data: &app.MarkupRenderingData{
@@ -86,3 +86,37 @@ func (s *MarkupRenderingSuite) TestRenderUnsupportedMarkup() {
 	// when/then
 	test.RenderRenderBadRequest(s.T(), s.svc.Context, s.svc, s.controller, &payload)
 }
+
+func (s *MarkupRenderingSuite) TestRenderJiraWiki() {
+	// given
+	payload := app.MarkupRenderingPayload{Data: &app.MarkupRenderingPayloadData{
+		Type: RenderingType,
+		Attributes: &app.MarkupRenderingPayloadDataAttributes{
+			Content: "h1. foo",
+			Markup:  rendering.SystemMarkupJiraWiki,
+		}}}
+
+	// when
+	_, result := test.RenderRenderOK(s.T(), s.svc.Context, s.svc, s.controller, &payload)
+	// then
+	require.NotNil(s.T(), result)
+	require.NotNil(s.T(), result.Data)
+	assert.Equal(s.T(), "<h1>foo</h1>\n", result.Data.Attributes.RenderedContent)
+}
+
+func (s *MarkupRenderingSuite) TestRenderAsciiDoc() {
+	// given
+	payload := app.MarkupRenderingPayload{Data: &app.MarkupRenderingPayloadData{
+		Type: RenderingType,
+		Attributes: &app.MarkupRenderingPayloadDataAttributes{
+			Content: "*foo*",
+			Markup:  rendering.SystemMarkupAsciiDoc,
+		}}}
+
+	// when
+	_, result := test.RenderRenderOK(s.T(), s.svc.Context, s.svc, s.controller, &payload)
+	// then
+	require.NotNil(s.T(), result)
+	require.NotNil(s.T(), result.Data)
+	assert.Equal(s.T(), "<p><strong>foo</strong></p>\n", result.Data.Attributes.RenderedContent)
+}
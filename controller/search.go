@@ -19,6 +19,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
 	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/rest/proxy"
 	"github.com/fabric8-services/fabric8-wit/search"
@@ -251,9 +252,10 @@ func (c *SearchController) WorkitemsCSV(ctx *app.WorkitemsCSVSearchContext) erro
 // getWorkItemsByFilterExpression retrieves Work Items for a given expression and parameters
 func getWorkItemsByFilterExpression(ctx context.Context, db application.DB, filterExpression string, filterParentexists *bool, offset *int, limit *int) ([]workitem.WorkItem, error) {
 	var result []workitem.WorkItem
+	ctx = withTextQueryEnv(ctx, filterExpression)
 	err := application.Transactional(db, func(appl application.Application) error {
 		var err error
-		result, _, _, _, err = appl.SearchItems().Filter(ctx, filterExpression, filterParentexists, offset, limit)
+		result, _, _, _, err = appl.SearchItems().Filter(ctx, filterExpression, filterParentexists, offset, limit, workitem.SortWorkItemsByDefault)
 		if err != nil {
 			log.Error(ctx, map[string]interface{}{
 				"err":               err,
@@ -266,6 +268,23 @@ func getWorkItemsByFilterExpression(ctx context.Context, db application.DB, filt
 	return result, err
 }
 
+// withTextQueryEnv returns a context that carries the current time and the
+// current user (if any) for the given filter expression if it is a text query
+// (see search.ParseTextQuery).
+func withTextQueryEnv(ctx context.Context, filterExpression string) context.Context {
+	if !search.IsTextQuery(filterExpression) {
+		return ctx
+	}
+	return search.ContextWithTextQueryEnv(ctx, search.TextQueryEnv{
+		Now: time.Now(),
+		// only looked up for queries that refer to "me", so that guest
+		// access is not logged for every other query
+		CurrentIdentity: func() (*uuid.UUID, error) {
+			return login.ContextIdentity(ctx)
+		},
+	})
+}
+
 // Show runs the show action.
 func (c *SearchController) Show(ctx *app.ShowSearchContext) error {
 	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
@@ -280,13 +299,29 @@ func (c *SearchController) Show(ctx *app.ShowSearchContext) error {
 	search.RegisterAsKnownURL(search.HostRegistrationKeyForBoardWI, urlRegexString)
 
 	if ctx.FilterExpression != nil {
+		var sortSpaceIDs []uuid.UUID
+		if ctx.Sort != nil {
+			filterSpaceIDs, err := search.FilterSpaceIDs(withTextQueryEnv(ctx.Context, *ctx.FilterExpression), *ctx.FilterExpression)
+			if err != nil {
+				return jsonapi.JSONErrorResponse(ctx, err)
+			}
+			for _, s := range filterSpaceIDs {
+				if spaceID, err := uuid.FromString(s); err == nil {
+					sortSpaceIDs = append(sortSpaceIDs, spaceID)
+				}
+			}
+		}
+		sortBy, err := parseSortWorkItemsBy(ctx, c.db, ctx.Sort, sortSpaceIDs...)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
 		var result []workitem.WorkItem
 		var count int
 		var ancestors link.AncestorList
 		var childLinks link.WorkItemLinkList
-		err := application.Transactional(c.db, func(appl application.Application) error {
+		err = application.Transactional(c.db, func(appl application.Application) error {
 			var err error
-			result, count, ancestors, childLinks, err = appl.SearchItems().Filter(ctx.Context, *ctx.FilterExpression, ctx.FilterParentexists, &offset, &limit)
+			result, count, ancestors, childLinks, err = appl.SearchItems().Filter(withTextQueryEnv(ctx.Context, *ctx.FilterExpression), *ctx.FilterExpression, ctx.FilterParentexists, &offset, &limit, sortBy)
 			if err != nil {
 				cause := errs.Cause(err)
 				switch cause.(type) {
@@ -333,12 +368,19 @@ func (c *SearchController) Show(ctx *app.ShowSearchContext) error {
 		if err != nil {
 			return errs.Wrap(err, "failed to enrich work item list")
 		}
-		setPagingLinks(response.Links, buildAbsoluteURL(ctx.Request), len(result), offset, limit, count, "filter[expression]="+*ctx.FilterExpression)
-
-		// Sort "data" by name or ID if no title given
-		var data WorkItemPtrSlice = response.Data
-		sort.Sort(data)
-		response.Data = data
+		additionalQuery := []string{"filter[expression]=" + url.QueryEscape(*ctx.FilterExpression)}
+		if ctx.Sort != nil {
+			additionalQuery = append(additionalQuery, "sort="+url.QueryEscape(*ctx.Sort))
+		}
+		setPagingLinks(response.Links, buildAbsoluteURL(ctx.Request), len(result), offset, limit, count, additionalQuery...)
+
+		// Sort "data" by name or ID if no title given and keep the order of
+		// the search otherwise
+		if ctx.Sort == nil {
+			var data WorkItemPtrSlice = response.Data
+			sort.Sort(data)
+			response.Data = data
+		}
 
 		// Sort work items in the "included" array by ID or title
 		var included WorkItemInterfaceSlice = response.Included
@@ -268,7 +268,7 @@ func (s *searchControllerTestSuite) TestSearchWorkItems() {
 	}))
 	// when
 	spaceIDStr := fxt.WorkItems[0].SpaceID.String()
-	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, &spaceIDStr)
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, nil, &spaceIDStr)
 	// then
 	require.NotEmpty(s.T(), sr.Data)
 	r := sr.Data[0]
@@ -289,7 +289,7 @@ func (s *searchControllerTestSuite) TestSearchPagination() {
 	svc := goa.New("TestSearchPagination")
 	svc.Context = goa.NewContext(context.Background(), nil, &http.Request{URL: &url.URL{Scheme: "https", Host: "foo.bar.com"}}, nil)
 	spaceIDStr := fxt.WorkItems[0].SpaceID.String()
-	_, sr := test.ShowSearchOK(s.T(), svc.Context, svc, s.controller, nil, nil, nil, nil, &q, &spaceIDStr)
+	_, sr := test.ShowSearchOK(s.T(), svc.Context, svc, s.controller, nil, nil, nil, nil, &q, nil, &spaceIDStr)
 	// then
 	// defaults in paging.go is 'pageSizeDefault = 20'
 	assert.Equal(s.T(), "http:///api/search?page[offset]=0&page[limit]=20&q=specialwordforsearch2", *sr.Links.First)
@@ -299,6 +299,37 @@ func (s *searchControllerTestSuite) TestSearchPagination() {
 	assert.Equal(s.T(), q, r.Attributes[workitem.SystemTitle])
 }
 
+func (s *searchControllerTestSuite) TestSearchPaginationWithTextQuery() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(3, tf.SetWorkItemTitles("paging & linking", "paging & linking", "paging & linking")))
+	filter := fmt.Sprintf(`space:%s AND title:"paging & linking"`, fxt.WorkItems[0].SpaceID)
+	// when
+	_, first := test.ShowSearchOK(s.T(), nil, nil, s.controller, &filter, nil, ptr.Int(2), nil, nil, nil, nil)
+	require.Len(s.T(), first.Data, 2)
+	require.NotNil(s.T(), first.Links.Next)
+	next, err := url.Parse(*first.Links.Next)
+	require.NoError(s.T(), err)
+	nextFilter := next.Query().Get("filter[expression]")
+	nextOffset := next.Query().Get("page[offset]")
+	// then
+	assert.Equal(s.T(), filter, nextFilter)
+	_, second := test.ShowSearchOK(s.T(), nil, nil, s.controller, &nextFilter, nil, ptr.Int(2), &nextOffset, nil, nil, nil)
+	require.Len(s.T(), second.Data, 1)
+	for _, wi := range first.Data {
+		assert.NotEqual(s.T(), *wi.ID, *second.Data[0].ID)
+	}
+}
+
+func (s *searchControllerTestSuite) TestSearchSortByUnknownField() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(1))
+	filter := fmt.Sprintf(`{"space": "%s"}`, fxt.WorkItems[0].SpaceID)
+	// when
+	_, jerrs := test.ShowSearchBadRequest(s.T(), nil, nil, s.controller, &filter, nil, nil, nil, nil, ptr.String("-priority"), nil)
+	// then
+	require.NotEmpty(s.T(), jerrs.Errors)
+}
+
 func (s *searchControllerTestSuite) TestSearchWithEmptyValue() {
 	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
 		wi := fxt.WorkItems[idx]
@@ -309,7 +340,7 @@ func (s *searchControllerTestSuite) TestSearchWithEmptyValue() {
 	// when
 	q := ""
 	spaceIDStr := fxt.WorkItems[0].SpaceID.String()
-	_, jerrs := test.ShowSearchBadRequest(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, &spaceIDStr)
+	_, jerrs := test.ShowSearchBadRequest(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, nil, &spaceIDStr)
 	// then
 	require.NotNil(s.T(), jerrs)
 	require.Len(s.T(), jerrs.Errors, 1)
@@ -329,7 +360,7 @@ func (s *searchControllerTestSuite) TestSearchWithDomainPortCombination() {
 	// when
 	q := `"http://localhost:8080/detail/154687364529310"`
 	spaceIDStr := fxt.WorkItems[0].SpaceID.String()
-	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, &spaceIDStr)
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, nil, &spaceIDStr)
 	// then
 	require.NotEmpty(s.T(), sr.Data)
 	r := sr.Data[0]
@@ -349,7 +380,7 @@ func (s *searchControllerTestSuite) TestSearchURLWithoutPort() {
 	// when
 	q := `"http://localhost/detail/876394"`
 	spaceIDStr := fxt.WorkItems[0].SpaceID.String()
-	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, &spaceIDStr)
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, nil, &spaceIDStr)
 	// then
 	require.NotEmpty(s.T(), sr.Data)
 	r := sr.Data[0]
@@ -369,7 +400,7 @@ func (s *searchControllerTestSuite) TestUnregisteredURLWithPort() {
 	// when
 	q := `http://some-other-domain:8080/different-path/`
 	spaceIDStr := fxt.WorkItems[0].SpaceID.String()
-	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, &spaceIDStr)
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, nil, &spaceIDStr)
 	// then
 	require.NotEmpty(s.T(), sr.Data)
 	r := sr.Data[0]
@@ -389,7 +420,7 @@ func (s *searchControllerTestSuite) TestUnwantedCharactersRelatedToSearchLogic()
 	// add url: in the query, that is not expected by the code hence need to make sure it gives expected result.
 	q := `http://url:some-random-other-domain:8080/different-path/`
 	spaceIDStr := fxt.WorkItems[0].SpaceID.String()
-	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, &spaceIDStr)
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, nil, &spaceIDStr)
 	// then
 	require.NotNil(s.T(), sr.Data)
 	assert.Empty(s.T(), sr.Data)
@@ -480,7 +511,7 @@ func (s *searchControllerTestSuite) TestSearchWorkItemsSpaceContext() {
 	// when
 	q := "common_word"
 	space1IDStr := fxt.Spaces[0].ID.String()
-	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, &space1IDStr)
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, nil, &space1IDStr)
 	// then
 	require.NotEmpty(s.T(), sr.Data)
 	assert.Len(s.T(), sr.Data, 3)
@@ -489,7 +520,7 @@ func (s *searchControllerTestSuite) TestSearchWorkItemsSpaceContext() {
 		assert.Contains(s.T(), item.Attributes[workitem.SystemTitle], "shutter_island common_word")
 	}
 	space2IDStr := fxt.Spaces[1].ID.String()
-	_, sr = test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, &space2IDStr)
+	_, sr = test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, nil, &space2IDStr)
 	// then
 	require.NotEmpty(s.T(), sr.Data)
 	assert.Len(s.T(), sr.Data, 5)
@@ -499,7 +530,7 @@ func (s *searchControllerTestSuite) TestSearchWorkItemsSpaceContext() {
 	}
 
 	// when searched without spaceID then it should get all related WI
-	_, sr = test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, nil)
+	_, sr = test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, nil, nil)
 	// then
 	require.NotEmpty(s.T(), sr.Data)
 	assert.Len(s.T(), sr.Data, 8)
@@ -517,7 +548,7 @@ func (s *searchControllerTestSuite) TestFullTextSearch() {
 		// when
 		q := "with 'single"
 		spaceIDStr := fxt.Spaces[0].ID.String()
-		_, sr := test.ShowSearchOK(t, nil, nil, s.controller, nil, nil, nil, nil, &q, &spaceIDStr)
+		_, sr := test.ShowSearchOK(t, nil, nil, s.controller, nil, nil, nil, nil, &q, nil, &spaceIDStr)
 		// then
 		require.NotNil(t, sr)
 		require.Len(t, sr.Data, 1)
@@ -547,7 +578,7 @@ func (s *searchControllerTestSuite) TestSearchWorkItemsWithoutSpaceContext() {
 
 	q := searchByMe
 	// when search without space context
-	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, nil)
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, nil, &q, nil, nil)
 	// then
 	require.NotEmpty(s.T(), sr.Data)
 	toBeFound := id.Map{}
@@ -569,7 +600,7 @@ func (s *searchControllerTestSuite) TestSearchFilter() {
 		// when
 		filter := fmt.Sprintf(`{"space": "%s"}`, fxt.WorkItems[0].SpaceID)
 		spaceIDStr := fxt.WorkItems[0].SpaceID.String()
-		_, sr := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, sr := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		// then
 		require.NotEmpty(t, sr.Data)
 		r := sr.Data[0]
@@ -583,7 +614,7 @@ func (s *searchControllerTestSuite) TestSearchFilter() {
 		// when
 		filter := `{"number": "foo"}`
 		spaceIDStr := fxt.WorkItems[0].SpaceID.String()
-		_, jerr := test.ShowSearchBadRequest(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, jerr := test.ShowSearchBadRequest(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		// then
 		require.NotEmpty(t, jerr)
 		require.Len(t, jerr.Errors, 1)
@@ -651,7 +682,7 @@ func (s *searchControllerTestSuite) TestSearchByWorkItemTypeGroup() {
 				{"space": "%s"}
 			]}`, fxt.Spaces[0].ID)
 			// when
-			_, sr := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil)
+			_, sr := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, nil)
 			// then
 			toBeFound := map[string]struct{}{
 				"open scenario":      {},
@@ -669,7 +700,7 @@ func (s *searchControllerTestSuite) TestSearchByWorkItemTypeGroup() {
 				{"space": "%s"}
 			]}`, fxt.Spaces[0].ID)
 			// when
-			_, sr := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil)
+			_, sr := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, nil)
 			// then
 			toBeFound := map[string]struct{}{
 				"open experience":   {},
@@ -685,7 +716,7 @@ func (s *searchControllerTestSuite) TestSearchByWorkItemTypeGroup() {
 				{"space": "%s"}
 			]}`, fxt.Spaces[0].ID)
 			// when
-			_, sr := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil)
+			_, sr := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, nil)
 			// then
 			toBeFound := map[string]struct{}{
 				"open feature":   {},
@@ -703,7 +734,7 @@ func (s *searchControllerTestSuite) TestSearchByWorkItemTypeGroup() {
 				{"space": "%s"}
 			]}`, fxt.Spaces[0].ID)
 			// when
-			_, sr := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil)
+			_, sr := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, nil)
 			// then
 			toBeFound := map[string]struct{}{
 				"open task":      {},
@@ -724,7 +755,7 @@ func (s *searchControllerTestSuite) TestSearchByWorkItemTypeGroup() {
 				{"space": "%s"}
 			]}`, "unknown work item type group", fxt.Spaces[0].ID)
 			// when
-			_, sr := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil)
+			_, sr := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, nil)
 			// then
 			require.Empty(t, sr.Data)
 		})
@@ -780,7 +811,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 		filter := fmt.Sprintf(`
 				{"label": {"$IN": ["%s", "%s"]}}`,
 			fxt.LabelByName("important").ID, fxt.LabelByName("ui").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotNil(t, result)
 		fmt.Println(result.Data)
 		require.NotEmpty(t, result.Data)
@@ -797,7 +828,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					]}
 				]}`,
 			spaceIDStr, fxt.LabelByName("backend").ID, fxt.IterationByName("sprint2").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 3+5+1) // 3 items with Backend label & 5+1 items with sprint2
 	})
@@ -809,7 +840,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					{"label": "%s"}
 				]}`,
 			spaceIDStr, fxt.LabelByName("ui").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 5) // 5 items having UI label
 	})
@@ -821,7 +852,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					{"label": "%s"}
 				]}`,
 			fxt.LabelByName("ui").ID, fxt.LabelByName("backend").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 8)
 	})
@@ -833,7 +864,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					{"label": "%s"}
 				]}`,
 			spaceIDStr, fxt.LabelByName("rest").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		assert.Len(t, result.Data, 0) // no items having REST label
 	})
 
@@ -844,7 +875,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					{"label": "%s", "negate": true}
 				]}`,
 			spaceIDStr, fxt.LabelByName("backend").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 5+1) // 6 items are not having Backend label
 	})
@@ -856,7 +887,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					{"iteration": "%s"}
 				]}`,
 			workitem.SystemStateResolved, fxt.IterationByName("sprint1").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		require.Len(t, result.Data, 3) // resolved items having sprint1 are 3
 	})
@@ -868,7 +899,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					{"iteration": {"$EQ": "%s"}}
 				]}`,
 			workitem.SystemStateResolved, fxt.IterationByName("sprint1").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		require.Len(t, result.Data, 3) // resolved items having sprint1 are 3
 	})
@@ -880,7 +911,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					{"iteration": "%s"}
 				]}`,
 			workitem.SystemStateResolved, fxt.IterationByName("sprint2").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.Len(t, result.Data, 0) // No items having state=resolved && sprint2
 	})
 
@@ -894,7 +925,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					{"iteration": "%s"}
 				]}`,
 			workitem.SystemStateResolved, fxt.IterationByName("sprint2").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 3+5+1) // resolved items + items in sprint2
 	})
@@ -906,7 +937,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					{"title": {"$SUBSTR":"%s"}}
 				]}`,
 			spaceIDStr, "special")
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 3)
 	})
@@ -918,7 +949,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 		filter := fmt.Sprintf(`
 				{"state": {"$IN": ["%s", "%s"]}}`,
 			workitem.SystemStateResolved, workitem.SystemStateClosed)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 3+5+1) // state = resolved or state = closed
 	})
@@ -933,7 +964,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					]}
 				]}`,
 			spaceIDStr, workitem.SystemStateResolved, fxt.IterationByName("sprint2").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 3+5+1)
 	})
@@ -948,7 +979,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					]}
 				]}`,
 			spaceIDStr, workitem.SystemStateResolved, fxt.IterationByName("sprint2").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 3+5+1)
 	})
@@ -963,7 +994,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					]}
 				]}`,
 			spaceIDStr, workitem.SystemStateResolved, fxt.IterationByName("sprint1").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		assert.Len(t, result.Data, 0)
 	})
 
@@ -978,7 +1009,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					]}
 				]}`,
 			spaceIDStr, workitem.SystemStateOpen, fakeIterationID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 9) // all items are other than open state & in other thatn fake itr
 	})
@@ -994,7 +1025,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					]}
 				]}`,
 			spaceIDStr, workitem.SystemStateOpen, fakeIterationID)
-		test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 	})
 
 	s.T().Run("space=ID AND (state!=open AND iteration!=fake-iterationID) using NE", func(t *testing.T) {
@@ -1008,7 +1039,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					]}
 				]}`,
 			spaceIDStr, workitem.SystemStateOpen, fakeIterationID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 9) // all items are other than open state & in other thatn fake itr
 	})
@@ -1021,7 +1052,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					{"state": "%s"}
 				]}`,
 			fakeSpaceID1, workitem.SystemStateOpen)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &fakeSpaceID1)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &fakeSpaceID1)
 		assert.Len(t, result.Data, 0) // we have 5 closed items but they are in different space
 	})
 
@@ -1033,7 +1064,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					{"state": "%s"}
 				]}`,
 			spaceIDStr, fxt.IdentityByUsername("bob").ID, workitem.SystemStateClosed)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 5) // we have 5 closed items assigned to bob
 	})
@@ -1047,7 +1078,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					{"iteration": "%s"}
 				]}`,
 			spaceIDStr, fxt.IdentityByUsername("alice").ID, fxt.IterationByName("sprint1").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 3) // alice worked on 3 issues in sprint1
 	})
@@ -1059,7 +1090,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					{"creator":"%s"}
 				]}`,
 			spaceIDStr, fxt.IdentityByUsername("spaceowner").ID.String())
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 9) // we have 9 items created by spaceowner
 	})
@@ -1074,7 +1105,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					{"iteration": "%s"}
 				]}`,
 			spaceIDStr, fxt.IdentityByUsername("alice").ID, workitem.SystemStateClosed, fxt.IterationByName("sprint1").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 3)
 	})
@@ -1090,7 +1121,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					]}
 				]}`,
 			spaceIDStr, workitem.SystemStateClosed, workitem.SystemStateResolved)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 3+5+1) //resolved + closed
 	})
@@ -1106,7 +1137,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					]}
 				]}`,
 			spaceIDStr, fxt.WorkItemTypeByName("bug").ID, fxt.WorkItemTypeByName("feature").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 3+5+1) //bugs + features
 	})
@@ -1122,7 +1153,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					]}
 				]}`,
 			spaceIDStr, fxt.WorkItemTypeByName("bug").ID, fxt.WorkItemTypeByName("feature").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 3+5+1) //bugs + features
 	})
@@ -1138,7 +1169,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					]}
 				]}`,
 			spaceIDStr, fxt.WorkItemTypeByName("bug").ID, workitem.SystemStateResolved, fxt.IdentityByUsername("bob").ID, fxt.IdentityByUsername("alice").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 3) //resolved bugs
 	})
@@ -1154,14 +1185,14 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 					]}
 				]}`,
 			spaceIDStr, fxt.WorkItemTypeByName("bug").ID, workitem.SystemStateResolved, fxt.IdentityByUsername("bob").ID, fxt.IdentityByUsername("alice").ID)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 		assert.Len(t, result.Data, 3) //resolved bugs
 	})
 
 	s.T().Run("bad expression missing curly brace", func(t *testing.T) {
 		filter := fmt.Sprintf(`{"state": "0fe7b23e-c66e-43a9-ab1b-fbad9924fe7c"`)
-		res, jerrs := test.ShowSearchBadRequest(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		res, jerrs := test.ShowSearchBadRequest(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotNil(t, jerrs)
 		require.Len(t, jerrs.Errors, 1)
 		require.NotNil(t, jerrs.Errors[0].ID)
@@ -1173,7 +1204,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 
 	s.T().Run("non existing key", func(t *testing.T) {
 		filter := fmt.Sprintf(`{"nonexistingkey": "0fe7b23e-c66e-43a9-ab1b-fbad9924fe7c"}`)
-		res, jerrs := test.ShowSearchBadRequest(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		res, jerrs := test.ShowSearchBadRequest(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotNil(t, jerrs)
 		require.Len(t, jerrs.Errors, 1)
 		require.NotNil(t, jerrs.Errors[0].ID)
@@ -1189,7 +1220,7 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 						{"assignee":null}
 					]}`,
 		)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotNil(s.T(), result)
 		require.NotEmpty(t, result.Data)
 	})
@@ -1198,13 +1229,13 @@ func (s *searchControllerTestSuite) TestSearchQueryScenarioDriven() {
 		filter := fmt.Sprintf(`
 					{"assignee":null}`,
 		)
-		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotEmpty(t, result.Data)
 	})
 
 	s.T().Run("assignee=null with negate", func(t *testing.T) {
 		filter := fmt.Sprintf(`{"$AND": [{"assignee":null, "negate": true}]}`)
-		res, jerrs := test.ShowSearchBadRequest(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+		res, jerrs := test.ShowSearchBadRequest(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 		require.NotNil(t, jerrs)
 		require.Len(t, jerrs.Errors, 1)
 		require.NotNil(t, jerrs.Errors[0].ID)
@@ -1234,7 +1265,7 @@ func (s *searchControllerTestSuite) TestSearchByJoinedData() {
 		// given
 		filter := fmt.Sprintf(`{"iteration.name": "%s"}`, fxt.Iterations[0].Name)
 		// when
-		resWriter, list := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, ptr.String(spaceIDStr))
+		resWriter, list := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, ptr.String(spaceIDStr))
 		// then
 		require.NotNil(t, resWriter)
 		require.NotNil(t, list)
@@ -1284,7 +1315,7 @@ func (s *searchControllerTestSuite) TestSearchWorkItemsWithChildIterationsOption
 
 		t.Run("without child iteration", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"iteration": "%s", "child": true}`, fxt.Iterations[2].ID)
-			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 			require.NotEmpty(t, result.Data)
 			assert.Len(t, result.Data, 4)
 			toBeFound := id.MapFromSlice(id.Slice{
@@ -1302,7 +1333,7 @@ func (s *searchControllerTestSuite) TestSearchWorkItemsWithChildIterationsOption
 		})
 		t.Run("with one child iteration", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"iteration": "%s", "child": true}`, fxt.Iterations[1].ID)
-			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 			require.NotEmpty(t, result.Data)
 			assert.Len(t, result.Data, 6)
 			toBeFound := id.MapFromSlice(id.Slice{
@@ -1322,7 +1353,7 @@ func (s *searchControllerTestSuite) TestSearchWorkItemsWithChildIterationsOption
 		})
 		t.Run("with one child iteration implicit", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"iteration": "%s"}`, fxt.Iterations[1].ID)
-			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 			require.NotEmpty(t, result.Data)
 			assert.Len(t, result.Data, 6)
 			toBeFound := id.MapFromSlice(id.Slice{
@@ -1342,7 +1373,7 @@ func (s *searchControllerTestSuite) TestSearchWorkItemsWithChildIterationsOption
 		})
 		t.Run("with two child iteration", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"iteration": "%s", "child": true}`, fxt.Iterations[0].ID)
-			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 			require.NotEmpty(t, result.Data)
 			assert.Len(t, result.Data, 9)
 			toBeFound := id.MapFromSlice(id.Slice{
@@ -1366,7 +1397,7 @@ func (s *searchControllerTestSuite) TestSearchWorkItemsWithChildIterationsOption
 
 		t.Run("without child iteration - implicit", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"iteration": "%s"}`, fxt.Iterations[2].ID)
-			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 			require.NotEmpty(t, result.Data)
 			assert.Len(t, result.Data, 4)
 			toBeFound := id.MapFromSlice(id.Slice{
@@ -1384,7 +1415,7 @@ func (s *searchControllerTestSuite) TestSearchWorkItemsWithChildIterationsOption
 		})
 		t.Run("without child iteration - child false", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"iteration": "%s", "child": false}`, fxt.Iterations[2].ID)
-			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 			require.NotEmpty(t, result.Data)
 			assert.Len(t, result.Data, 4)
 			toBeFound := id.MapFromSlice(id.Slice{
@@ -1402,7 +1433,7 @@ func (s *searchControllerTestSuite) TestSearchWorkItemsWithChildIterationsOption
 		})
 		t.Run("with one child iteration - child false", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"iteration": "%s", "child": false}`, fxt.Iterations[1].ID)
-			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 			require.NotEmpty(t, result.Data)
 			assert.Len(t, result.Data, 2)
 			toBeFound := id.MapFromSlice(id.Slice{
@@ -1418,7 +1449,7 @@ func (s *searchControllerTestSuite) TestSearchWorkItemsWithChildIterationsOption
 		})
 		t.Run("with two child iteration - child false", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"iteration": "%s", "child": false}`, fxt.Iterations[0].ID)
-			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 			require.NotEmpty(t, result.Data)
 			assert.Len(t, result.Data, 3)
 			toBeFound := id.MapFromSlice(id.Slice{
@@ -1501,7 +1532,7 @@ func (s *searchControllerTestSuite) TestIncludedParents() {
 			t.Run(testName, func(t *testing.T) {
 				t.Logf("Running with filter: %s", filter)
 				// when
-				_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+				_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 				// then
 				require.NotEmpty(t, result.Data)
 				assert.Len(t, result.Data, len(searchForTitles))
@@ -1633,7 +1664,7 @@ func (s *searchControllerTestSuite) TestIncludedChildren() {
 		t.Run("B,C with tree-view = true", func(t *testing.T) {
 			// when
 			filter := fmt.Sprintf(`{"$AND":[{"space":"%[1]s"}, {"$OR": [{"title":"B"}, {"title":"C"}]}], "$OPTS":{"%[2]s": true}}`, spaceIDStr, search.OptTreeViewKey)
-			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 			// then
 			require.NotEmpty(t, result.Data)
 			// check "data" section
@@ -1693,7 +1724,7 @@ func (s *searchControllerTestSuite) TestIncludedChildren() {
 		t.Run("B,C with tree-view = false", func(t *testing.T) {
 			// when
 			filter := fmt.Sprintf(`{"$AND":[{"space":"%[1]s"}, {"$OR": [{"title":"B"}, {"title":"C"}]}], "$OPTS":{"%[2]s": false}}`, spaceIDStr, search.OptTreeViewKey)
-			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, &spaceIDStr)
+			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, &spaceIDStr)
 			// then
 			require.NotEmpty(t, result.Data)
 			require.Empty(t, result.Included)
@@ -1735,7 +1766,7 @@ func (s *searchControllerTestSuite) TestUpdateWorkItem() {
 		filter := fmt.Sprintf(`{"$AND":[{"space":"%s"},{"assignee":null}]}`, fxt.Spaces[0].ID.String())
 		t.Run("filter null", func(t *testing.T) {
 			// when
-			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil)
+			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, nil)
 			// then
 			require.Len(t, result.Data, 1)
 			require.Equal(t, fxt.WorkItemByTitle("unassigned").ID, *result.Data[0].ID)
@@ -1749,7 +1780,7 @@ func (s *searchControllerTestSuite) TestUpdateWorkItem() {
 				_, updated := test.UpdateWorkitemOK(t, s.svc.Context, s.svc, workitemCtrl, *wi.ID, &payload2)
 				compareWithGoldenAgnostic(t, filepath.Join(s.testDir, "show", "filter_assignee_null_update_work_item.golden.json"), updated)
 
-				_, result = test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil)
+				_, result = test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, nil)
 				compareWithGoldenAgnostic(t, filepath.Join(s.testDir, "show", "filter_assignee_null_show_after_update_work_item.golden.json"), updated)
 				assert.Nil(s.T(), result.Data[0].Attributes[workitem.SystemAssignees])
 
@@ -1774,7 +1805,7 @@ func (s *searchControllerTestSuite) TestUpdateWorkItem() {
 		filter := fmt.Sprintf(`{"$AND":[{"space":"%s"},{"label":{"$EQ":null}}]}`, fxt.Spaces[0].ID.String())
 		t.Run("filter null", func(t *testing.T) {
 			// when
-			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil)
+			_, result := test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, nil)
 			// then
 			require.Len(t, result.Data, 1)
 			require.Equal(t, fxt.WorkItemByTitle("unlabelled").ID, *result.Data[0].ID)
@@ -1787,7 +1818,7 @@ func (s *searchControllerTestSuite) TestUpdateWorkItem() {
 				_, updated := test.UpdateWorkitemOK(t, s.svc.Context, s.svc, workitemCtrl, *wi.ID, &payload2)
 				compareWithGoldenAgnostic(t, filepath.Join(s.testDir, "show", "filter_label_null_update_work_item.golden.json"), updated)
 
-				_, result = test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil)
+				_, result = test.ShowSearchOK(t, nil, nil, s.controller, &filter, nil, nil, nil, nil, nil, nil)
 				compareWithGoldenAgnostic(t, filepath.Join(s.testDir, "show", "filter_label_null_show_after_update_work_item.golden.json"), updated)
 				assert.Nil(s.T(), result.Data[0].Attributes[workitem.SystemLabels])
 			})
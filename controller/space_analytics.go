@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/workitem/analytics"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// maxAnalyticsRange is the longest date range that the analytics of a space
+// can be retrieved for at once
+const maxAnalyticsRange = 366 * 24 * time.Hour
+
+// SpaceAnalyticsController implements the space_analytics resource.
+type SpaceAnalyticsController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewSpaceAnalyticsController creates a space_analytics controller.
+func NewSpaceAnalyticsController(service *goa.Service, db application.DB) *SpaceAnalyticsController {
+	return &SpaceAnalyticsController{
+		Controller: service.NewController("SpaceAnalyticsController"),
+		db:         db,
+	}
+}
+
+// Show runs the show action.
+func (c *SpaceAnalyticsController) Show(ctx *app.ShowSpaceAnalyticsContext) error {
+	end := time.Now()
+	if ctx.End != nil {
+		end = *ctx.End
+	}
+	if end.Sub(ctx.Start) > maxAnalyticsRange {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("start", ctx.Start).Expected(fmt.Sprintf("a time at most %d days before the end", maxAnalyticsRange/(24*time.Hour))))
+	}
+	var report *analytics.Report
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		report, err = appl.Analytics().Load(ctx, ctx.SpaceID, ctx.Typegroup, ctx.Start, end)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.SpaceAnalyticsSingle{
+		Data: ConvertSpaceAnalytics(ctx.Request, ctx.SpaceID, ctx.Typegroup, *report),
+	})
+}
+
+// ConvertSpaceAnalytics converts the analytics of a space from internal to
+// external REST representation
+func ConvertSpaceAnalytics(request *http.Request, spaceID uuid.UUID, typeGroupID *uuid.UUID, report analytics.Report) *app.SpaceAnalytics {
+	spaceIDStr := spaceID.String()
+	spaceURL := rest.AbsoluteURL(request, app.SpaceHref(spaceIDStr))
+	selfURL := rest.AbsoluteURL(request, app.SpaceHref(spaceIDStr)+"/analytics")
+	res := &app.SpaceAnalytics{
+		Type: analytics.APIStringTypeAnalytics,
+		ID:   &spaceID,
+		Attributes: &app.SpaceAnalyticsAttributes{
+			Start:          report.Start,
+			End:            report.End,
+			CumulativeFlow: make([]*app.CumulativeFlowPoint, len(report.CumulativeFlow)),
+			FlowTimes:      make([]*app.WorkItemTypeFlowTimes, len(report.FlowTimes)),
+		},
+		Relationships: &app.SpaceAnalyticsRelations{
+			Space: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &space.SpaceType,
+					ID:   &spaceIDStr,
+				},
+				Links: &app.GenericLinks{
+					Self:    &spaceURL,
+					Related: &spaceURL,
+				},
+			},
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+	if typeGroupID != nil {
+		typeGroupIDStr := typeGroupID.String()
+		typeGroupURL := rest.AbsoluteURL(request, app.WorkItemTypeGroupHref(typeGroupIDStr))
+		res.Relationships.Typegroup = &app.RelationGeneric{
+			Data: &app.GenericData{
+				Type: &APIWorkItemTypeGroups,
+				ID:   &typeGroupIDStr,
+			},
+			Links: &app.GenericLinks{
+				Self:    &typeGroupURL,
+				Related: &typeGroupURL,
+			},
+		}
+	}
+	for i, p := range report.CumulativeFlow {
+		res.Attributes.CumulativeFlow[i] = &app.CumulativeFlowPoint{
+			Time:   p.Time,
+			Counts: p.Counts,
+		}
+	}
+	for i, t := range report.FlowTimes {
+		res.Attributes.FlowTimes[i] = &app.WorkItemTypeFlowTimes{
+			WorkItemType: t.WorkItemTypeID,
+			LeadTime:     convertPercentiles(t.LeadTime),
+			CycleTime:    convertPercentiles(t.CycleTime),
+		}
+	}
+	return res
+}
+
+// convertPercentiles converts percentiles of durations to hours
+func convertPercentiles(p analytics.Percentiles) *app.DurationPercentiles {
+	return &app.DurationPercentiles{
+		Count: p.Count,
+		P50:   p.P50.Hours(),
+		P85:   p.P85.Hours(),
+		P95:   p.P95.Hours(),
+	}
+}
@@ -0,0 +1,86 @@
+package controller_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/analytics"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SpaceAnalyticsControllerTestSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestSpaceAnalyticsController(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &SpaceAnalyticsControllerTestSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *SpaceAnalyticsControllerTestSuite) UnSecuredController() (*goa.Service, *SpaceAnalyticsController) {
+	svc := goa.New("SpaceAnalytics-Service")
+	return svc, NewSpaceAnalyticsController(svc, s.GormDB)
+}
+
+func (s *SpaceAnalyticsControllerTestSuite) TestShow() {
+	// given a space with one new and one closed work item
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.CreateWorkItemEnvironment(),
+		tf.WorkItems(2, tf.SetWorkItemField(workitem.SystemState, workitem.SystemStateNew, workitem.SystemStateClosed)),
+		tf.WorkItemTypeGroups(1),
+	)
+	svc, ctrl := s.UnSecuredController()
+	start := time.Now().Add(-48 * time.Hour)
+
+	s.T().Run("ok", func(t *testing.T) {
+		// when
+		_, res := test.ShowSpaceAnalyticsOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, start, nil)
+		// then
+		require.NotNil(t, res.Data)
+		assert.Equal(t, analytics.APIStringTypeAnalytics, res.Data.Type)
+		assert.Equal(t, fxt.Spaces[0].ID, *res.Data.ID)
+		require.NotNil(t, res.Data.Relationships.Space.Data.ID)
+		assert.Equal(t, fxt.Spaces[0].ID.String(), *res.Data.Relationships.Space.Data.ID)
+		assert.Nil(t, res.Data.Relationships.Typegroup)
+		flow := res.Data.Attributes.CumulativeFlow
+		require.True(t, len(flow) >= 3)
+		// the work items were created after the start of the date range
+		assert.Empty(t, flow[0].Counts)
+		assert.Equal(t, map[string]int{workitem.SystemStateNew: 1, workitem.SystemStateClosed: 1}, flow[len(flow)-1].Counts)
+		require.Len(t, res.Data.Attributes.FlowTimes, 1)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, res.Data.Attributes.FlowTimes[0].WorkItemType)
+		assert.Equal(t, 1, res.Data.Attributes.FlowTimes[0].LeadTime.Count)
+		assert.Equal(t, 0, res.Data.Attributes.FlowTimes[0].CycleTime.Count)
+	})
+
+	s.T().Run("ok - type group", func(t *testing.T) {
+		// when
+		_, res := test.ShowSpaceAnalyticsOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, start, &fxt.WorkItemTypeGroups[0].ID)
+		// then
+		require.NotNil(t, res.Data.Relationships.Typegroup)
+		assert.Equal(t, fxt.WorkItemTypeGroups[0].ID.String(), *res.Data.Relationships.Typegroup.Data.ID)
+	})
+
+	s.T().Run("bad request - end before start", func(t *testing.T) {
+		test.ShowSpaceAnalyticsBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, ptr.Time(start.Add(-time.Hour)), start, nil)
+	})
+
+	s.T().Run("bad request - date range too long", func(t *testing.T) {
+		test.ShowSpaceAnalyticsBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, time.Now().Add(-400*24*time.Hour), nil)
+	})
+
+	s.T().Run("not found - unknown space", func(t *testing.T) {
+		test.ShowSpaceAnalyticsNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), nil, start, nil)
+	})
+}
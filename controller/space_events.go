@@ -0,0 +1,287 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/search"
+	"github.com/fabric8-services/fabric8-wit/workitem/event"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"golang.org/x/net/websocket"
+)
+
+// SpaceEventsController implements the space_events resource.
+type SpaceEventsController struct {
+	*goa.Controller
+	db     application.DB
+	config SpaceEventsControllerConfig
+}
+
+// SpaceEventsControllerConfig the config interface for the SpaceEventsController
+type SpaceEventsControllerConfig interface {
+	GetEventStreamPollInterval() time.Duration
+	GetEventStreamBatchSize() int
+	GetPostgresTransactionTimeout() time.Duration
+}
+
+// NewSpaceEventsController creates a space_events controller.
+func NewSpaceEventsController(service *goa.Service, db application.DB, config SpaceEventsControllerConfig) *SpaceEventsController {
+	return &SpaceEventsController{
+		Controller: service.NewController("SpaceEventsController"),
+		db:         db,
+		config:     config,
+	}
+}
+
+// SpaceChange is a change sent through the change stream of a space
+type SpaceChange struct {
+	// ID identifies the change and is used to resume the stream after it
+	ID         uuid.UUID  `json:"id"`
+	Type       string     `json:"type"`
+	Timestamp  time.Time  `json:"timestamp"`
+	SpaceID    uuid.UUID  `json:"space_id"`
+	WorkItemID uuid.UUID  `json:"work_item_id"`
+	TargetID   *uuid.UUID `json:"target_id,omitempty"`
+	ModifierID uuid.UUID  `json:"modifier_id"`
+	// Events holds the field changes of a work item update
+	Events []*app.Event `json:"events,omitempty"`
+}
+
+// Watch runs the watch action.
+func (c *SpaceEventsController) Watch(ctx *app.WatchSpaceEventsContext) error {
+	var filter criteria.Expression
+	if ctx.FilterExpression != nil {
+		exp, _, err := search.ParseFilterString(withTextQueryEnv(ctx, *ctx.FilterExpression), *ctx.FilterExpression)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("filter[expression]", *ctx.FilterExpression))
+		}
+		filter = exp
+	}
+	after := ctx.After
+	if after == nil && ctx.LastEventID != nil {
+		lastEventID, err := uuid.FromString(*ctx.LastEventID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("Last-Event-ID", *ctx.LastEventID))
+		}
+		after = &lastEventID
+	}
+	var position *event.Position
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		err := appl.Spaces().CheckExists(ctx, ctx.SpaceID)
+		if err != nil {
+			return err
+		}
+		if after != nil {
+			position, err = appl.Events().ChangePosition(ctx, ctx.SpaceID, *after)
+		} else {
+			// without a change to resume from, only the changes from now
+			// on are streamed
+			position, err = appl.Events().LatestPosition(ctx, ctx.SpaceID)
+		}
+		if err != nil {
+			return err
+		}
+		// make sure the filter is valid before the stream starts
+		_, err = appl.Events().ListChanges(ctx, ctx.SpaceID, filter, position, 1)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+
+	if strings.EqualFold(ctx.Request.Header.Get("Upgrade"), "websocket") {
+		c.watchWSHandler(ctx, filter, position).ServeHTTP(ctx.ResponseWriter, ctx.Request)
+		return nil
+	}
+	return c.watchEventSource(ctx, filter, position)
+}
+
+// watchWSHandler streams the changes of the space through a websocket
+func (c *SpaceEventsController) watchWSHandler(ctx *app.WatchSpaceEventsContext, filter criteria.Expression, start *event.Position) websocket.Handler {
+	return func(ws *websocket.Conn) {
+		defer ws.Close()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for {
+				var m string
+				err := websocket.Message.Receive(ws, &m)
+				if err != nil {
+					if err != io.EOF {
+						log.Error(ctx, map[string]interface{}{
+							"err": err,
+						}, "error reading from websocket")
+					}
+					return
+				}
+			}
+		}()
+		c.streamChanges(ctx, ctx.Request, ctx.SpaceID, filter, start, done, func(change SpaceChange) error {
+			return websocket.JSON.Send(ws, change)
+		})
+	}
+}
+
+// watchEventSource streams the changes of the space as Server-Sent Events
+func (c *SpaceEventsController) watchEventSource(ctx *app.WatchSpaceEventsContext, filter criteria.Expression, start *event.Position) error {
+	ctx.ResponseData.Header().Set("Content-Type", "text/event-stream")
+	ctx.ResponseData.Header().Set("Cache-Control", "no-cache")
+	ctx.ResponseData.Header().Set("Connection", "keep-alive")
+	ctx.ResponseData.WriteHeader(http.StatusOK)
+	flush := func() {
+		if f, ok := ctx.ResponseData.ResponseWriter.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+	flush()
+	done := make(chan struct{})
+	go func() {
+		<-ctx.Request.Context().Done()
+		close(done)
+	}()
+	c.streamChanges(ctx, ctx.Request, ctx.SpaceID, filter, start, done, func(change SpaceChange) error {
+		data, err := json.Marshal(change)
+		if err != nil {
+			return errs.Wrapf(err, "failed to marshal change %s", change.ID)
+		}
+		if _, err := fmt.Fprintf(ctx.ResponseData, "id: %s\nevent: %s\ndata: %s\n\n", change.ID, change.Type, data); err != nil {
+			return err
+		}
+		flush()
+		return nil
+	})
+	return nil
+}
+
+// streamChanges sends the changes of the given space after the given
+// position (nil for the beginning) until the done channel is closed or
+// sending a change fails. New changes are looked up at the configured poll
+// interval.
+//
+// A change gets the time at which its transaction recorded it but only shows
+// up once the transaction commits, which can be up to the transaction timeout
+// later. That's why every poll lists the changes of the transaction timeout
+// before the latest sent change again and skips the ones that were already
+// sent. Changes that show up late before the start position are not sent.
+func (c *SpaceEventsController) streamChanges(ctx context.Context, req *http.Request, spaceID uuid.UUID, filter criteria.Expression, start *event.Position, done <-chan struct{}, send func(SpaceChange) error) {
+	ticker := time.NewTicker(c.config.GetEventStreamPollInterval())
+	defer ticker.Stop()
+	batchSize := c.config.GetEventStreamBatchSize()
+	window := c.config.GetPostgresTransactionTimeout()
+	// latest is the time of the latest sent change
+	var latest time.Time
+	// sent holds the times of the sent changes that are still in the window
+	sent := map[uuid.UUID]time.Time{}
+	after := start
+	for {
+		if !latest.IsZero() {
+			from := event.Position{Time: latest.Add(-window)}
+			after = &from
+			if start != nil && !from.Time.After(start.Time) {
+				after = start
+			}
+			for id, t := range sent {
+				if t.Before(from.Time) {
+					delete(sent, id)
+				}
+			}
+		}
+		more := true
+		for more {
+			var changes []SpaceChange
+			err := application.Transactional(c.db, func(appl application.Application) error {
+				list, err := appl.Events().ListChanges(ctx, spaceID, filter, after, batchSize)
+				if err != nil {
+					return err
+				}
+				more = len(list) == batchSize
+				if len(list) > 0 {
+					last := list[len(list)-1].Position()
+					after = &last
+				}
+				unsent := make([]event.Change, 0, len(list))
+				for _, change := range list {
+					if _, ok := sent[change.ID]; !ok {
+						unsent = append(unsent, change)
+					}
+				}
+				changes, err = ConvertSpaceChanges(ctx, appl, req, spaceID, unsent)
+				return err
+			})
+			if err != nil {
+				log.Error(ctx, map[string]interface{}{
+					"space_id": spaceID,
+					"err":      err,
+				}, "failed to load the changes of the space")
+				return
+			}
+			for _, change := range changes {
+				if err := send(change); err != nil {
+					log.Error(ctx, map[string]interface{}{
+						"space_id": spaceID,
+						"err":      err,
+					}, "failed to send the change")
+					return
+				}
+				sent[change.ID] = change.Timestamp
+				if change.Timestamp.After(latest) {
+					latest = change.Timestamp
+				}
+			}
+			if more {
+				// more changes are waiting, unless the client is gone
+				select {
+				case <-done:
+					return
+				default:
+				}
+			}
+		}
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ConvertSpaceChanges converts the changes of a space from internal to
+// external REST representation
+func ConvertSpaceChanges(ctx context.Context, appl application.Application, req *http.Request, spaceID uuid.UUID, changes []event.Change) ([]SpaceChange, error) {
+	res := make([]SpaceChange, len(changes))
+	for i, change := range changes {
+		res[i] = SpaceChange{
+			ID:         change.ID,
+			Type:       change.Name,
+			Timestamp:  change.Timestamp,
+			SpaceID:    spaceID,
+			WorkItemID: change.WorkItemID,
+			ModifierID: change.Modifier,
+		}
+		if change.TargetID != uuid.Nil {
+			targetID := change.TargetID
+			res[i].TargetID = &targetID
+		}
+		if len(change.Events) > 0 {
+			events, err := ConvertEvents(ctx, appl, req, change.Events, change.WorkItemID)
+			if err != nil {
+				return nil, errs.Wrapf(err, "failed to convert the events of change %s", change.ID)
+			}
+			res[i].Events = events
+		}
+	}
+	return res, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/goadesign/goa"
@@ -16,6 +17,7 @@ import (
 
 type trackerConfiguration interface {
 	GetGithubAuthToken() string
+	GetJiraAuthToken() string
 }
 
 // TrackerController implements the tracker resource.
@@ -29,6 +31,7 @@ type TrackerController struct {
 func GetAccessTokens(configuration trackerConfiguration) map[string]string {
 	tokens := map[string]string{
 		remoteworkitem.ProviderGithub: configuration.GetGithubAuthToken(),
+		remoteworkitem.ProviderJira:   configuration.GetJiraAuthToken(),
 		// add tokens for other types
 	}
 	return tokens
@@ -56,8 +59,9 @@ func (c *TrackerController) Create(ctx *app.CreateTrackerContext) error {
 	var tracker *remoteworkitem.Tracker
 	err = application.Transactional(c.db, func(appl application.Application) error {
 		tracker = &remoteworkitem.Tracker{
-			URL:  ctx.Payload.Data.Attributes.URL,
-			Type: ctx.Payload.Data.Attributes.Type,
+			URL:          ctx.Payload.Data.Attributes.URL,
+			Type:         ctx.Payload.Data.Attributes.Type,
+			FieldMapping: ConvertFieldMappingToModel(ctx.Payload.Data.Attributes.FieldMapping),
 		}
 		return appl.Trackers().Create(ctx.Context, tracker)
 	})
@@ -164,6 +168,9 @@ func (c *TrackerController) Update(ctx *app.UpdateTrackerContext) error {
 		if &ctx.Payload.Data.Attributes.Type != nil {
 			trkr.Type = ctx.Payload.Data.Attributes.Type
 		}
+		if ctx.Payload.Data.Attributes.FieldMapping != nil {
+			trkr.FieldMapping = ConvertFieldMappingToModel(ctx.Payload.Data.Attributes.FieldMapping)
+		}
 		_, err = appl.Trackers().Save(ctx.Context, trkr)
 		return err
 	})
@@ -183,8 +190,9 @@ func ConvertTracker(request *http.Request, tracker remoteworkitem.Tracker) *app.
 		Type: trackerStringType,
 		ID:   &tracker.ID,
 		Attributes: &app.TrackerAttributes{
-			URL:  tracker.URL,
-			Type: tracker.Type,
+			URL:          tracker.URL,
+			Type:         tracker.Type,
+			FieldMapping: ConvertFieldMapping(tracker.FieldMapping),
 		},
 		Links: &app.GenericLinks{
 			Self: &selfURL,
@@ -193,6 +201,60 @@ func ConvertTracker(request *http.Request, tracker remoteworkitem.Tracker) *app.
 	return t
 }
 
+// ConvertFieldMapping converts a field mapping from internal to external
+// REST representation
+func ConvertFieldMapping(m remoteworkitem.FieldMapping) *app.FieldMapping {
+	if m.IsEmpty() {
+		return nil
+	}
+	res := &app.FieldMapping{
+		WorkItemType: m.WorkItemTypeID,
+		States:       m.States,
+	}
+	for _, e := range m.Fields {
+		entry := &app.FieldMappingEntry{
+			Remote: e.Remote,
+			Field:  e.Field,
+		}
+		if e.Converter != "" {
+			entry.Converter = ptr.String(e.Converter)
+		}
+		if e.Pattern != "" {
+			entry.Pattern = ptr.String(e.Pattern)
+		}
+		res.Fields = append(res.Fields, entry)
+	}
+	return res
+}
+
+// ConvertFieldMappingToModel converts a field mapping from external REST
+// representation to the internal one
+func ConvertFieldMappingToModel(m *app.FieldMapping) remoteworkitem.FieldMapping {
+	res := remoteworkitem.FieldMapping{}
+	if m == nil {
+		return res
+	}
+	res.WorkItemTypeID = m.WorkItemType
+	res.States = m.States
+	for _, e := range m.Fields {
+		if e == nil {
+			continue
+		}
+		entry := remoteworkitem.FieldMappingEntry{
+			Remote: e.Remote,
+			Field:  e.Field,
+		}
+		if e.Converter != nil {
+			entry.Converter = *e.Converter
+		}
+		if e.Pattern != nil {
+			entry.Pattern = *e.Pattern
+		}
+		res.Fields = append(res.Fields, entry)
+	}
+	return res
+}
+
 func validateCreateTrackerPayload(ctx *app.CreateTrackerContext) error {
 	if ctx.Payload.Data.Attributes.URL == "" {
 		return errors.NewBadParameterError("URL", "").Expected("not nil")
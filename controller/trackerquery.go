@@ -19,6 +19,7 @@ import (
 
 type trackerQueryConfiguration interface {
 	GetGithubAuthToken() string
+	GetJiraAuthToken() string
 }
 
 // TrackerqueryController implements the trackerquery resource.
@@ -32,6 +33,7 @@ type TrackerqueryController struct {
 func getAccessTokensForTrackerQuery(configuration trackerQueryConfiguration) map[string]string {
 	tokens := map[string]string{
 		remoteworkitem.ProviderGithub: configuration.GetGithubAuthToken(),
+		remoteworkitem.ProviderJira:   configuration.GetJiraAuthToken(),
 		// add tokens for other types
 	}
 	return tokens
@@ -98,6 +100,10 @@ func (c *TrackerqueryController) Create(ctx *app.CreateTrackerqueryContext) erro
 			TrackerID: ctx.Payload.Data.Relationships.Tracker.Data.ID,
 			SpaceID:   *ctx.Payload.Data.Relationships.Space.Data.ID,
 		}
+		if ctx.Payload.Data.Attributes.WriteBack != nil {
+			trackerQuery.WriteBack = *ctx.Payload.Data.Attributes.WriteBack
+		}
+		trackerQuery.FieldMapping = ConvertFieldMappingToModel(ctx.Payload.Data.Attributes.FieldMapping)
 		trackerQuery.ID = *ctx.Payload.Data.ID
 		tq, err := appl.TrackerQueries().Create(ctx.Context, trackerQuery)
 		if err != nil {
@@ -148,15 +154,27 @@ func (c *TrackerqueryController) Update(ctx *app.UpdateTrackerqueryContext) erro
 		if err != nil {
 			return errs.Wrapf(err, "failed to update tracker query %s", ctx.Payload.Data.ID)
 		}
+		previousQuery, previousTrackerID := tq.Query, tq.TrackerID
 		if &ctx.Payload.Data.Attributes.Query != nil {
 			tq.Query = ctx.Payload.Data.Attributes.Query
 		}
 		if &ctx.Payload.Data.Attributes.Schedule != nil {
 			tq.Schedule = ctx.Payload.Data.Attributes.Schedule
 		}
+		if ctx.Payload.Data.Attributes.WriteBack != nil {
+			tq.WriteBack = *ctx.Payload.Data.Attributes.WriteBack
+		}
+		if ctx.Payload.Data.Attributes.FieldMapping != nil {
+			tq.FieldMapping = ConvertFieldMappingToModel(ctx.Payload.Data.Attributes.FieldMapping)
+		}
 		if &ctx.Payload.Data.Relationships.Tracker.Data.ID != nil {
 			tq.TrackerID = ctx.Payload.Data.Relationships.Tracker.Data.ID
 		}
+		if tq.Query != previousQuery || tq.TrackerID != previousTrackerID || ctx.Payload.Data.Attributes.FieldMapping != nil {
+			// the items imported so far may not match the new configuration,
+			// so the next run fetches all items again
+			tq.LastSyncedAt = nil
+		}
 		_, err = appl.TrackerQueries().Save(ctx.Context, *tq)
 		if err != nil {
 			return errs.Wrapf(err, "failed to update tracker query %s", ctx.Payload.Data.ID)
@@ -195,6 +213,50 @@ func (c *TrackerqueryController) Delete(ctx *app.DeleteTrackerqueryContext) erro
 	return ctx.NoContent()
 }
 
+// Sync runs the sync action.
+func (c *TrackerqueryController) Sync(ctx *app.SyncTrackerqueryContext) error {
+	_, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	run, err := c.scheduler.Sync(ctx, ctx.ID, getAccessTokensForTrackerQuery(c.configuration))
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.Accepted(&app.TrackerQueryRunSingle{
+		Data: ConvertTrackerQueryRunToApp(ctx.Request, *run),
+	})
+}
+
+// ListRuns runs the listRuns action.
+func (c *TrackerqueryController) ListRuns(ctx *app.ListRunsTrackerqueryContext) error {
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		err := appl.TrackerQueries().CheckExists(ctx, ctx.ID)
+		if err != nil {
+			return err
+		}
+		runs, count, err := appl.TrackerQueryRuns().List(ctx, ctx.ID, offset, limit)
+		if err != nil {
+			return err
+		}
+		res := &app.TrackerQueryRunList{
+			Data:  []*app.TrackerQueryRun{},
+			Meta:  &app.TrackerQueryRunListMeta{TotalCount: count},
+			Links: &app.PagingLinks{},
+		}
+		for _, run := range runs {
+			res.Data = append(res.Data, ConvertTrackerQueryRunToApp(ctx.Request, run))
+		}
+		setPagingLinks(res.Links, buildAbsoluteURL(ctx.Request), len(runs), offset, limit, count)
+		return ctx.OK(res)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return nil
+}
+
 // List runs the list action.
 func (c *TrackerqueryController) List(ctx *app.ListTrackerqueryContext) error {
 	return application.Transactional(c.db, func(appl application.Application) error {
@@ -225,8 +287,11 @@ func convertTrackerQueryToApp(appl application.Application, request *http.Reques
 		Type: trackerQueryStringType,
 		ID:   &trackerquery.ID,
 		Attributes: &app.TrackerQueryAttributes{
-			Query:    trackerquery.Query,
-			Schedule: trackerquery.Schedule,
+			Query:        trackerquery.Query,
+			Schedule:     trackerquery.Schedule,
+			WriteBack:    &trackerquery.WriteBack,
+			FieldMapping: ConvertFieldMapping(trackerquery.FieldMapping),
+			LastSyncedAt: trackerquery.LastSyncedAt,
 		},
 		Links: &app.GenericLinks{
 			Self: &selfURL,
@@ -235,6 +300,39 @@ func convertTrackerQueryToApp(appl application.Application, request *http.Reques
 	return t
 }
 
+// ConvertTrackerQueryRunToApp converts a run of a tracker query from internal
+// to external REST representation
+func ConvertTrackerQueryRunToApp(request *http.Request, run remoteworkitem.TrackerQueryRun) *app.TrackerQueryRun {
+	trackerQueryURL := rest.AbsoluteURL(request, app.TrackerqueryHref(run.TrackerQueryID))
+	res := &app.TrackerQueryRun{
+		Type: remoteworkitem.APIStringTypeTrackerQueryRun,
+		ID:   &run.ID,
+		Attributes: &app.TrackerQueryRunAttributes{
+			Status:       run.Status,
+			StartedAt:    run.StartedAt,
+			FinishedAt:   run.FinishedAt,
+			Since:        run.Since,
+			ItemsFetched: run.ItemsFetched,
+			ItemsCreated: run.ItemsCreated,
+			ItemsUpdated: run.ItemsUpdated,
+			ItemsFailed:  run.ItemsFailed,
+			Errors:       []string(run.Errors),
+		},
+		Relationships: &app.TrackerQueryRunRelations{
+			Trackerquery: &app.RelationKindUUID{
+				Data: &app.DataKindUUID{
+					ID:   run.TrackerQueryID,
+					Type: remoteworkitem.APIStringTypeTrackerQuery,
+				},
+				Links: &app.GenericLinks{
+					Self: &trackerQueryURL,
+				},
+			},
+		},
+	}
+	return res
+}
+
 func validateCreateTrackerQueryPayload(ctx *app.CreateTrackerqueryContext) error {
 	if ctx.Payload.Data.Attributes.Query == "" {
 		return errors.NewBadParameterError("Query", "").Expected("not empty")
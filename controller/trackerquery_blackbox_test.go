@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"net/http"
 	"testing"
+	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/fabric8-services/fabric8-wit/app"
@@ -301,6 +302,47 @@ func (rest *TestTrackerQueryREST) TestCreateTrackerQueryID() {
 	})
 }
 
+func (rest *TestTrackerQueryREST) TestListTrackerQueryRuns() {
+	t := rest.T()
+	resource.Require(t, resource.Database)
+
+	svc, _, trackerQueryCtrl := rest.SecuredController()
+	fxt := tf.NewTestFixture(t, rest.DB, tf.Spaces(1), tf.Trackers(1))
+	tqpayload := newCreateTrackerQueryPayload(fxt.Spaces[0].ID, fxt.Trackers[0].ID)
+	_, tq := test.CreateTrackerqueryCreated(t, svc.Context, svc, trackerQueryCtrl, &tqpayload)
+	runRepo := remoteworkitem.NewTrackerQueryRunRepository(rest.DB)
+	for i := 0; i < 3; i++ {
+		run := remoteworkitem.TrackerQueryRun{
+			TrackerQueryID: *tq.Data.ID,
+			Status:         remoteworkitem.RunStatusFailed,
+			StartedAt:      time.Now().Add(time.Duration(i) * time.Minute),
+			ItemsFetched:   i,
+			Errors:         remoteworkitem.RunErrors{"boom"},
+		}
+		require.NoError(t, runRepo.Create(svc.Context, &run))
+	}
+
+	t.Run("most recent first", func(t *testing.T) {
+		limit := 2
+		_, runs := test.ListRunsTrackerqueryOK(t, svc.Context, svc, trackerQueryCtrl, *tq.Data.ID, &limit, nil)
+		require.Len(t, runs.Data, 2)
+		assert.Equal(t, 3, runs.Meta.TotalCount)
+		assert.Equal(t, 2, runs.Data[0].Attributes.ItemsFetched)
+		assert.Equal(t, 1, runs.Data[1].Attributes.ItemsFetched)
+		assert.Equal(t, []string{"boom"}, runs.Data[0].Attributes.Errors)
+		assert.Equal(t, *tq.Data.ID, runs.Data[0].Relationships.Trackerquery.Data.ID)
+		require.NotNil(t, runs.Links.Next)
+	})
+
+	t.Run("unknown tracker query", func(t *testing.T) {
+		test.ListRunsTrackerqueryNotFound(t, svc.Context, svc, trackerQueryCtrl, uuid.NewV4(), nil, nil)
+	})
+
+	t.Run("sync unknown tracker query", func(t *testing.T) {
+		test.SyncTrackerqueryNotFound(t, svc.Context, svc, trackerQueryCtrl, uuid.NewV4())
+	})
+}
+
 func newCreateTrackerQueryPayload(spaceID uuid.UUID, trackerID uuid.UUID) app.CreateTrackerqueryPayload {
 	trackerQueryID := uuid.NewV4()
 	return app.CreateTrackerqueryPayload{
@@ -0,0 +1,265 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/notification"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/webhook"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// WebhookController implements the webhook resource.
+type WebhookController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWebhookController creates a webhook controller.
+func NewWebhookController(service *goa.Service, db application.DB) *WebhookController {
+	return &WebhookController{
+		Controller: service.NewController("WebhookController"),
+		db:         db,
+	}
+}
+
+// checkSpaceOwner returns a ForbiddenError if the current user is not the
+// owner of the given space. Only space owners may manage the webhooks of a
+// space since they expose all of its events.
+func checkSpaceOwner(ctx context.Context, appl application.Application, spaceID uuid.UUID, currentUser uuid.UUID) error {
+	s, err := appl.Spaces().Load(ctx, spaceID)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	if !uuid.Equal(currentUser, s.OwnerID) {
+		log.Warn(ctx, map[string]interface{}{
+			"space_id":     s.ID,
+			"space_owner":  s.OwnerID,
+			"current_user": currentUser,
+		}, "user is not the space owner")
+		return errors.NewForbiddenError("user is not the space owner")
+	}
+	return nil
+}
+
+// validateWebhookEventTypes returns a BadParameterError if one of the given
+// event types is unknown
+func validateWebhookEventTypes(eventTypes []string) error {
+	for _, t := range eventTypes {
+		if !notification.IsKnownMessageType(t) {
+			return errors.NewBadParameterError("data.attributes.event-types", t).Expected(strings.Join(notification.MessageTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// Create runs the create action.
+func (c *WebhookController) Create(ctx *app.CreateWebhookContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	attrs := ctx.Payload.Data.Attributes
+	if attrs.URL == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.url", nil).Expected("not nil"))
+	}
+	if attrs.Secret == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.secret", nil).Expected("not nil"))
+	}
+	if err := validateWebhookEventTypes(attrs.EventTypes); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	w := webhook.Webhook{
+		SpaceID:    ctx.SpaceID,
+		URL:        strings.TrimSpace(*attrs.URL),
+		EventTypes: attrs.EventTypes,
+		Secret:     *attrs.Secret,
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return err
+		}
+		return errs.WithStack(appl.Webhooks().Create(ctx, &w))
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WebhookSingle{
+		Data: ConvertWebhook(ctx.Request, w),
+	}
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.WebhookHref(ctx.SpaceID, res.Data.ID)))
+	return ctx.Created(res)
+}
+
+// List runs the list action.
+func (c *WebhookController) List(ctx *app.ListWebhookContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	var hooks []webhook.Webhook
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return err
+		}
+		hooks, err = appl.Webhooks().List(ctx, ctx.SpaceID)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WebhookList{
+		Data: ConvertWebhooks(ctx.Request, hooks),
+	}
+	res.Meta = &app.WorkItemListResponseMeta{
+		TotalCount: len(res.Data),
+	}
+	return ctx.OK(res)
+}
+
+// Show runs the show action.
+func (c *WebhookController) Show(ctx *app.ShowWebhookContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	var w *webhook.Webhook
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return err
+		}
+		w, err = appl.Webhooks().Load(ctx, ctx.WebhookID, ctx.SpaceID)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.WebhookSingle{
+		Data: ConvertWebhook(ctx.Request, *w),
+	})
+}
+
+// Update runs the update action.
+func (c *WebhookController) Update(ctx *app.UpdateWebhookContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	attrs := ctx.Payload.Data.Attributes
+	if attrs.Version == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.version", nil).Expected("not nil"))
+	}
+	if err := validateWebhookEventTypes(attrs.EventTypes); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	var w *webhook.Webhook
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return err
+		}
+		w, err = appl.Webhooks().Load(ctx, ctx.WebhookID, ctx.SpaceID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		if w.Version != *attrs.Version {
+			return errors.NewVersionConflictError("version conflict")
+		}
+		if attrs.URL != nil {
+			w.URL = strings.TrimSpace(*attrs.URL)
+		}
+		if attrs.Secret != nil {
+			w.Secret = *attrs.Secret
+		}
+		if attrs.EventTypes != nil {
+			w.EventTypes = attrs.EventTypes
+		}
+		w, err = appl.Webhooks().Save(ctx, *w)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.WebhookSingle{
+		Data: ConvertWebhook(ctx.Request, *w),
+	})
+}
+
+// Delete runs the delete action.
+func (c *WebhookController) Delete(ctx *app.DeleteWebhookContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return err
+		}
+		if _, err := appl.Webhooks().Load(ctx, ctx.WebhookID, ctx.SpaceID); err != nil {
+			return errs.WithStack(err)
+		}
+		return errs.WithStack(appl.Webhooks().Delete(ctx, ctx.WebhookID))
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.NoContent()
+}
+
+// ConvertWebhook converts from internal to external REST representation. The
+// secret of the webhook is never included.
+func ConvertWebhook(request *http.Request, w webhook.Webhook) *app.Webhook {
+	spaceID := w.SpaceID.String()
+	relatedURL := rest.AbsoluteURL(request, app.WebhookHref(spaceID, w.ID))
+	spaceRelatedURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID))
+	eventTypes := []string(w.EventTypes)
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return &app.Webhook{
+		Type: webhook.APIStringTypeWebhook,
+		ID:   &w.ID,
+		Attributes: &app.WebhookAttributes{
+			URL:        &w.URL,
+			EventTypes: eventTypes,
+			CreatedAt:  &w.CreatedAt,
+			UpdatedAt:  &w.UpdatedAt,
+			Version:    &w.Version,
+		},
+		Links: &app.GenericLinks{
+			Self:    &relatedURL,
+			Related: &relatedURL,
+		},
+		Relationships: &app.WebhookRelations{
+			Space: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &space.SpaceType,
+					ID:   &spaceID,
+				},
+				Links: &app.GenericLinks{
+					Self:    &spaceRelatedURL,
+					Related: &spaceRelatedURL,
+				},
+			},
+		},
+	}
+}
+
+// ConvertWebhooks from internal to external REST representation
+func ConvertWebhooks(request *http.Request, hooks []webhook.Webhook) []*app.Webhook {
+	var ls = []*app.Webhook{}
+	for _, w := range hooks {
+		ls = append(ls, ConvertWebhook(request, w))
+	}
+	return ls
+}
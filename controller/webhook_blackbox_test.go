@@ -0,0 +1,119 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/webhook"
+	"github.com/goadesign/goa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type webhookSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestWebhookREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &webhookSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func newCreateWebhookPayload(url string, secret string, eventTypes ...string) *app.CreateWebhookPayload {
+	return &app.CreateWebhookPayload{
+		Data: &app.Webhook{
+			Type: webhook.APIStringTypeWebhook,
+			Attributes: &app.WebhookAttributes{
+				URL:        &url,
+				Secret:     &secret,
+				EventTypes: eventTypes,
+			},
+		},
+	}
+}
+
+func (s *webhookSuite) TestCRUD() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Spaces(1))
+	svc := testsupport.ServiceAsUser("Webhook-Service", *fxt.Identities[0])
+	ctrl := NewWebhookController(svc, s.GormDB)
+	spaceID := fxt.Spaces[0].ID
+
+	// when
+	_, created := test.CreateWebhookCreated(s.T(), svc.Context, svc, ctrl, spaceID, newCreateWebhookPayload("https://example.com/hook", "s3cr3t", "workitem.create"))
+	// then
+	require.NotNil(s.T(), created.Data.ID)
+	assert.Equal(s.T(), "https://example.com/hook", *created.Data.Attributes.URL)
+	assert.Equal(s.T(), []string{"workitem.create"}, created.Data.Attributes.EventTypes)
+	assert.Nil(s.T(), created.Data.Attributes.Secret, "the secret must never be returned")
+	webhookID := *created.Data.ID
+
+	s.T().Run("list", func(t *testing.T) {
+		_, list := test.ListWebhookOK(t, svc.Context, svc, ctrl, spaceID)
+		require.Len(t, list.Data, 1)
+		assert.Equal(t, webhookID, *list.Data[0].ID)
+	})
+
+	s.T().Run("update", func(t *testing.T) {
+		payload := &app.UpdateWebhookPayload{
+			Data: &app.Webhook{
+				Type: webhook.APIStringTypeWebhook,
+				ID:   &webhookID,
+				Attributes: &app.WebhookAttributes{
+					EventTypes: []string{"link.create", "link.delete"},
+					Version:    created.Data.Attributes.Version,
+				},
+			},
+		}
+		_, updated := test.UpdateWebhookOK(t, svc.Context, svc, ctrl, spaceID, webhookID, payload)
+		assert.Equal(t, []string{"link.create", "link.delete"}, updated.Data.Attributes.EventTypes)
+		assert.Equal(t, "https://example.com/hook", *updated.Data.Attributes.URL)
+		// the version is outdated now
+		test.UpdateWebhookConflict(t, svc.Context, svc, ctrl, spaceID, webhookID, payload)
+	})
+
+	s.T().Run("delete", func(t *testing.T) {
+		test.DeleteWebhookNoContent(t, svc.Context, svc, ctrl, spaceID, webhookID)
+		test.ShowWebhookNotFound(t, svc.Context, svc, ctrl, spaceID, webhookID)
+	})
+}
+
+func (s *webhookSuite) TestCreateFailures() {
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Spaces(1), tf.Identities(2))
+	svc := testsupport.ServiceAsUser("Webhook-Service", *fxt.Identities[0])
+	ctrl := NewWebhookController(svc, s.GormDB)
+	spaceID := fxt.Spaces[0].ID
+
+	s.T().Run("bad request", func(t *testing.T) {
+		for name, payload := range map[string]*app.CreateWebhookPayload{
+			"unknown event type": newCreateWebhookPayload("https://example.com/hook", "s3cr3t", "workitem.explode"),
+			"invalid url":        newCreateWebhookPayload("example.com", "s3cr3t"),
+			"missing secret":     {Data: &app.Webhook{Type: webhook.APIStringTypeWebhook, Attributes: &app.WebhookAttributes{URL: ptr.String("https://example.com/hook")}}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				test.CreateWebhookBadRequest(t, svc.Context, svc, ctrl, spaceID, payload)
+			})
+		}
+	})
+
+	s.T().Run("forbidden for non space owner", func(t *testing.T) {
+		svc := testsupport.ServiceAsUser("Webhook-Service", *fxt.Identities[1])
+		ctrl := NewWebhookController(svc, s.GormDB)
+		test.CreateWebhookForbidden(t, svc.Context, svc, ctrl, spaceID, newCreateWebhookPayload("https://example.com/hook", "s3cr3t"))
+		test.ListWebhookForbidden(t, svc.Context, svc, ctrl, spaceID)
+	})
+
+	s.T().Run("unauthorized", func(t *testing.T) {
+		svc := goa.New("Webhook-Service")
+		ctrl := NewWebhookController(svc, s.GormDB)
+		test.CreateWebhookUnauthorized(t, svc.Context, svc, ctrl, spaceID, newCreateWebhookPayload("https://example.com/hook", "s3cr3t"))
+	})
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"path"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-common/id"
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/attachment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space/authz"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+)
+
+// WorkItemAttachmentsController implements the work_item_attachments resource.
+type WorkItemAttachmentsController struct {
+	*goa.Controller
+	db     application.DB
+	store  attachment.BlobStore
+	config WorkItemAttachmentsControllerConfiguration
+}
+
+// WorkItemAttachmentsControllerConfiguration the configuration for the WorkItemAttachmentsController
+type WorkItemAttachmentsControllerConfiguration interface {
+	GetAttachmentMaxSize() int64
+}
+
+// NewWorkItemAttachmentsController creates a work_item_attachments controller.
+func NewWorkItemAttachmentsController(service *goa.Service, db application.DB, store attachment.BlobStore, config WorkItemAttachmentsControllerConfiguration) *WorkItemAttachmentsController {
+	return &WorkItemAttachmentsController{
+		Controller: service.NewController("WorkItemAttachmentsController"),
+		db:         db,
+		store:      store,
+		config:     config,
+	}
+}
+
+// List runs the list action.
+func (c *WorkItemAttachmentsController) List(ctx *app.ListWorkItemAttachmentsContext) error {
+	var attachments []attachment.Attachment
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.WorkItems().CheckExists(ctx, ctx.WiID); err != nil {
+			return errs.WithStack(err)
+		}
+		var err error
+		attachments, err = appl.Attachments().List(ctx, ctx.WiID)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.AttachmentList{
+		Data: ConvertAttachments(ctx.Request, attachments),
+	}
+	res.Meta = &app.WorkItemListResponseMeta{
+		TotalCount: len(res.Data),
+	}
+	return ctx.OK(res)
+}
+
+// Upload runs the upload action.
+func (c *WorkItemAttachmentsController) Upload(ctx *app.UploadWorkItemAttachmentsContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	// only keep the file name of paths sent by some clients
+	name := path.Base(strings.Replace(ctx.Name, "\\", "/", -1))
+	if name == "/" || name == "." || name == ".." {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("name", ctx.Name).Expected("file name"))
+	}
+	a := attachment.Attachment{
+		WorkItemID:  ctx.WiID,
+		Name:        name,
+		ContentType: ctx.Request.Header.Get("Content-Type"),
+		UploaderID:  *currentUser,
+	}
+	// authorize and validate the upload first, the content is stored outside
+	// of any transaction so that a slow upload doesn't keep one open
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		authorized, err := authz.Authorize(ctx, wi.SpaceID.String())
+		if err != nil {
+			return errors.NewUnauthorizedError(err.Error())
+		}
+		if !authorized {
+			return errors.NewForbiddenError("user is not a space collaborator")
+		}
+		if ctx.Comment != nil {
+			cm, err := appl.Comments().Load(ctx, *ctx.Comment)
+			if err != nil {
+				if ok, _ := errors.IsNotFoundError(err); ok {
+					return errors.NewBadParameterError("comment", *ctx.Comment)
+				}
+				return errs.WithStack(err)
+			}
+			if cm.ParentID != wi.ID {
+				return errors.NewBadParameterError("comment", *ctx.Comment).Expected("a comment of work item " + wi.ID.String())
+			}
+			a.CommentID = id.NullUUID{UUID: cm.ID, Valid: true}
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if err := attachment.PutContent(ctx, c.store, &a, ctx.Request.Body, c.config.GetAttachmentMaxSize()); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Attachments().Create(ctx, &a)
+	})
+	if err != nil {
+		// the content was stored but the attachment could not be recorded
+		if err := c.store.Delete(ctx, a.BlobKey); err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"blob_key": a.BlobKey,
+				"err":      err,
+			}, "unable to remove the content of the attachment that could not be recorded")
+		}
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.AttachmentSingle{
+		Data: ConvertAttachment(ctx.Request, a),
+	}
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.AttachmentsHref(a.ID)))
+	return ctx.Created(res)
+}
@@ -796,7 +796,7 @@ func (s *searchParentExistsSuite) TestSearchWorkItemListFilterUsingParentExists(
 		var pe *bool
 		// when
 		sid := space.SystemSpace.String()
-		test.ShowSearchBadRequest(t, nil, nil, s.searchCtrl, nil, pe, nil, nil, nil, &sid)
+		test.ShowSearchBadRequest(t, nil, nil, s.searchCtrl, nil, pe, nil, nil, nil, nil, &sid)
 	})
 	s.T().Run("with parentexists value set to false", func(t *testing.T) {
 		// given
@@ -810,7 +810,7 @@ func (s *searchParentExistsSuite) TestSearchWorkItemListFilterUsingParentExists(
 			s.fxt.Spaces[0].ID.String(),
 			s.fxt.WorkItemByTitle("bug1").Type)
 
-		_, result := test.ShowSearchOK(t, nil, nil, s.searchCtrl, &filter, &pe, nil, nil, nil, nil)
+		_, result := test.ShowSearchOK(t, nil, nil, s.searchCtrl, &filter, &pe, nil, nil, nil, nil, nil)
 		// then
 		assert.Len(t, result.Data, 1)
 		checkChildrenRelationship(t, lookupWorkitemFromSearchList(t, *result, s.fxt.WorkItemByTitle("bug1").ID), hasChildren)
@@ -829,7 +829,7 @@ func (s *searchParentExistsSuite) TestSearchWorkItemListFilterUsingParentExists(
 			s.fxt.Spaces[0].ID.String(),
 			s.fxt.WorkItemByTitle("bug1").Type)
 
-		_, result := test.ShowSearchOK(t, nil, nil, s.searchCtrl, &filter, &pe, nil, nil, nil, &sid)
+		_, result := test.ShowSearchOK(t, nil, nil, s.searchCtrl, &filter, &pe, nil, nil, nil, nil, &sid)
 		// then
 		assert.Len(t, result.Data, 3)
 		checkChildrenRelationship(t, lookupWorkitemFromSearchList(t, *result, s.fxt.WorkItemByTitle("bug1").ID), hasChildren)
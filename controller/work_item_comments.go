@@ -8,6 +8,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
 	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/login"
 	"github.com/fabric8-services/fabric8-wit/notification"
@@ -54,8 +55,10 @@ func NewNotifyingWorkItemCommentsController(service *goa.Service, db application
 // Create runs the create action.
 func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsContext) error {
 	var newComment comment.Comment
+	var msg notification.Message
+	var mentionMsgs []notification.Message
 	err := application.Transactional(c.db, func(appl application.Application) error {
-		_, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		wi, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
 		if err != nil {
 			return goa.ErrNotFound(err.Error())
 		}
@@ -73,6 +76,18 @@ func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsConte
 			if err != nil {
 				return jsonapi.JSONErrorResponse(ctx, err)
 			}
+			// replies must belong to the same work item as the comment they
+			// reply to
+			parentComment, err := appl.Comments().Load(ctx, tempUUID)
+			if err != nil {
+				if ok, _ := errors.IsNotFoundError(err); ok {
+					return errors.NewBadParameterError("parent-comment", stringUUID)
+				}
+				return err
+			}
+			if parentComment.ParentID != ctx.WiID {
+				return errors.NewBadParameterError("parent-comment", stringUUID).Expected("a comment of work item " + ctx.WiID.String())
+			}
 			parentCommentID = id.NullUUID{
 				UUID:  tempUUID,
 				Valid: true,
@@ -90,9 +105,18 @@ func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsConte
 		if err != nil {
 			return goa.ErrInternal(err.Error())
 		}
+		msg = notification.NewCommentCreated(wi.SpaceID, newComment.ID.String())
+		if err := notification.Enqueue(ctx, appl.Outbox(), msg); err != nil {
+			return err
+		}
+		mentioned, msgs, err := storeMentions(ctx, appl, wi.SpaceID, newComment, *currentUserIdentityID)
+		if err != nil {
+			return err
+		}
+		mentionMsgs = msgs
 
 		res := &app.CommentSingle{
-			Data: ConvertComment(ctx.Request, newComment),
+			Data: ConvertComment(ctx.Request, newComment, CommentIncludeMentions(map[uuid.UUID][]uuid.UUID{newComment.ID: mentioned})),
 		}
 		return ctx.OK(res)
 	})
@@ -100,7 +124,10 @@ func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsConte
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
 	if ctx.ResponseData.Status == 200 {
-		c.notification.Send(ctx, notification.NewCommentCreated(newComment.ID.String()))
+		c.notification.Send(ctx, msg)
+		for _, m := range mentionMsgs {
+			c.notification.Send(ctx, m)
+		}
 	}
 	return nil
 }
@@ -118,11 +145,15 @@ func (c *WorkItemCommentsController) List(ctx *app.ListWorkItemCommentsContext)
 		if err != nil {
 			return goa.ErrInternal(err.Error())
 		}
+		mentions, err := appl.CommentMentions().List(ctx, commentIDs(comments))
+		if err != nil {
+			return err
+		}
 		return ctx.ConditionalEntities(comments, c.config.GetCacheControlComments, func() error {
 			res := &app.CommentList{}
 			res.Data = []*app.Comment{}
 			res.Meta = &app.CommentListMeta{TotalCount: count}
-			res.Data = ConvertComments(ctx.Request, comments)
+			res.Data = ConvertComments(ctx.Request, comments, CommentIncludeMentions(mentions))
 			res.Links = &app.PagingLinks{}
 			setPagingLinks(res.Links, buildAbsoluteURL(ctx.Request), len(comments), offset, limit, count)
 			return ctx.OK(res)
@@ -134,6 +165,59 @@ func (c *WorkItemCommentsController) List(ctx *app.ListWorkItemCommentsContext)
 	return nil
 }
 
+// ListThreads runs the listThreads action.
+func (c *WorkItemCommentsController) ListThreads(ctx *app.ListThreadsWorkItemCommentsContext) error {
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		err := appl.WorkItems().CheckExists(ctx, ctx.WiID)
+		if err != nil {
+			return goa.ErrNotFound(err.Error())
+		}
+		threads, tc, err := appl.Comments().ListThreads(ctx, ctx.WiID, &offset, &limit)
+		count := int(tc)
+		if err != nil {
+			return goa.ErrInternal(err.Error())
+		}
+		replies, err := appl.Comments().ListReplies(ctx, commentIDs(threads))
+		if err != nil {
+			return err
+		}
+		all := append(append([]comment.Comment{}, threads...), replies...)
+		mentions, err := appl.CommentMentions().List(ctx, commentIDs(all))
+		if err != nil {
+			return err
+		}
+		// a changed reply changes the response as well
+		return ctx.ConditionalEntities(all, c.config.GetCacheControlComments, func() error {
+			includeReplies := CommentIncludeReplies(replies)
+			includeMentions := CommentIncludeMentions(mentions)
+			res := &app.CommentList{}
+			res.Meta = &app.CommentListMeta{TotalCount: count}
+			res.Data = ConvertComments(ctx.Request, threads, includeReplies, includeMentions)
+			res.Included = []interface{}{}
+			for _, reply := range replies {
+				res.Included = append(res.Included, ConvertComment(ctx.Request, reply, includeReplies, includeMentions))
+			}
+			res.Links = &app.PagingLinks{}
+			setPagingLinks(res.Links, buildAbsoluteURL(ctx.Request), len(threads), offset, limit, count)
+			return ctx.OK(res)
+		})
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return nil
+}
+
+// commentIDs returns the IDs of the given comments
+func commentIDs(comments []comment.Comment) []uuid.UUID {
+	ids := make([]uuid.UUID, len(comments))
+	for i, c := range comments {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
 // Relations runs the relation action.
 // TODO: Should only return Resource Identifier Objects, not complete object (See List)
 func (c *WorkItemCommentsController) Relations(ctx *app.RelationsWorkItemCommentsContext) error {
@@ -351,3 +351,83 @@ func (rest *TestCommentREST) TestListCommentsByMissingParentWorkItem() {
 	limit := 1
 	test.ListWorkItemCommentsNotFound(rest.T(), svc.Context, svc, ctrl, uuid.NewV4(), &limit, &offset, nil, nil)
 }
+
+func (rest *TestCommentREST) TestCreateCommentWithMentions() {
+	// given
+	fxt := tf.NewTestFixture(rest.T(), rest.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1), tf.Identities(2, func(fxt *tf.TestFixture, idx int) error {
+		fxt.Identities[idx].Username = uuid.NewV4().String()
+		return nil
+	}))
+	wi := fxt.WorkItems[0]
+	rest.notification.Messages = nil
+	body := "@" + fxt.Identities[0].Username + " and @" + fxt.Identities[1].Username + " please have a look, @unknown-user too. But not `@" + fxt.Identities[1].Username + "`"
+	p := rest.newCreateWorkItemCommentsPayload(body, ptr.String(rendering.SystemMarkupMarkdown))
+	svc, ctrl := rest.SecuredController()
+	// when
+	_, c := test.CreateWorkItemCommentsOK(rest.T(), svc.Context, svc, ctrl, wi.ID, p)
+	// then
+	require.NotNil(rest.T(), c.Data.Relationships.Mentions)
+	require.Len(rest.T(), c.Data.Relationships.Mentions.Data, 2)
+	assert.Equal(rest.T(), fxt.Identities[0].ID.String(), *c.Data.Relationships.Mentions.Data[0].ID)
+	assert.Equal(rest.T(), fxt.Identities[1].ID.String(), *c.Data.Relationships.Mentions.Data[1].ID)
+	require.Len(rest.T(), rest.notification.Messages, 3)
+	assert.Equal(rest.T(), "comment.create", rest.notification.Messages[0].MessageType)
+	for i, msg := range rest.notification.Messages[1:] {
+		assert.Equal(rest.T(), "comment.mention", msg.MessageType)
+		assert.Equal(rest.T(), c.Data.ID.String(), msg.TargetID)
+		assert.Equal(rest.T(), wi.SpaceID, msg.SpaceID)
+		assert.Equal(rest.T(), fxt.Identities[i].ID, msg.Custom["mentioned_identity_id"])
+	}
+}
+
+func (rest *TestCommentREST) TestCreateCommentWithParentCommentOfOtherWorkItem() {
+	// given
+	fxt := tf.NewTestFixture(rest.T(), rest.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(2), tf.Comments(1))
+	p := rest.newCreateWorkItemCommentsPayload("Test Child", nil)
+	p.Data.Relationships = &app.CreateCommentRelations{
+		ParentComment: &app.RelationGeneric{
+			Data: &app.GenericData{
+				Type: ptr.String("comments"),
+				ID:   ptr.String(fxt.Comments[0].ID.String()),
+			},
+		},
+	}
+	svc, ctrl := rest.SecuredController()
+	// when/then
+	test.CreateWorkItemCommentsBadRequest(rest.T(), svc.Context, svc, ctrl, fxt.WorkItems[1].ID, p)
+}
+
+func (rest *TestCommentREST) TestListThreads() {
+	// given a thread of a comment, two replies to it and a reply to the first
+	// reply followed by a second thread without replies
+	fxt := tf.NewTestFixture(rest.T(), rest.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1), tf.Comments(5, func(fxt *tf.TestFixture, idx int) error {
+		switch idx {
+		case 1, 2:
+			fxt.Comments[idx].ParentCommentID = id.NullUUID{UUID: fxt.Comments[0].ID, Valid: true}
+		case 3:
+			fxt.Comments[idx].ParentCommentID = id.NullUUID{UUID: fxt.Comments[1].ID, Valid: true}
+		}
+		return nil
+	}))
+	svc, ctrl := rest.UnSecuredController()
+	// when
+	res, cs := test.ListThreadsWorkItemCommentsOK(rest.T(), svc.Context, svc, ctrl, fxt.WorkItems[0].ID, nil, nil, nil, nil)
+	// then
+	assertResponseHeaders(rest.T(), res)
+	assert.Equal(rest.T(), 2, cs.Meta.TotalCount)
+	require.Len(rest.T(), cs.Data, 2)
+	assert.Equal(rest.T(), fxt.Comments[4].ID, *cs.Data[0].ID)
+	assert.Empty(rest.T(), cs.Data[0].Relationships.Replies.Data)
+	assert.Equal(rest.T(), fxt.Comments[0].ID, *cs.Data[1].ID)
+	replies := cs.Data[1].Relationships.Replies
+	require.Len(rest.T(), replies.Data, 2)
+	assert.Equal(rest.T(), 2, replies.Meta["totalCount"])
+	assert.Equal(rest.T(), fxt.Comments[1].ID.String(), *replies.Data[0].ID)
+	assert.Equal(rest.T(), fxt.Comments[2].ID.String(), *replies.Data[1].ID)
+	require.Len(rest.T(), cs.Included, 3)
+	reply, ok := cs.Included[0].(*app.Comment)
+	require.True(rest.T(), ok)
+	assert.Equal(rest.T(), fxt.Comments[1].ID, *reply.ID)
+	require.Len(rest.T(), reply.Relationships.Replies.Data, 1)
+	assert.Equal(rest.T(), fxt.Comments[3].ID.String(), *reply.Relationships.Replies.Data[0].ID)
+}
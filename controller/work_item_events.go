@@ -11,6 +11,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/event"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	"github.com/goadesign/goa"
 	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
@@ -141,6 +142,68 @@ func ConvertEvent(ctx context.Context, appl application.Application, req *http.R
 		return &e, nil
 	}
 
+	switch wiEvent.Name {
+	case event.LinkAddedEvent, event.LinkRemovedEvent:
+		linkID := wiEvent.TargetID.String()
+		e.Relationships.Link = &app.RelationGeneric{
+			Links: &app.GenericLinks{
+				Self: ptr.String(rest.AbsoluteURL(req, app.WorkItemLinkHref(linkID))),
+			},
+			Data: &app.GenericData{
+				ID:   ptr.String(linkID),
+				Type: ptr.String(link.EndpointWorkItemLinks),
+			},
+		}
+		// the old or new value is the work item at the other end of the link
+		convertWorkItem := func(val interface{}) (*app.RelationGenericList, error) {
+			id, ok := val.(uuid.UUID)
+			if !ok {
+				return nil, errs.Errorf("failed to convert linked work item ID to UUID: %s", val)
+			}
+			return &app.RelationGenericList{
+				Data: []*app.GenericData{
+					{
+						ID:    ptr.String(id.String()),
+						Type:  ptr.String(APIStringTypeWorkItem),
+						Links: &app.GenericLinks{Self: ptr.String(rest.AbsoluteURL(req, app.WorkitemHref(id.String())))},
+					},
+				},
+			}, nil
+		}
+		if wiEvent.Old != nil {
+			e.Relationships.OldValue, err = convertWorkItem(wiEvent.Old)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if wiEvent.New != nil {
+			e.Relationships.NewValue, err = convertWorkItem(wiEvent.New)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &e, nil
+	case event.CommentAddedEvent, event.CommentEditedEvent, event.CommentDeletedEvent:
+		commentID := wiEvent.TargetID.String()
+		e.Relationships.Comment = &app.RelationGeneric{
+			Links: &app.GenericLinks{
+				Self: ptr.String(rest.AbsoluteURL(req, app.CommentsHref(commentID))),
+			},
+			Data: &app.GenericData{
+				ID:   ptr.String(commentID),
+				Type: ptr.String(APIStringTypeComments),
+			},
+		}
+		// the old and new values are the bodies of the comment
+		if wiEvent.Old != nil {
+			e.Attributes.OldValue = ptr.Interface(wiEvent.Old)
+		}
+		if wiEvent.New != nil {
+			e.Attributes.NewValue = ptr.Interface(wiEvent.New)
+		}
+		return &e, nil
+	}
+
 	fieldName := wiEvent.Name
 	fieldDef, ok := wit.Fields[fieldName]
 	if !ok {
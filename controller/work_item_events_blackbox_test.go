@@ -16,6 +16,8 @@ import (
 	testsupport "github.com/fabric8-services/fabric8-wit/test"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/event"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -689,4 +691,31 @@ func (s *TestEvent) TestListEvent() {
 		compareWithGoldenAgnostic(t, filepath.Join(s.testDir, "list", "ok-witype-change.res.payload.golden.json"), eventList)
 		compareWithGoldenAgnostic(t, filepath.Join(s.testDir, "list", "ok-witype-change.res.headers.golden.json"), res.Header())
 	})
+
+	s.T().Run("event list ok - link and comment", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(2), tf.WorkItemLinks(1), tf.Comments(1))
+		svc := testsupport.ServiceAsSpaceUser("Event-Service", *fxt.Identities[0], &TestSpaceAuthzService{*fxt.Identities[0], ""})
+		EventCtrl := NewEventsController(svc, s.GormDB, s.Configuration)
+		_, eventList := test.ListWorkItemEventsOK(t, svc.Context, svc, EventCtrl, fxt.WorkItems[0].ID, nil, nil, nil)
+		require.Len(t, eventList.Data, 2)
+		// the link was created before the comment
+		linkEvent := eventList.Data[0]
+		assert.Equal(t, event.LinkAddedEvent, linkEvent.Attributes.Name)
+		require.NotNil(t, linkEvent.Relationships.Link)
+		assert.Equal(t, fxt.WorkItemLinks[0].ID.String(), *linkEvent.Relationships.Link.Data.ID)
+		assert.Equal(t, link.EndpointWorkItemLinks, *linkEvent.Relationships.Link.Data.Type)
+		assert.Nil(t, linkEvent.Relationships.OldValue)
+		require.NotNil(t, linkEvent.Relationships.NewValue)
+		require.Len(t, linkEvent.Relationships.NewValue.Data, 1)
+		assert.Equal(t, fxt.WorkItems[1].ID.String(), *linkEvent.Relationships.NewValue.Data[0].ID)
+		assert.Equal(t, APIStringTypeWorkItem, *linkEvent.Relationships.NewValue.Data[0].Type)
+		commentEvent := eventList.Data[1]
+		assert.Equal(t, event.CommentAddedEvent, commentEvent.Attributes.Name)
+		require.NotNil(t, commentEvent.Relationships.Comment)
+		assert.Equal(t, fxt.Comments[0].ID.String(), *commentEvent.Relationships.Comment.Data.ID)
+		assert.Equal(t, APIStringTypeComments, *commentEvent.Relationships.Comment.Data.Type)
+		assert.Nil(t, commentEvent.Attributes.OldValue)
+		require.NotNil(t, commentEvent.Attributes.NewValue)
+		assert.Equal(t, fxt.Comments[0].Body, *commentEvent.Attributes.NewValue)
+	})
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/notification"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/space/authz"
 	"github.com/fabric8-services/fabric8-wit/workitem"
@@ -21,8 +22,9 @@ import (
 // WorkItemLinkController implements the work-item-link resource.
 type WorkItemLinkController struct {
 	*goa.Controller
-	db     application.DB
-	config WorkItemLinkControllerConfig
+	db           application.DB
+	notification notification.Channel
+	config       WorkItemLinkControllerConfig
 }
 
 // WorkItemLinkControllerConfig the config interface for the WorkitemLinkController
@@ -33,10 +35,20 @@ type WorkItemLinkControllerConfig interface {
 
 // NewWorkItemLinkController creates a work-item-link controller.
 func NewWorkItemLinkController(service *goa.Service, db application.DB, config WorkItemLinkControllerConfig) *WorkItemLinkController {
+	return NewNotifyingWorkItemLinkController(service, db, &notification.DevNullChannel{}, config)
+}
+
+// NewNotifyingWorkItemLinkController creates a work-item-link controller with notification broadcast.
+func NewNotifyingWorkItemLinkController(service *goa.Service, db application.DB, notificationChannel notification.Channel, config WorkItemLinkControllerConfig) *WorkItemLinkController {
+	n := notificationChannel
+	if n == nil {
+		n = &notification.DevNullChannel{}
+	}
 	return &WorkItemLinkController{
-		Controller: service.NewController("WorkItemLinkController"),
-		db:         db,
-		config:     config,
+		Controller:   service.NewController("WorkItemLinkController"),
+		db:           db,
+		notification: n,
+		config:       config,
 	}
 }
 
@@ -234,14 +246,24 @@ func (c *WorkItemLinkController) Create(ctx *app.CreateWorkItemLinkContext) erro
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
 	var createdModelLink *link.WorkItemLink
+	var msg notification.Message
 	err = application.Transactional(c.db, func(appl application.Application) error {
 		var err error
 		createdModelLink, err = appl.WorkItemLinks().Create(ctx.Context, modelLink.SourceID, modelLink.TargetID, modelLink.LinkTypeID, *currentUserIdentityID)
-		return err
+		if err != nil {
+			return err
+		}
+		source, err := appl.WorkItems().LoadByID(ctx.Context, createdModelLink.SourceID)
+		if err != nil {
+			return err
+		}
+		msg = notification.NewLinkCreated(source.SpaceID, createdModelLink.ID.String(), createdModelLink.SourceID, createdModelLink.TargetID)
+		return notification.Enqueue(ctx, appl.Outbox(), msg)
 	})
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
+	c.notification.Send(ctx, msg)
 	// convert from model to rest representation
 	createdAppLink := ConvertLinkFromModel(ctx.Request, *createdModelLink)
 	if err := enrichLinkSingle(ctx.Context, c.db, ctx.Request, &createdAppLink); err != nil {
@@ -319,12 +341,26 @@ func (c *WorkItemLinkController) Delete(ctx *app.DeleteWorkItemLinkContext) erro
 	if !authorized {
 		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to delete the link"))
 	}
+	var msg notification.Message
 	err = application.Transactional(c.db, func(appl application.Application) error {
-		return appl.WorkItemLinks().Delete(ctx.Context, ctx.LinkID, *currentUserIdentityID)
+		deletedLink, err := appl.WorkItemLinks().Load(ctx.Context, ctx.LinkID)
+		if err != nil {
+			return err
+		}
+		source, err := appl.WorkItems().LoadByID(ctx.Context, deletedLink.SourceID)
+		if err != nil {
+			return err
+		}
+		if err := appl.WorkItemLinks().Delete(ctx.Context, ctx.LinkID, *currentUserIdentityID); err != nil {
+			return err
+		}
+		msg = notification.NewLinkDeleted(source.SpaceID, deletedLink.ID.String(), deletedLink.SourceID, deletedLink.TargetID)
+		return notification.Enqueue(ctx, appl.Outbox(), msg)
 	})
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
+	c.notification.Send(ctx, msg)
 	return ctx.OK([]byte{})
 }
 
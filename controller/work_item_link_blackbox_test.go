@@ -17,6 +17,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	notificationsupport "github.com/fabric8-services/fabric8-wit/test/notification"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
 	testtoken "github.com/fabric8-services/fabric8-wit/test/token"
 	"github.com/fabric8-services/fabric8-wit/workitem"
@@ -263,6 +264,36 @@ func (s *workItemLinkSuite) TestDelete() {
 	})
 }
 
+func (s *workItemLinkSuite) TestNotifications() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(2), tf.WorkItemLinkTypes(1))
+	svc := testsupport.ServiceAsUser("WorkItemLink-Service", *fxt.Identities[0])
+	channel := notificationsupport.FakeNotificationChannel{}
+	ctrl := NewNotifyingWorkItemLinkController(svc, s.GormDB, &channel, s.Configuration)
+
+	s.T().Run("link created", func(t *testing.T) {
+		// when
+		_, l := test.CreateWorkItemLinkCreated(t, svc.Context, svc, ctrl, newCreateWorkItemLinkPayload(fxt.WorkItems[0].ID, fxt.WorkItems[1].ID, fxt.WorkItemLinkTypes[0].ID))
+		// then
+		require.Len(t, channel.Messages, 1)
+		require.Equal(t, "link.create", channel.Messages[0].MessageType)
+		require.Equal(t, l.Data.ID.String(), channel.Messages[0].TargetID)
+		require.Equal(t, fxt.Spaces[0].ID, channel.Messages[0].SpaceID)
+		require.Equal(t, fxt.WorkItems[0].ID, channel.Messages[0].Custom["source_id"])
+		require.Equal(t, fxt.WorkItems[1].ID, channel.Messages[0].Custom["target_id"])
+
+		t.Run("link deleted", func(t *testing.T) {
+			// when
+			test.DeleteWorkItemLinkOK(t, svc.Context, svc, ctrl, *l.Data.ID)
+			// then
+			require.Len(t, channel.Messages, 2)
+			require.Equal(t, "link.delete", channel.Messages[1].MessageType)
+			require.Equal(t, l.Data.ID.String(), channel.Messages[1].TargetID)
+			require.Equal(t, fxt.Spaces[0].ID, channel.Messages[1].SpaceID)
+		})
+	})
+}
+
 func (s *workItemLinkSuite) TestShow() {
 	s.T().Run(http.StatusText(http.StatusOK), func(t *testing.T) {
 		t.Run("normal", func(t *testing.T) {
@@ -169,6 +169,7 @@ func (c *WorkitemController) Update(ctx *app.UpdateWorkitemContext) error {
 
 	}
 	var rev *workitem.Revision
+	var msg notification.Message
 	err = application.Transactional(c.db, func(appl application.Application) error {
 		// The Number of a work item is not allowed to be changed which is why
 		// we overwrite the values with its old value after the work item was
@@ -183,7 +184,8 @@ func (c *WorkitemController) Update(ctx *app.UpdateWorkitemContext) error {
 		if err != nil {
 			return errs.Wrap(err, "Error updating work item")
 		}
-		return nil
+		msg = notification.NewWorkItemUpdated(wi.SpaceID, wi.ID.String(), rev.ID)
+		return notification.Enqueue(ctx, appl.Outbox(), msg)
 	})
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
@@ -192,7 +194,7 @@ func (c *WorkitemController) Update(ctx *app.UpdateWorkitemContext) error {
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to load work item type: %s", wi.Type))
 	}
-	c.notification.Send(ctx, notification.NewWorkItemUpdated(ctx.Payload.Data.ID.String(), rev.ID))
+	c.notification.Send(ctx, msg)
 	converted, err := ConvertWorkItem(ctx.Request, *wit, *wi, workItemIncludeHasChildren(ctx, c.db))
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
@@ -207,6 +209,66 @@ func (c *WorkitemController) Update(ctx *app.UpdateWorkitemContext) error {
 	return ctx.OK(resp)
 }
 
+// Revert does POST workitem/revert
+func (c *WorkitemController) Revert(ctx *app.RevertWorkitemContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	wi, err := c.db.WorkItems().LoadByID(ctx, ctx.WiID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	creatorIDStr, ok := wi.Fields[workitem.SystemCreator].(string)
+	if !ok {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewInternalError(ctx, errs.New("work item doesn't have creator")))
+	}
+	creatorID, err := uuid.FromString(creatorIDStr)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	authorized, err := authorizeWorkitemEditor(ctx, c.db, wi.SpaceID, creatorIDStr, currentUserIdentityID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
+	}
+	// like in an update only the creator and the space owner can change the
+	// type of a work item
+	if ctx.RestoreType {
+		if err := c.WorkitemCreatorOrSpaceOwner(ctx, wi.SpaceID, creatorID, *currentUserIdentityID); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+	}
+	var rev *workitem.Revision
+	var msg notification.Message
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, rev, err = appl.WorkItems().Revert(ctx, ctx.WiID, ctx.RevisionID, ctx.Version, ctx.RestoreType, *currentUserIdentityID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to revert work item %s to revision %s", ctx.WiID, ctx.RevisionID)
+		}
+		msg = notification.NewWorkItemUpdated(wi.SpaceID, wi.ID.String(), rev.ID)
+		return notification.Enqueue(ctx, appl.Outbox(), msg)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	wit, err := c.db.WorkItemTypes().Load(ctx.Context, wi.Type)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to load work item type: %s", wi.Type))
+	}
+	c.notification.Send(ctx, msg)
+	converted, err := ConvertWorkItem(ctx.Request, *wit, *wi, workItemIncludeHasChildren(ctx, c.db))
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	ctx.ResponseData.Header().Set("Last-Modified", lastModified(*wi))
+	return ctx.OK(&app.WorkItemSingle{
+		Data: converted,
+	})
+}
+
 // Show does GET workitem
 func (c *WorkitemController) Show(ctx *app.ShowWorkitemContext) error {
 	var wi *workitem.WorkItem
@@ -281,6 +343,7 @@ func (c *WorkitemController) Delete(ctx *app.DeleteWorkitemContext) error {
 		}
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
+	msg := notification.NewWorkItemDeleted(wi.SpaceID, ctx.WiID.String())
 	err = application.Transactional(c.db, func(appl application.Application) error {
 		if err := appl.WorkItemLinks().DeleteRelatedLinks(ctx, ctx.WiID, *currentUserIdentityID); err != nil {
 			return errs.Wrapf(err, "failed to delete work item links related to work item %s", ctx.WiID)
@@ -288,11 +351,12 @@ func (c *WorkitemController) Delete(ctx *app.DeleteWorkitemContext) error {
 		if err := appl.WorkItems().Delete(ctx, ctx.WiID, *currentUserIdentityID); err != nil {
 			return errs.Wrapf(err, "error deleting work item %s", ctx.WiID)
 		}
-		return nil
+		return notification.Enqueue(ctx, appl.Outbox(), msg)
 	})
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
+	c.notification.Send(ctx, msg)
 	return ctx.OK([]byte{})
 }
 
@@ -1110,6 +1174,38 @@ func workItemIncludeEvents(request *http.Request, wi *workitem.WorkItem, wi2 *ap
 	}
 }
 
+// parseSortWorkItemsBy parses the given sort order of the work items of the
+// given spaces (see workitem.ParseSortWorkItemsBy). Only the fields of the
+// work item types of the space templates of these spaces can be used for
+// sorting.
+func parseSortWorkItemsBy(ctx context.Context, db application.DB, sort *string, spaceIDs ...uuid.UUID) (workitem.SortWorkItemsBy, error) {
+	if sort == nil {
+		return workitem.ParseSortWorkItemsBy(nil, nil)
+	}
+	var wits []workitem.WorkItemType
+	err := application.Transactional(db, func(appl application.Application) error {
+		for _, spaceID := range spaceIDs {
+			s, err := appl.Spaces().Load(ctx, spaceID)
+			if err != nil {
+				if ok, _ := errors.IsNotFoundError(err); ok {
+					continue
+				}
+				return errs.WithStack(err)
+			}
+			spaceWITs, err := appl.WorkItemTypes().List(ctx, s.SpaceTemplateID)
+			if err != nil {
+				return errs.Wrapf(err, "failed to load the work item types of space template %s", s.SpaceTemplateID)
+			}
+			wits = append(wits, spaceWITs...)
+		}
+		return nil
+	})
+	if err != nil {
+		return workitem.SortWorkItemsBy(""), err
+	}
+	return workitem.ParseSortWorkItemsBy(sort, wits)
+}
+
 func loadWorkItemTypesFromArr(ctx context.Context, appl application.Application, wis []workitem.WorkItem) ([]workitem.WorkItemType, error) {
 	wits := make([]workitem.WorkItemType, len(wis))
 	for idx, wi := range wis {
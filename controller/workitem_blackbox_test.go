@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"html"
 	"net/http"
+	"net/url"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -533,6 +534,110 @@ func (s *WorkItemSuite) TestReorderWorkitemNotFoundOK() {
 	test.ReorderWorkitemsNotFound(s.T(), s.svc.Context, s.svc, s.workitemsCtrl, fxt.Spaces[0].ID, &payload2)
 }
 
+func (s *WorkItemSuite) TestBulkUpdateWorkitems() {
+	newPayload := func(attrs app.WorkItemBulkUpdateAttributes) *app.BulkUpdateWorkitemsPayload {
+		if attrs.Patch == nil {
+			attrs.Patch = &app.WorkItem{
+				Type:       APIStringTypeWorkItem,
+				Attributes: map[string]interface{}{workitem.SystemState: workitem.SystemStateClosed},
+			}
+		}
+		return &app.BulkUpdateWorkitemsPayload{
+			Data: &app.WorkItemBulkUpdate{
+				Type:       "workitembulkupdates",
+				Attributes: &attrs,
+			},
+		}
+	}
+
+	s.T().Run("ok - items with versions", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(3, tf.SetWorkItemField(workitem.SystemState, workitem.SystemStateNew, workitem.SystemStateNew, workitem.SystemStateNew)))
+		payload := newPayload(app.WorkItemBulkUpdateAttributes{
+			Items: []*app.WorkItemBulkUpdateItem{
+				{ID: fxt.WorkItems[0].ID, Version: ptr.Int(fxt.WorkItems[0].Version)},
+				{ID: fxt.WorkItems[1].ID, Version: ptr.Int(fxt.WorkItems[1].Version + 10)},
+			},
+		})
+		// when
+		_, res := test.BulkUpdateWorkitemsOK(t, s.svc.Context, s.svc, s.workitemsCtrl, fxt.Spaces[0].ID, payload)
+		// then
+		require.Len(t, res.Data.Attributes.Results, 2)
+		updated := res.Data.Attributes.Results[0]
+		assert.Equal(t, fxt.WorkItems[0].ID, updated.ID)
+		assert.Equal(t, "updated", updated.Status)
+		require.NotNil(t, updated.Version)
+		assert.Equal(t, fxt.WorkItems[0].Version+1, *updated.Version)
+		conflict := res.Data.Attributes.Results[1]
+		assert.Equal(t, fxt.WorkItems[1].ID, conflict.ID)
+		assert.Equal(t, "conflict", conflict.Status)
+		assert.NotNil(t, conflict.Detail)
+		for idx, expected := range []string{workitem.SystemStateClosed, workitem.SystemStateNew, workitem.SystemStateNew} {
+			wi, err := s.repoWit.LoadByID(s.Ctx, fxt.WorkItems[idx].ID)
+			require.NoError(t, err)
+			assert.Equal(t, expected, wi.Fields[workitem.SystemState], "unexpected state of work item %d", idx)
+		}
+	})
+
+	s.T().Run("ok - filter", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(3, tf.SetWorkItemField(workitem.SystemState, workitem.SystemStateNew, workitem.SystemStateOpen, workitem.SystemStateOpen)))
+		filter := fmt.Sprintf(`{"state": "%s"}`, workitem.SystemStateOpen)
+		payload := newPayload(app.WorkItemBulkUpdateAttributes{Filter: &filter})
+		// when
+		_, res := test.BulkUpdateWorkitemsOK(t, s.svc.Context, s.svc, s.workitemsCtrl, fxt.Spaces[0].ID, payload)
+		// then
+		require.Len(t, res.Data.Attributes.Results, 2)
+		for _, r := range res.Data.Attributes.Results {
+			assert.Equal(t, "updated", r.Status)
+		}
+		for idx, expected := range []string{workitem.SystemStateNew, workitem.SystemStateClosed, workitem.SystemStateClosed} {
+			wi, err := s.repoWit.LoadByID(s.Ctx, fxt.WorkItems[idx].ID)
+			require.NoError(t, err)
+			assert.Equal(t, expected, wi.Fields[workitem.SystemState], "unexpected state of work item %d", idx)
+		}
+	})
+
+	s.T().Run("fail - invalid value", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+		payload := newPayload(app.WorkItemBulkUpdateAttributes{
+			Items: []*app.WorkItemBulkUpdateItem{{ID: fxt.WorkItems[0].ID}},
+			Patch: &app.WorkItem{
+				Type:       APIStringTypeWorkItem,
+				Attributes: map[string]interface{}{workitem.SystemState: "unknown state"},
+			},
+		})
+		// when
+		_, res := test.BulkUpdateWorkitemsOK(t, s.svc.Context, s.svc, s.workitemsCtrl, fxt.Spaces[0].ID, payload)
+		// then
+		require.Len(t, res.Data.Attributes.Results, 1)
+		assert.Equal(t, "failed", res.Data.Attributes.Results[0].Status)
+	})
+
+	s.T().Run("fail - bad request", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1), tf.Spaces(2))
+		filter := `{"state": "open"}`
+		for name, tc := range map[string]struct {
+			spaceID uuid.UUID
+			attrs   app.WorkItemBulkUpdateAttributes
+		}{
+			"neither filter nor items": {fxt.Spaces[0].ID, app.WorkItemBulkUpdateAttributes{}},
+			"filter and items":         {fxt.Spaces[0].ID, app.WorkItemBulkUpdateAttributes{Filter: &filter, Items: []*app.WorkItemBulkUpdateItem{{ID: fxt.WorkItems[0].ID}}}},
+			"version in patch": {fxt.Spaces[0].ID, app.WorkItemBulkUpdateAttributes{Filter: &filter, Patch: &app.WorkItem{
+				Type:       APIStringTypeWorkItem,
+				Attributes: map[string]interface{}{workitem.SystemVersion: 1},
+			}}},
+			"item of another space": {fxt.Spaces[1].ID, app.WorkItemBulkUpdateAttributes{Items: []*app.WorkItemBulkUpdateItem{{ID: fxt.WorkItems[0].ID}}}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				test.BulkUpdateWorkitemsBadRequest(t, s.svc.Context, s.svc, s.workitemsCtrl, tc.spaceID, newPayload(tc.attrs))
+			})
+		}
+	})
+}
+
 // TestUpdateWorkitemWithoutReorder tests that when workitem is updated, execution order of workitem doesnot change.
 func (s *WorkItemSuite) TestUpdateWorkitemWithoutReorder() {
 
@@ -2000,6 +2105,39 @@ func (s *WorkItem2Suite) TestListOrder() {
 
 		})
 	})
+
+	s.T().Run("list by field", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemTypes[idx].Fields["effort"] = workitem.FieldDefinition{
+					Label: "Effort",
+					Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+				}
+				return nil
+			}),
+			tf.WorkItems(3, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields["effort"] = float64(idx)
+				return nil
+			}),
+		)
+
+		t.Run("field of a work item type of the space", func(t *testing.T) {
+			// when
+			_, actualWIs := test.ListWorkitemsOK(t, s.svc.Context, s.svc, s.workitemsCtrl, fxt.Spaces[0].ID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, ptr.String("-effort"), nil, nil)
+			// then
+			require.Len(t, actualWIs.Data, 3)
+			for i, v := range []int{2, 1, 0} {
+				require.Equal(t, fxt.WorkItems[v].ID, *actualWIs.Data[i].ID)
+			}
+		})
+
+		t.Run("unknown field", func(t *testing.T) {
+			// when
+			_, jerrs := test.ListWorkitemsBadRequest(t, s.svc.Context, s.svc, s.workitemsCtrl, fxt.Spaces[0].ID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, ptr.String("-priority"), nil, nil)
+			// then
+			require.NotEmpty(t, jerrs.Errors)
+		})
+	})
 }
 func (s *WorkItem2Suite) setupAreaWorkItem(createWorkItem bool) (uuid.UUID, string, *app.WorkItemSingle) {
 	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Areas(1))
@@ -3140,6 +3278,16 @@ func (s *WorkItem2Suite) TestWI2FilterExpressionRedirection() {
 	assert.Contains(s.T(), location, expectedLocation)
 }
 
+func (s *WorkItem2Suite) TestWI2TextQueryRedirection() {
+	c := minimumRequiredCreatePayload()
+	queryExpression := `title:"a & b" OR state:open`
+	respWriter := test.ListWorkitemsTemporaryRedirect(s.T(), s.svc.Context, s.svc, s.workitemsCtrl, *c.Data.Relationships.Space.Data.ID, nil, nil, nil, &queryExpression, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	location, err := url.Parse(respWriter.Header().Get("location"))
+	require.NoError(s.T(), err)
+	expected := fmt.Sprintf(`space:%s AND (%s)`, *c.Data.Relationships.Space.Data.ID, queryExpression)
+	assert.Equal(s.T(), expected, location.Query().Get("filter[expression]"))
+}
+
 func (s *WorkItem2Suite) TestNotificationSentOnCreate() {
 	// given
 	// Default created WI in setupTest
@@ -3170,6 +3318,54 @@ func (s *WorkItem2Suite) TestNotificationSendOnUpdate() {
 	assert.Equal(s.T(), s.wi.ID.String(), s.notification.Messages[1].TargetID)
 }
 
+func (s *WorkItem2Suite) TestRevert() {
+	// given a work item whose title was changed
+	s.minimumPayload.Data.Attributes[workitem.SystemTitle] = "Changed title"
+	_, updated := test.UpdateWorkitemOK(s.T(), s.svc.Context, s.svc, s.workitemCtrl, *s.wi.ID, s.minimumPayload)
+	version, ok := updated.Data.Attributes[workitem.SystemVersion].(int)
+	require.True(s.T(), ok)
+	revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, *s.wi.ID)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), revisions, 2)
+
+	s.T().Run("ok", func(t *testing.T) {
+		// when
+		_, reverted := test.RevertWorkitemOK(t, s.svc.Context, s.svc, s.workitemCtrl, *s.wi.ID, false, revisions[0].ID, version)
+		// then
+		require.NotNil(t, reverted)
+		assert.Equal(t, "Test WI", reverted.Data.Attributes[workitem.SystemTitle])
+		assert.Equal(t, version+1, reverted.Data.Attributes[workitem.SystemVersion])
+		require.Equal(t, 3, len(s.notification.Messages))
+		assert.Equal(t, "workitem.update", s.notification.Messages[2].MessageType)
+		assert.Equal(t, s.wi.ID.String(), s.notification.Messages[2].TargetID)
+	})
+	s.T().Run("version conflict", func(t *testing.T) {
+		test.RevertWorkitemConflict(t, s.svc.Context, s.svc, s.workitemCtrl, *s.wi.ID, false, revisions[1].ID, version)
+	})
+	s.T().Run("unknown revision", func(t *testing.T) {
+		test.RevertWorkitemNotFound(t, s.svc.Context, s.svc, s.workitemCtrl, *s.wi.ID, false, uuid.NewV4(), version+1)
+	})
+	s.T().Run("unknown work item", func(t *testing.T) {
+		test.RevertWorkitemNotFound(t, s.svc.Context, s.svc, s.workitemCtrl, uuid.NewV4(), false, revisions[0].ID, version+1)
+	})
+}
+
+func (s *WorkItem2Suite) TestNotificationSentOnDelete() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(1))
+	svc := testsupport.ServiceAsUser("TestDeleteWI2-Service", *fxt.Identities[0])
+
+	// when
+	test.DeleteWorkitemOK(s.T(), svc.Context, svc, s.workitemCtrl, fxt.WorkItems[0].ID)
+
+	// then
+	require.Equal(s.T(), 2, len(s.notification.Messages))
+	// index 0 is workitem.create, index 1 should be workitem.delete
+	assert.Equal(s.T(), "workitem.delete", s.notification.Messages[1].MessageType)
+	assert.Equal(s.T(), fxt.WorkItems[0].ID.String(), s.notification.Messages[1].TargetID)
+	assert.Equal(s.T(), fxt.Spaces[0].ID, s.notification.Messages[1].SpaceID)
+}
+
 func minimumRequiredCreatePayloadWithSpace(spaceID uuid.UUID) app.CreateWorkitemsPayload {
 	spaceSelfURL := rest.AbsoluteURL(&http.Request{Host: "api.service.domain.org"}, app.SpaceHref(spaceID.String()))
 	return app.CreateWorkitemsPayload{
@@ -0,0 +1,456 @@
+package controller
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/codebase"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// csvTypeColumn is the header of the column that holds the name of the
+	// work item type (see ConvertWorkItemsToCSV)
+	csvTypeColumn = "_Type"
+	// csvMoreNote is the prefix of the note that is added to paged CSV exports
+	csvMoreNote = "WIT_NOTE_MORE:"
+	// csvListSeparator separates the elements of list values
+	csvListSeparator = ";"
+)
+
+// csvIgnoredFields are the fields that are maintained by the system and that
+// are therefore never set from a CSV
+var csvIgnoredFields = map[string]struct{}{
+	workitem.SystemNumber:    {},
+	workitem.SystemCreator:   {},
+	workitem.SystemCreatedAt: {},
+	workitem.SystemUpdatedAt: {},
+	workitem.SystemOrder:     {},
+}
+
+// WorkItemImportResult holds the outcome of a CSV import
+type WorkItemImportResult struct {
+	Created int
+	Updated int
+	Errors  []WorkItemImportError
+}
+
+// WorkItemImportError describes why a row of a CSV could not be imported
+type WorkItemImportError struct {
+	// Row is the line of the row in the CSV, starting with 1 for the header
+	Row int
+	// Column is the header of the invalid column or empty if the error
+	// concerns the whole row
+	Column string
+	Detail string
+}
+
+// ImportWorkItemsFromCSV creates or updates the work items of the given space
+// from a CSV in the layout produced by ConvertWorkItemsToCSV. The columns are
+// mapped to the fields of the given work item types by label or key and
+// users, iterations, areas and labels are resolved by name. Rows with a
+// number update the existing work item with that number. Rows with invalid
+// values are reported in the result and are validated before anything is
+// stored, so they never touch the database. An error is returned if the CSV
+// cannot be read at all or if the storage fails; the import must then be
+// rolled back as a whole because the transaction of the given application is
+// no longer usable. It is up to the caller to roll back the transaction if the
+// result contains errors as well.
+func ImportWorkItemsFromCSV(ctx context.Context, appl application.Application, spaceID uuid.UUID, wits []workitem.WorkItemType, r io.Reader, modifierID uuid.UUID) (*WorkItemImportResult, error) {
+	reader := csv.NewReader(r)
+	// rows with the wrong number of columns are reported per row
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.NewBadParameterErrorFromString("the CSV must have a header line")
+	}
+	if err != nil {
+		return nil, errors.NewBadParameterErrorFromString(fmt.Sprintf("failed to read the CSV: %s", err))
+	}
+	imp := csvImporter{
+		appl:    appl,
+		spaceID: spaceID,
+		header:  header,
+		wits:    map[string]workitem.WorkItemType{},
+		names:   map[workitem.Kind]map[string]string{},
+	}
+	for _, wit := range wits {
+		imp.wits[wit.Name] = wit
+	}
+	result := &WorkItemImportResult{}
+	for row := 2; ; row++ {
+		line, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.NewBadParameterErrorFromString(fmt.Sprintf("failed to read row %d of the CSV: %s", row, err))
+		}
+		if len(line) == 1 && strings.HasPrefix(line[0], csvMoreNote) {
+			continue
+		}
+		if len(line) != len(header) {
+			result.Errors = append(result.Errors, WorkItemImportError{Row: row, Detail: fmt.Sprintf("expected %d columns but got %d", len(header), len(line))})
+			continue
+		}
+		created, rowErrs, err := imp.importRow(ctx, line, modifierID)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to import row %d", row)
+		}
+		for _, e := range rowErrs {
+			e.Row = row
+			result.Errors = append(result.Errors, e)
+		}
+		if len(rowErrs) > 0 {
+			continue
+		}
+		if created {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+	return result, nil
+}
+
+// ConvertWorkItemImport converts the outcome of a CSV import into its REST
+// representation
+func ConvertWorkItemImport(res WorkItemImportResult, dryRun bool) *app.WorkItemImport {
+	importErrors := make([]*app.WorkItemImportError, len(res.Errors))
+	for i, e := range res.Errors {
+		importErrors[i] = &app.WorkItemImportError{
+			Row:    e.Row,
+			Detail: e.Detail,
+		}
+		if e.Column != "" {
+			importErrors[i].Column = ptr.String(e.Column)
+		}
+	}
+	return &app.WorkItemImport{
+		Type: "workitemimports",
+		Attributes: &app.WorkItemImportAttributes{
+			DryRun:  dryRun,
+			Created: res.Created,
+			Updated: res.Updated,
+			Errors:  importErrors,
+		},
+	}
+}
+
+// csvImporter imports the rows of a CSV into a space
+type csvImporter struct {
+	appl    application.Application
+	spaceID uuid.UUID
+	header  []string
+	// wits are the work item types of the space by name
+	wits map[string]workitem.WorkItemType
+	// names caches the IDs of the users, iterations, areas and labels by
+	// name. Ambiguous names are mapped to an empty ID.
+	names map[workitem.Kind]map[string]string
+}
+
+// importRow creates or updates the work item of the given row. It returns
+// true if a work item was created. Invalid values are reported as row errors,
+// the returned error is reserved for storage failures, after which the
+// transaction can't be used anymore.
+func (imp *csvImporter) importRow(ctx context.Context, line []string, modifierID uuid.UUID) (bool, []WorkItemImportError, error) {
+	var existing *workitem.WorkItem
+	var wit *workitem.WorkItemType
+	for i, column := range imp.header {
+		value := strings.TrimSpace(line[i])
+		if value == "" {
+			continue
+		}
+		if column == csvTypeColumn {
+			t, ok := imp.wits[value]
+			if !ok {
+				return false, []WorkItemImportError{{Column: column, Detail: fmt.Sprintf("unknown work item type '%s'", value)}}, nil
+			}
+			wit = &t
+			continue
+		}
+		if !imp.isNumberColumn(column) {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return false, []WorkItemImportError{{Column: column, Detail: fmt.Sprintf("invalid work item number '%s'", value)}}, nil
+		}
+		existing, err = imp.appl.WorkItems().Load(ctx, imp.spaceID, number)
+		if err != nil {
+			if ok, _ := errors.IsNotFoundError(err); ok {
+				return false, []WorkItemImportError{{Column: column, Detail: fmt.Sprintf("work item %d does not exist in the space", number)}}, nil
+			}
+			return false, nil, errs.WithStack(err)
+		}
+	}
+	if wit == nil {
+		if existing == nil {
+			return false, []WorkItemImportError{{Column: csvTypeColumn, Detail: "the work item type is missing"}}, nil
+		}
+		t, err := imp.appl.WorkItemTypes().Load(ctx, existing.Type)
+		if err != nil {
+			return false, nil, errs.WithStack(err)
+		}
+		wit = t
+	}
+	fields := map[string]interface{}{}
+	if existing != nil {
+		for k, v := range existing.Fields {
+			fields[k] = v
+		}
+	}
+	var rowErrs []WorkItemImportError
+	for i, column := range imp.header {
+		if column == csvTypeColumn {
+			continue
+		}
+		value := strings.TrimSpace(line[i])
+		fieldName, fieldDef, ok := csvColumnField(*wit, column)
+		if !ok {
+			// the CSV holds the columns of all types, so an empty value
+			// in a column of another type is fine
+			if value != "" {
+				rowErrs = append(rowErrs, WorkItemImportError{Column: column, Detail: fmt.Sprintf("work item type '%s' has no field with this label or key", wit.Name)})
+			}
+			continue
+		}
+		if _, ignored := csvIgnoredFields[fieldName]; ignored || fieldDef.ReadOnly {
+			continue
+		}
+		v, err := imp.convertValue(ctx, fieldDef.Type, value, fields[fieldName])
+		if e, isValueErr := err.(csvValueError); isValueErr {
+			rowErrs = append(rowErrs, WorkItemImportError{Column: column, Detail: e.detail})
+			continue
+		}
+		if err != nil {
+			return false, nil, err
+		}
+		// validate the value like the work item repository does, so that
+		// storing the work item only fails if the storage fails
+		if _, err := fieldDef.ConvertToModel(fieldName, v); err != nil {
+			rowErrs = append(rowErrs, WorkItemImportError{Column: column, Detail: fmt.Sprintf("'%s' is not a valid value: %s", value, errs.Cause(err))})
+			continue
+		}
+		fields[fieldName] = v
+	}
+	if len(rowErrs) > 0 {
+		return false, rowErrs, nil
+	}
+	if existing == nil {
+		if _, _, err := imp.appl.WorkItems().Create(ctx, imp.spaceID, wit.ID, fields, modifierID); err != nil {
+			return false, nil, errs.WithStack(err)
+		}
+		return true, nil, nil
+	}
+	existing.Type = wit.ID
+	existing.Fields = fields
+	if _, _, err := imp.appl.WorkItems().Save(ctx, imp.spaceID, *existing, modifierID); err != nil {
+		return false, nil, errs.WithStack(err)
+	}
+	return false, nil, nil
+}
+
+// isNumberColumn returns true if the given column holds the work item number
+func (imp *csvImporter) isNumberColumn(column string) bool {
+	for _, wit := range imp.wits {
+		if fieldName, _, ok := csvColumnField(wit, column); ok && fieldName == workitem.SystemNumber {
+			return true
+		}
+	}
+	return column == workitem.SystemNumber
+}
+
+// csvColumnField returns the field of the given work item type whose key or
+// label matches the given column header
+func csvColumnField(wit workitem.WorkItemType, column string) (string, workitem.FieldDefinition, bool) {
+	if def, ok := wit.Fields[column]; ok {
+		return column, def, true
+	}
+	for name, def := range wit.Fields {
+		if def.Label == column {
+			return name, def, true
+		}
+	}
+	return "", workitem.FieldDefinition{}, false
+}
+
+// csvValueError describes an invalid value in a CSV
+type csvValueError struct {
+	detail string
+}
+
+func (e csvValueError) Error() string {
+	return e.detail
+}
+
+func newCSVValueError(format string, args ...interface{}) error {
+	return csvValueError{detail: fmt.Sprintf(format, args...)}
+}
+
+// convertValue converts the given CSV value into a value of the given field
+// type. It is the reverse of convertValueToString. The current value of the
+// field is used to keep the markup of markup fields.
+func (imp *csvImporter) convertValue(ctx context.Context, fieldType workitem.FieldType, value string, current interface{}) (interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+	switch t := fieldType.(type) {
+	case workitem.ListType:
+		var res []interface{}
+		for _, elem := range strings.Split(value, csvListSeparator) {
+			v, err := imp.convertValue(ctx, t.ComponentType, strings.TrimSpace(elem), nil)
+			if err != nil {
+				return nil, err
+			}
+			if v != nil {
+				res = append(res, v)
+			}
+		}
+		return res, nil
+	case workitem.EnumType:
+		return imp.convertValue(ctx, t.BaseType, value, current)
+	}
+	switch kind := fieldType.GetKind(); kind {
+	case workitem.KindInteger:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, newCSVValueError("'%s' is not an integer", value)
+		}
+		return v, nil
+	case workitem.KindFloat:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, newCSVValueError("'%s' is not a number", value)
+		}
+		return v, nil
+	case workitem.KindBoolean:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, newCSVValueError("'%s' is not a boolean", value)
+		}
+		return v, nil
+	case workitem.KindInstant:
+		v, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, newCSVValueError("'%s' is not a RFC3339 date", value)
+		}
+		return v, nil
+	case workitem.KindMarkup:
+		markup := rendering.SystemMarkupDefault
+		if c := rendering.NewMarkupContentFromValue(current); c != nil {
+			markup = c.Markup
+		}
+		return rendering.NewMarkupContent(value, markup), nil
+	case workitem.KindCodebase:
+		return parseCodebaseContent(value)
+	case workitem.KindUser, workitem.KindIteration, workitem.KindArea, workitem.KindLabel:
+		return imp.resolveName(ctx, kind, value)
+	default:
+		return value, nil
+	}
+}
+
+// parseCodebaseContent parses a codebase in the "repository#branch#file:line"
+// format of the CSV export
+func parseCodebaseContent(value string) (interface{}, error) {
+	parts := strings.SplitN(value, "#", 3)
+	if len(parts) != 3 {
+		return nil, newCSVValueError("'%s' is not a codebase of the form 'repository#branch#file:line'", value)
+	}
+	cb := codebase.Content{Repository: parts[0], Branch: parts[1], FileName: parts[2]}
+	if i := strings.LastIndex(parts[2], ":"); i >= 0 {
+		line, err := strconv.Atoi(parts[2][i+1:])
+		if err != nil {
+			return nil, newCSVValueError("'%s' has an invalid line number", value)
+		}
+		cb.FileName, cb.LineNumber = parts[2][:i], line
+	}
+	return cb, nil
+}
+
+// resolveName returns the ID of the user, iteration, area or label with the
+// given name
+func (imp *csvImporter) resolveName(ctx context.Context, kind workitem.Kind, name string) (interface{}, error) {
+	names, loaded := imp.names[kind]
+	if !loaded {
+		names = map[string]string{}
+		imp.names[kind] = names
+	}
+	add := func(n string, id uuid.UUID) {
+		if _, exists := names[n]; exists {
+			names[n] = ""
+			return
+		}
+		names[n] = id.String()
+	}
+	switch kind {
+	case workitem.KindUser:
+		// there are too many users to load them all, so they are looked up
+		// one by one
+		if _, ok := names[name]; !ok {
+			identities, err := imp.appl.Identities().Query(account.IdentityFilterByUsername(name))
+			if err != nil {
+				return nil, errs.WithStack(err)
+			}
+			if len(identities) > 0 {
+				add(name, identities[0].ID)
+			}
+		}
+	case workitem.KindIteration:
+		if !loaded {
+			iterations, err := imp.appl.Iterations().List(ctx, imp.spaceID)
+			if err != nil {
+				return nil, errs.WithStack(err)
+			}
+			for _, i := range iterations {
+				add(i.Name, i.ID)
+			}
+		}
+	case workitem.KindArea:
+		if !loaded {
+			areas, err := imp.appl.Areas().List(ctx, imp.spaceID)
+			if err != nil {
+				return nil, errs.WithStack(err)
+			}
+			for _, a := range areas {
+				add(a.Name, a.ID)
+			}
+		}
+	case workitem.KindLabel:
+		if !loaded {
+			labels, err := imp.appl.Labels().List(ctx, imp.spaceID)
+			if err != nil {
+				return nil, errs.WithStack(err)
+			}
+			for _, l := range labels {
+				add(l.Name, l.ID)
+			}
+		}
+	}
+	id, ok := names[name]
+	if !ok {
+		if kind == workitem.KindUser {
+			return nil, newCSVValueError("no user named '%s' exists", name)
+		}
+		return nil, newCSVValueError("no %s named '%s' exists in the space", kind, name)
+	}
+	if id == "" {
+		return nil, newCSVValueError("the %s name '%s' is ambiguous", kind, name)
+	}
+	return id, nil
+}
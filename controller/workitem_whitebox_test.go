@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/csv"
+	"fmt"
 	"io"
 	"strconv"
 	"testing"
@@ -536,6 +537,147 @@ func (rest *TestWorkItemREST) TestConvertWorkItemsToCSV() {
 	})
 }
 
+func (rest *TestWorkItemREST) TestImportWorkItemsFromCSV() {
+	newFixture := func(t *testing.T) (*tf.TestFixture, []workitem.WorkItemType, [][]string) {
+		fxt := tf.NewTestFixture(t, rest.DB, tf.CreateWorkItemEnvironment(),
+			tf.Spaces(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.Spaces[0].SpaceTemplateID = spacetemplate.SystemAgileTemplateID
+				return nil
+			}),
+			tf.Labels(2, tf.SetLabelNames("important", "ui")),
+			tf.WorkItems(2, func(fxt *tf.TestFixture, idx int) error {
+				wi := fxt.WorkItems[idx]
+				wi.Type = uuid.FromStringOrNil("2853459d-60ef-4fbe-aaf4-eccb9f554b34") // Task
+				wi.Fields[workitem.SystemLabels] = []string{fxt.LabelByName("important").ID.String()}
+				wi.Fields[workitem.SystemState] = interface{}("New")
+				wi.Fields["effort"] = 42.0
+				return nil
+			}),
+		)
+		wits, err := rest.GormDB.WorkItemTypes().List(rest.Ctx, spacetemplate.SystemAgileTemplateID)
+		require.NoError(t, err)
+		exported, _, err := ConvertWorkItemsToCSV(rest.Ctx, rest.GormDB, wits, []workitem.WorkItem{*fxt.WorkItems[0], *fxt.WorkItems[1]}, true)
+		require.NoError(t, err)
+		grid, err := csv.NewReader(bytes.NewBufferString(exported)).ReadAll()
+		require.NoError(t, err)
+		return fxt, wits, grid
+	}
+	column := func(t *testing.T, grid [][]string, label string) int {
+		for idx, l := range grid[0] {
+			if l == label {
+				return idx
+			}
+		}
+		require.Fail(t, "missing column", label)
+		return -1
+	}
+	toCSV := func(t *testing.T, grid [][]string) io.Reader {
+		buf := new(bytes.Buffer)
+		require.NoError(t, csv.NewWriter(buf).WriteAll(grid))
+		return buf
+	}
+
+	rest.T().Run("ok - update and create", func(t *testing.T) {
+		// given an export with a changed row and a new row
+		fxt, wits, grid := newFixture(t)
+		grid[1][column(t, grid, "Title")] = "changed title"
+		grid[1][column(t, grid, "Labels")] = "important;ui"
+		newRow := append([]string{}, grid[2]...)
+		newRow[column(t, grid, "Number")] = ""
+		newRow[column(t, grid, "Title")] = "new title"
+		newRow[column(t, grid, "Effort")] = "5"
+		grid = append(grid, newRow)
+		// when
+		res, err := ImportWorkItemsFromCSV(rest.Ctx, rest.GormDB, fxt.Spaces[0].ID, wits, toCSV(t, grid), fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		require.Empty(t, res.Errors)
+		assert.Equal(t, 1, res.Created)
+		assert.Equal(t, 2, res.Updated)
+		changed, err := rest.GormDB.WorkItems().Load(rest.Ctx, fxt.Spaces[0].ID, fxt.WorkItems[0].Number)
+		require.NoError(t, err)
+		assert.Equal(t, "changed title", changed.Fields[workitem.SystemTitle])
+		assert.Equal(t, fxt.WorkItems[0].Version+1, changed.Version)
+		assert.ElementsMatch(t, []interface{}{fxt.LabelByName("important").ID.String(), fxt.LabelByName("ui").ID.String()}, changed.Fields[workitem.SystemLabels])
+		unchanged, err := rest.GormDB.WorkItems().Load(rest.Ctx, fxt.Spaces[0].ID, fxt.WorkItems[1].Number)
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItems[1].Fields[workitem.SystemTitle], unchanged.Fields[workitem.SystemTitle])
+		assert.Equal(t, 42.0, unchanged.Fields["effort"])
+		created, err := rest.GormDB.WorkItems().Load(rest.Ctx, fxt.Spaces[0].ID, fxt.WorkItems[1].Number+1)
+		require.NoError(t, err)
+		assert.Equal(t, "new title", created.Fields[workitem.SystemTitle])
+		assert.Equal(t, 5.0, created.Fields["effort"])
+		assert.Equal(t, fxt.WorkItems[1].Type, created.Type)
+	})
+
+	rest.T().Run("ok - columns by key", func(t *testing.T) {
+		// given
+		fxt, wits, _ := newFixture(t)
+		grid := [][]string{
+			{"_Type", workitem.SystemTitle, workitem.SystemState, workitem.SystemAssignees},
+			{"Task", "by key", "New", fxt.Identities[0].Username},
+		}
+		// when
+		res, err := ImportWorkItemsFromCSV(rest.Ctx, rest.GormDB, fxt.Spaces[0].ID, wits, toCSV(t, grid), fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		require.Empty(t, res.Errors)
+		assert.Equal(t, 1, res.Created)
+		created, err := rest.GormDB.WorkItems().Load(rest.Ctx, fxt.Spaces[0].ID, fxt.WorkItems[1].Number+1)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{fxt.Identities[0].ID.String()}, created.Fields[workitem.SystemAssignees])
+	})
+
+	rest.T().Run("fail - invalid rows", func(t *testing.T) {
+		// given
+		fxt, wits, grid := newFixture(t)
+		grid[1][column(t, grid, "Labels")] = "unknown label"
+		grid[1][column(t, grid, "Effort")] = "a lot"
+		grid[2][column(t, grid, "_Type")] = "unknown type"
+		missingWI := append([]string{}, grid[2]...)
+		missingWI[column(t, grid, "_Type")] = "Task"
+		missingWI[column(t, grid, "Number")] = "1000"
+		grid = append(grid, missingWI, []string{"too", "short"})
+		// when
+		res, err := ImportWorkItemsFromCSV(rest.Ctx, rest.GormDB, fxt.Spaces[0].ID, wits, toCSV(t, grid), fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 0, res.Created)
+		assert.Equal(t, 0, res.Updated)
+		assert.ElementsMatch(t, []WorkItemImportError{
+			{Row: 2, Column: "Labels", Detail: "no label named 'unknown label' exists in the space"},
+			{Row: 2, Column: "Effort", Detail: "'a lot' is not a number"},
+			{Row: 3, Column: "_Type", Detail: "unknown work item type 'unknown type'"},
+			{Row: 4, Column: "Number", Detail: "work item 1000 does not exist in the space"},
+			{Row: 5, Detail: fmt.Sprintf("expected %d columns but got 2", len(grid[0]))},
+		}, res.Errors)
+	})
+
+	rest.T().Run("fail - value rejected by the work item type", func(t *testing.T) {
+		// given
+		fxt, wits, grid := newFixture(t)
+		grid[1][column(t, grid, "State")] = "unknown state"
+		// when
+		res, err := ImportWorkItemsFromCSV(rest.Ctx, rest.GormDB, fxt.Spaces[0].ID, wits, toCSV(t, grid), fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, 2, res.Errors[0].Row)
+		assert.Equal(t, "State", res.Errors[0].Column)
+		// the other row is still imported
+		assert.Equal(t, 1, res.Updated)
+	})
+
+	rest.T().Run("fail - empty CSV", func(t *testing.T) {
+		// given
+		fxt, wits, _ := newFixture(t)
+		// when
+		_, err := ImportWorkItemsFromCSV(rest.Ctx, rest.GormDB, fxt.Spaces[0].ID, wits, bytes.NewBufferString(""), fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+	})
+}
+
 func (rest *TestWorkItemREST) TestLoadWorkItemTypes() {
 	fxt := tf.NewTestFixture(rest.T(), rest.DB,
 		tf.WorkItemTypes(3),
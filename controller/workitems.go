@@ -1,7 +1,10 @@
 package controller
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/fabric8-services/fabric8-wit/app"
@@ -12,6 +15,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/login"
 	"github.com/fabric8-services/fabric8-wit/notification"
+	"github.com/fabric8-services/fabric8-wit/ptr"
 	query "github.com/fabric8-services/fabric8-wit/query/simple"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/search"
@@ -110,6 +114,7 @@ func (c *WorkitemsController) Create(ctx *app.CreateWorkitemsContext) error {
 		Fields: make(map[string]interface{}),
 	}
 	var rev *workitem.Revision
+	var msg notification.Message
 	err = application.Transactional(c.db, func(appl application.Application) error {
 		//verify spaceID:
 		// To be removed once we have endpoint like - /api/space/{spaceID}/workitems
@@ -128,7 +133,8 @@ func (c *WorkitemsController) Create(ctx *app.CreateWorkitemsContext) error {
 		if err != nil {
 			return errs.Wrap(err, fmt.Sprintf("Error creating work item"))
 		}
-		return nil
+		msg = notification.NewWorkItemCreated(wi.SpaceID, wi.ID.String(), rev.ID)
+		return notification.Enqueue(ctx, appl.Outbox(), msg)
 	})
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
@@ -150,7 +156,7 @@ func (c *WorkitemsController) Create(ctx *app.CreateWorkitemsContext) error {
 	}
 	ctx.ResponseData.Header().Set("Last-Modified", lastModified(*wi))
 	ctx.ResponseData.Header().Set("Location", app.WorkitemHref(wi2.ID))
-	c.notification.Send(ctx, notification.NewWorkItemCreated(wi.ID.String(), rev.ID))
+	c.notification.Send(ctx, msg)
 	return ctx.Created(resp)
 }
 
@@ -169,6 +175,10 @@ func (c *WorkitemsController) List(ctx *app.ListWorkitemsContext) error {
 		// Then add new AND clause with spaceID as another child of input query
 		// Then convert new Query object into simple string
 		queryWithSpaceID := fmt.Sprintf(`{"%s":[{"space": "%s" }, %s]}`, search.AND, ctx.SpaceID, q)
+		if search.IsTextQuery(q) {
+			// text queries contain spaces, quotes and operators like "&"
+			queryWithSpaceID = url.QueryEscape(fmt.Sprintf(`space:%s AND (%s)`, ctx.SpaceID, q))
+		}
 		queryWithSpaceID = fmt.Sprintf("?filter[expression]=%s", queryWithSpaceID)
 		searchURL := app.SearchHref() + queryWithSpaceID
 		ctx.ResponseData.Header().Set("Location", searchURL)
@@ -226,7 +236,7 @@ func (c *WorkitemsController) List(ctx *app.ListWorkitemsContext) error {
 	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
 	var workitems []workitem.WorkItem
 	var count int
-	sort, err := workitem.ParseSortWorkItemsBy(ctx.Sort)
+	sort, err := parseSortWorkItemsBy(ctx, c.db, ctx.Sort, ctx.SpaceID)
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
@@ -328,3 +338,213 @@ func (c *WorkitemsController) Reorder(ctx *app.ReorderWorkitemsContext) error {
 	}
 	return ctx.OK(resp)
 }
+
+// errImportRollback is used to roll back the transaction of a CSV import that
+// is only validated or that has invalid rows
+var errImportRollback = errs.New("rollback of CSV import")
+
+// Import does POST workitems/import
+func (c *WorkitemsController) Import(ctx *app.ImportWorkitemsContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	authorized, err := authz.Authorize(ctx, ctx.SpaceID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
+	}
+	dryRun := ctx.DryRun != nil && *ctx.DryRun
+	var result *WorkItemImportResult
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		s, err := appl.Spaces().Load(ctx, ctx.SpaceID)
+		if err != nil {
+			return err
+		}
+		wits, err := appl.WorkItemTypes().List(ctx, s.SpaceTemplateID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to list the work item types of space %s", ctx.SpaceID)
+		}
+		result, err = ImportWorkItemsFromCSV(ctx, appl, ctx.SpaceID, wits, ctx.Request.Body, *currentUserIdentityID)
+		if err != nil {
+			return err
+		}
+		if dryRun || len(result.Errors) > 0 {
+			return errImportRollback
+		}
+		return nil
+	})
+	if err != nil && errs.Cause(err) != errImportRollback {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if !dryRun && len(result.Errors) > 0 {
+		first := result.Errors[0]
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterErrorFromString(fmt.Sprintf(
+			"the CSV has %d invalid values, e.g. in row %d: %s; use dryRun to list all of them", len(result.Errors), first.Row, first.Detail)))
+	}
+	return ctx.OK(&app.WorkItemImportSingle{Data: ConvertWorkItemImport(*result, dryRun)})
+}
+
+// The statuses of the work items of a bulk update
+const (
+	bulkUpdateStatusUpdated  = "updated"
+	bulkUpdateStatusConflict = "conflict"
+	bulkUpdateStatusFailed   = "failed"
+)
+
+// maxBulkUpdateItems is the maximum number of work items that can be changed
+// by a single bulk update
+const maxBulkUpdateItems = 500
+
+// bulkUpdateTarget is a work item of a bulk update together with the version
+// the changes are based on
+type bulkUpdateTarget struct {
+	wi      workitem.WorkItem
+	version int
+}
+
+// BulkUpdate does PATCH workitems/bulk
+func (c *WorkitemsController) BulkUpdate(ctx *app.BulkUpdateWorkitemsContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	authorized, err := authz.Authorize(ctx, ctx.SpaceID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
+	}
+	if ctx.Payload == nil || ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil || ctx.Payload.Data.Attributes.Patch == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("missing data.attributes.patch element in request", nil))
+	}
+	attrs := ctx.Payload.Data.Attributes
+	if (attrs.Filter == nil) == (len(attrs.Items) == 0) {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterErrorFromString("either data.attributes.filter or data.attributes.items must be given"))
+	}
+	patch := *attrs.Patch
+	if patch.Relationships != nil && patch.Relationships.BaseType != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterErrorFromString("the type of work items cannot be changed by a bulk update"))
+	}
+	if _, ok := patch.Attributes[workitem.SystemVersion]; ok {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterErrorFromString("the patch of a bulk update must not have a version, use data.attributes.items instead"))
+	}
+	var results []*app.WorkItemBulkUpdateResult
+	var msgs []notification.Message
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		targets, err := loadBulkUpdateTargets(ctx, appl, ctx.SpaceID, attrs)
+		if err != nil {
+			return err
+		}
+		for _, target := range targets {
+			result := &app.WorkItemBulkUpdateResult{ID: target.wi.ID}
+			results = append(results, result)
+			wi, rev, err := bulkUpdateWorkItem(ctx, appl, patch, target, *currentUserIdentityID)
+			if err != nil {
+				switch errs.Cause(err).(type) {
+				case errors.VersionConflictError:
+					result.Status = bulkUpdateStatusConflict
+				case errors.BadParameterError, errors.NotFoundError, errors.ConversionError:
+					result.Status = bulkUpdateStatusFailed
+				default:
+					return err
+				}
+				result.Detail = ptr.String(err.Error())
+				continue
+			}
+			msg := notification.NewWorkItemUpdated(wi.SpaceID, wi.ID.String(), rev.ID)
+			if err := notification.Enqueue(ctx, appl.Outbox(), msg); err != nil {
+				return err
+			}
+			result.Status = bulkUpdateStatusUpdated
+			result.Version = ptr.Int(wi.Version)
+			msgs = append(msgs, msg)
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	// notify about the changes once all work items are committed
+	for _, msg := range msgs {
+		c.notification.Send(ctx, msg)
+	}
+	return ctx.OK(&app.WorkItemBulkUpdateOutcomeSingle{
+		Data: &app.WorkItemBulkUpdateOutcome{
+			Type: "workitembulkupdates",
+			Attributes: &app.WorkItemBulkUpdateOutcomeAttributes{
+				Results: results,
+			},
+		},
+	})
+}
+
+// loadBulkUpdateTargets loads the work items of the given space that are
+// selected by the filter or the item list of a bulk update
+func loadBulkUpdateTargets(ctx context.Context, appl application.Application, spaceID uuid.UUID, attrs *app.WorkItemBulkUpdateAttributes) ([]bulkUpdateTarget, error) {
+	var targets []bulkUpdateTarget
+	if attrs.Filter != nil {
+		// restrict the filter to the space in the same way as List does
+		q := *attrs.Filter
+		queryWithSpaceID := fmt.Sprintf(`{"%s":[{"space": "%s" }, %s]}`, search.AND, spaceID, q)
+		if search.IsTextQuery(q) {
+			queryWithSpaceID = fmt.Sprintf(`space:%s AND (%s)`, spaceID, q)
+		}
+		// load one more work item than allowed to find out if there are too many
+		wis, _, _, _, err := appl.SearchItems().Filter(withTextQueryEnv(ctx, q), queryWithSpaceID, nil, ptr.Int(0), ptr.Int(maxBulkUpdateItems+1), workitem.SortWorkItemsByDefault)
+		if err != nil {
+			return nil, errors.NewBadParameterError("data.attributes.filter", q).Expected(fmt.Sprintf("valid filter expression (%s)", err))
+		}
+		for _, wi := range wis {
+			targets = append(targets, bulkUpdateTarget{wi: wi, version: wi.Version})
+		}
+	} else {
+		for _, item := range attrs.Items {
+			wi, err := appl.WorkItems().LoadByID(ctx, item.ID)
+			if err != nil {
+				return nil, err
+			}
+			if wi.SpaceID != spaceID {
+				return nil, errors.NewBadParameterError("data.attributes.items.id", item.ID).Expected("work item of space " + spaceID.String())
+			}
+			target := bulkUpdateTarget{wi: *wi, version: wi.Version}
+			if item.Version != nil {
+				target.version = *item.Version
+			}
+			targets = append(targets, target)
+		}
+	}
+	if len(targets) > maxBulkUpdateItems {
+		return nil, errors.NewBadParameterErrorFromString(fmt.Sprintf("a bulk update cannot change more than %d work items", maxBulkUpdateItems))
+	}
+	return targets, nil
+}
+
+// bulkUpdateWorkItem applies the patch of a bulk update to a single work item
+// and saves it with the version of the target
+func bulkUpdateWorkItem(ctx context.Context, appl application.Application, patch app.WorkItem, target bulkUpdateTarget, modifierID uuid.UUID) (*workitem.WorkItem, *workitem.Revision, error) {
+	wi := target.wi
+	wi.Fields = make(map[string]interface{}, len(target.wi.Fields))
+	for k, v := range target.wi.Fields {
+		wi.Fields[k] = v
+	}
+	// the patch is shared by all work items, so the version is set on a copy
+	attributes := make(map[string]interface{}, len(patch.Attributes)+1)
+	for k, v := range patch.Attributes {
+		attributes[k] = v
+	}
+	attributes[workitem.SystemVersion] = target.version
+	patch.Attributes = attributes
+	// The Number of a work item is not allowed to be changed which is why we
+	// overwrite the values with its old value after the work item was
+	// converted.
+	oldNumber := wi.Number
+	if err := ConvertJSONAPIToWorkItem(ctx, http.MethodPatch, appl, patch, &wi, wi.Type, wi.SpaceID); err != nil {
+		return nil, nil, err
+	}
+	wi.Number = oldNumber
+	return appl.WorkItems().Save(ctx, wi.SpaceID, wi, modifierID)
+}
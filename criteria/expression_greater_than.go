@@ -0,0 +1,20 @@
+package criteria
+
+// GreaterThanExpression represents the "greater than" operator
+type GreaterThanExpression struct {
+	binaryExpression
+}
+
+// Ensure GreaterThanExpression implements the Expression interface
+var _ Expression = &GreaterThanExpression{}
+var _ Expression = (*GreaterThanExpression)(nil)
+
+// Accept implements ExpressionVisitor
+func (t *GreaterThanExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.GreaterThan(t)
+}
+
+// GreaterThan constructs a GreaterThanExpression
+func GreaterThan(left Expression, right Expression) Expression {
+	return reparent(&GreaterThanExpression{binaryExpression{expression{}, left, right}})
+}
@@ -0,0 +1,20 @@
+package criteria
+
+// GreaterThanOrEqualExpression represents the "greater than or equal" operator
+type GreaterThanOrEqualExpression struct {
+	binaryExpression
+}
+
+// Ensure GreaterThanOrEqualExpression implements the Expression interface
+var _ Expression = &GreaterThanOrEqualExpression{}
+var _ Expression = (*GreaterThanOrEqualExpression)(nil)
+
+// Accept implements ExpressionVisitor
+func (t *GreaterThanOrEqualExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.GreaterThanOrEqual(t)
+}
+
+// GreaterThanOrEqual constructs a GreaterThanOrEqualExpression
+func GreaterThanOrEqual(left Expression, right Expression) Expression {
+	return reparent(&GreaterThanOrEqualExpression{binaryExpression{expression{}, left, right}})
+}
@@ -0,0 +1,20 @@
+package criteria
+
+// LessThanOrEqualExpression represents the "less than or equal" operator
+type LessThanOrEqualExpression struct {
+	binaryExpression
+}
+
+// Ensure LessThanOrEqualExpression implements the Expression interface
+var _ Expression = &LessThanOrEqualExpression{}
+var _ Expression = (*LessThanOrEqualExpression)(nil)
+
+// Accept implements ExpressionVisitor
+func (t *LessThanOrEqualExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.LessThanOrEqual(t)
+}
+
+// LessThanOrEqual constructs a LessThanOrEqualExpression
+func LessThanOrEqual(left Expression, right Expression) Expression {
+	return reparent(&LessThanOrEqualExpression{binaryExpression{expression{}, left, right}})
+}
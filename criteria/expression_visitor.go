@@ -7,6 +7,10 @@ type ExpressionVisitor interface {
 	Or(a *OrExpression) interface{}
 	Equals(e *EqualsExpression) interface{}
 	Substring(e *SubstringExpression) interface{}
+	GreaterThan(e *GreaterThanExpression) interface{}
+	GreaterThanOrEqual(e *GreaterThanOrEqualExpression) interface{}
+	LessThan(e *LessThanExpression) interface{}
+	LessThanOrEqual(e *LessThanOrEqualExpression) interface{}
 	Parameter(v *ParameterExpression) interface{}
 	Literal(c *LiteralExpression) interface{}
 	Not(e *NotExpression) interface{}
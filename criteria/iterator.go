@@ -34,6 +34,22 @@ func (i *postOrderIterator) Substring(exp *SubstringExpression) interface{} {
 	return i.binary(exp)
 }
 
+func (i *postOrderIterator) GreaterThan(exp *GreaterThanExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) GreaterThanOrEqual(exp *GreaterThanOrEqualExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) LessThan(exp *LessThanExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) LessThanOrEqual(exp *LessThanOrEqualExpression) interface{} {
+	return i.binary(exp)
+}
+
 func (i *postOrderIterator) Parameter(exp *ParameterExpression) interface{} {
 	return i.visit(exp)
 }
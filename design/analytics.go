@@ -0,0 +1,105 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var spaceAnalytics = a.Type("SpaceAnalytics", func() {
+	a.Description(`JSONAPI store for the cumulative flow and the lead and cycle times of the work items of a space. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("spaceanalytics")
+	})
+	a.Attribute("id", d.UUID, "ID of the space", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", spaceAnalyticsAttributes)
+	a.Attribute("relationships", spaceAnalyticsRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var spaceAnalyticsAttributes = a.Type("SpaceAnalyticsAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of the analytics of a space. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("start", d.DateTime, "The start of the date range", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("end", d.DateTime, "The end of the date range, which is now at the latest", func() {
+		a.Example("2016-12-29T23:18:14Z")
+	})
+	a.Attribute("cumulativeFlow", a.ArrayOf(cumulativeFlowPoint), "The number of work items per state at the start of the date range and at the end of each day")
+	a.Attribute("flowTimes", a.ArrayOf(workItemTypeFlowTimes), "The lead and cycle times of the work items closed in the date range per work item type")
+	a.Required("start", "end", "cumulativeFlow", "flowTimes")
+})
+
+var cumulativeFlowPoint = a.Type("CumulativeFlowPoint", func() {
+	a.Description("The number of work items per state at a point in time")
+	a.Attribute("time", d.DateTime, "The point in time", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("counts", a.HashOf(d.String, d.Integer), "The number of work items per value of system.state", func() {
+		a.Example(map[string]int{"new": 3, "in progress": 2, "closed": 7})
+	})
+	a.Required("time", "counts")
+})
+
+var workItemTypeFlowTimes = a.Type("WorkItemTypeFlowTimes", func() {
+	a.Description("The lead and cycle times of the work items of a type")
+	a.Attribute("workItemType", d.UUID, "ID of the work item type", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("leadTime", durationPercentiles, "The time from the creation of a work item until its state was one of the mClosed meta-state")
+	a.Attribute("cycleTime", durationPercentiles, "The time from the first time the state of a work item was one of the mInprogress meta-state until it was closed")
+	a.Required("workItemType", "leadTime", "cycleTime")
+})
+
+var durationPercentiles = a.Type("DurationPercentiles", func() {
+	a.Description("The percentiles of a set of durations in hours")
+	a.Attribute("count", d.Integer, "Number of durations", func() {
+		a.Example(12)
+	})
+	a.Attribute("p50", d.Number, "The median in hours", func() {
+		a.Example(26.5)
+	})
+	a.Attribute("p85", d.Number, "The 85th percentile in hours", func() {
+		a.Example(70)
+	})
+	a.Attribute("p95", d.Number, "The 95th percentile in hours", func() {
+		a.Example(120.25)
+	})
+	a.Required("count", "p50", "p85", "p95")
+})
+
+var spaceAnalyticsRelationships = a.Type("SpaceAnalyticsRelations", func() {
+	a.Attribute("space", relationGeneric, "This defines the space")
+	a.Attribute("typegroup", relationGeneric, "This defines the work item type group the work items belong to")
+})
+
+var spaceAnalyticsSingle = JSONSingle(
+	"SpaceAnalytics", "Holds the cumulative flow and the lead and cycle times of the work items of a space",
+	spaceAnalytics,
+	nil)
+
+var _ = a.Resource("space_analytics", func() {
+	a.Parent("space")
+
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("analytics"),
+		)
+		a.Description(`Retrieve the daily number of work items per state (cumulative flow) and the
+lead and cycle times of the work items per work item type. Both are derived
+from the state transitions recorded in the revisions of the work items. The
+date range spans 366 days at most.`)
+		a.Params(func() {
+			a.Param("start", d.DateTime, "The start of the date range")
+			a.Param("end", d.DateTime, "The end of the date range (defaults to now)")
+			a.Param("typegroup", d.UUID, "ID of the work item type group to restrict the work items to")
+			a.Required("start")
+		})
+		a.Response(d.OK, spaceAnalyticsSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
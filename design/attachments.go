@@ -0,0 +1,144 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var attachment = a.Type("Attachment", func() {
+	a.Description(`JSONAPI store for the data of an attachment. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("attachments")
+	})
+	a.Attribute("id", d.UUID, "ID of the attachment", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", attachmentAttributes)
+	a.Attribute("relationships", attachmentRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var attachmentAttributes = a.Type("AttachmentAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of an attachment. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("name", d.String, "The file name of the attachment", func() {
+		a.Example("screenshot.png")
+	})
+	a.Attribute("content-type", d.String, "The content type of the attachment", func() {
+		a.Example("image/png")
+	})
+	a.Attribute("size", d.Integer, "The size of the attachment in bytes", func() {
+		a.Example(1024)
+	})
+	a.Attribute("created-at", d.DateTime, "When the attachment was uploaded", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+})
+
+var attachmentRelationships = a.Type("AttachmentRelations", func() {
+	a.Attribute("workitem", relationGeneric, "This defines the work item the file is attached to")
+	a.Attribute("comment", relationGeneric, "This defines the comment the file is attached to, if any")
+	a.Attribute("uploader", relationGeneric, "This defines the user who uploaded the attachment")
+})
+
+var attachmentList = JSONList(
+	"Attachment", "Holds the list of attachments",
+	attachment,
+	nil,
+	meta,
+)
+
+var attachmentSingle = JSONSingle(
+	"Attachment", "Holds a single attachment",
+	attachment,
+	nil,
+)
+
+var _ = a.Resource("attachments", func() {
+	a.BasePath("/attachments")
+
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("/:attachmentID"),
+		)
+		a.Description("Retrieve the attachment with the given ID.")
+		a.Params(func() {
+			a.Param("attachmentID", d.UUID, "ID of the attachment")
+		})
+		a.Response(d.OK, attachmentSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("download", func() {
+		a.Routing(
+			a.GET("/:attachmentID/content"),
+		)
+		a.Description("Download the content of the attachment with the given ID.")
+		a.Params(func() {
+			a.Param("attachmentID", d.UUID, "ID of the attachment")
+		})
+		a.Response(d.OK, "application/octet-stream")
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:attachmentID"),
+		)
+		a.Description("Delete the attachment with the given ID.")
+		a.Params(func() {
+			a.Param("attachmentID", d.UUID, "ID of the attachment to delete")
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NoContent)
+	})
+})
+
+var _ = a.Resource("work_item_attachments", func() {
+	a.Parent("workitem")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("attachments"),
+		)
+		a.Description("List the files attached to the given work item and to its comments.")
+		a.Response(d.OK, attachmentList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("upload", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("attachments"),
+		)
+		a.Description(`Attach a file to the given work item or to one of its comments.
+The request body is the content of the file and the Content-Type header its content type.
+The size of the content is limited by the configuration.`)
+		a.Params(func() {
+			a.Param("name", d.String, "the file name of the attachment", func() {
+				a.MinLength(1)
+			})
+			a.Param("comment", d.UUID, "the ID of the comment of the work item the file is attached to")
+			a.Required("name")
+		})
+		a.Response(d.Created, "/attachments/.*", func() {
+			a.Media(attachmentSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
@@ -71,6 +71,8 @@ var commentRelationships = a.Type("CommentRelations", func() {
 	a.Attribute("created-by", commentCreatedBy, "DEPRECATED. This defines the creator of the comment.")
 	a.Attribute("parent", relationGeneric, "This defines the owning resource of the comment.")
 	a.Attribute("parent-comment", relationGeneric, "This defines the parent comment resource.")
+	a.Attribute("replies", relationGenericList, "The direct replies to the comment. Only set when listing threads, meta.totalCount holds the number of direct replies.")
+	a.Attribute("mentions", relationGenericList, "The identities mentioned with @username in the comment body.")
 })
 
 var commentCreatedBy = a.Type("CommentCreatedBy", func() {
@@ -193,6 +195,25 @@ var _ = a.Resource("work_item_comments", func() {
 		a.Response(d.InternalServerError, JSONAPIErrors)
 		a.Response(d.NotFound, JSONAPIErrors)
 	})
+	a.Action("listThreads", func() {
+		a.Routing(
+			a.GET("comments/threads"),
+		)
+		a.Description(`List the top-level comments associated with the given work item.
+		The replies of each listed comment are nested under its "replies" relationship
+		and returned in "included", oldest first.`)
+		a.Params(func() {
+			a.Param("page[offset]", d.String, `Paging start position is a string pointing to
+			the beginning of pagination.  The value starts from 0 onwards.`)
+			a.Param("page[limit]", d.Integer, `Paging size is the number of top-level comments in a page`)
+		})
+		a.UseTrait("conditional")
+		a.Response(d.OK, commentArray)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
 	a.Action("relations", func() {
 		a.Routing(
 			a.GET("relationships/comments"),
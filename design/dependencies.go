@@ -0,0 +1,71 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var dependencies = a.Type("Dependencies", func() {
+	a.Description(`JSONAPI store for the dependencies of a work item or an iteration. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("dependencies")
+	})
+	a.Attribute("id", d.UUID, "ID of the work item or iteration whose dependencies are described", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", dependenciesAttributes)
+	a.Attribute("relationships", dependenciesRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "id", "attributes", "relationships")
+})
+
+var dependenciesAttributes = a.Type("DependenciesAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of dependencies. See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("blocked", d.Boolean, "Whether or not there are unresolved work items that block the work item (or one of the iteration's work items)", func() {
+		a.Example(true)
+	})
+	a.Required("blocked")
+})
+
+var dependenciesRelationships = a.Type("DependenciesRelations", func() {
+	a.Attribute("blockers", relationGenericList, "All work items that directly or transitively block the work item (or one of the iteration's work items)")
+	a.Attribute("openBlockers", relationGenericList, "The unresolved work items that directly or transitively block the work item (or one of the iteration's work items)")
+	a.Attribute("blocking", relationGenericList, "All work items that are directly or transitively blocked by the work item (or one of the iteration's work items)")
+	a.Attribute("criticalPath", relationGenericList, "The longest chain of unresolved work items that need to be resolved one after another, starting with the work item to resolve first")
+	a.Required("blockers", "openBlockers", "blocking", "criticalPath")
+})
+
+var dependenciesSingle = JSONSingle(
+	"Dependencies", "Holds the dependencies of a work item or an iteration",
+	dependencies,
+	nil)
+
+var _ = a.Resource("dependencies", func() {
+	a.Action("showWorkItem", func() {
+		a.Routing(
+			a.GET("/workitems/:wiID/dependencies"),
+		)
+		a.Description("Show the work items that block or are blocked by the given work item through links with a dependency topology")
+		a.Params(func() {
+			a.Param("wiID", d.UUID, "ID of the work item")
+		})
+		a.Response(d.OK, dependenciesSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+
+	a.Action("showIteration", func() {
+		a.Routing(
+			a.GET("/iterations/:iterationID/dependencies"),
+		)
+		a.Description("Show the work items that block or are blocked by the work items of the given iteration through links with a dependency topology")
+		a.Params(func() {
+			a.Param("iterationID", d.UUID, "ID of the iteration")
+		})
+		a.Response(d.OK, dependenciesSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+})
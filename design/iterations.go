@@ -68,6 +68,64 @@ var iterationSingle = JSONSingle(
 	iteration,
 	nil)
 
+var iterationBurndown = a.Type("IterationBurndown", func() {
+	a.Description(`JSONAPI store for the burndown and burnup chart data of an iteration. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("iterationburndowns")
+	})
+	a.Attribute("id", d.UUID, "ID of the iteration", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", iterationBurndownAttributes)
+	a.Attribute("relationships", iterationBurndownRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var iterationBurndownAttributes = a.Type("IterationBurndownAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of the burndown of an iteration. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("field", d.String, "The numeric field whose values are summed up", func() {
+		a.Example("storypoints")
+	})
+	a.Attribute("points", a.ArrayOf(iterationBurndownPoint), "The work items of the iteration at its start and at the end of each day until its end or until now")
+	a.Required("points")
+})
+
+var iterationBurndownPoint = a.Type("IterationBurndownPoint", func() {
+	a.Description("The work items of an iteration at a point in time")
+	a.Attribute("time", d.DateTime, "The point in time", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("open", d.Integer, "Number of work items that are not closed", func() {
+		a.Example(8)
+	})
+	a.Attribute("closed", d.Integer, "Number of closed work items", func() {
+		a.Example(4)
+	})
+	a.Attribute("remaining", d.Number, "Sum of the field of the work items that are not closed", func() {
+		a.Example(21)
+	})
+	a.Attribute("completed", d.Number, "Sum of the field of the closed work items", func() {
+		a.Example(13)
+	})
+	a.Attribute("added", d.Integer, "Number of work items added to the iteration since the previous point in time", func() {
+		a.Example(1)
+	})
+	a.Attribute("removed", d.Integer, "Number of work items removed from the iteration since the previous point in time", func() {
+		a.Example(0)
+	})
+	a.Required("time", "open", "closed", "remaining", "completed", "added", "removed")
+})
+
+var iterationBurndownRelationships = a.Type("IterationBurndownRelations", func() {
+	a.Attribute("iteration", relationGeneric, "This defines the iteration")
+})
+
+var iterationBurndownSingle = JSONSingle(
+	"IterationBurndown", "Holds the burndown and burnup chart data of an iteration",
+	iterationBurndown,
+	nil)
+
 // new version of "list" for migration
 var _ = a.Resource("iteration", func() {
 	a.BasePath("/iterations")
@@ -86,6 +144,23 @@ var _ = a.Resource("iteration", func() {
 		a.Response(d.InternalServerError, JSONAPIErrors)
 		a.Response(d.NotFound, JSONAPIErrors)
 	})
+	a.Action("burndown", func() {
+		a.Routing(
+			a.GET("/:iterationID/burndown"),
+		)
+		a.Description(`Retrieve the daily number of open and closed work items of the iteration
+and its child iterations between the start and the end of the iteration, as
+well as the work items added to or removed from the iteration. The values of
+a numeric field can be summed up for burndown and burnup charts.`)
+		a.Params(func() {
+			a.Param("iterationID", d.String, "Iteration Identifier")
+			a.Param("field", d.String, "The numeric field whose values are summed up, e.g. \"storypoints\" or \"effort\"")
+		})
+		a.Response(d.OK, iterationBurndownSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
 	a.Action("create-child", func() {
 		a.Security("jwt")
 		a.Routing(
@@ -35,10 +35,19 @@ var _ = a.Resource("search", func() {
 			a.Param("page[offset]", d.String, "Paging start position") // #428
 			a.Param("page[limit]", d.Integer, "Paging size")
 			a.Param("filter[parentexists]", d.Boolean, "if false list work items without any parent")
-			a.Param("filter[expression]", d.String, "Filter expression in JSON format", func() {
+			a.Param("filter[expression]", d.String, `Filter expression in JSON format or as a text query like
+"state:open AND assignee:me AND (label:ui OR label:ux) AND updated>-7d". The JSON format accepts the option
+{"$OPTS": {"as-of": "2018-01-01T00:00:00Z"}} to evaluate the filter against the work items as they were at
+the given point in time, including the work items deleted since then. The parents and children of the
+tree-view option are then the ones linked at that time as well.`, func() {
 				a.Example(`{$AND: [{"space": "f73988a2-1916-4572-910b-2df23df4dcc3"}, {"state": "NEW"}]}`)
 			})
 			a.Param("spaceID", d.String, "The optional space ID of the space to be searched in, if the filter[expression] query parameter is not provided")
+			a.Param("sort", d.String, `Sort order of the work items found by the filter[expression]: one of "execution",
+				"created", "updated" or the key of a work item type field (e.g. "effort"). A leading "-" sorts
+				in descending order (e.g. "-created").`, func() {
+				a.Example("-effort")
+			})
 		})
 		a.Response(d.OK, func() {
 			a.Media(searchWorkItemList)
@@ -56,7 +65,11 @@ var _ = a.Resource("search", func() {
 			a.Param("page[offset]", d.Integer, "Paging start position")
 			a.Param("page[limit]", d.Integer, "Paging size")
 			a.Param("filter[parentexists]", d.Boolean, "if false list work items without any parent")
-			a.Param("filter[expression]", d.String, "Filter expression in JSON format", func() {
+			a.Param("filter[expression]", d.String, `Filter expression in JSON format or as a text query like
+"state:open AND assignee:me AND (label:ui OR label:ux) AND updated>-7d". The JSON format accepts the option
+{"$OPTS": {"as-of": "2018-01-01T00:00:00Z"}} to evaluate the filter against the work items as they were at
+the given point in time, including the work items deleted since then. The parents and children of the
+tree-view option are then the ones linked at that time as well.`, func() {
 				a.Example(`{$AND: [{"space": "f73988a2-1916-4572-910b-2df23df4dcc3"}, {"state": "NEW"}]}`)
 			})
 		})
@@ -33,6 +33,13 @@ var trackerQueryAttributes = a.Type("TrackerQueryAttributes", func() {
 	a.Attribute("schedule", d.String, "Schedule to fetch and import. Expression Format -> [Seconds] [Minutes] [Hours] [Day of month] [Month] [Day of week]. See also -> https://godoc.org/github.com/robfig/cron", func() {
 		a.Example("0 0/15 * * * *")
 	})
+	a.Attribute("fieldMapping", fieldMapping, "Overrides the field mapping of the tracker")
+	a.Attribute("writeBack", d.Boolean, "Whether or not local changes of the title, state, assignees and comments of the imported work items are written back to the remote tracker. Only supported for GitHub and Jira trackers", func() {
+		a.Example(false)
+	})
+	a.Attribute("lastSyncedAt", d.DateTime, "Start of the last successful run. The next run only fetches the remote items changed since then. (read-only)", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
 	a.Required("query", "schedule")
 })
 
@@ -52,6 +59,69 @@ var trackerQuerySingle = JSONSingle(
 	trackerquery,
 	nil)
 
+var trackerQueryRun = a.Type("TrackerQueryRun", func() {
+	a.Description(`JSONAPI store for a run that fetched and imported the items of a tracker query. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("trackerqueryrun")
+	})
+	a.Attribute("id", d.UUID, "ID of the run", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", trackerQueryRunAttributes)
+	a.Attribute("relationships", trackerQueryRunRelationships)
+	a.Required("type", "attributes")
+})
+
+var trackerQueryRunAttributes = a.Type("TrackerQueryRunAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a tracker query run. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("status", d.String, "State of the run", func() {
+		a.Enum("running", "succeeded", "failed")
+	})
+	a.Attribute("startedAt", d.DateTime, "When the run started", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("finishedAt", d.DateTime, "When the run finished", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("since", d.DateTime, "Only the remote items changed since then were fetched. Not set if all items were fetched.", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("itemsFetched", d.Integer, "Number of fetched remote items", func() {
+		a.Example(12)
+	})
+	a.Attribute("itemsCreated", d.Integer, "Number of work items created from the fetched items", func() {
+		a.Example(2)
+	})
+	a.Attribute("itemsUpdated", d.Integer, "Number of work items updated from the fetched items", func() {
+		a.Example(9)
+	})
+	a.Attribute("itemsFailed", d.Integer, "Number of fetched items that could not be imported", func() {
+		a.Example(1)
+	})
+	a.Attribute("errors", a.ArrayOf(d.String), "The first errors that occurred during the run")
+	a.Required("status", "startedAt", "itemsFetched", "itemsCreated", "itemsUpdated", "itemsFailed")
+})
+
+var trackerQueryRunRelationships = a.Type("TrackerQueryRunRelations", func() {
+	a.Attribute("trackerquery", relationKindUUID, "This defines the tracker query of the run")
+})
+
+var trackerQueryRunListMeta = a.Type("TrackerQueryRunListMeta", func() {
+	a.Attribute("totalCount", d.Integer)
+	a.Required("totalCount")
+})
+
+var trackerQueryRunList = JSONList(
+	"TrackerQueryRun", "Holds the list of the runs of a tracker query",
+	trackerQueryRun,
+	pagingLinks,
+	trackerQueryRunListMeta)
+
+var trackerQueryRunSingle = JSONSingle(
+	"TrackerQueryRun", "Holds a single run of a tracker query",
+	trackerQueryRun,
+	nil)
+
 var _ = a.Resource("trackerquery", func() {
 	a.BasePath("/trackerqueries")
 
@@ -136,4 +206,36 @@ var _ = a.Resource("trackerquery", func() {
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
 
+	a.Action("sync", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:id/sync"),
+		)
+		a.Description("Fetch and import the items of the tracker query now. The run happens in the background.")
+		a.Params(func() {
+			a.Param("id", d.UUID, "id")
+		})
+		a.Response(d.Accepted, trackerQueryRunSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+	})
+
+	a.Action("listRuns", func() {
+		a.Routing(
+			a.GET("/:id/runs"),
+		)
+		a.Description("List the runs of the tracker query, the most recent run first.")
+		a.Params(func() {
+			a.Param("id", d.UUID, "id")
+			a.Param("page[offset]", d.String, "Paging start position")
+			a.Param("page[limit]", d.Integer, "Paging size")
+		})
+		a.Response(d.OK, trackerQueryRunList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
 })
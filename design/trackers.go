@@ -31,11 +31,39 @@ var trackerAttributes = a.Type("TrackerAttributes", func() {
 		a.Example("#ffa7cb")
 	})
 	a.Attribute("Type", d.String, "Type of the tracker", func() {
-		a.Enum("github", "jira")
+		a.Enum("github", "jira", "gitlab")
 	})
+	a.Attribute("fieldMapping", fieldMapping, "Configures how the items of the tracker are imported")
 	a.Required("URL", "Type")
 })
 
+var fieldMapping = a.Type("FieldMapping", func() {
+	a.Description(`Configures which remote attributes are imported into which work item fields. The mapping is validated against the fields of the target work item type.`)
+	a.Attribute("workItemType", d.UUID, "ID of the type of the imported work items, defaults to the bug type. It must belong to the space template of the spaces the tracker queries import into", func() {
+		a.Example("26787039-b68f-4e28-8814-c2f93be1ef4e")
+	})
+	a.Attribute("fields", a.ArrayOf(fieldMappingEntry), "Mappings that replace the default mappings of the tracker type for the same work item fields")
+	a.Attribute("states", a.HashOf(d.String, d.String), "Maps remote states to values of the local state field", func() {
+		a.Example(map[string]string{"In Review": "in progress", "Done": "closed"})
+	})
+})
+
+var fieldMappingEntry = a.Type("FieldMappingEntry", func() {
+	a.Attribute("remote", d.String, "Key of the attribute in the flattened remote item", func() {
+		a.Example("fields.priority.name")
+	})
+	a.Attribute("field", d.String, "Name of the work item field", func() {
+		a.Example("system.labels")
+	})
+	a.Attribute("converter", d.String, "Converter to apply to the remote value, defaults to string", func() {
+		a.Enum("string", "list", "pattern", "markdown", "jirawiki", "state")
+	})
+	a.Attribute("pattern", d.String, "Key pattern of the remote list elements for the pattern converter", func() {
+		a.Example("labels.?.name")
+	})
+	a.Required("remote", "field")
+})
+
 var trackerRelationships = a.Type("TrackerRelations", func() {
 })
 
@@ -0,0 +1,149 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var webhook = a.Type("Webhook", func() {
+	a.Description(`JSONAPI store for the data of a webhook. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("webhooks")
+	})
+	a.Attribute("id", d.UUID, "ID of the webhook", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", webhookAttributes)
+	a.Attribute("links", genericLinks)
+	a.Attribute("relationships", webhookRelationships)
+	a.Required("type", "attributes")
+})
+
+var webhookRelationships = a.Type("WebhookRelations", func() {
+	a.Attribute("space", relationGeneric, "This defines the space whose events are sent to the webhook")
+})
+
+var webhookAttributes = a.Type("WebhookAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a webhook. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("url", d.String, mandatoryOnCreate("The http or https URL the event payloads are posted to. Its host must only resolve to public addresses."), func() {
+		a.Example("https://example.com/hooks/wit")
+	})
+	a.Attribute("event-types", a.ArrayOf(d.String), `The types of the events sent to the webhook
+(e.g. "workitem.create", "comment.update", "link.delete"). All events are sent if empty.`, func() {
+		a.Example([]string{"workitem.create", "workitem.update"})
+	})
+	a.Attribute("secret", d.String, mandatoryOnCreate("The shared secret used to sign the payloads with HMAC-SHA256, it is never returned"))
+	a.Attribute("created-at", d.DateTime, "When the webhook was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("updated-at", d.DateTime, "When the webhook was updated", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("version", d.Integer, "Version for optimistic concurrency control (optional during creating)", func() {
+		a.Example(23)
+	})
+})
+
+var webhookList = JSONList(
+	"Webhook", "Holds the list of webhooks",
+	webhook,
+	pagingLinks,
+	meta,
+)
+
+var webhookSingle = JSONSingle(
+	"Webhook", "Holds a single webhook",
+	webhook,
+	nil,
+)
+
+var _ = a.Resource("webhook", func() {
+	a.Parent("space")
+	a.BasePath("/webhooks")
+
+	a.Action("show", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET("/:webhookID"),
+		)
+		a.Description("Retrieve the webhook for the given id.")
+		a.Params(func() {
+			a.Param("webhookID", d.UUID, "ID of the webhook")
+		})
+		a.Response(d.OK, webhookSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("list", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the webhooks of the space.")
+		a.Response(d.OK, webhookList)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Create a webhook with the URL, event types and secret.")
+		a.Payload(webhookSingle)
+		a.Response(d.Created, "/webhooks/.*", func() {
+			a.Media(webhookSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:webhookID"),
+		)
+		a.Description("Update the webhook for the given id.")
+		a.Params(func() {
+			a.Param("webhookID", d.UUID, "ID of the webhook to update")
+		})
+		a.Payload(webhookSingle)
+		a.Response(d.OK, func() {
+			a.Media(webhookSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:webhookID"),
+		)
+		a.Description("Delete the webhook with the given ID.")
+		a.Params(func() {
+			a.Param("webhookID", d.UUID, "ID of the webhook to delete")
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NoContent)
+	})
+})
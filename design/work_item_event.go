@@ -44,6 +44,8 @@ var eventRelationships = a.Type("EventRelations", func() {
 	a.Attribute("oldValue", relationGenericList)
 	a.Attribute("newValue", relationGenericList)
 	a.Attribute("workItemType", relationGeneric, "The type of the work item at the event's point in time")
+	a.Attribute("link", relationGeneric, "The work item link that was added or removed. Only for 'link.added' and 'link.removed' events.")
+	a.Attribute("comment", relationGeneric, "The comment that was added, edited or deleted. Only for 'comment.added', 'comment.edited' and 'comment.deleted' events.")
 
 	a.Required("workItemType", "modifier")
 })
@@ -70,7 +72,7 @@ var _ = a.Resource("work_item_events", func() {
 		a.Params(func() {
 			a.Param("revisionID", d.UUID, "an optional revision ID to filter events by")
 		})
-		a.Description("List events associated with the given work item")
+		a.Description("List events associated with the given work item, including the changes of its links and comments")
 		a.UseTrait("conditional") // Refer: goasupport/conditional_request/generator.go
 		a.Response(d.OK, eventList)
 		a.Response(d.NotModified)
@@ -78,3 +80,30 @@ var _ = a.Resource("work_item_events", func() {
 		a.Response(d.InternalServerError, JSONAPIErrors)
 	})
 })
+
+var _ = a.Resource("space_events", func() {
+	a.Parent("space")
+
+	a.Action("watch", func() {
+		a.Routing(
+			a.GET("events/watch"),
+		)
+		a.Params(func() {
+			a.Param("filter[expression]", d.String, `Optional filter expression in JSON format or as a text query. Only the changes of
+work items that currently match the filter are streamed.`, func() {
+				a.Example(`state:open AND label:ui`)
+			})
+			a.Param("after", d.UUID, "ID of the last change received. The stream resumes right after this change.")
+		})
+		a.Headers(func() {
+			a.Header("Last-Event-ID", d.String, "ID of the last change received, sent by Server-Sent Events clients when reconnecting")
+		})
+		a.Description(`Stream the changes of the work items, links and comments of the given space. The stream is a
+websocket when the request asks for a protocol upgrade and Server-Sent Events otherwise.`)
+		a.Response(d.SwitchingProtocols)
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+})
@@ -106,6 +106,119 @@ var workItemReorder = Reorder(
 	workItem,
 	position)
 
+// workItemImport holds the outcome of a CSV import of work items
+var workItemImport = a.Type("WorkItemImport", func() {
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitemimports")
+	})
+	a.Attribute("attributes", workItemImportAttributes)
+	a.Required("type", "attributes")
+})
+
+var workItemImportAttributes = a.Type("WorkItemImportAttributes", func() {
+	a.Attribute("dryRun", d.Boolean, "Whether or not the imported work items were discarded", func() {
+		a.Example(true)
+	})
+	a.Attribute("created", d.Integer, "Number of rows that create a work item", func() {
+		a.Example(3)
+	})
+	a.Attribute("updated", d.Integer, "Number of rows that update the work item with the same number", func() {
+		a.Example(5)
+	})
+	a.Attribute("errors", a.ArrayOf(workItemImportError), "Rows that cannot be imported")
+	a.Required("dryRun", "created", "updated", "errors")
+})
+
+var workItemImportError = a.Type("WorkItemImportError", func() {
+	a.Attribute("row", d.Integer, "Line of the row in the CSV, starting with 1 for the header line", func() {
+		a.Example(2)
+	})
+	a.Attribute("column", d.String, "Header of the column whose value is invalid", func() {
+		a.Example("Iteration")
+	})
+	a.Attribute("detail", d.String, "Reason why the row cannot be imported", func() {
+		a.Example("no iteration named 'Sprint 1' exists in the space")
+	})
+	a.Required("row", "detail")
+})
+
+// workItemImportSingle is the media type for the outcome of a CSV import
+var workItemImportSingle = JSONSingle(
+	"WorkItemImport", "Holds the outcome of a CSV import of work items",
+	workItemImport,
+	nil)
+
+// workItemBulkUpdate applies the same changes to many work items
+var workItemBulkUpdate = a.Type("WorkItemBulkUpdate", func() {
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitembulkupdates")
+	})
+	a.Attribute("attributes", workItemBulkUpdateAttributes)
+	a.Required("type", "attributes")
+})
+
+var workItemBulkUpdateAttributes = a.Type("WorkItemBulkUpdateAttributes", func() {
+	a.Attribute("filter", d.String, `Filter expression in JSON format or as a text query selecting the work items
+to update (see /search). Only the work items of the space are updated.`, func() {
+		a.Example("state:open AND iteration:\"Sprint 1\"")
+	})
+	a.Attribute("items", a.ArrayOf(workItemBulkUpdateItem), "The work items to update, as an alternative to the filter")
+	a.Attribute("patch", workItem, `The changes to apply to every work item in the same form as the payload of
+PATCH /workitems/:wiID, but without a version`)
+	a.Required("patch")
+})
+
+var workItemBulkUpdateItem = a.Type("WorkItemBulkUpdateItem", func() {
+	a.Attribute("id", d.UUID, "ID of the work item to update", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("version", d.Integer, "Version of the work item the changes are based on. If missing, the current version is updated.", func() {
+		a.Example(3)
+	})
+	a.Required("id")
+})
+
+var workItemBulkUpdateResult = a.Type("WorkItemBulkUpdateResult", func() {
+	a.Attribute("id", d.UUID, "ID of the work item", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("status", d.String, "Whether the work item was updated, had a version conflict or failed for another reason", func() {
+		a.Enum("updated", "conflict", "failed")
+	})
+	a.Attribute("version", d.Integer, "The new version of an updated work item", func() {
+		a.Example(4)
+	})
+	a.Attribute("detail", d.String, "The reason why the work item was not updated", func() {
+		a.Example("version conflict")
+	})
+	a.Required("id", "status")
+})
+
+var workItemBulkUpdateOutcome = a.Type("WorkItemBulkUpdateOutcome", func() {
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitembulkupdates")
+	})
+	a.Attribute("attributes", workItemBulkUpdateOutcomeAttributes)
+	a.Required("type", "attributes")
+})
+
+var workItemBulkUpdateOutcomeAttributes = a.Type("WorkItemBulkUpdateOutcomeAttributes", func() {
+	a.Attribute("results", a.ArrayOf(workItemBulkUpdateResult), "The result for each work item")
+	a.Required("results")
+})
+
+// workItemBulkUpdateSingle is the payload of a bulk update of work items
+var workItemBulkUpdateSingle = JSONSingle(
+	"WorkItemBulkUpdate", "Holds the changes to apply to many work items",
+	workItemBulkUpdate,
+	nil)
+
+// workItemBulkUpdateOutcomeSingle is the media type for the outcome of a bulk update
+var workItemBulkUpdateOutcomeSingle = JSONSingle(
+	"WorkItemBulkUpdateOutcome", "Holds the per work item outcome of a bulk update",
+	workItemBulkUpdateOutcome,
+	nil)
+
 // endpoints that DO NOT depend on the space id (ie, when the work item ID is specified in the URL, there's no need to pass the space ID)
 var _ = a.Resource("workitem", func() {
 	a.BasePath("/workitems")
@@ -179,6 +292,32 @@ var _ = a.Resource("workitem", func() {
 		a.Response(d.Unauthorized, JSONAPIErrors)
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
+
+	a.Action("revert", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiID/revert"),
+		)
+		a.Description("Restore the fields and optionally the type of the work item from one of its revisions.")
+		a.Params(func() {
+			a.Param("wiID", d.UUID, "ID of the work item to revert")
+			a.Param("revision_id", d.UUID, "ID of the revision of the work item to restore (see the revision ID of its events)")
+			a.Param("version", d.Integer, "The current version of the work item")
+			a.Param("restore_type", d.Boolean, "Whether the type of the work item is restored as well", func() {
+				a.Default(false)
+			})
+			a.Required("revision_id", "version")
+		})
+		a.Response(d.OK, func() {
+			a.Media(workItemSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
 })
 
 // endpoints that depend on the space id
@@ -200,12 +339,14 @@ var _ = a.Resource("workitems", func() {
 			a.Param("filter[area]", d.String, "AreaID to filter work items")
 			a.Param("filter[workitemstate]", d.String, "work item state to filter work items by")
 			a.Param("filter[parentexists]", d.Boolean, "if false list work items without any parent")
-			a.Param("filter[expression]", d.String, "accepts query in JSON format and redirects to /api/search? API", func() {
+			a.Param("filter[expression]", d.String, `accepts query in JSON format or as a text query (e.g. "state:open AND
+assignee:me") and redirects to /api/search? API`, func() {
 				a.Example(`{$AND: [{"space": "f73988a2-1916-4572-910b-2df23df4dcc3"}, {"state": "NEW"}]}`)
 			})
-			a.Param("sort", d.String, func() {
-				a.Enum("execution", "created", "updated",
-					"-execution", "-created", "-updated")
+			a.Param("sort", d.String, `Sort order of the work items: one of "execution", "created",
+				"updated" or the key of a work item type field (e.g. "effort"). A leading "-" sorts
+				in descending order (e.g. "-created").`, func() {
+				a.Example("-effort")
 			})
 		})
 		a.UseTrait("conditional")
@@ -233,6 +374,44 @@ var _ = a.Resource("workitems", func() {
 		a.Response(d.NotFound, JSONAPIErrors)
 	})
 
+	a.Action("bulkUpdate", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/bulk"),
+		)
+		a.Description(`Apply the same changes to all work items of the space that match a filter expression
+or to an explicit list of work items. All work items are updated in a single transaction; work items
+with a version conflict or invalid values are reported and left unchanged.`)
+		a.Payload(workItemBulkUpdateSingle)
+		a.Response(d.OK, workItemBulkUpdateOutcomeSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("import", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/import"),
+		)
+		a.Description(`Create or update work items from a CSV in the layout of /search/workitems/csv.
+The request body is the CSV. Columns are mapped to the fields of the work item type given in the
+"_Type" column by field label or key; users, iterations, areas and labels are resolved by name.
+Rows with a number update the work item with that number. All rows are imported in a single
+transaction.`)
+		a.Params(func() {
+			a.Param("dryRun", d.Boolean, "if true only validate the rows and discard the imported work items")
+		})
+		a.Response(d.OK, workItemImportSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
 	a.Action("reorder", func() {
 		a.Security("jwt")
 		a.Routing(
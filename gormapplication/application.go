@@ -7,16 +7,20 @@ import (
 	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/application"
 	"github.com/fabric8-services/fabric8-wit/area"
+	"github.com/fabric8-services/fabric8-wit/attachment"
 	"github.com/fabric8-services/fabric8-wit/codebase"
 	"github.com/fabric8-services/fabric8-wit/comment"
 	"github.com/fabric8-services/fabric8-wit/iteration"
 	"github.com/fabric8-services/fabric8-wit/label"
+	"github.com/fabric8-services/fabric8-wit/notification/outbox"
 	"github.com/fabric8-services/fabric8-wit/query"
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/search"
 	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/fabric8-services/fabric8-wit/webhook"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/analytics"
 	"github.com/fabric8-services/fabric8-wit/workitem/event"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	"github.com/jinzhu/gorm"
@@ -52,12 +56,14 @@ var _ application.Application = &GormDB{}
 var _ application.Application = &GormTransaction{}
 
 func NewGormDB(db *gorm.DB) *GormDB {
-	return &GormDB{GormBase{db}, ""}
+	return &GormDB{GormBase{db: db}, ""}
 }
 
 // GormBase is a base struct for gorm implementations of db & transaction
 type GormBase struct {
-	db *gorm.DB
+	db                   *gorm.DB
+	workItemChangeHook   application.WorkItemChangeHook
+	workItemChangeFilter application.WorkItemChangeFilter
 }
 
 type GormTransaction struct {
@@ -69,8 +75,16 @@ type GormDB struct {
 	txIsoLevel string
 }
 
-func (g *GormBase) WorkItems() workitem.WorkItemRepository {
-	return workitem.NewWorkItemRepository(g.db)
+// WorkItems returns a work item repository that runs the work item change
+// hook of the DB, if any, in its own transaction
+func (g *GormDB) WorkItems() workitem.WorkItemRepository {
+	return newWorkItemRepository(workitem.NewWorkItemRepository(g.db), g, nil, g.workItemChangeHook, g.workItemChangeFilter)
+}
+
+// WorkItems returns a work item repository that runs the work item change
+// hook of the DB, if any, in this transaction
+func (g *GormTransaction) WorkItems() workitem.WorkItemRepository {
+	return newWorkItemRepository(workitem.NewWorkItemRepository(g.db), nil, g, g.workItemChangeHook, g.workItemChangeFilter)
 }
 
 func (g *GormBase) WorkItemTypes() workitem.WorkItemTypeRepository {
@@ -87,6 +101,9 @@ func (g *GormBase) Trackers() remoteworkitem.TrackerRepository {
 func (g *GormBase) TrackerQueries() remoteworkitem.TrackerQueryRepository {
 	return remoteworkitem.NewTrackerQueryRepository(g.db)
 }
+func (g *GormBase) TrackerQueryRuns() remoteworkitem.TrackerQueryRunRepository {
+	return remoteworkitem.NewTrackerQueryRunRepository(g.db)
+}
 
 func (g *GormBase) SearchItems() application.SearchRepository {
 	return search.NewGormSearchRepository(g.db)
@@ -117,6 +134,17 @@ func (g *GormBase) Comments() comment.Repository {
 	return comment.NewRepository(g.db)
 }
 
+// CommentMentions returns a repository of the identities mentioned in
+// comments
+func (g *GormBase) CommentMentions() comment.MentionRepository {
+	return comment.NewMentionRepository(g.db)
+}
+
+// Attachments returns an attachment repository
+func (g *GormBase) Attachments() attachment.Repository {
+	return attachment.NewAttachmentRepository(g.db)
+}
+
 // Iterations returns a iteration repository
 func (g *GormBase) Iterations() iteration.Repository {
 	return iteration.NewIterationRepository(g.db)
@@ -137,6 +165,11 @@ func (g *GormBase) Events() event.Repository {
 	return event.NewEventRepository(g.db)
 }
 
+// Analytics returns a work item analytics repository
+func (g *GormBase) Analytics() analytics.Repository {
+	return analytics.NewRepository(g.db)
+}
+
 // Queries returns a queries repository
 func (g *GormBase) Queries() query.Repository {
 	return query.NewQueryRepository(g.db)
@@ -162,6 +195,21 @@ func (g *GormBase) Boards() workitem.BoardRepository {
 	return workitem.NewBoardRepository(g.db)
 }
 
+// ActionRules returns a work item action rule repository
+func (g *GormBase) ActionRules() workitem.ActionRuleRepository {
+	return workitem.NewActionRuleRepository(g.db)
+}
+
+// Webhooks returns a webhook repository
+func (g *GormBase) Webhooks() webhook.Repository {
+	return webhook.NewWebhookRepository(g.db)
+}
+
+// Outbox returns a notification outbox repository
+func (g *GormBase) Outbox() outbox.Repository {
+	return outbox.NewRepository(g.db)
+}
+
 func (g *GormBase) DB() *gorm.DB {
 	return g.db
 }
@@ -184,6 +232,19 @@ func (g *GormDB) SetTransactionIsolationLevel(level TXIsoLevel) error {
 	return nil
 }
 
+// SetWorkItemChangeHook sets the hook that the work item repositories of
+// this DB and of its transactions run on every change of a work item
+func (g *GormDB) SetWorkItemChangeHook(hook application.WorkItemChangeHook) {
+	g.workItemChangeHook = hook
+}
+
+// SetWorkItemChangeFilter sets the filter that the work item repositories of
+// this DB and of its transactions ask before they run the work item change
+// hook. Without a filter the hook runs on every change of a work item.
+func (g *GormDB) SetWorkItemChangeFilter(filter application.WorkItemChangeFilter) {
+	g.workItemChangeFilter = filter
+}
+
 // Begin implements TransactionSupport
 func (g *GormDB) BeginTransaction() (application.Transaction, error) {
 	tx := g.db.Begin()
@@ -195,9 +256,9 @@ func (g *GormDB) BeginTransaction() (application.Transaction, error) {
 		if tx.Error != nil {
 			return nil, tx.Error
 		}
-		return &GormTransaction{GormBase{tx}}, nil
+		return &GormTransaction{GormBase{tx, g.workItemChangeHook, g.workItemChangeFilter}}, nil
 	}
-	return &GormTransaction{GormBase{tx}}, nil
+	return &GormTransaction{GormBase{tx, g.workItemChangeHook, g.workItemChangeFilter}}, nil
 }
 
 // Commit implements TransactionSupport
@@ -0,0 +1,208 @@
+package gormapplication
+
+import (
+	"context"
+
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// savepointName is the name of the savepoints of nested transactions. Nested
+// transactions end in the reverse order in which they began, so they can all
+// use the same name.
+const savepointName = "nested_transaction"
+
+// nestedDB makes code that begins its own transactions, like the work item
+// change hook, take part in an open transaction. Its transactions are
+// savepoints of the open transaction.
+type nestedDB struct {
+	*GormTransaction
+}
+
+// BeginTransaction implements application.DB
+func (g nestedDB) BeginTransaction() (application.Transaction, error) {
+	if err := g.db.Exec("SAVEPOINT " + savepointName).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return nestedTransaction{g.GormTransaction}, nil
+}
+
+// nestedTransaction is a savepoint of an open transaction
+type nestedTransaction struct {
+	*GormTransaction
+}
+
+// Commit releases the savepoint
+func (g nestedTransaction) Commit() error {
+	return errors.WithStack(g.db.Exec("RELEASE SAVEPOINT " + savepointName).Error)
+}
+
+// Rollback undoes the changes made since the savepoint and releases it
+func (g nestedTransaction) Rollback() error {
+	if err := g.db.Exec("ROLLBACK TO SAVEPOINT " + savepointName).Error; err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(g.db.Exec("RELEASE SAVEPOINT " + savepointName).Error)
+}
+
+// workItemChangeKey is the context key of the workItemChange that the hook
+// is running for
+type workItemChangeKey struct{}
+
+// workItemChange holds the latest revision of every work item changed by a
+// work item change and the hook it runs, and the IDs of the work items the
+// hook ran for
+type workItemChange struct {
+	revisions map[uuid.UUID]*workitem.Revision
+	hooked    map[uuid.UUID]struct{}
+}
+
+// workItemRepository runs the work item change hook after every created,
+// saved or reverted work item. The change and the hook are undone together
+// if either of them fails.
+type workItemRepository struct {
+	workitem.WorkItemRepository
+	db     *GormDB
+	tx     *GormTransaction
+	hook   application.WorkItemChangeHook
+	filter application.WorkItemChangeFilter
+}
+
+// newWorkItemRepository wraps the given repository so that it runs the given
+// hook for the changes the given filter lets through. tx is the transaction
+// of the repository or nil if it doesn't work in a transaction.
+func newWorkItemRepository(repo workitem.WorkItemRepository, db *GormDB, tx *GormTransaction, hook application.WorkItemChangeHook, filter application.WorkItemChangeFilter) workitem.WorkItemRepository {
+	if hook == nil {
+		return repo
+	}
+	return &workItemRepository{WorkItemRepository: repo, db: db, tx: tx, hook: hook, filter: filter}
+}
+
+// Create implements workitem.WorkItemRepository
+func (r *workItemRepository) Create(ctx context.Context, spaceID uuid.UUID, typeID uuid.UUID, fields map[string]interface{}, creatorID uuid.UUID) (*workitem.WorkItem, *workitem.Revision, error) {
+	return r.change(ctx, creatorID, &spaceID, nil, func(ctx context.Context, repo workitem.WorkItemRepository) (*workitem.WorkItem, *workitem.Revision, error) {
+		return repo.Create(ctx, spaceID, typeID, fields, creatorID)
+	})
+}
+
+// Save implements workitem.WorkItemRepository
+func (r *workItemRepository) Save(ctx context.Context, spaceID uuid.UUID, wi workitem.WorkItem, modifierID uuid.UUID) (*workitem.WorkItem, *workitem.Revision, error) {
+	return r.change(ctx, modifierID, &spaceID, &wi.ID, func(ctx context.Context, repo workitem.WorkItemRepository) (*workitem.WorkItem, *workitem.Revision, error) {
+		return repo.Save(ctx, spaceID, wi, modifierID)
+	})
+}
+
+// Revert implements workitem.WorkItemRepository
+func (r *workItemRepository) Revert(ctx context.Context, id uuid.UUID, revisionID uuid.UUID, version int, restoreType bool, modifierID uuid.UUID) (*workitem.WorkItem, *workitem.Revision, error) {
+	return r.change(ctx, modifierID, nil, &id, func(ctx context.Context, repo workitem.WorkItemRepository) (*workitem.WorkItem, *workitem.Revision, error) {
+		return repo.Revert(ctx, id, revisionID, version, restoreType, modifierID)
+	})
+}
+
+// change makes the given change to the work item with the given ID (nil for a
+// new work item) in the given space (nil if it is not known before the work
+// item is loaded) and runs the hook in the same transaction. It returns the
+// work item and its latest revision after the hook ran.
+func (r *workItemRepository) change(ctx context.Context, modifierID uuid.UUID, spaceID *uuid.UUID, id *uuid.UUID, do func(context.Context, workitem.WorkItemRepository) (*workitem.WorkItem, *workitem.Revision, error)) (*workitem.WorkItem, *workitem.Revision, error) {
+	if c, ok := ctx.Value(workItemChangeKey{}).(*workItemChange); ok {
+		// the hook is changing a work item. It runs again for work items it
+		// didn't run for yet, which makes it run at most once per work item
+		// and ends for any link graph between the changed work items.
+		hooked := false
+		if id != nil {
+			_, hooked = c.hooked[*id]
+		}
+		if hooked || r.tx == nil {
+			wi, rev, err := do(ctx, r.WorkItemRepository)
+			if err != nil {
+				return nil, nil, err
+			}
+			c.revisions[wi.ID] = rev
+			return wi, rev, nil
+		}
+		return r.changeAndRunHook(ctx, c, modifierID, spaceID, id, do)
+	}
+	if r.tx == nil {
+		// run the change and the hook in a new transaction
+		var wi *workitem.WorkItem
+		var rev *workitem.Revision
+		err := application.Transactional(r.db, func(appl application.Application) error {
+			var err error
+			wi, rev, err = do(ctx, appl.WorkItems())
+			return err
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return wi, rev, nil
+	}
+	c := &workItemChange{
+		revisions: map[uuid.UUID]*workitem.Revision{},
+		hooked:    map[uuid.UUID]struct{}{},
+	}
+	return r.changeAndRunHook(ctx, c, modifierID, spaceID, id, do)
+}
+
+// changeAndRunHook makes the given change and runs the hook for the changed
+// work item in a savepoint of the transaction of the repository, unless the
+// filter tells that the hook has nothing to do for the space of the work
+// item.
+func (r *workItemRepository) changeAndRunHook(ctx context.Context, c *workItemChange, modifierID uuid.UUID, spaceID *uuid.UUID, id *uuid.UUID, do func(context.Context, workitem.WorkItemRepository) (*workitem.WorkItem, *workitem.Revision, error)) (*workitem.WorkItem, *workitem.Revision, error) {
+	var wi *workitem.WorkItem
+	db := nestedDB{r.tx}
+	err := application.Transactional(db, func(appl application.Application) error {
+		run := true
+		var older *workitem.WorkItem
+		if spaceID != nil {
+			var err error
+			if run, err = r.runsHook(ctx, db, *spaceID); err != nil {
+				return err
+			}
+		}
+		if run && id != nil {
+			var err error
+			older, err = r.WorkItemRepository.LoadByID(ctx, *id)
+			if err != nil {
+				return err
+			}
+			if spaceID == nil {
+				if run, err = r.runsHook(ctx, db, older.SpaceID); err != nil {
+					return err
+				}
+			}
+		}
+		newer, newerRev, err := do(ctx, r.WorkItemRepository)
+		if err != nil {
+			return err
+		}
+		c.revisions[newer.ID] = newerRev
+		if !run {
+			wi = newer
+			return nil
+		}
+		c.hooked[newer.ID] = struct{}{}
+		wi, err = r.hook(context.WithValue(ctx, workItemChangeKey{}, c), db, modifierID, older, *newer)
+		if err != nil {
+			return err
+		}
+		if wi == nil {
+			wi = newer
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return wi, c.revisions[wi.ID], nil
+}
+
+// runsHook returns true if the hook has to run for a change of a work item in
+// the given space.
+func (r *workItemRepository) runsHook(ctx context.Context, db application.DB, spaceID uuid.UUID) (bool, error) {
+	if r.filter == nil {
+		return true, nil
+	}
+	return r.filter(ctx, db, spaceID)
+}
@@ -0,0 +1,69 @@
+package iteration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/actions/change"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeSet(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	start := time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+	older := iteration.Iteration{
+		ID:          uuid.NewV4(),
+		SpaceID:     uuid.NewV4(),
+		Name:        "Sprint 1",
+		Description: ptr.String("first sprint"),
+		StartAt:     &start,
+		State:       iteration.StateStart,
+	}
+
+	t.Run("new iteration", func(t *testing.T) {
+		changes, err := older.ChangeSet(nil)
+		require.NoError(t, err)
+		require.Equal(t, change.Set{
+			{AttributeName: iteration.AttributeState, NewValue: iteration.StateStart},
+		}, changes)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		newer := older
+		// same instant in another location is no change.
+		startInLocal := start.Local()
+		newer.StartAt = &startInLocal
+		changes, err := newer.ChangeSet(older)
+		require.NoError(t, err)
+		require.Empty(t, changes)
+	})
+
+	t.Run("state and name changed", func(t *testing.T) {
+		newer := older
+		newer.State = iteration.StateClose
+		newer.Name = "Sprint 1 (done)"
+		changes, err := newer.ChangeSet(older)
+		require.NoError(t, err)
+		require.Equal(t, change.Set{
+			{AttributeName: iteration.AttributeState, NewValue: iteration.StateClose, OldValue: iteration.StateStart},
+			{AttributeName: iteration.AttributeName, NewValue: "Sprint 1 (done)", OldValue: "Sprint 1"},
+		}, changes)
+	})
+
+	t.Run("different iteration", func(t *testing.T) {
+		newer := older
+		newer.ID = uuid.NewV4()
+		_, err := newer.ChangeSet(older)
+		require.Error(t, err)
+	})
+
+	t.Run("different entity", func(t *testing.T) {
+		_, err := older.ChangeSet(&older)
+		require.Error(t, err)
+	})
+}
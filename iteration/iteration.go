@@ -3,9 +3,11 @@ package iteration
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strconv"
 	"time"
 
+	"github.com/fabric8-services/fabric8-wit/actions/change"
 	"github.com/fabric8-services/fabric8-wit/application/repository"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
@@ -27,6 +29,19 @@ const (
 	IterationNotActive     = false
 )
 
+// Attribute names of an iteration as reported in a change set. They are
+// prefixed so that the action rules of a space template that are triggered by
+// iteration changes never match a work item field.
+const (
+	AttributeName        = "iteration.name"
+	AttributeDescription = "iteration.description"
+	AttributeStartAt     = "iteration.start_at"
+	AttributeEndAt       = "iteration.end_at"
+	AttributeState       = "iteration.state"
+	AttributeUserActive  = "iteration.user_active"
+	AttributeParent      = "iteration.parent"
+)
+
 // Iteration describes a single iteration
 type Iteration struct {
 	gormsupport.Lifecycle
@@ -77,6 +92,59 @@ func (m Iteration) GetLastModified() time.Time {
 
 }
 
+// ChangeSet derives a changeset between this iteration and a given iteration.
+func (m Iteration) ChangeSet(older change.Detector) (change.Set, error) {
+	if older == nil {
+		// this is changeset for a new ChangeDetector, report all observed
+		// attributes to the change set.
+		return change.Set{
+			{
+				AttributeName: AttributeState,
+				NewValue:      m.State,
+				OldValue:      nil,
+			},
+		}, nil
+	}
+	olderIteration, ok := older.(Iteration)
+	if !ok {
+		return nil, errs.New("Other entity is not an Iteration: " + reflect.TypeOf(older).String())
+	}
+	if m.ID != olderIteration.ID {
+		return nil, errs.New("Other entity has not the same ID: " + olderIteration.ID.String())
+	}
+	changes := change.Set{}
+	add := func(attributeName string, newValue, oldValue interface{}) {
+		if !reflect.DeepEqual(newValue, oldValue) {
+			changes = append(changes, change.Change{
+				AttributeName: attributeName,
+				NewValue:      newValue,
+				OldValue:      oldValue,
+			})
+		}
+	}
+	// compare the state first as most actions are connected to it.
+	add(AttributeState, m.State, olderIteration.State)
+	add(AttributeName, m.Name, olderIteration.Name)
+	add(AttributeDescription, m.Description, olderIteration.Description)
+	if !equalTimes(m.StartAt, olderIteration.StartAt) {
+		changes = append(changes, change.Change{AttributeName: AttributeStartAt, NewValue: m.StartAt, OldValue: olderIteration.StartAt})
+	}
+	if !equalTimes(m.EndAt, olderIteration.EndAt) {
+		changes = append(changes, change.Change{AttributeName: AttributeEndAt, NewValue: m.EndAt, OldValue: olderIteration.EndAt})
+	}
+	add(AttributeUserActive, m.UserActive, olderIteration.UserActive)
+	add(AttributeParent, m.Parent(), olderIteration.Parent())
+	return changes, nil
+}
+
+// equalTimes returns true if both times are nil or represent the same instant.
+func equalTimes(t1, t2 *time.Time) bool {
+	if t1 == nil || t2 == nil {
+		return t1 == t2
+	}
+	return t1.Equal(*t2)
+}
+
 // IsRoot Checks if given iteration is a root iteration or not
 func (m Iteration) IsRoot(spaceID uuid.UUID) bool {
 	return m.SpaceID == spaceID && len(m.Path) == 1 && m.Path[0] == m.ID
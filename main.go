@@ -12,8 +12,10 @@ import (
 	"time"
 
 	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/actions"
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/attachment"
 	"github.com/fabric8-services/fabric8-wit/auth"
 	"github.com/fabric8-services/fabric8-wit/closeable"
 	"github.com/fabric8-services/fabric8-wit/configuration"
@@ -33,6 +35,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/space/authz"
 	"github.com/fabric8-services/fabric8-wit/swagger"
 	"github.com/fabric8-services/fabric8-wit/token"
+	"github.com/fabric8-services/fabric8-wit/webhook"
 	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/logging/logrus"
 	"github.com/goadesign/goa/middleware"
@@ -180,12 +183,19 @@ func main() {
 			log.Panic(nil, map[string]interface{}{
 				"err": err,
 				"url": config.GetNotificationServiceURL(),
-			}, "failed to parse notification service url")
+			}, "failed to create the notification service channel")
 		}
 		notificationChannel = channel
 	}
+	// deliver events to the webhooks of the spaces as well
+	webhook.SetAllowPrivateAddresses(config.IsWebhookPrivateAddressesEnabled())
+	notificationChannel = notification.MultiChannel{notificationChannel, notification.NewWebhookChannel(db, config)}
 
 	appDB := gormapplication.NewGormDB(db)
+	// run the action rules of the space templates in the transaction of every
+	// work item change
+	appDB.SetWorkItemChangeHook(actions.OnWorkItemChange)
+	appDB.SetWorkItemChangeFilter(actions.HasWorkItemActionRules)
 
 	tokenManager, err := token.NewManager(config)
 	if err != nil {
@@ -208,6 +218,12 @@ func main() {
 
 	service.Use(metric.Recorder())
 
+	// the controllers store their notifications in the outbox and only wake
+	// up the dispatcher which delivers them through the configured channels
+	dispatcher := notification.NewDispatcher(appDB, notificationChannel, config)
+	dispatcher.Start(context.Background())
+	notificationChannel = dispatcher
+
 	loginService := login.NewKeycloakOAuthProvider(identityRepository, userRepository, tokenManager, appDB)
 	loginCtrl := controller.NewLoginController(service, loginService, config, identityRepository)
 	app.MountLoginController(service, loginCtrl)
@@ -250,7 +266,7 @@ func main() {
 	app.MountWorkItemLinkTypesController(service, workItemLinkTypesCtrl)
 
 	// Mount "work item link" controller
-	workItemLinkCtrl := controller.NewWorkItemLinkController(service, appDB, config)
+	workItemLinkCtrl := controller.NewNotifyingWorkItemLinkController(service, appDB, notificationChannel, config)
 	app.MountWorkItemLinkController(service, workItemLinkCtrl)
 
 	// Mount "work item comments" controller
@@ -275,6 +291,14 @@ func main() {
 	workItemEventsCtrl := controller.NewEventsController(service, appDB, config)
 	app.MountWorkItemEventsController(service, workItemEventsCtrl)
 
+	// Mount "space_events" controller
+	spaceEventsCtrl := controller.NewSpaceEventsController(service, appDB, config)
+	app.MountSpaceEventsController(service, spaceEventsCtrl)
+
+	// Mount "dependencies" controller
+	dependenciesCtrl := controller.NewDependenciesController(service, appDB)
+	app.MountDependenciesController(service, dependenciesCtrl)
+
 	if config.GetFeatureWorkitemRemote() {
 		// Scheduler to fetch and import remote tracker items
 		scheduler = remoteworkitem.NewScheduler(db)
@@ -338,6 +362,10 @@ func main() {
 	spaceIterationCtrl := controller.NewSpaceIterationsController(service, appDB, config)
 	app.MountSpaceIterationsController(service, spaceIterationCtrl)
 
+	// Mount "space analytics" controller
+	spaceAnalyticsCtrl := controller.NewSpaceAnalyticsController(service, appDB)
+	app.MountSpaceAnalyticsController(service, spaceAnalyticsCtrl)
+
 	// Mount "render" controller
 	renderCtrl := controller.NewRenderController(service)
 	app.MountRenderController(service, renderCtrl)
@@ -399,6 +427,23 @@ func main() {
 	queriesCtrl := controller.NewQueryController(service, appDB, config)
 	app.MountQueryController(service, queriesCtrl)
 
+	// Mount "webhooks" controller
+	webhooksCtrl := controller.NewWebhookController(service, appDB)
+	app.MountWebhookController(service, webhooksCtrl)
+
+	// Mount "attachments" and "work_item_attachments" controllers
+	blobStore, err := attachment.NewFileSystemBlobStore(config.GetAttachmentsFileSystemPath())
+	if err != nil {
+		log.Panic(nil, map[string]interface{}{
+			"err":  err,
+			"path": config.GetAttachmentsFileSystemPath(),
+		}, "failed to create the attachment blob store")
+	}
+	attachmentsCtrl := controller.NewAttachmentsController(service, appDB, blobStore)
+	app.MountAttachmentsController(service, attachmentsCtrl)
+	workItemAttachmentsCtrl := controller.NewWorkItemAttachmentsController(service, appDB, blobStore, config)
+	app.MountWorkItemAttachmentsController(service, workItemAttachmentsCtrl)
+
 	// proxying call to "/api/features/*" to the toggles service
 	featuresCtrl := controller.NewFeaturesController(service, config)
 	app.MountFeaturesController(service, featuresCtrl)
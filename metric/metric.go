@@ -45,6 +45,13 @@ var (
 		Help:      "Bucketed histogram of the HTTP request sizes in bytes.",
 		Buckets:   []float64{1000, 5000, 10000, 20000, 30000, 40000, 50000},
 	}, reqLabels)
+
+	outboxDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "notification_outbox_depth",
+		Help:      "Number of notification messages waiting in the outbox (state=pending) or given up on (state=dead).",
+	}, []string{"state"})
 )
 
 func registerMetrics() {
@@ -52,6 +59,7 @@ func registerMetrics() {
 	reqDuration = register(reqDuration, "request_duration_seconds").(*prometheus.HistogramVec)
 	resSize = register(resSize, "response_size_bytes").(*prometheus.HistogramVec)
 	reqSize = register(reqSize, "request_size_bytes").(*prometheus.HistogramVec)
+	outboxDepth = register(outboxDepth, "notification_outbox_depth").(*prometheus.GaugeVec)
 	log.Info(nil, nil, "metrics registered successfully")
 }
 
@@ -95,3 +103,10 @@ func reportRequestSize(method, entity, code string, size int64) {
 		reqSize.WithLabelValues(method, entity, code).Observe(float64(size))
 	}
 }
+
+// ReportNotificationOutboxDepth records the number of pending and dead
+// messages in the notification outbox
+func ReportNotificationOutboxDepth(pending, dead int) {
+	outboxDepth.WithLabelValues("pending").Set(float64(pending))
+	outboxDepth.WithLabelValues("dead").Set(float64(dead))
+}
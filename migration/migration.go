@@ -468,6 +468,42 @@ func GetMigrations() Migrations {
 	// Version 110
 	m = append(m, steps{ExecuteSQLFile("110-trackerquery-to-use-uuid.sql")})
 
+	// Version 111
+	m = append(m, steps{ExecuteSQLFile("111-work-item-action-rules.sql")})
+
+	// Version 112
+	m = append(m, steps{ExecuteSQLFile("112-tracker-write-back.sql")})
+
+	// Version 113
+	m = append(m, steps{ExecuteSQLFile("113-tracker-field-mapping.sql")})
+
+	// Version 114
+	m = append(m, steps{ExecuteSQLFile("114-webhooks.sql")})
+
+	// Version 115
+	m = append(m, steps{ExecuteSQLFile("115-notification-outbox.sql")})
+
+	// Version 116
+	m = append(m, steps{ExecuteSQLFile("116-tracker-query-runs.sql")})
+
+	// Version 117
+	m = append(m, steps{ExecuteSQLFile("117-comment-mentions.sql")})
+
+	// Version 118
+	m = append(m, steps{ExecuteSQLFile("118-attachments.sql")})
+
+	// Version 119
+	m = append(m, steps{ExecuteSQLFile("119-work-item-revisions-time-index.sql")})
+
+	// Version 120
+	m = append(m, steps{ExecuteSQLFile("120-revisions-time-index.sql")})
+
+	// Version 121
+	m = append(m, steps{ExecuteSQLFile("121-notification-outbox-delivered-to.sql")})
+
+	// Version 122
+	m = append(m, steps{ExecuteSQLFile("122-tracker-pushed-comments.sql")})
+
 	// Version N
 	//
 	// In order to add an upgrade, simply append an array of MigrationFunc to the
@@ -160,6 +160,18 @@ func TestMigrations(t *testing.T) {
 	t.Run("TestMigration108", testMigration108NumberColumnForArea)
 	t.Run("TestMigration109", testMigration109NumberColumnForIteration)
 	t.Run("TestMigration110", testMigration110TrackerQueryID)
+	t.Run("TestMigration111", testMigration111WorkItemActionRules)
+	t.Run("TestMigration112", testMigration112TrackerWriteBack)
+	t.Run("TestMigration113", testMigration113TrackerFieldMapping)
+	t.Run("TestMigration114", testMigration114Webhooks)
+	t.Run("TestMigration115", testMigration115NotificationOutbox)
+	t.Run("TestMigration116", testMigration116TrackerQueryRuns)
+	t.Run("TestMigration117", testMigration117CommentMentions)
+	t.Run("TestMigration118", testMigration118Attachments)
+	t.Run("TestMigration119", testMigration119WorkItemRevisionsTimeIndex)
+	t.Run("TestMigration120", testMigration120RevisionsTimeIndex)
+	t.Run("TestMigration121", testMigration121NotificationOutboxDeliveredTo)
+	t.Run("TestMigration122", testMigration122TrackerPushedComments)
 
 	// Perform the migration
 	err = migration.Migrate(sqlDB, databaseName)
@@ -1402,6 +1414,80 @@ func testMigration110TrackerQueryID(t *testing.T) {
 	require.True(t, checkTqConstraint(t, "tracker_queries", "PRIMARY KEY"))
 }
 
+func testMigration111WorkItemActionRules(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:112], 112)
+	require.True(t, dialect.HasTable("work_item_action_rules"))
+	require.True(t, dialect.HasIndex("work_item_action_rules", "work_item_action_rules_space_template_idx"))
+}
+
+func testMigration112TrackerWriteBack(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:113], 113)
+	require.True(t, dialect.HasColumn("tracker_queries", "write_back"))
+	require.True(t, dialect.HasColumn("tracker_items", "remote_updated_at"))
+	require.True(t, dialect.HasColumn("tracker_items", "synced_version"))
+	require.True(t, dialect.HasColumn("tracker_items", "synced_at"))
+}
+
+func testMigration113TrackerFieldMapping(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:114], 114)
+	require.True(t, dialect.HasColumn("trackers", "field_mapping"))
+	require.True(t, dialect.HasColumn("tracker_queries", "field_mapping"))
+}
+
+func testMigration114Webhooks(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:115], 115)
+	require.True(t, dialect.HasTable("webhooks"))
+	require.True(t, dialect.HasIndex("webhooks", "webhooks_space_id_idx"))
+}
+
+func testMigration115NotificationOutbox(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:116], 116)
+	require.True(t, dialect.HasTable("notification_outbox"))
+	require.True(t, dialect.HasIndex("notification_outbox", "notification_outbox_due_idx"))
+}
+
+func testMigration116TrackerQueryRuns(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:117], 117)
+	require.True(t, dialect.HasTable("tracker_query_runs"))
+	require.True(t, dialect.HasIndex("tracker_query_runs", "tracker_query_runs_tracker_query_id_idx"))
+	require.True(t, dialect.HasColumn("tracker_queries", "last_synced_at"))
+}
+
+func testMigration117CommentMentions(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:118], 118)
+	require.True(t, dialect.HasTable("comment_mentions"))
+	require.True(t, dialect.HasIndex("comment_mentions", "comment_mentions_identity_id_idx"))
+	require.True(t, dialect.HasIndex("comments", "comments_parent_comment_id_idx"))
+}
+
+func testMigration118Attachments(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:119], 119)
+	require.True(t, dialect.HasTable("attachments"))
+	require.True(t, dialect.HasIndex("attachments", "attachments_work_item_id_idx"))
+}
+
+func testMigration119WorkItemRevisionsTimeIndex(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:120], 120)
+	require.True(t, dialect.HasIndex("work_item_revisions", "work_item_revisions_work_item_id_time_idx"))
+}
+
+func testMigration120RevisionsTimeIndex(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:121], 121)
+	require.True(t, dialect.HasIndex("work_item_revisions", "work_item_revisions_time_idx"))
+	require.True(t, dialect.HasIndex("work_item_link_revisions", "work_item_link_revisions_time_idx"))
+	require.True(t, dialect.HasIndex("comment_revisions", "comment_revisions_time_idx"))
+}
+
+func testMigration121NotificationOutboxDeliveredTo(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:122], 122)
+	require.True(t, dialect.HasColumn("notification_outbox", "delivered_to"))
+}
+
+func testMigration122TrackerPushedComments(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:123], 123)
+	require.True(t, dialect.HasTable("tracker_pushed_comments"))
+}
+
 // runSQLscript loads the given filename from the packaged SQL test files and
 // executes it on the given database. Golang text/template module is used
 // to handle all the optional arguments passed to the sql test files
@@ -0,0 +1,19 @@
+-- new table for user configurable action rules of a space template
+CREATE TABLE work_item_action_rules (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    space_template_id uuid NOT NULL REFERENCES space_templates(id) ON DELETE CASCADE,
+    name text NOT NULL CHECK(name <> ''),
+    description text,
+    work_item_type_id uuid REFERENCES work_item_types(id) ON DELETE CASCADE,
+    attribute_name text NOT NULL CHECK(attribute_name <> ''),
+    attribute_value text,
+    action_key text NOT NULL CHECK(action_key <> ''),
+    action_config text NOT NULL DEFAULT '',
+    position integer DEFAULT 0 NOT NULL,
+    CONSTRAINT work_item_action_rules_name_space_template_id_unique UNIQUE(space_template_id, name)
+);
+
+CREATE INDEX work_item_action_rules_space_template_idx ON work_item_action_rules (space_template_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,7 @@
+-- opt-in write-back of local edits to the remote tracker
+ALTER TABLE tracker_queries ADD COLUMN write_back boolean DEFAULT false NOT NULL;
+
+-- sync markers used to detect local and remote changes since the last sync
+ALTER TABLE tracker_items ADD COLUMN remote_updated_at timestamp with time zone;
+ALTER TABLE tracker_items ADD COLUMN synced_version integer;
+ALTER TABLE tracker_items ADD COLUMN synced_at timestamp with time zone;
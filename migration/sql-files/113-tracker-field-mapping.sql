@@ -0,0 +1,3 @@
+-- configurable mapping of remote attributes to work item fields
+ALTER TABLE trackers ADD COLUMN field_mapping jsonb;
+ALTER TABLE tracker_queries ADD COLUMN field_mapping jsonb;
@@ -0,0 +1,14 @@
+-- outgoing webhooks subscribed to the events of a space
+CREATE TABLE webhooks (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    space_id uuid NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    url text NOT NULL CHECK(url <> ''),
+    event_types jsonb,
+    secret text NOT NULL CHECK(secret <> ''),
+    version integer DEFAULT 0 NOT NULL
+);
+
+CREATE INDEX webhooks_space_id_idx ON webhooks (space_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,19 @@
+-- notification messages written together with the changes they announce and
+-- delivered by a background dispatcher
+CREATE TABLE notification_outbox (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    message_id uuid NOT NULL,
+    message_type text NOT NULL CHECK(message_type <> ''),
+    space_id uuid,
+    target_id text NOT NULL,
+    user_id text,
+    custom jsonb,
+    attempts integer DEFAULT 0 NOT NULL,
+    next_attempt_at timestamp with time zone DEFAULT now() NOT NULL,
+    last_error text,
+    dead_at timestamp with time zone
+);
+
+CREATE INDEX notification_outbox_due_idx ON notification_outbox (next_attempt_at) WHERE dead_at IS NULL;
@@ -0,0 +1,22 @@
+-- history of the fetch and import runs of the tracker queries
+CREATE TABLE tracker_query_runs (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tracker_query_id uuid NOT NULL REFERENCES tracker_queries(id) ON DELETE CASCADE,
+    status text NOT NULL CHECK(status <> ''),
+    started_at timestamp with time zone NOT NULL,
+    finished_at timestamp with time zone,
+    since timestamp with time zone,
+    items_fetched integer DEFAULT 0 NOT NULL,
+    items_created integer DEFAULT 0 NOT NULL,
+    items_updated integer DEFAULT 0 NOT NULL,
+    items_failed integer DEFAULT 0 NOT NULL,
+    errors jsonb
+);
+
+CREATE INDEX tracker_query_runs_tracker_query_id_idx ON tracker_query_runs (tracker_query_id, started_at);
+
+-- the start of the last successful run from which the next run fetches the
+-- changed items only
+ALTER TABLE tracker_queries ADD COLUMN last_synced_at timestamp with time zone;
@@ -0,0 +1,12 @@
+-- identities mentioned with "@username" in the body of a comment
+CREATE TABLE comment_mentions (
+    created_at timestamp with time zone,
+    comment_id uuid NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    identity_id uuid NOT NULL REFERENCES identities(id) ON DELETE CASCADE,
+    PRIMARY KEY (comment_id, identity_id)
+);
+
+CREATE INDEX comment_mentions_identity_id_idx ON comment_mentions (identity_id);
+
+-- the replies of a comment are looked up to list the comments as threads
+CREATE INDEX comments_parent_comment_id_idx ON comments (parent_comment_id) WHERE parent_comment_id IS NOT NULL;
@@ -0,0 +1,17 @@
+-- files attached to work items or to their comments, the content is kept in a
+-- blob store under the blob key
+CREATE TABLE attachments (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    work_item_id uuid NOT NULL REFERENCES work_items(id) ON DELETE CASCADE,
+    comment_id uuid REFERENCES comments(id) ON DELETE CASCADE,
+    name text NOT NULL CHECK(name <> ''),
+    content_type text NOT NULL CHECK(content_type <> ''),
+    size bigint NOT NULL CHECK(size >= 0),
+    uploader_id uuid NOT NULL REFERENCES identities(id),
+    blob_key text NOT NULL UNIQUE CHECK(blob_key <> '')
+);
+
+CREATE INDEX attachments_work_item_id_idx ON attachments (work_item_id) WHERE deleted_at IS NULL;
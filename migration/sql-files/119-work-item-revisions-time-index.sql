@@ -0,0 +1,2 @@
+-- speeds up looking up the latest revision of a work item at a given time
+CREATE INDEX work_item_revisions_work_item_id_time_idx ON work_item_revisions USING BTREE (work_item_id, revision_time);
@@ -0,0 +1,4 @@
+-- bounds the change stream of a space to the changes after a given time
+CREATE INDEX work_item_revisions_time_idx ON work_item_revisions USING BTREE (revision_time);
+CREATE INDEX work_item_link_revisions_time_idx ON work_item_link_revisions USING BTREE (revision_time);
+CREATE INDEX comment_revisions_time_idx ON comment_revisions USING BTREE (revision_time);
@@ -0,0 +1,3 @@
+-- the recipients that accepted a notification message whose delivery failed
+-- for other recipients, so that retries skip them
+ALTER TABLE notification_outbox ADD COLUMN delivered_to jsonb;
@@ -0,0 +1,17 @@
+-- local comments written back to a remote tracker, so that they are posted
+-- exactly once no matter when the sync markers of their item change
+CREATE TABLE tracker_pushed_comments (
+    comment_id uuid PRIMARY KEY REFERENCES comments(id) ON DELETE CASCADE,
+    tracker_id uuid NOT NULL REFERENCES trackers(id) ON DELETE CASCADE,
+    pushed_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+-- the comments created before the last sync of their item were either
+-- written back already or predate the write-back and must not be posted
+INSERT INTO tracker_pushed_comments (comment_id, tracker_id, pushed_at)
+SELECT c.id, ti.tracker_id, ti.synced_at
+FROM tracker_items ti
+JOIN work_items wi ON wi.fields->>'system.remote_item_id' IN (ti.item::jsonb->>'url', ti.item::jsonb->>'self')
+JOIN comments c ON c.parent_id = wi.id
+WHERE ti.synced_at IS NOT NULL AND c.created_at <= ti.synced_at
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,191 @@
+package notification
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/metric"
+	"github.com/fabric8-services/fabric8-wit/notification/outbox"
+	errs "github.com/pkg/errors"
+)
+
+// maxOutboxBackoff caps the delay between two delivery attempts of a message
+const maxOutboxBackoff = time.Hour
+
+// Enqueue adds the message to the given outbox. Call it in the transaction
+// that makes the change the message is about so that the message is stored
+// if and only if the change is committed. The Dispatcher delivers it later.
+func Enqueue(ctx context.Context, repo outbox.Repository, msg Message) error {
+	setCurrentIdentity(ctx, &msg)
+	return repo.Enqueue(ctx, &outbox.Entry{
+		MessageID:   msg.MessageID,
+		MessageType: msg.MessageType,
+		SpaceID:     msg.SpaceID,
+		TargetID:    msg.TargetID,
+		UserID:      msg.UserID,
+		Custom:      msg.Custom,
+	})
+}
+
+func messageFromEntry(e outbox.Entry) Message {
+	return Message{
+		MessageID:   e.MessageID,
+		MessageType: e.MessageType,
+		SpaceID:     e.SpaceID,
+		TargetID:    e.TargetID,
+		UserID:      e.UserID,
+		Custom:      e.Custom,
+	}
+}
+
+// DispatcherConfiguration holds the configuration options of the Dispatcher
+type DispatcherConfiguration interface {
+	GetNotificationOutboxPollInterval() time.Duration
+	GetNotificationOutboxBatchSize() int
+	GetNotificationOutboxMaxAttempts() int
+	GetNotificationOutboxRetryBackoff() time.Duration
+	GetNotificationOutboxLease() time.Duration
+}
+
+// Dispatcher delivers the messages of the outbox through a Channel with
+// at-least-once semantics: a message is only removed from the outbox once the
+// channel accepted it. Failed deliveries are retried with an exponential
+// backoff and moved to the dead letters after the configured number of
+// attempts. Channels that are no Deliverer are considered to always accept
+// a message. The recipients that accepted a message are stored with the
+// entry, so a retry only reaches the others. The messages are leased in a
+// short transaction and delivered outside of it, so no row lock is held while
+// the channel is called.
+type Dispatcher struct {
+	db      application.DB
+	channel Channel
+	config  DispatcherConfiguration
+	wakeup  chan struct{}
+}
+
+// NewDispatcher creates a dispatcher delivering the outbox of the given DB
+// through the given channel
+func NewDispatcher(db application.DB, channel Channel, config DispatcherConfiguration) *Dispatcher {
+	return &Dispatcher{
+		db:      db,
+		channel: channel,
+		config:  config,
+		wakeup:  make(chan struct{}, 1),
+	}
+}
+
+// Send wakes up the dispatcher so that the messages of a just committed
+// transaction are delivered without waiting for the next poll. The message
+// itself must already be in the outbox, see Enqueue.
+func (d *Dispatcher) Send(context.Context, Message) {
+	select {
+	case d.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// Start runs the dispatcher in the background until the given context is done
+func (d *Dispatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(d.config.GetNotificationOutboxPollInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-d.wakeup:
+			}
+			// keep going while full batches are processed
+			for {
+				processed, err := d.Dispatch(ctx)
+				if err != nil {
+					log.Error(ctx, map[string]interface{}{
+						"err": err,
+					}, "failed to dispatch the notification outbox")
+					break
+				}
+				if processed < d.config.GetNotificationOutboxBatchSize() {
+					break
+				}
+			}
+		}
+	}()
+}
+
+// Dispatch delivers one batch of due messages and returns how many messages
+// it processed
+func (d *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	var entries []outbox.Entry
+	err := application.Transactional(d.db, func(appl application.Application) error {
+		var err error
+		entries, err = appl.Outbox().ClaimDue(ctx, d.config.GetNotificationOutboxBatchSize(), d.config.GetNotificationOutboxLease())
+		return err
+	})
+	if err != nil {
+		return 0, errs.Wrap(err, "failed to claim the due notifications")
+	}
+	for i, e := range entries {
+		receipts := Receipts{}
+		for _, recipient := range e.DeliveredTo {
+			receipts[recipient] = struct{}{}
+		}
+		deliveryErr := deliver(ctx, d.channel, messageFromEntry(e), receipts)
+		if err := d.record(ctx, e, receipts, deliveryErr); err != nil {
+			return i, errs.Wrapf(err, "failed to record the delivery of notification %s", e.MessageID)
+		}
+	}
+	pending, dead, err := d.db.Outbox().Count(ctx)
+	if err != nil {
+		return len(entries), errs.Wrap(err, "failed to count the notification outbox entries")
+	}
+	metric.ReportNotificationOutboxDepth(pending, dead)
+	return len(entries), nil
+}
+
+// record removes the delivered entry from the outbox or schedules the next
+// attempt of the failed one along with the recipients that accepted it. An
+// entry that is gone was handled by another dispatcher after its lease ended.
+func (d *Dispatcher) record(ctx context.Context, e outbox.Entry, receipts Receipts, deliveryErr error) error {
+	var err error
+	switch {
+	case deliveryErr == nil:
+		err = d.db.Outbox().Delete(ctx, e.ID)
+	case e.Attempts+1 >= d.config.GetNotificationOutboxMaxAttempts():
+		log.Error(ctx, map[string]interface{}{
+			"message_id": e.MessageID,
+			"type":       e.MessageType,
+			"attempts":   e.Attempts + 1,
+			"err":        deliveryErr,
+		}, "giving up on the notification")
+		err = d.db.Outbox().Kill(ctx, e.ID, deliveryErr.Error())
+	default:
+		deliveredTo := make(outbox.Recipients, 0, len(receipts))
+		for recipient := range receipts {
+			deliveredTo = append(deliveredTo, recipient)
+		}
+		sort.Strings(deliveredTo)
+		err = d.db.Outbox().Retry(ctx, e.ID, deliveryErr.Error(), time.Now().Add(d.backoff(e.Attempts)), deliveredTo)
+	}
+	if ok, _ := errors.IsNotFoundError(err); ok {
+		return nil
+	}
+	return err
+}
+
+// backoff returns the delay before the next attempt after the given number
+// of failed attempts
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	backoff := d.config.GetNotificationOutboxRetryBackoff()
+	for i := 0; i < attempts && backoff < maxOutboxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxOutboxBackoff {
+		return maxOutboxBackoff
+	}
+	return backoff
+}
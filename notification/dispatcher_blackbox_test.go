@@ -0,0 +1,149 @@
+package notification_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/notification"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type dispatcherConfig struct{}
+
+func (dispatcherConfig) GetNotificationOutboxPollInterval() time.Duration { return time.Second }
+func (dispatcherConfig) GetNotificationOutboxBatchSize() int              { return 1000 }
+func (dispatcherConfig) GetNotificationOutboxMaxAttempts() int            { return 2 }
+func (dispatcherConfig) GetNotificationOutboxRetryBackoff() time.Duration { return 0 }
+func (dispatcherConfig) GetNotificationOutboxLease() time.Duration        { return time.Minute }
+
+// failingChannel records the delivered messages and fails the delivery of
+// the messages whose target is in failFor
+type failingChannel struct {
+	delivered []notification.Message
+	failFor   map[string]bool
+}
+
+func (c *failingChannel) Send(context.Context, notification.Message) {}
+
+func (c *failingChannel) Deliver(ctx context.Context, msg notification.Message, receipts notification.Receipts) error {
+	if c.failFor[msg.TargetID] {
+		return errs.New("delivery failed")
+	}
+	c.delivered = append(c.delivered, msg)
+	return nil
+}
+
+// recipientChannel delivers a message to the named recipient unless it
+// already accepted it and counts the deliveries per message
+type recipientChannel struct {
+	name       string
+	fail       bool
+	deliveries map[uuid.UUID]int
+}
+
+func (c *recipientChannel) Send(context.Context, notification.Message) {}
+
+func (c *recipientChannel) Deliver(ctx context.Context, msg notification.Message, receipts notification.Receipts) error {
+	if _, ok := receipts[c.name]; ok {
+		return nil
+	}
+	c.deliveries[msg.MessageID]++
+	if c.fail {
+		return errs.New("delivery failed")
+	}
+	receipts[c.name] = struct{}{}
+	return nil
+}
+
+type dispatcherSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestDispatcher(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &dispatcherSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *dispatcherSuite) TestDispatch() {
+	// given two messages enqueued in a committed transaction and one that was rolled back
+	ok := notification.NewWorkItemCreated(uuid.NewV4(), uuid.NewV4().String(), uuid.NewV4())
+	failing := notification.NewCommentCreated(uuid.NewV4(), uuid.NewV4().String())
+	rolledBack := notification.NewWorkItemDeleted(uuid.NewV4(), uuid.NewV4().String())
+	err := application.Transactional(s.GormDB, func(appl application.Application) error {
+		require.NoError(s.T(), notification.Enqueue(s.Ctx, appl.Outbox(), ok))
+		return notification.Enqueue(s.Ctx, appl.Outbox(), failing)
+	})
+	require.NoError(s.T(), err)
+	err = application.Transactional(s.GormDB, func(appl application.Application) error {
+		require.NoError(s.T(), notification.Enqueue(s.Ctx, appl.Outbox(), rolledBack))
+		return errs.New("rollback")
+	})
+	require.Error(s.T(), err)
+	_, deadBefore, err := s.GormDB.Outbox().Count(s.Ctx)
+	require.NoError(s.T(), err)
+	channel := &failingChannel{failFor: map[string]bool{failing.TargetID: true}}
+	dispatcher := notification.NewDispatcher(s.GormDB, channel, dispatcherConfig{})
+
+	s.T().Run("delivered messages leave the outbox", func(t *testing.T) {
+		// when
+		_, err := dispatcher.Dispatch(s.Ctx)
+		// then
+		require.NoError(t, err)
+		targets := map[string]notification.Message{}
+		for _, msg := range channel.delivered {
+			targets[msg.TargetID] = msg
+		}
+		require.Contains(t, targets, ok.TargetID)
+		assert.Equal(t, ok.MessageID, targets[ok.TargetID].MessageID)
+		assert.Equal(t, ok.SpaceID, targets[ok.TargetID].SpaceID)
+		assert.Equal(t, "workitem.create", targets[ok.TargetID].MessageType)
+		assert.NotContains(t, targets, failing.TargetID)
+		assert.NotContains(t, targets, rolledBack.TargetID)
+	})
+
+	s.T().Run("failing messages end up in the dead letters", func(t *testing.T) {
+		// when
+		channel.delivered = nil
+		_, err := dispatcher.Dispatch(s.Ctx)
+		// then
+		require.NoError(t, err)
+		for _, msg := range channel.delivered {
+			assert.NotEqual(t, ok.TargetID, msg.TargetID, "delivered messages must not be delivered again")
+		}
+		_, dead, err := s.GormDB.Outbox().Count(s.Ctx)
+		require.NoError(t, err)
+		assert.Equal(t, deadBefore+1, dead)
+	})
+}
+
+func (s *dispatcherSuite) TestDispatchRetry() {
+	// given a message delivered through a channel that accepts it and one
+	// that fails once
+	msg := notification.NewWorkItemCreated(uuid.NewV4(), uuid.NewV4().String(), uuid.NewV4())
+	err := application.Transactional(s.GormDB, func(appl application.Application) error {
+		return notification.Enqueue(s.Ctx, appl.Outbox(), msg)
+	})
+	require.NoError(s.T(), err)
+	accepting := &recipientChannel{name: "accepting", deliveries: map[uuid.UUID]int{}}
+	failing := &recipientChannel{name: "failing", fail: true, deliveries: map[uuid.UUID]int{}}
+	dispatcher := notification.NewDispatcher(s.GormDB, notification.MultiChannel{accepting, failing}, dispatcherConfig{})
+	_, err = dispatcher.Dispatch(s.Ctx)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, accepting.deliveries[msg.MessageID])
+	require.Equal(s.T(), 1, failing.deliveries[msg.MessageID])
+	// when
+	failing.fail = false
+	_, err = dispatcher.Dispatch(s.Ctx)
+	// then the retry only reaches the channel that failed
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, accepting.deliveries[msg.MessageID])
+	assert.Equal(s.T(), 2, failing.deliveries[msg.MessageID])
+}
@@ -2,11 +2,13 @@ package notification
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
-	"fmt"
-
+	"github.com/fabric8-services/fabric8-wit/auth"
 	"github.com/fabric8-services/fabric8-wit/goasupport"
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/login"
@@ -14,6 +16,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/rest"
 	goaclient "github.com/goadesign/goa/client"
 	goauuid "github.com/goadesign/goa/uuid"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -22,53 +25,157 @@ type Channel interface {
 	Send(context.Context, Message)
 }
 
+// Receipts holds the names of the recipients that accepted a message, e.g.
+// the notification service or a single webhook
+type Receipts map[string]struct{}
+
+// Deliverer is implemented by channels that can report whether a message was
+// delivered. The Dispatcher retries messages whose delivery failed. Deliver
+// skips the recipients that are in the given receipts and adds the ones that
+// accept the message, even if the delivery fails for other recipients. This
+// way a retry only reaches the recipients that didn't accept the message yet.
+type Deliverer interface {
+	Deliver(context.Context, Message, Receipts) error
+}
+
+// deliver uses the Deliverer of the channel if it has one and falls back to
+// a fire-and-forget Send otherwise
+func deliver(ctx context.Context, c Channel, msg Message, receipts Receipts) error {
+	if d, ok := c.(Deliverer); ok {
+		return d.Deliver(ctx, msg, receipts)
+	}
+	c.Send(ctx, msg)
+	return nil
+}
+
+// The types of the messages sent through a Channel
+const (
+	MessageTypeWorkItemCreate = "workitem.create"
+	MessageTypeWorkItemUpdate = "workitem.update"
+	MessageTypeWorkItemDelete = "workitem.delete"
+	MessageTypeCommentCreate  = "comment.create"
+	MessageTypeCommentUpdate  = "comment.update"
+	MessageTypeCommentMention = "comment.mention"
+	MessageTypeLinkCreate     = "link.create"
+	MessageTypeLinkDelete     = "link.delete"
+)
+
+// MessageTypes holds all known message types
+var MessageTypes = []string{
+	MessageTypeWorkItemCreate,
+	MessageTypeWorkItemUpdate,
+	MessageTypeWorkItemDelete,
+	MessageTypeCommentCreate,
+	MessageTypeCommentUpdate,
+	MessageTypeCommentMention,
+	MessageTypeLinkCreate,
+	MessageTypeLinkDelete,
+}
+
+// IsKnownMessageType returns true if the given type is one of the MessageTypes
+func IsKnownMessageType(messageType string) bool {
+	for _, t := range MessageTypes {
+		if t == messageType {
+			return true
+		}
+	}
+	return false
+}
+
 // Message represents a new event of a Type for a Target performed by a User
 // See helper constructors like NewWorkItemCreated, NewCommentUpdated
 type Message struct {
 	MessageID   uuid.UUID // unique ID per event
 	UserID      *string
+	SpaceID     uuid.UUID // the space in which the event happened
 	TargetID    string
 	MessageType string
 	Custom      map[string]interface{}
 }
 
 func (m Message) String() string {
-	return fmt.Sprintf("id:%v type:%v by:%v for:%v custom:%+v", m.MessageID, m.MessageType, m.UserID, m.TargetID, m.Custom)
+	return fmt.Sprintf("id:%v type:%v by:%v in:%v for:%v custom:%+v", m.MessageID, m.MessageType, m.UserID, m.SpaceID, m.TargetID, m.Custom)
 }
 
 // NewWorkItemCreated creates a new message instance for the newly created WorkItemID
-func NewWorkItemCreated(workitemID string, revisionID uuid.UUID) Message {
+func NewWorkItemCreated(spaceID uuid.UUID, workitemID string, revisionID uuid.UUID) Message {
 	return Message{
 		MessageID:   uuid.NewV4(),
-		MessageType: "workitem.create",
+		MessageType: MessageTypeWorkItemCreate,
+		SpaceID:     spaceID,
 		TargetID:    workitemID,
 		Custom:      map[string]interface{}{"revision_id": revisionID},
 	}
 }
 
 // NewWorkItemUpdated creates a new message instance for the updated WorkItemID
-func NewWorkItemUpdated(workitemID string, revisionID uuid.UUID) Message {
+func NewWorkItemUpdated(spaceID uuid.UUID, workitemID string, revisionID uuid.UUID) Message {
 	return Message{
 		MessageID:   uuid.NewV4(),
-		MessageType: "workitem.update",
+		MessageType: MessageTypeWorkItemUpdate,
+		SpaceID:     spaceID,
 		TargetID:    workitemID,
 		Custom:      map[string]interface{}{"revision_id": revisionID},
 	}
 }
 
+// NewWorkItemDeleted creates a new message instance for the deleted WorkItemID
+func NewWorkItemDeleted(spaceID uuid.UUID, workitemID string) Message {
+	return Message{MessageID: uuid.NewV4(), MessageType: MessageTypeWorkItemDelete, SpaceID: spaceID, TargetID: workitemID}
+}
+
 // NewCommentCreated creates a new message instance for the newly created CommentID
-func NewCommentCreated(commentID string) Message {
-	return Message{MessageID: uuid.NewV4(), MessageType: "comment.create", TargetID: commentID}
+func NewCommentCreated(spaceID uuid.UUID, commentID string) Message {
+	return Message{MessageID: uuid.NewV4(), MessageType: MessageTypeCommentCreate, SpaceID: spaceID, TargetID: commentID}
 }
 
 // NewCommentUpdated creates a new message instance for the updated CommentID
-func NewCommentUpdated(commentID string) Message {
-	return Message{MessageID: uuid.NewV4(), MessageType: "comment.update", TargetID: commentID}
+func NewCommentUpdated(spaceID uuid.UUID, commentID string) Message {
+	return Message{MessageID: uuid.NewV4(), MessageType: MessageTypeCommentUpdate, SpaceID: spaceID, TargetID: commentID}
+}
+
+// NewCommentMentioned creates a new message instance for the identity that
+// was mentioned in the given CommentID
+func NewCommentMentioned(spaceID uuid.UUID, commentID string, identityID uuid.UUID) Message {
+	return Message{
+		MessageID:   uuid.NewV4(),
+		MessageType: MessageTypeCommentMention,
+		SpaceID:     spaceID,
+		TargetID:    commentID,
+		Custom:      map[string]interface{}{"mentioned_identity_id": identityID},
+	}
+}
+
+// NewLinkCreated creates a new message instance for the newly created LinkID
+// between the given source and target work items
+func NewLinkCreated(spaceID uuid.UUID, linkID string, sourceID, targetID uuid.UUID) Message {
+	return Message{
+		MessageID:   uuid.NewV4(),
+		MessageType: MessageTypeLinkCreate,
+		SpaceID:     spaceID,
+		TargetID:    linkID,
+		Custom:      map[string]interface{}{"source_id": sourceID, "target_id": targetID},
+	}
+}
+
+// NewLinkDeleted creates a new message instance for the deleted LinkID
+// between the given source and target work items
+func NewLinkDeleted(spaceID uuid.UUID, linkID string, sourceID, targetID uuid.UUID) Message {
+	return Message{
+		MessageID:   uuid.NewV4(),
+		MessageType: MessageTypeLinkDelete,
+		SpaceID:     spaceID,
+		TargetID:    linkID,
+		Custom:      map[string]interface{}{"source_id": sourceID, "target_id": targetID},
+	}
 }
 
 func setCurrentIdentity(ctx context.Context, msg *Message) {
+	if msg.UserID != nil {
+		return
+	}
 	currentUserIdentityID, err := login.ContextIdentity(ctx)
-	if err != nil {
+	if err == nil {
 		uID := currentUserIdentityID.String()
 		msg.UserID = &uID
 	}
@@ -80,14 +187,63 @@ type DevNullChannel struct{}
 // Send NO-OP
 func (d *DevNullChannel) Send(context.Context, Message) {}
 
+// MultiChannel passes every message on to all of its channels
+type MultiChannel []Channel
+
+// Send sends the message to all channels
+func (m MultiChannel) Send(ctx context.Context, msg Message) {
+	for _, c := range m {
+		c.Send(ctx, msg)
+	}
+}
+
+// Deliver delivers the message to all channels and fails if any of them
+// failed. The receipts are shared by the channels, so a retry only reaches
+// the recipients that didn't accept the message yet. Channels that are no
+// Deliverer receive the message on every attempt.
+func (m MultiChannel) Deliver(ctx context.Context, msg Message, receipts Receipts) error {
+	var failures []string
+	for _, c := range m {
+		if err := deliver(ctx, c, msg, receipts); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	if len(failures) > 0 {
+		return errs.Errorf("failed to deliver message %s to %d channel(s): %s", msg.MessageID, len(failures), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
 // ServiceConfiguration holds configuration options required to interact with the fabric8-notification API
 type ServiceConfiguration interface {
+	auth.ServiceAccountConfiguration
 	GetNotificationServiceURL() string
+	GetNotificationServiceHTTPTimeout() time.Duration
 }
 
-// Service is a simple client Channel to the fabric8-notification service
+// Service is a simple client Channel to the fabric8-notification service.
+// Messages are delivered in the background, so the requests are signed with
+// a token of the service account rather than the one of the user. Without
+// service account credentials the requests carry the token of the request
+// that sent the message, if any, and are unsigned otherwise.
 type Service struct {
 	config ServiceConfiguration
+	signer goaclient.Signer
+	client *http.Client
+}
+
+// serviceRecipient is the name of the fabric8-notification service in the
+// Receipts of a message
+const serviceRecipient = "notification-service"
+
+// serviceMessageTypes are the message types the fabric8-notification service
+// knows how to handle
+var serviceMessageTypes = map[string]struct{}{
+	MessageTypeWorkItemCreate: {},
+	MessageTypeWorkItemUpdate: {},
+	MessageTypeCommentCreate:  {},
+	MessageTypeCommentUpdate:  {},
+	MessageTypeCommentMention: {},
 }
 
 func validateConfig(config ServiceConfiguration) error {
@@ -104,61 +260,83 @@ func NewServiceChannel(config ServiceConfiguration) (Channel, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Service{config: config}, nil
+	s := &Service{
+		config: config,
+		client: &http.Client{Timeout: config.GetNotificationServiceHTTPTimeout()},
+	}
+	if config.GetServiceAccountID() == "" || config.GetServiceAccountSecret() == "" {
+		log.Warn(nil, map[string]interface{}{
+			"url": config.GetNotificationServiceURL(),
+		}, "missing service account credentials, the notifications delivered in the background are not signed")
+	} else {
+		s.signer = auth.NewServiceAccountSigner(config)
+	}
+	return s, nil
 }
 
-// Send invokes the fabric8-notification API
+// Send invokes the fabric8-notification API in the background
 func (s *Service) Send(ctx context.Context, msg Message) {
+	setCurrentIdentity(ctx, &msg)
 	go func(ctx context.Context, msg Message) {
-		setCurrentIdentity(ctx, &msg)
-
-		u, err := url.Parse(s.config.GetNotificationServiceURL())
-		if err != nil {
-			log.Error(ctx, map[string]interface{}{
-				"url": s.config.GetNotificationServiceURL(),
-				"err": err,
-			}, "unable to parse GetNotificationServiceURL")
-		}
-
-		cl := client.New(goaclient.HTTPClientDoer(http.DefaultClient))
-		cl.Host = u.Host
-		cl.Scheme = u.Scheme
-		cl.SetJWTSigner(goasupport.NewForwardSigner(ctx))
-
-		msgID := goauuid.UUID(msg.MessageID)
-
-		resp, err := cl.SendNotify(
-			goasupport.ForwardContextRequestID(ctx),
-			client.SendNotifyPath(),
-			&client.SendNotifyPayload{
-				Data: &client.Notification{
-					Type: "notifications",
-					ID:   &msgID,
-					Attributes: &client.NotificationAttributes{
-						Type:   msg.MessageType,
-						ID:     msg.TargetID,
-						Custom: msg.Custom,
-					},
-				},
-			},
-		)
-		if err != nil {
+		if err := s.Deliver(ctx, msg, Receipts{}); err != nil {
 			log.Error(ctx, map[string]interface{}{
 				"message_id": msg.MessageID,
 				"type":       msg.MessageType,
 				"target_id":  msg.TargetID,
 				"err":        err,
 			}, "unable to send notification")
-		} else if resp.StatusCode >= 400 {
-			log.Error(ctx, map[string]interface{}{
-				"status":     resp.StatusCode,
-				"message_id": msg.MessageID,
-				"type":       msg.MessageType,
-				"target_id":  msg.TargetID,
-				"err":        err,
-			}, "unexpected response code")
 		}
-		defer rest.CloseResponse(resp)
-
 	}(ctx, msg)
 }
+
+// Deliver invokes the fabric8-notification API and returns an error unless
+// the service accepted the message. Messages of types the service does not
+// handle and messages the service already accepted are skipped.
+func (s *Service) Deliver(ctx context.Context, msg Message, receipts Receipts) error {
+	if _, ok := serviceMessageTypes[msg.MessageType]; !ok {
+		return nil
+	}
+	if _, ok := receipts[serviceRecipient]; ok {
+		return nil
+	}
+	u, err := url.Parse(s.config.GetNotificationServiceURL())
+	if err != nil {
+		return errs.Wrapf(err, "unable to parse the notification service url %s", s.config.GetNotificationServiceURL())
+	}
+
+	cl := client.New(goaclient.HTTPClientDoer(s.client))
+	cl.Host = u.Host
+	cl.Scheme = u.Scheme
+	if s.signer != nil {
+		cl.SetJWTSigner(s.signer)
+	} else {
+		cl.SetJWTSigner(goasupport.NewForwardSigner(ctx))
+	}
+
+	msgID := goauuid.UUID(msg.MessageID)
+
+	resp, err := cl.SendNotify(
+		goasupport.ForwardContextRequestID(ctx),
+		client.SendNotifyPath(),
+		&client.SendNotifyPayload{
+			Data: &client.Notification{
+				Type: "notifications",
+				ID:   &msgID,
+				Attributes: &client.NotificationAttributes{
+					Type:   msg.MessageType,
+					ID:     msg.TargetID,
+					Custom: msg.Custom,
+				},
+			},
+		},
+	)
+	if err != nil {
+		return errs.Wrapf(err, "unable to send notification %s", msg.MessageID)
+	}
+	defer rest.CloseResponse(resp)
+	if resp.StatusCode >= 400 {
+		return errs.Errorf("unexpected response code %d for notification %s", resp.StatusCode, msg.MessageID)
+	}
+	receipts[serviceRecipient] = struct{}{}
+	return nil
+}
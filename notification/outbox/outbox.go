@@ -0,0 +1,248 @@
+package outbox
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// OutboxTableName constant that holds table name of the notification outbox
+const OutboxTableName = "notification_outbox"
+
+// Custom holds the custom attributes of a notification message
+type Custom map[string]interface{}
+
+// Ensure Custom implements the Valuer interface
+var _ driver.Valuer = Custom{}
+
+// Value implements the https://golang.org/pkg/database/sql/driver/#Valuer
+// interface
+func (c Custom) Value() (driver.Value, error) {
+	if len(c) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements the https://golang.org/pkg/database/sql/#Scanner
+// interface
+func (c *Custom) Scan(src interface{}) error {
+	*c = nil
+	if src == nil {
+		return nil
+	}
+	bytes, ok := src.([]byte)
+	if !ok {
+		return errs.Errorf("scanned value is not a byte array: %+v (%[1]T)", src)
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+// Recipients holds the names of the recipients that accepted a notification
+// message
+type Recipients []string
+
+// Ensure Recipients implements the Valuer interface
+var _ driver.Valuer = Recipients{}
+
+// Value implements the https://golang.org/pkg/database/sql/driver/#Valuer
+// interface
+func (r Recipients) Value() (driver.Value, error) {
+	if len(r) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(r)
+}
+
+// Scan implements the https://golang.org/pkg/database/sql/#Scanner
+// interface
+func (r *Recipients) Scan(src interface{}) error {
+	*r = nil
+	if src == nil {
+		return nil
+	}
+	bytes, ok := src.([]byte)
+	if !ok {
+		return errs.Errorf("scanned value is not a byte array: %+v (%[1]T)", src)
+	}
+	return json.Unmarshal(bytes, r)
+}
+
+// Entry is a notification message waiting in the outbox to be delivered. An
+// entry is removed once it was delivered and marked as dead once all delivery
+// attempts failed. DeliveredTo holds the recipients that accepted the message
+// in an attempt that failed for other recipients.
+type Entry struct {
+	ID            uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	MessageID     uuid.UUID `sql:"type:uuid"`
+	MessageType   string
+	SpaceID       uuid.UUID `sql:"type:uuid"`
+	TargetID      string
+	UserID        *string
+	Custom        Custom     `sql:"type:jsonb"`
+	DeliveredTo   Recipients `sql:"type:jsonb"`
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     *string
+	DeadAt        *time.Time
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (e Entry) TableName() string {
+	return OutboxTableName
+}
+
+// Repository describes interactions with the notification outbox.
+type Repository interface {
+	// Enqueue stores a new entry that is due for delivery immediately
+	Enqueue(ctx context.Context, e *Entry) error
+	// ClaimDue returns up to limit entries that are due for delivery and
+	// leases them: their next attempt is postponed by the lease duration so
+	// that no other dispatcher claims them while they are delivered. Entries
+	// claimed by concurrent transactions are skipped. An entry that is neither
+	// deleted, retried nor killed before its lease ends is due again.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]Entry, error)
+	// Delete removes a delivered entry
+	Delete(ctx context.Context, ID uuid.UUID) error
+	// Retry records a failed delivery attempt along with the recipients that
+	// accepted the message so far and schedules the next one
+	Retry(ctx context.Context, ID uuid.UUID, lastError string, nextAttemptAt time.Time, deliveredTo Recipients) error
+	// Kill records a failed delivery attempt and moves the entry to the dead
+	// letters which are never delivered again
+	Kill(ctx context.Context, ID uuid.UUID, lastError string) error
+	// Count returns the number of pending and dead entries
+	Count(ctx context.Context) (pending int, dead int, err error)
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+// GormRepository is the implementation of the storage interface for the
+// notification outbox.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+// Enqueue implements Repository
+func (r *GormRepository) Enqueue(ctx context.Context, e *Entry) error {
+	defer goa.MeasureSince([]string{"goa", "db", "outbox", "enqueue"}, time.Now())
+	e.ID = uuid.NewV4()
+	e.NextAttemptAt = time.Now()
+	if err := r.db.Create(e).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"message_id": e.MessageID,
+			"type":       e.MessageType,
+			"err":        err,
+		}, "unable to add the message to the outbox")
+		return errors.NewInternalError(ctx, err)
+	}
+	return nil
+}
+
+// ClaimDue implements Repository
+func (r *GormRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]Entry, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "outbox", "claim"}, time.Now())
+	var entries []Entry
+	err := r.db.Set("gorm:query_option", "FOR UPDATE SKIP LOCKED").
+		Where("dead_at IS NULL AND next_attempt_at <= ?", time.Now()).
+		Order("created_at").
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+	ids := make([]uuid.UUID, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	err = r.db.Model(&Entry{}).Where("id IN (?)", ids).UpdateColumn("next_attempt_at", time.Now().Add(lease)).Error
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "unable to lease the outbox entries")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return entries, nil
+}
+
+// Delete implements Repository
+func (r *GormRepository) Delete(ctx context.Context, ID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "outbox", "delete"}, time.Now())
+	tx := r.db.Delete(Entry{ID: ID})
+	if tx.Error != nil {
+		return errors.NewInternalError(ctx, tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("outbox entry", ID.String())
+	}
+	return nil
+}
+
+// Retry implements Repository
+func (r *GormRepository) Retry(ctx context.Context, ID uuid.UUID, lastError string, nextAttemptAt time.Time, deliveredTo Recipients) error {
+	defer goa.MeasureSince([]string{"goa", "db", "outbox", "retry"}, time.Now())
+	return r.update(ctx, ID, map[string]interface{}{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt,
+		"delivered_to":    deliveredTo,
+	})
+}
+
+// Kill implements Repository
+func (r *GormRepository) Kill(ctx context.Context, ID uuid.UUID, lastError string) error {
+	defer goa.MeasureSince([]string{"goa", "db", "outbox", "kill"}, time.Now())
+	return r.update(ctx, ID, map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": lastError,
+		"dead_at":    time.Now(),
+	})
+}
+
+func (r *GormRepository) update(ctx context.Context, ID uuid.UUID, fields map[string]interface{}) error {
+	tx := r.db.Model(&Entry{}).Where("id = ?", ID).Updates(fields)
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"entry_id": ID,
+			"err":      tx.Error,
+		}, "unable to update the outbox entry")
+		return errors.NewInternalError(ctx, tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("outbox entry", ID.String())
+	}
+	return nil
+}
+
+// Count implements Repository
+func (r *GormRepository) Count(ctx context.Context) (int, int, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "outbox", "count"}, time.Now())
+	var res struct {
+		Pending int
+		Dead    int
+	}
+	err := r.db.Raw(`SELECT
+		count(*) FILTER (WHERE dead_at IS NULL) AS pending,
+		count(*) FILTER (WHERE dead_at IS NOT NULL) AS dead
+		FROM ` + OutboxTableName).Scan(&res).Error
+	if err != nil {
+		return 0, 0, errors.NewInternalError(ctx, err)
+	}
+	return res.Pending, res.Dead, nil
+}
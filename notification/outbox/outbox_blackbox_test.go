@@ -0,0 +1,104 @@
+package outbox_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/notification/outbox"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type outboxRepositorySuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestOutboxRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &outboxRepositorySuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *outboxRepositorySuite) enqueue(t *testing.T, repo outbox.Repository) outbox.Entry {
+	e := outbox.Entry{
+		MessageID:   uuid.NewV4(),
+		MessageType: "workitem.create",
+		SpaceID:     uuid.NewV4(),
+		TargetID:    uuid.NewV4().String(),
+		Custom:      outbox.Custom{"revision_id": uuid.NewV4().String()},
+	}
+	require.NoError(t, repo.Enqueue(s.Ctx, &e))
+	return e
+}
+
+func (s *outboxRepositorySuite) TestLifecycle() {
+	// given
+	repo := outbox.NewRepository(s.DB)
+	pending, dead, err := repo.Count(s.Ctx)
+	require.NoError(s.T(), err)
+	first := s.enqueue(s.T(), repo)
+	second := s.enqueue(s.T(), repo)
+
+	s.T().Run("claim due entries", func(t *testing.T) {
+		entries, err := repo.ClaimDue(s.Ctx, 1000, time.Minute)
+		require.NoError(t, err)
+		ids := map[uuid.UUID]outbox.Entry{}
+		for _, e := range entries {
+			ids[e.MessageID] = e
+		}
+		require.Contains(t, ids, first.MessageID)
+		require.Contains(t, ids, second.MessageID)
+		assert.Equal(t, first.Custom, ids[first.MessageID].Custom)
+		assert.Equal(t, first.TargetID, ids[first.MessageID].TargetID)
+		assert.Equal(t, 0, ids[first.MessageID].Attempts)
+	})
+
+	s.T().Run("claimed entries are not due before their lease ends", func(t *testing.T) {
+		entries, err := repo.ClaimDue(s.Ctx, 1000, time.Minute)
+		require.NoError(t, err)
+		for _, e := range entries {
+			assert.NotEqual(t, first.ID, e.ID)
+			assert.NotEqual(t, second.ID, e.ID)
+		}
+	})
+
+	s.T().Run("retried entries are not due before their next attempt", func(t *testing.T) {
+		require.NoError(t, repo.Retry(s.Ctx, first.ID, "boom", time.Now().Add(time.Hour), outbox.Recipients{"notification-service"}))
+		entries, err := repo.ClaimDue(s.Ctx, 1000, time.Minute)
+		require.NoError(t, err)
+		for _, e := range entries {
+			assert.NotEqual(t, first.ID, e.ID)
+		}
+	})
+
+	s.T().Run("retried entries keep the recipients that accepted them", func(t *testing.T) {
+		var e outbox.Entry
+		require.NoError(t, s.DB.Where("id = ?", first.ID).First(&e).Error)
+		assert.Equal(t, outbox.Recipients{"notification-service"}, e.DeliveredTo)
+		assert.Equal(t, 1, e.Attempts)
+	})
+
+	s.T().Run("dead entries are counted but never due", func(t *testing.T) {
+		require.NoError(t, repo.Kill(s.Ctx, second.ID, "boom"))
+		entries, err := repo.ClaimDue(s.Ctx, 1000, time.Minute)
+		require.NoError(t, err)
+		for _, e := range entries {
+			assert.NotEqual(t, second.ID, e.ID)
+		}
+		p, d, err := repo.Count(s.Ctx)
+		require.NoError(t, err)
+		assert.Equal(t, pending+1, p)
+		assert.Equal(t, dead+1, d)
+	})
+
+	s.T().Run("delete delivered entry", func(t *testing.T) {
+		require.NoError(t, repo.Delete(s.Ctx, first.ID))
+		require.Error(t, repo.Delete(s.Ctx, first.ID))
+		p, _, err := repo.Count(s.Ctx)
+		require.NoError(t, err)
+		assert.Equal(t, pending, p)
+	})
+}
@@ -0,0 +1,163 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/webhook"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// The headers sent along with every webhook payload
+const (
+	WebhookHeaderEvent     = "X-WIT-Event"
+	WebhookHeaderDelivery  = "X-WIT-Delivery"
+	WebhookHeaderSignature = "X-WIT-Signature"
+)
+
+// WebhookConfiguration holds the configuration options of the webhook deliveries
+type WebhookConfiguration interface {
+	GetWebhookHTTPTimeout() time.Duration
+}
+
+// WebhookPayload is the JSON document posted to the URL of a webhook
+type WebhookPayload struct {
+	ID        uuid.UUID              `json:"id"`
+	Event     string                 `json:"event"`
+	SpaceID   uuid.UUID              `json:"space_id"`
+	TargetID  string                 `json:"target_id"`
+	UserID    *string                `json:"user_id,omitempty"`
+	Custom    map[string]interface{} `json:"custom,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// SignWebhookPayload returns the value of the signature header for the given
+// payload, i.e. the hex encoded HMAC-SHA256 of the payload keyed with the
+// webhook secret and prefixed with "sha256=".
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookChannel is a Channel that posts the messages of a space to the
+// webhooks subscribed to them
+type WebhookChannel struct {
+	db     *gorm.DB
+	client *http.Client
+}
+
+// NewWebhookChannel creates a channel delivering messages to the webhooks
+// stored in the given DB
+func NewWebhookChannel(db *gorm.DB, config WebhookConfiguration) *WebhookChannel {
+	return &WebhookChannel{
+		db:     db,
+		client: webhook.NewHTTPClient(config.GetWebhookHTTPTimeout()),
+	}
+}
+
+// Send delivers the message in the background. Failed deliveries are only
+// logged, use the Dispatcher to have them retried.
+func (c *WebhookChannel) Send(ctx context.Context, msg Message) {
+	setCurrentIdentity(ctx, &msg)
+	go func(ctx context.Context, msg Message) {
+		if err := c.Deliver(ctx, msg, Receipts{}); err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"message_id": msg.MessageID,
+				"type":       msg.MessageType,
+				"err":        err,
+			}, "unable to deliver the webhooks")
+		}
+	}(ctx, msg)
+}
+
+// Deliver posts the signed message payload once to every webhook of the
+// message's space that is subscribed to the message type and fails if any
+// of them did not accept it. The webhooks in the given receipts are skipped
+// and the ones that accept the message are added to them, so a retried
+// delivery only posts to the webhooks that didn't accept the message yet.
+func (c *WebhookChannel) Deliver(ctx context.Context, msg Message, receipts Receipts) error {
+	if uuid.Equal(msg.SpaceID, uuid.Nil) {
+		return nil
+	}
+	hooks, err := webhook.NewWebhookRepository(c.db).List(ctx, msg.SpaceID)
+	if err != nil {
+		return errs.Wrapf(err, "failed to list the webhooks of space %s", msg.SpaceID)
+	}
+	body, err := json.Marshal(WebhookPayload{
+		ID:        msg.MessageID,
+		Event:     msg.MessageType,
+		SpaceID:   msg.SpaceID,
+		TargetID:  msg.TargetID,
+		UserID:    msg.UserID,
+		Custom:    msg.Custom,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return errs.Wrapf(err, "failed to marshal the payload of message %s", msg.MessageID)
+	}
+	var failures []string
+	for _, hook := range hooks {
+		if !hook.Accepts(msg.MessageType) {
+			continue
+		}
+		recipient := webhookRecipient(hook.ID)
+		if _, ok := receipts[recipient]; ok {
+			continue
+		}
+		if err := c.post(hook.URL, msg, body, SignWebhookPayload(hook.Secret, body)); err != nil {
+			log.Warn(ctx, map[string]interface{}{
+				"webhook_id": hook.ID,
+				"message_id": msg.MessageID,
+				"err":        err,
+			}, "webhook delivery failed")
+			failures = append(failures, fmt.Sprintf("webhook %s: %s", hook.ID, err))
+			continue
+		}
+		receipts[recipient] = struct{}{}
+	}
+	if len(failures) > 0 {
+		return errs.Errorf("failed to deliver message %s to %d webhook(s): %s", msg.MessageID, len(failures), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// webhookRecipient returns the name of the webhook with the given ID in the
+// Receipts of a message
+func webhookRecipient(ID uuid.UUID) string {
+	return "webhook:" + ID.String()
+}
+
+// post sends the payload to the given URL and fails unless the response has
+// a 2xx status code
+func (c *WebhookChannel) post(url string, msg Message, body []byte, signature string) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errs.Wrapf(err, "failed to create the request to %s", url)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookHeaderEvent, msg.MessageType)
+	req.Header.Set(WebhookHeaderDelivery, msg.MessageID.String())
+	req.Header.Set(WebhookHeaderSignature, signature)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errs.Wrapf(err, "failed to post to %s", url)
+	}
+	defer rest.CloseResponse(resp)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errs.Errorf("unexpected response code %d from %s", resp.StatusCode, url)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package notification_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/notification"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/webhook"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type webhookConfig struct{}
+
+func (webhookConfig) GetWebhookHTTPTimeout() time.Duration { return time.Second }
+
+type webhookChannelSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestWebhookChannel(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &webhookChannelSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *webhookChannelSuite) TestDeliver() {
+	// the test servers listen on the loopback interface
+	webhook.SetAllowPrivateAddresses(true)
+	defer webhook.SetAllowPrivateAddresses(false)
+	channel := notification.NewWebhookChannel(s.DB, webhookConfig{})
+	var servers []*httptest.Server
+	defer func() {
+		for _, server := range servers {
+			server.Close()
+		}
+	}()
+	// newHook creates a webhook in the given space posting to a server that
+	// answers with the given status and returns the number of received posts
+	newHook := func(t *testing.T, spaceID uuid.UUID, status int, eventTypes ...string) *int {
+		var posts int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			posts++
+			w.WriteHeader(status)
+		}))
+		servers = append(servers, server)
+		hook := webhook.Webhook{SpaceID: spaceID, URL: server.URL, Secret: "s3cr3t", EventTypes: eventTypes}
+		require.NoError(t, webhook.NewWebhookRepository(s.DB).Create(s.Ctx, &hook))
+		return &posts
+	}
+
+	s.T().Run("signed payload is posted once to every subscribed webhook", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		msg := notification.NewWorkItemCreated(fxt.Spaces[0].ID, uuid.NewV4().String(), uuid.NewV4())
+		var posts int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			posts++
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, notification.SignWebhookPayload("s3cr3t", body), r.Header.Get(notification.WebhookHeaderSignature))
+			assert.Equal(t, "workitem.create", r.Header.Get(notification.WebhookHeaderEvent))
+			assert.Equal(t, msg.MessageID.String(), r.Header.Get(notification.WebhookHeaderDelivery))
+			payload := notification.WebhookPayload{}
+			require.NoError(t, json.Unmarshal(body, &payload))
+			assert.Equal(t, msg.MessageID, payload.ID)
+			assert.Equal(t, fxt.Spaces[0].ID, payload.SpaceID)
+			assert.Equal(t, msg.TargetID, payload.TargetID)
+		}))
+		defer server.Close()
+		hook := webhook.Webhook{SpaceID: fxt.Spaces[0].ID, URL: server.URL, Secret: "s3cr3t"}
+		require.NoError(t, webhook.NewWebhookRepository(s.DB).Create(s.Ctx, &hook))
+		subscribed := newHook(t, fxt.Spaces[0].ID, http.StatusOK, notification.MessageTypeWorkItemCreate)
+		unsubscribed := newHook(t, fxt.Spaces[0].ID, http.StatusOK, notification.MessageTypeCommentCreate)
+		// when
+		err := channel.Deliver(s.Ctx, msg, notification.Receipts{})
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, posts)
+		assert.Equal(t, 1, *subscribed)
+		assert.Equal(t, 0, *unsubscribed)
+	})
+
+	s.T().Run("delivery fails if any webhook does not accept the payload", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		msg := notification.NewWorkItemCreated(fxt.Spaces[0].ID, uuid.NewV4().String(), uuid.NewV4())
+		accepting := newHook(t, fxt.Spaces[0].ID, http.StatusOK)
+		failing := newHook(t, fxt.Spaces[0].ID, http.StatusBadGateway)
+		// when
+		err := channel.Deliver(s.Ctx, msg, notification.Receipts{})
+		// then
+		require.Error(t, err)
+		assert.Equal(t, 1, *accepting)
+		assert.Equal(t, 1, *failing, "failed posts are retried by the dispatcher only")
+	})
+
+	s.T().Run("retried delivery skips the webhooks that accepted the payload", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		msg := notification.NewWorkItemCreated(fxt.Spaces[0].ID, uuid.NewV4().String(), uuid.NewV4())
+		accepting := newHook(t, fxt.Spaces[0].ID, http.StatusOK)
+		failing := newHook(t, fxt.Spaces[0].ID, http.StatusBadGateway)
+		receipts := notification.Receipts{}
+		require.Error(t, channel.Deliver(s.Ctx, msg, receipts))
+		require.Len(t, receipts, 1)
+		// when
+		err := channel.Deliver(s.Ctx, msg, receipts)
+		// then
+		require.Error(t, err)
+		assert.Equal(t, 1, *accepting)
+		assert.Equal(t, 2, *failing)
+		assert.Len(t, receipts, 1)
+	})
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	// expected value computed with: echo -n 'payload' | openssl dgst -sha256 -hmac 'secret'
+	assert.Equal(t, "sha256=b82fcb791acec57859b989b430a826488ce2e479fdf92326bd0a2e8375a42ba4", notification.SignWebhookPayload("secret", []byte("payload")))
+}
@@ -0,0 +1,313 @@
+package remoteworkitem
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// The converters that can be used in a field mapping
+const (
+	ConverterString   = "string"
+	ConverterList     = "list"
+	ConverterPattern  = "pattern"
+	ConverterMarkdown = "markdown"
+	ConverterJiraWiki = "jirawiki"
+	ConverterState    = "state"
+)
+
+// resolvedFields are the mapping targets that are not work item fields but
+// get resolved into the creator and assignee identities, the labels or the
+// iteration of the work item. The value tells if the target holds a list.
+var resolvedFields = map[string]bool{
+	remoteCreatorLogin:        false,
+	remoteCreatorProfileURL:   false,
+	RemoteAssigneeLogins:      true,
+	RemoteAssigneeProfileURLs: true,
+	RemoteLabelNames:          true,
+	RemoteIterationName:       false,
+}
+
+// FieldMappingEntry maps a remote attribute to a field of the work item.
+type FieldMappingEntry struct {
+	// Remote is the key of the attribute in the flattened remote item, e.g.
+	// "fields.priority.name" or "labels.0.name".
+	Remote string `json:"remote"`
+	// Field is the name of the work item field the attribute is mapped to.
+	Field string `json:"field"`
+	// Converter is the name of the converter to apply. Defaults to "string".
+	Converter string `json:"converter,omitempty"`
+	// Pattern is the key pattern used by the "pattern" converter to collect
+	// all elements of a remote list, e.g. "labels.?.name".
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// FieldMapping configures how the items of a remote tracker are imported. It
+// can be set on a tracker and on a tracker query, in which case the mapping
+// of the tracker query takes precedence.
+type FieldMapping struct {
+	// WorkItemTypeID is the type of the imported work items. Defaults to the
+	// bug type.
+	WorkItemTypeID *uuid.UUID `json:"work_item_type,omitempty"`
+	// Fields replace the default mappings of the provider for the same work
+	// item fields.
+	Fields []FieldMappingEntry `json:"fields,omitempty"`
+	// States maps remote states to values of the local state field. States
+	// without an entry are imported as they are.
+	States map[string]string `json:"states,omitempty"`
+}
+
+// Ensure FieldMapping implements the Scanner and Valuer interfaces
+var _ sql.Scanner = (*FieldMapping)(nil)
+var _ driver.Valuer = (*FieldMapping)(nil)
+
+// IsEmpty returns true if the mapping does not change the default mapping
+func (m FieldMapping) IsEmpty() bool {
+	return m.WorkItemTypeID == nil && len(m.Fields) == 0 && len(m.States) == 0
+}
+
+// Value implements the https://golang.org/pkg/database/sql/driver/#Valuer interface
+func (m FieldMapping) Value() (driver.Value, error) {
+	if m.IsEmpty() {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
+// Scan implements the https://golang.org/pkg/database/sql/#Scanner interface
+func (m *FieldMapping) Scan(src interface{}) error {
+	*m = FieldMapping{}
+	if src == nil {
+		return nil
+	}
+	var b []byte
+	switch v := src.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errs.Errorf("unexpected type of field mapping: %T", src)
+	}
+	return json.Unmarshal(b, m)
+}
+
+// Merge returns a copy of the mapping that is overridden by the given mapping.
+func (m FieldMapping) Merge(other FieldMapping) FieldMapping {
+	res := FieldMapping{
+		WorkItemTypeID: m.WorkItemTypeID,
+		Fields:         append(append([]FieldMappingEntry{}, m.Fields...), other.Fields...),
+		States:         map[string]string{},
+	}
+	if other.WorkItemTypeID != nil {
+		res.WorkItemTypeID = other.WorkItemTypeID
+	}
+	for k, v := range m.States {
+		res.States[k] = v
+	}
+	for k, v := range other.States {
+		res.States[k] = v
+	}
+	return res
+}
+
+// TypeID returns the type of the imported work items
+func (m FieldMapping) TypeID() uuid.UUID {
+	if m.WorkItemTypeID != nil {
+		return *m.WorkItemTypeID
+	}
+	return workitem.SystemBug
+}
+
+// RemoteState returns the remote state that is mapped to the given local
+// state. If several remote states are mapped to it, the first of them in
+// alphabetical order is returned so that writing back always picks the same
+// one. ok is false if no remote state is mapped to the local state.
+func (m FieldMapping) RemoteState(local string) (remote string, ok bool) {
+	candidates := []string{}
+	for r, l := range m.States {
+		if strings.EqualFold(l, local) {
+			candidates = append(candidates, r)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.Strings(candidates)
+	return candidates[0], true
+}
+
+// localState returns the local state that the given remote state is mapped
+// to or the remote state itself if it is not mapped.
+func (m FieldMapping) localState(remote string) string {
+	for r, l := range m.States {
+		if strings.EqualFold(r, remote) {
+			return l
+		}
+	}
+	return remote
+}
+
+// converter returns the attribute converter of the given entry
+func (m FieldMapping) converter(e FieldMappingEntry) (AttributeConverter, error) {
+	switch e.Converter {
+	case "", ConverterString:
+		return StringConverter{}, nil
+	case ConverterList:
+		return ListConverter{}, nil
+	case ConverterPattern:
+		if !strings.Contains(e.Pattern, "?") {
+			return nil, errs.Errorf("the pattern of remote attribute %s must contain a '?'", e.Remote)
+		}
+		return PatternToListConverter{pattern: e.Pattern}, nil
+	case ConverterMarkdown:
+		return MarkupConverter{markup: rendering.SystemMarkupMarkdown}, nil
+	case ConverterJiraWiki:
+		return MarkupConverter{markup: rendering.SystemMarkupJiraWiki}, nil
+	case ConverterState:
+		return &StateMappingConverter{states: m.States}, nil
+	}
+	return nil, errs.Errorf("unknown converter %s for remote attribute %s", e.Converter, e.Remote)
+}
+
+// RemoteWorkItemMap returns the mapping of the given provider with the
+// changes of this field mapping applied.
+func (m FieldMapping) RemoteWorkItemMap(providerType string) (RemoteWorkItemMap, error) {
+	defaults, ok := RemoteWorkItemKeyMaps[providerType]
+	if !ok {
+		return nil, BadParameterError{parameter: "providerType", value: providerType}
+	}
+	res := RemoteWorkItemMap{}
+	for from, to := range defaults {
+		// the state mapping also applies to the default state attribute
+		if to == remoteState && len(m.States) > 0 {
+			from = AttributeMapper{from.Expression, &StateMappingConverter{states: m.States}}
+		}
+		res[from] = to
+	}
+	for _, e := range m.Fields {
+		if e.Remote == "" || e.Field == "" {
+			return nil, errs.New("remote attribute and field must not be empty")
+		}
+		if e.Field == remoteItemID {
+			return nil, errs.Errorf("the mapping of field %s cannot be changed", remoteItemID)
+		}
+		converter, err := m.converter(e)
+		if err != nil {
+			return nil, err
+		}
+		for from, to := range res {
+			if to == e.Field {
+				delete(res, from)
+			}
+		}
+		res[AttributeMapper{AttributeExpression(e.Remote), converter}] = e.Field
+	}
+	return res, nil
+}
+
+// Validate checks that the mapping can be applied to the items of the given
+// provider and that all mapped fields exist in the given work item type and
+// match the converters used for them.
+func (m FieldMapping) Validate(providerType string, wit workitem.WorkItemType) error {
+	mapping, err := m.RemoteWorkItemMap(providerType)
+	if err != nil {
+		return err
+	}
+	for from, to := range mapping {
+		if isList, ok := resolvedFields[to]; ok {
+			if _, isString := from.AttributeConverter.(StringConverter); isString == isList {
+				return errs.Errorf("field %s cannot be mapped with a %T", to, from.AttributeConverter)
+			}
+			continue
+		}
+		def, ok := wit.Fields[to]
+		if !ok {
+			return errs.Errorf("field %s does not exist in work item type %s", to, wit.Name)
+		}
+		kind := def.Type.GetKind()
+		switch c := from.AttributeConverter.(type) {
+		case ListConverter, PatternToListConverter:
+			if kind != workitem.KindList {
+				return errs.Errorf("field %s of kind %s cannot hold a list", to, kind)
+			}
+		case MarkupConverter:
+			if kind != workitem.KindMarkup {
+				return errs.Errorf("field %s of kind %s cannot hold markup", to, kind)
+			}
+		case *StateMappingConverter:
+			enum, ok := def.Type.(workitem.EnumType)
+			if !ok {
+				return errs.Errorf("remote states can only be mapped to enum fields but field %s is of kind %s", to, kind)
+			}
+			for remote, local := range c.states {
+				if !enumContains(enum, local) {
+					return errs.Errorf("remote state %s is mapped to %s which is not a value of field %s", remote, local, to)
+				}
+			}
+		default:
+			if kind == workitem.KindList || kind == workitem.KindMarkup {
+				return errs.Errorf("field %s of kind %s needs a %s converter", to, kind, kind)
+			}
+		}
+	}
+	return nil
+}
+
+func enumContains(enum workitem.EnumType, value string) bool {
+	for _, v := range enum.Values {
+		if fmt.Sprint(v) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFieldMapping checks the given mapping against the work item type it
+// refers to. Unless spaceTemplateID is nil, the work item type must belong to
+// that space template, which is the template of the space the items are
+// imported into.
+func validateFieldMapping(ctx context.Context, db *gorm.DB, m FieldMapping, providerType string, spaceTemplateID *uuid.UUID) error {
+	if m.IsEmpty() {
+		return nil
+	}
+	wit, err := workitem.NewWorkItemTypeRepository(db).Load(ctx, m.TypeID())
+	if err != nil {
+		return errors.NewBadParameterError("fieldMapping.workItemType", m.TypeID()).Expected("existing work item type")
+	}
+	if spaceTemplateID != nil && wit.SpaceTemplateID != *spaceTemplateID {
+		return errors.NewBadParameterError("fieldMapping.workItemType", m.TypeID()).Expected(fmt.Sprintf("work item type of space template %s", *spaceTemplateID))
+	}
+	if err := m.Validate(providerType, *wit); err != nil {
+		return errors.NewBadParameterErrorFromString(fmt.Sprintf("invalid field mapping: %s", err.Error()))
+	}
+	return nil
+}
+
+// StateMappingConverter maps remote states to local states. The lookup of
+// the remote states is case insensitive and unknown states are returned as
+// they are.
+type StateMappingConverter struct {
+	states map[string]string
+}
+
+// Convert maps the given remote state to a local state
+func (c *StateMappingConverter) Convert(value interface{}, item AttributeAccessor) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	return FieldMapping{States: c.states}.localState(s), nil
+}
@@ -0,0 +1,175 @@
+package remoteworkitem
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldMappingRemoteWorkItemMap(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	// given
+	item := TrackerItem{Item: `{"title":"linking","url":"http://github.com/api/1","state":"closed","body":"desc","labels":[{"name":"bug"},{"name":"ui"}],"milestone":{"title":"v1"}}`}
+	accessor, err := NewGitHubRemoteWorkItem(item)
+	require.NoError(t, err)
+
+	t.Run("default mapping", func(t *testing.T) {
+		// when
+		mapping, err := FieldMapping{}.RemoteWorkItemMap(ProviderGithub)
+		require.NoError(t, err)
+		res, err := Map(accessor, mapping)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "linking", res.Fields[workitem.SystemTitle])
+		assert.Equal(t, "closed", res.Fields[workitem.SystemState])
+	})
+
+	t.Run("custom mapping", func(t *testing.T) {
+		// given
+		m := FieldMapping{
+			Fields: []FieldMappingEntry{
+				{Remote: "milestone.title", Field: workitem.SystemTitle},
+				{Remote: "labels.0.name", Field: "labels", Converter: ConverterPattern, Pattern: "labels.?.name"},
+				{Remote: "body", Field: workitem.SystemDescription, Converter: ConverterJiraWiki},
+			},
+			States: map[string]string{"Closed": "resolved"},
+		}
+		// when
+		mapping, err := m.RemoteWorkItemMap(ProviderGithub)
+		require.NoError(t, err)
+		res, err := Map(accessor, mapping)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "v1", res.Fields[workitem.SystemTitle])
+		assert.Equal(t, []string{"bug", "ui"}, res.Fields["labels"])
+		assert.Equal(t, rendering.NewMarkupContent("desc", rendering.SystemMarkupJiraWiki), res.Fields[workitem.SystemDescription])
+		assert.Equal(t, "resolved", res.Fields[workitem.SystemState])
+		assert.Equal(t, "http://github.com/api/1", res.Fields[workitem.SystemRemoteItemID])
+	})
+
+	t.Run("invalid mappings", func(t *testing.T) {
+		for name, m := range map[string]FieldMapping{
+			"unknown converter": {Fields: []FieldMappingEntry{{Remote: "title", Field: workitem.SystemTitle, Converter: "foo"}}},
+			"missing pattern":   {Fields: []FieldMappingEntry{{Remote: "labels.0.name", Field: "labels", Converter: ConverterPattern}}},
+			"empty remote":      {Fields: []FieldMappingEntry{{Field: workitem.SystemTitle}}},
+			"remote item id":    {Fields: []FieldMappingEntry{{Remote: "html_url", Field: workitem.SystemRemoteItemID}}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := m.RemoteWorkItemMap(ProviderGithub)
+				require.Error(t, err)
+			})
+		}
+		_, err := FieldMapping{}.RemoteWorkItemMap("unknown")
+		require.Error(t, err)
+	})
+}
+
+func TestFieldMappingValidate(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	// given
+	wit := workitem.WorkItemType{
+		Name: "bug",
+		Fields: workitem.FieldDefinitions{
+			workitem.SystemTitle:        {Type: workitem.SimpleType{Kind: workitem.KindString}},
+			workitem.SystemDescription:  {Type: workitem.SimpleType{Kind: workitem.KindMarkup}},
+			workitem.SystemRemoteItemID: {Type: workitem.SimpleType{Kind: workitem.KindString}},
+			workitem.SystemState: {Type: workitem.EnumType{
+				SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
+				BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+				Values:     []interface{}{"new", "open", "closed"},
+			}},
+			"labels": {Type: workitem.ListType{
+				SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+				ComponentType: workitem.SimpleType{Kind: workitem.KindString},
+			}},
+		},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		m := FieldMapping{
+			Fields: []FieldMappingEntry{{Remote: "labels.0.name", Field: "labels", Converter: ConverterPattern, Pattern: "labels.?.name"}},
+			States: map[string]string{"Done": "closed"},
+		}
+		require.NoError(t, m.Validate(ProviderGithub, wit))
+		require.NoError(t, FieldMapping{}.Validate(ProviderJira, wit))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, m := range map[string]FieldMapping{
+			"unknown field":         {Fields: []FieldMappingEntry{{Remote: "title", Field: "foo"}}},
+			"list into string":      {Fields: []FieldMappingEntry{{Remote: "labels.0.name", Field: workitem.SystemTitle, Converter: ConverterList}}},
+			"string into list":      {Fields: []FieldMappingEntry{{Remote: "title", Field: "labels"}}},
+			"string into markup":    {Fields: []FieldMappingEntry{{Remote: "title", Field: workitem.SystemDescription}}},
+			"unknown state":         {States: map[string]string{"Done": "finished"}},
+			"state into non-enum":   {Fields: []FieldMappingEntry{{Remote: "state", Field: workitem.SystemTitle, Converter: ConverterState}}},
+			"list into creator":     {Fields: []FieldMappingEntry{{Remote: "user.login", Field: remoteCreatorLogin, Converter: ConverterList}}},
+			"string into assignees": {Fields: []FieldMappingEntry{{Remote: "assignee.login", Field: RemoteAssigneeLogins}}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				require.Error(t, m.Validate(ProviderGithub, wit))
+			})
+		}
+	})
+}
+
+func TestFieldMappingStorage(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+
+	t.Run("empty mapping is stored as null", func(t *testing.T) {
+		v, err := FieldMapping{}.Value()
+		require.NoError(t, err)
+		assert.Nil(t, v)
+		m := FieldMapping{States: map[string]string{"a": "b"}}
+		require.NoError(t, m.Scan(nil))
+		assert.True(t, m.IsEmpty())
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		typeID := uuid.NewV4()
+		expected := FieldMapping{
+			WorkItemTypeID: &typeID,
+			Fields:         []FieldMappingEntry{{Remote: "title", Field: workitem.SystemTitle, Converter: ConverterString}},
+			States:         map[string]string{"Done": "closed"},
+		}
+		v, err := expected.Value()
+		require.NoError(t, err)
+		actual := FieldMapping{}
+		require.NoError(t, actual.Scan(v))
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		typeID := uuid.NewV4()
+		tracker := FieldMapping{
+			Fields: []FieldMappingEntry{{Remote: "title", Field: workitem.SystemTitle}},
+			States: map[string]string{"Done": "closed", "Review": "open"},
+		}
+		query := FieldMapping{
+			WorkItemTypeID: &typeID,
+			States:         map[string]string{"Review": "in progress"},
+		}
+		merged := tracker.Merge(query)
+		assert.Equal(t, typeID, merged.TypeID())
+		assert.Equal(t, tracker.Fields, merged.Fields)
+		assert.Equal(t, map[string]string{"Done": "closed", "Review": "in progress"}, merged.States)
+		assert.Equal(t, workitem.SystemBug, FieldMapping{}.TypeID())
+	})
+
+	t.Run("remote state", func(t *testing.T) {
+		m := FieldMapping{States: map[string]string{"Won't Fix": "closed", "Done": "closed", "Review": "open"}}
+		for i := 0; i < 10; i++ {
+			remote, ok := m.RemoteState("closed")
+			require.True(t, ok)
+			assert.Equal(t, "Done", remote)
+		}
+		assert.Equal(t, "closed", m.localState("won't fix"))
+		assert.Equal(t, "Unknown", m.localState("Unknown"))
+		_, ok := m.RemoteState("new")
+		assert.False(t, ok)
+	})
+}
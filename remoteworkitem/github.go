@@ -2,6 +2,8 @@ package remoteworkitem
 
 import (
 	"encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/fabric8-services/fabric8-wit/log"
 
@@ -18,6 +20,17 @@ type githubFetcher interface {
 type GithubTracker struct {
 	URL   string
 	Query string
+	// Since restricts the fetched issues to the ones updated since then
+	Since *time.Time
+}
+
+// query returns the search query including the restriction to the issues
+// updated since the last sync
+func (g *GithubTracker) query() string {
+	if g.Since == nil {
+		return g.Query
+	}
+	return fmt.Sprintf("%s updated:>=%s", g.Query, g.Since.UTC().Format(time.RFC3339))
 }
 
 // GithubIssueFetcher fetch issues from github
@@ -50,12 +63,13 @@ func (g *GithubTracker) fetch(f githubFetcher) chan TrackerItemContent {
 			},
 		}
 		for {
-			result, response, err := f.listIssues(g.Query, opts)
+			result, response, err := f.listIssues(g.query(), opts)
 			if err != nil {
 				log.Warn(nil, map[string]interface{}{
 					"err":   err,
 					"query": g.Query,
 				}, "unable to fetch remote items")
+				item <- TrackerItemContent{Err: err}
 				break
 			}
 			issues := result.Issues
@@ -63,6 +63,10 @@ func TestGithubFetchWithRateLimit(t *testing.T) {
 	fetch := g.fetch(&f)
 	// then
 	assert.Equal(t, 0, len(fetch))
+	i := <-fetch
+	assert.IsType(t, &github.RateLimitError{}, i.Err)
+	_, more := <-fetch
+	assert.False(t, more)
 }
 
 func TestGithubFetchWithRecording(t *testing.T) {
@@ -0,0 +1,181 @@
+package remoteworkitem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/log"
+
+	"github.com/pkg/errors"
+)
+
+// gitlabDefaultURL is the base URL of gitlab.com which is used when the
+// tracker has no URL
+const gitlabDefaultURL = "https://gitlab.com"
+
+// gitlabAPIPath is the path of the GitLab REST API relative to the base URL of
+// a GitLab installation
+const gitlabAPIPath = "/api/v4"
+
+// gitlabFetcher provides issue listing
+type gitlabFetcher interface {
+	// listIssues returns the issues of the given page and the number of the
+	// next page or 0 if it was the last page.
+	listIssues(query string, page int) ([]json.RawMessage, int, error)
+}
+
+// GitlabTracker represents the GitLab tracker provider. The URL is the base
+// URL of the GitLab installation, e.g. "https://gitlab.example.com", and
+// defaults to gitlab.com. The query is the query string of the GitLab issues
+// API, e.g. "state=opened&labels=bug". A "project" parameter with the path or
+// ID of a project restricts the query to the issues of that project.
+type GitlabTracker struct {
+	URL   string
+	Query string
+	// Since restricts the fetched issues to the ones updated since then
+	Since *time.Time
+}
+
+// query returns the issues query including the restriction to the issues
+// updated since the last sync
+func (g *GitlabTracker) query() string {
+	if g.Since == nil {
+		return g.Query
+	}
+	since := "updated_after=" + url.QueryEscape(g.Since.UTC().Format(time.RFC3339))
+	if g.Query == "" {
+		return since
+	}
+	return g.Query + "&" + since
+}
+
+// gitlabIssueFetcher fetches issues from the GitLab REST API
+type gitlabIssueFetcher struct {
+	client    *http.Client
+	apiURL    string
+	authToken string
+}
+
+// gitlabAPIURL returns the URL of the REST API of the GitLab installation
+// with the given base URL
+func gitlabAPIURL(baseURL string) string {
+	if baseURL == "" {
+		baseURL = gitlabDefaultURL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if strings.HasSuffix(baseURL, gitlabAPIPath) {
+		return baseURL
+	}
+	return baseURL + gitlabAPIPath
+}
+
+// issuesURL returns the URL of the given page of issues matching the query
+func (f *gitlabIssueFetcher) issuesURL(query string, page int) (string, error) {
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid GitLab query: %s", query)
+	}
+	path := "/issues"
+	if project := params.Get("project"); project != "" {
+		params.Del("project")
+		path = "/projects/" + url.PathEscape(project) + "/issues"
+	} else if params.Get("scope") == "" {
+		// the issues API only returns the issues created by the current
+		// user by default
+		params.Set("scope", "all")
+	}
+	params.Set("per_page", "20")
+	if page > 0 {
+		params.Set("page", strconv.Itoa(page))
+	}
+	return f.apiURL + path + "?" + params.Encode(), nil
+}
+
+// listIssues lists the issues of the given page
+func (f *gitlabIssueFetcher) listIssues(query string, page int) ([]json.RawMessage, int, error) {
+	issuesURL, err := f.issuesURL(query, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	req, err := http.NewRequest(http.MethodGet, issuesURL, nil)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to create request GET %s", issuesURL)
+	}
+	req.Header.Set("Accept", "application/json")
+	if f.authToken != "" {
+		req.Header.Set("Private-Token", f.authToken)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "request GET %s failed", issuesURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, InternalError{simpleError{message: fmt.Sprintf("request GET %s failed with status %s", issuesURL, resp.Status)}}
+	}
+	var issues []json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to decode the response of GET %s", issuesURL)
+	}
+	nextPage := 0
+	if next := resp.Header.Get("X-Next-Page"); next != "" {
+		nextPage, err = strconv.Atoi(next)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "invalid next page: %s", next)
+		}
+	}
+	return issues, nextPage, nil
+}
+
+// Fetch tracker items from GitLab
+func (g *GitlabTracker) Fetch(authToken string) chan TrackerItemContent {
+	f := gitlabIssueFetcher{
+		client:    http.DefaultClient,
+		apiURL:    gitlabAPIURL(g.URL),
+		authToken: authToken,
+	}
+	return g.fetch(&f)
+}
+
+func (g *GitlabTracker) fetch(f gitlabFetcher) chan TrackerItemContent {
+	item := make(chan TrackerItemContent)
+	go func() {
+		page := 0
+		for {
+			issues, nextPage, err := f.listIssues(g.query(), page)
+			if err != nil {
+				log.Warn(nil, map[string]interface{}{
+					"err":   err,
+					"query": g.Query,
+				}, "unable to fetch remote items")
+				item <- TrackerItemContent{Err: err}
+				break
+			}
+			for _, issue := range issues {
+				var l struct {
+					WebURL string `json:"web_url"`
+				}
+				if err := json.Unmarshal(issue, &l); err != nil {
+					log.Warn(nil, map[string]interface{}{
+						"err": err,
+					}, "unable to read remote item")
+					item <- TrackerItemContent{Err: errors.Wrap(err, "unable to read remote item")}
+					continue
+				}
+				id, _ := json.Marshal(l.WebURL)
+				item <- TrackerItemContent{ID: string(id), Content: issue}
+			}
+			if nextPage == 0 {
+				break
+			}
+			page = nextPage
+		}
+		close(item)
+	}()
+	return item
+}
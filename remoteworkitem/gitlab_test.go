@@ -0,0 +1,142 @@
+package remoteworkitem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeGitlabIssueFetcher struct{}
+
+// listIssues returns one issue on the first and no issue on the second page
+func (f *fakeGitlabIssueFetcher) listIssues(query string, page int) ([]json.RawMessage, int, error) {
+	if page == 0 {
+		return []json.RawMessage{json.RawMessage(`{"id":1,"web_url":"https://gitlab.com/group/project/issues/1"}`)}, 2, nil
+	}
+	return []json.RawMessage{}, 0, nil
+}
+
+func TestGitlabFetch(t *testing.T) {
+	// given
+	resource.Require(t, resource.UnitTest)
+	f := fakeGitlabIssueFetcher{}
+	g := GitlabTracker{URL: "", Query: ""}
+	// when
+	fetch := g.fetch(&f)
+	// then
+	i := <-fetch
+	assert.Equal(t, `"https://gitlab.com/group/project/issues/1"`, i.ID)
+	assert.Equal(t, `{"id":1,"web_url":"https://gitlab.com/group/project/issues/1"}`, string(i.Content))
+	_, more := <-fetch
+	assert.False(t, more)
+}
+
+func TestGitlabFetchWithFakeAPI(t *testing.T) {
+	// given
+	resource.Require(t, resource.UnitTest)
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if r.Header.Get("Private-Token") != "s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			w.Header().Set("X-Next-Page", "2")
+			page = "1"
+		} else {
+			w.Header().Set("X-Next-Page", "")
+		}
+		fmt.Fprintf(w, `[{"iid":%[1]s,"title":"issue %[1]s","web_url":"https://gitlab.example.com/group/project/issues/%[1]s"}]`, page)
+	}))
+	defer server.Close()
+	g := GitlabTracker{URL: server.URL + "/", Query: "project=group/project&state=opened"}
+	f := gitlabIssueFetcher{client: server.Client(), apiURL: gitlabAPIURL(g.URL), authToken: "s3cr3t"}
+	// when
+	var items []TrackerItemContent
+	for item := range g.fetch(&f) {
+		items = append(items, item)
+	}
+	// then
+	require.Len(t, items, 2)
+	assert.Equal(t, `"https://gitlab.example.com/group/project/issues/1"`, items[0].ID)
+	assert.Contains(t, string(items[0].Content), `"title":"issue 1"`)
+	assert.Equal(t, `"https://gitlab.example.com/group/project/issues/2"`, items[1].ID)
+	require.Len(t, requests, 2)
+	assert.Equal(t, "/api/v4/projects/group%2Fproject/issues", requests[0].URL.EscapedPath())
+	assert.Equal(t, "opened", requests[0].URL.Query().Get("state"))
+	assert.Equal(t, "", requests[0].URL.Query().Get("project"))
+	assert.Equal(t, "2", requests[1].URL.Query().Get("page"))
+
+	t.Run("unauthorized", func(t *testing.T) {
+		f := gitlabIssueFetcher{client: server.Client(), apiURL: gitlabAPIURL(g.URL)}
+		fetch := g.fetch(&f)
+		i := <-fetch
+		require.Error(t, i.Err)
+		assert.Empty(t, i.Content)
+		_, more := <-fetch
+		assert.False(t, more)
+	})
+}
+
+func TestGitlabIssuesURL(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	f := gitlabIssueFetcher{apiURL: gitlabAPIURL("")}
+	for query, expected := range map[string]string{
+		"":                                "https://gitlab.com/api/v4/issues?per_page=20&scope=all",
+		"labels=bug&scope=assigned_to_me": "https://gitlab.com/api/v4/issues?labels=bug&per_page=20&scope=assigned_to_me",
+		"project=42":                      "https://gitlab.com/api/v4/projects/42/issues?per_page=20",
+	} {
+		t.Run(query, func(t *testing.T) {
+			actual, err := f.issuesURL(query, 0)
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+	t.Run("self-hosted", func(t *testing.T) {
+		assert.Equal(t, "https://gitlab.example.com/api/v4", gitlabAPIURL("https://gitlab.example.com"))
+		assert.Equal(t, "https://gitlab.example.com/api/v4", gitlabAPIURL("https://gitlab.example.com/api/v4/"))
+	})
+}
+
+func TestGitlabRemoteWorkItemMapping(t *testing.T) {
+	// given
+	resource.Require(t, resource.UnitTest)
+	item := TrackerItem{Item: `{"title":"crash","description":"**it** crashes","state":"opened","web_url":"https://gitlab.com/group/project/issues/1","author":{"username":"jdoe","web_url":"https://gitlab.com/jdoe"},"assignees":[{"username":"jdoe","web_url":"https://gitlab.com/jdoe"},{"username":"asmith","web_url":"https://gitlab.com/asmith"}],"labels":["bug","ui"],"milestone":{"title":"v1"}}`}
+	accessor, err := NewGitlabRemoteWorkItem(item)
+	require.NoError(t, err)
+	mapping, err := FieldMapping{}.RemoteWorkItemMap(ProviderGitlab)
+	require.NoError(t, err)
+	// when
+	res, err := Map(accessor, mapping)
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "crash", res.Fields[workitem.SystemTitle])
+	assert.Equal(t, rendering.NewMarkupContent("**it** crashes", rendering.SystemMarkupMarkdown), res.Fields[workitem.SystemDescription])
+	assert.Equal(t, "open", res.Fields[workitem.SystemState])
+	assert.Equal(t, "https://gitlab.com/group/project/issues/1", res.Fields[workitem.SystemRemoteItemID])
+	assert.Equal(t, "jdoe", res.Fields[remoteCreatorLogin])
+	assert.Equal(t, "https://gitlab.com/jdoe", res.Fields[remoteCreatorProfileURL])
+	assert.Equal(t, []string{"jdoe", "asmith"}, res.Fields[RemoteAssigneeLogins])
+	assert.Equal(t, []string{"https://gitlab.com/jdoe", "https://gitlab.com/asmith"}, res.Fields[RemoteAssigneeProfileURLs])
+	assert.Equal(t, []string{"bug", "ui"}, res.Fields[RemoteLabelNames])
+	assert.Equal(t, "v1", res.Fields[RemoteIterationName])
+
+	t.Run("closed issue", func(t *testing.T) {
+		accessor, err := NewGitlabRemoteWorkItem(TrackerItem{Item: `{"state":"closed","web_url":"https://gitlab.com/group/project/issues/2"}`})
+		require.NoError(t, err)
+		res, err := Map(accessor, mapping)
+		require.NoError(t, err)
+		assert.Equal(t, "closed", res.Fields[workitem.SystemState])
+		assert.Equal(t, []string{}, res.Fields[RemoteLabelNames])
+	})
+}
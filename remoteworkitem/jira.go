@@ -2,14 +2,44 @@ package remoteworkitem
 
 import (
 	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	jira "github.com/andygrunwald/go-jira"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/pkg/errors"
 )
 
 // JiraTracker represents the Jira tracker provider
 type JiraTracker struct {
 	URL   string
 	Query string
+	// Since restricts the fetched issues to the ones updated since then
+	Since *time.Time
+}
+
+// jqlOrderBy matches the ORDER BY clause at the end of a JQL query
+var jqlOrderBy = regexp.MustCompile(`(?i)(^|\s+)ORDER\s+BY\s+.*$`)
+
+// query returns the JQL query including the restriction to the issues
+// updated since the last sync. JQL compares dates in the time zone of the
+// user, so the restriction is widened by a day to not miss any update.
+func (j *JiraTracker) query() string {
+	if j.Since == nil {
+		return j.Query
+	}
+	since := fmt.Sprintf(`updated >= "%s"`, j.Since.AddDate(0, 0, -1).UTC().Format("2006/01/02 15:04"))
+	orderBy := strings.TrimSpace(jqlOrderBy.FindString(j.Query))
+	if orderBy != "" {
+		orderBy = " " + orderBy
+	}
+	where := strings.TrimSpace(jqlOrderBy.ReplaceAllString(j.Query, ""))
+	if where == "" {
+		return since + orderBy
+	}
+	return fmt.Sprintf("(%s) AND %s%s", where, since, orderBy)
 }
 
 type jiraFetcher interface {
@@ -40,10 +70,21 @@ func (j *JiraTracker) Fetch(authToken string) chan TrackerItemContent {
 func (j *JiraTracker) fetch(f jiraFetcher) chan TrackerItemContent {
 	item := make(chan TrackerItemContent)
 	go func() {
-		issues, _, _ := f.listIssues(j.Query, nil)
+		issues, _, err := f.listIssues(j.query(), nil)
+		if err != nil {
+			log.Warn(nil, map[string]interface{}{
+				"err":   err,
+				"query": j.Query,
+			}, "unable to fetch remote items")
+			item <- TrackerItemContent{Err: err}
+		}
 		for _, l := range issues {
 			id, _ := json.Marshal(l.Key)
-			issue, _, _ := f.getIssue(l.Key)
+			issue, _, err := f.getIssue(l.Key)
+			if err != nil {
+				item <- TrackerItemContent{ID: string(id), Err: errors.Wrapf(err, "unable to fetch remote item %s", l.Key)}
+				continue
+			}
 			content, _ := json.Marshal(issue)
 			item <- TrackerItemContent{ID: string(id), Content: content}
 		}
@@ -64,3 +64,23 @@ func TestJiraFetchWithRecording(t *testing.T) {
 	assert.Equal(t, `"ARQ-2009"`, trackerItemContents[3].ID)
 	assert.Equal(t, `"ARQ-2010"`, trackerItemContents[4].ID)
 }
+
+func TestJiraQuerySince(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	since := time.Date(2018, 1, 2, 10, 30, 0, 0, time.UTC)
+	for query, expected := range map[string]string{
+		"project = ARQ":                      `(project = ARQ) AND updated >= "2018/01/01 10:30"`,
+		"project = ARQ ORDER BY created ASC": `(project = ARQ) AND updated >= "2018/01/01 10:30" ORDER BY created ASC`,
+		"order by created":                   `updated >= "2018/01/01 10:30" order by created`,
+		"":                                   `updated >= "2018/01/01 10:30"`,
+	} {
+		t.Run(query, func(t *testing.T) {
+			j := JiraTracker{Query: query, Since: &since}
+			assert.Equal(t, expected, j.query())
+		})
+	}
+	t.Run("without last sync", func(t *testing.T) {
+		j := JiraTracker{Query: "project = ARQ"}
+		assert.Equal(t, "project = ARQ", j.query())
+	})
+}
@@ -15,6 +15,7 @@ import (
 const (
 	ProviderGithub = "github"
 	ProviderJira   = "jira"
+	ProviderGitlab = "gitlab"
 
 	// The keys in the flattened response JSON of a typical Github issue.
 	GithubTitle                      = "title"
@@ -27,6 +28,7 @@ const (
 	GithubAssigneesLoginPattern      = "assignees.?.login"
 	GithubAssigneesProfileURL        = "assignees.0.url"
 	GithubAssigneesProfileURLPattern = "assignees.?.url"
+	GithubUpdatedAt                  = "updated_at"
 
 	// The keys in the flattened response JSON of a typical Jira issue.
 	JiraTitle              = "fields.summary"
@@ -37,6 +39,23 @@ const (
 	JiraCreatorProfileURL  = "fields.creator.self"
 	JiraAssigneeLogin      = "fields.assignee.key"
 	JiraAssigneeProfileURL = "fields.assignee.self"
+	JiraUpdatedAt          = "fields.updated"
+
+	// The keys in the flattened response JSON of a typical GitLab issue.
+	GitlabTitle                      = "title"
+	GitlabDescription                = "description"
+	GitlabState                      = "state"
+	GitlabID                         = "web_url"
+	GitlabCreatorLogin               = "author.username"
+	GitlabCreatorProfileURL          = "author.web_url"
+	GitlabAssigneesLogin             = "assignees.0.username"
+	GitlabAssigneesLoginPattern      = "assignees.?.username"
+	GitlabAssigneesProfileURL        = "assignees.0.web_url"
+	GitlabAssigneesProfileURLPattern = "assignees.?.web_url"
+	GitlabLabels                     = "labels.0"
+	GitlabLabelsPattern              = "labels.?"
+	GitlabMilestone                  = "milestone.title"
+	GitlabUpdatedAt                  = "updated_at"
 )
 
 // RemoteWorkItem a temporary structure that holds the relevant field values retrieved from a remote work item
@@ -58,6 +77,12 @@ const (
 	remoteCreatorProfileURL   = "system.creator.profile_url"
 	RemoteAssigneeLogins      = "system.assignees.login"
 	RemoteAssigneeProfileURLs = "system.assignees.profile_url"
+	// RemoteLabelNames holds the names of the labels of the remote item which
+	// are resolved into the labels of the space
+	RemoteLabelNames = "system.labels.name"
+	// RemoteIterationName holds the name of the iteration (or milestone) of
+	// the remote item which is resolved into an iteration of the space
+	RemoteIterationName = "system.iteration.name"
 )
 
 // RemoteWorkItemKeyMaps relate remote attribute keys to internal representation
@@ -82,6 +107,18 @@ var RemoteWorkItemKeyMaps = map[string]RemoteWorkItemMap{
 		AttributeMapper{AttributeExpression(JiraAssigneeLogin), ListConverter{}}:                                RemoteAssigneeLogins,
 		AttributeMapper{AttributeExpression(JiraAssigneeProfileURL), ListConverter{}}:                           RemoteAssigneeProfileURLs,
 	},
+	ProviderGitlab: {
+		AttributeMapper{AttributeExpression(GitlabTitle), StringConverter{}}:                                                               remoteTitle,
+		AttributeMapper{AttributeExpression(GitlabDescription), MarkupConverter{markup: rendering.SystemMarkupMarkdown}}:                   remoteDescription,
+		AttributeMapper{AttributeExpression(GitlabState), GitlabStateConverter{}}:                                                          remoteState,
+		AttributeMapper{AttributeExpression(GitlabID), StringConverter{}}:                                                                  remoteItemID,
+		AttributeMapper{AttributeExpression(GitlabCreatorLogin), StringConverter{}}:                                                        remoteCreatorLogin,
+		AttributeMapper{AttributeExpression(GitlabCreatorProfileURL), StringConverter{}}:                                                   remoteCreatorProfileURL,
+		AttributeMapper{AttributeExpression(GitlabAssigneesLogin), PatternToListConverter{pattern: GitlabAssigneesLoginPattern}}:           RemoteAssigneeLogins,
+		AttributeMapper{AttributeExpression(GitlabAssigneesProfileURL), PatternToListConverter{pattern: GitlabAssigneesProfileURLPattern}}: RemoteAssigneeProfileURLs,
+		AttributeMapper{AttributeExpression(GitlabLabels), PatternToListConverter{pattern: GitlabLabelsPattern}}:                           RemoteLabelNames,
+		AttributeMapper{AttributeExpression(GitlabMilestone), StringConverter{}}:                                                           RemoteIterationName,
+	},
 }
 
 type AttributeConverter interface {
@@ -113,6 +150,9 @@ type GithubStateConverter struct{}
 
 type JiraStateConverter struct{}
 
+// GitlabStateConverter converts the "opened" state of GitLab issues to "open"
+type GitlabStateConverter struct{}
+
 // Convert converts the given value to a string
 func (converter StringConverter) Convert(value interface{}, item AttributeAccessor) (interface{}, error) {
 	return value, nil
@@ -185,6 +225,14 @@ func (jhc JiraStateConverter) Convert(value interface{}, item AttributeAccessor)
 	return value, nil
 }
 
+// Convert converts the given GitLab issue state
+func (glc GitlabStateConverter) Convert(value interface{}, item AttributeAccessor) (interface{}, error) {
+	if s, ok := value.(string); ok && s == "opened" {
+		return "open", nil
+	}
+	return value, nil
+}
+
 type AttributeMapper struct {
 	Expression         AttributeExpression
 	AttributeConverter AttributeConverter
@@ -206,6 +254,7 @@ type AttributeAccessor interface {
 var RemoteWorkItemImplRegistry = map[string]func(TrackerItem) (AttributeAccessor, error){
 	ProviderGithub: NewGitHubRemoteWorkItem,
 	ProviderJira:   NewJiraRemoteWorkItem,
+	ProviderGitlab: NewGitlabRemoteWorkItem,
 }
 
 // GitHubRemoteWorkItem knows how to implement a FieldAccessor on a GitHub Issue JSON struct
@@ -251,6 +300,27 @@ func (jira JiraRemoteWorkItem) Get(field AttributeExpression) interface{} {
 	return jira.issue[string(field)]
 }
 
+// GitlabRemoteWorkItem knows how to implement a FieldAccessor on a GitLab Issue JSON struct
+type GitlabRemoteWorkItem struct {
+	issue map[string]interface{}
+}
+
+// NewGitlabRemoteWorkItem creates a new Decoded AttributeAccessor for a GitLab Issue
+func NewGitlabRemoteWorkItem(item TrackerItem) (AttributeAccessor, error) {
+	var j map[string]interface{}
+	err := json.Unmarshal([]byte(item.Item), &j)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	j = Flatten(j)
+	return GitlabRemoteWorkItem{issue: j}, nil
+}
+
+// Get attribute from issue map
+func (gl GitlabRemoteWorkItem) Get(field AttributeExpression) interface{} {
+	return gl.issue[string(field)]
+}
+
 // Map maps the remote WorkItem to a local RemoteWorkItem
 func Map(remoteItem AttributeAccessor, mapping RemoteWorkItemMap) (RemoteWorkItem, error) {
 	remoteWorkItem := RemoteWorkItem{Fields: make(map[string]interface{})}
@@ -1,37 +1,58 @@
 package remoteworkitem
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/models"
-
-	"context"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 
 	"github.com/jinzhu/gorm"
-	"github.com/pkg/errors"
+	errs "github.com/pkg/errors"
 	"github.com/robfig/cron"
 	uuid "github.com/satori/go.uuid"
 )
 
 // TrackerSchedule capture all configuration
 type trackerSchedule struct {
-	TrackerID   uuid.UUID
-	URL         string
-	TrackerType string
-	Query       string
-	Schedule    string
-	SpaceID     uuid.UUID
+	TrackerQueryID uuid.UUID
+	TrackerID      uuid.UUID
+	URL            string
+	TrackerType    string
+	Query          string
+	Schedule       string
+	SpaceID        uuid.UUID
+	WriteBack      bool
+	// LastSyncedAt is the start of the last successful run of the tracker
+	// query
+	LastSyncedAt *time.Time
+	// the field mappings of the tracker and the tracker query
+	TrackerFieldMapping FieldMapping
+	QueryFieldMapping   FieldMapping
+}
+
+// fieldMapping returns the effective field mapping of the tracker query
+func (ts trackerSchedule) fieldMapping() FieldMapping {
+	return ts.TrackerFieldMapping.Merge(ts.QueryFieldMapping)
 }
 
 // Scheduler represents scheduler
 type Scheduler struct {
 	db *gorm.DB
+	// running holds the IDs of the tracker queries that are being synced
+	running   map[uuid.UUID]bool
+	runningMu sync.Mutex
 }
 
 var cr *cron.Cron
 
 // NewScheduler creates a new Scheduler
 func NewScheduler(db *gorm.DB) *Scheduler {
-	s := Scheduler{db: db}
+	s := Scheduler{db: db, running: map[uuid.UUID]bool{}}
 	return &s
 }
 
@@ -41,44 +62,182 @@ func (s *Scheduler) Stop() {
 	cr.Stop()
 }
 
-func batchID() string {
-	u1 := uuid.NewV4().String()
-	return u1
-}
-
 // ScheduleAllQueries fetch and import of remote tracker items
 func (s *Scheduler) ScheduleAllQueries(ctx context.Context, accessTokens map[string]string) {
 	cr.Stop()
 
 	trackerQueries := fetchTrackerQueries(s.db)
 	for _, tq := range trackerQueries {
+		tq := tq
 		cr.AddFunc(tq.Schedule, func() {
-			tr := lookupProvider(tq)
-			authToken := accessTokens[tq.TrackerType]
-
-			// In case of Jira, no auth token is needed hence the map wouldnt
-			// return anything. So effectively the authToken is optional.
-
-			for i := range tr.Fetch(authToken) {
-				models.Transactional(s.db, func(tx *gorm.DB) error {
-					// Save the remote items in a 'temporary' table.
-					err := Upload(tx, tq.TrackerID, i)
-					if err != nil {
-						return errors.WithStack(err)
-					}
-					// Convert the remote item into a local work item and persist in the DB.
-					_, err = ConvertToWorkItemModel(ctx, tx, tq.TrackerID, i, tq.TrackerType, tq.SpaceID)
-					return errors.WithStack(err)
-				})
+			// The given context may belong to a request that has ended long
+			// ago, so it is not used for the run.
+			run, err := s.start(context.Background(), tq.TrackerQueryID)
+			if ok, _ := errors.IsDataConflictError(err); ok {
+				log.Info(ctx, map[string]interface{}{
+					"trackerquery_id": tq.TrackerQueryID,
+				}, "skipping the scheduled run of the tracker query which is still running")
+				return
+			}
+			if err != nil {
+				log.Error(ctx, map[string]interface{}{
+					"err":             err,
+					"trackerquery_id": tq.TrackerQueryID,
+				}, "unable to start the scheduled run of the tracker query")
+				return
 			}
+			s.run(context.Background(), tq.TrackerQueryID, run, accessTokens)
 		})
 	}
 	cr.Start()
 }
 
+// Sync starts a run of the given tracker query in the background and returns
+// it without waiting for it to finish. Returns a NotFoundError if the tracker
+// query does not exist and a DataConflictError if it is already running.
+func (s *Scheduler) Sync(ctx context.Context, trackerQueryID uuid.UUID, accessTokens map[string]string) (*TrackerQueryRun, error) {
+	run, err := s.start(ctx, trackerQueryID)
+	if err != nil {
+		return nil, err
+	}
+	go s.run(context.Background(), trackerQueryID, run, accessTokens)
+	return run, nil
+}
+
+// start records a new run of the given tracker query unless the tracker query
+// is already running
+func (s *Scheduler) start(ctx context.Context, trackerQueryID uuid.UUID) (*TrackerQueryRun, error) {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+	if s.running[trackerQueryID] {
+		return nil, errors.NewDataConflictError(fmt.Sprintf("tracker query %s is already running", trackerQueryID))
+	}
+	ts, err := fetchTrackerQuery(s.db, trackerQueryID)
+	if err != nil {
+		return nil, err
+	}
+	run := TrackerQueryRun{
+		TrackerQueryID: trackerQueryID,
+		Status:         RunStatusRunning,
+		StartedAt:      time.Now(),
+		Since:          ts.LastSyncedAt,
+	}
+	if err := NewTrackerQueryRunRepository(s.db).Create(ctx, &run); err != nil {
+		return nil, err
+	}
+	s.running[trackerQueryID] = true
+	return &run, nil
+}
+
+// run writes back the local changes if enabled, fetches and imports the items
+// of the given tracker query and records the outcome in the given run. Only
+// the items changed since the last successful run are fetched.
+func (s *Scheduler) run(ctx context.Context, trackerQueryID uuid.UUID, run *TrackerQueryRun, accessTokens map[string]string) {
+	defer func() {
+		s.runningMu.Lock()
+		defer s.runningMu.Unlock()
+		delete(s.running, trackerQueryID)
+	}()
+	fetchFailed := false
+	tq, err := fetchTrackerQuery(s.db, trackerQueryID)
+	if err != nil {
+		fetchFailed = true
+		run.addError(err)
+	} else {
+		fetchFailed = s.fetchAndImport(ctx, *tq, run, accessTokens)
+	}
+	run.finish(fetchFailed)
+
+	err = models.Transactional(s.db, func(tx *gorm.DB) error {
+		if err := NewTrackerQueryRunRepository(tx).Save(ctx, run); err != nil {
+			return err
+		}
+		if run.Status != RunStatusSucceeded {
+			return nil
+		}
+		// the items changed during the run may have been missed, so the next
+		// run fetches the items changed since the start of this run
+		return tx.Model(&TrackerQuery{}).Where("id = ?", trackerQueryID).UpdateColumn("last_synced_at", run.StartedAt).Error
+	})
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":                err,
+			"trackerquery_id":    trackerQueryID,
+			"trackerqueryrun_id": run.ID,
+		}, "unable to record the run of the tracker query")
+	}
+}
+
+// fetchAndImport syncs the items of the given tracker query and counts them in
+// the given run. Returns true if the items could not be fetched.
+func (s *Scheduler) fetchAndImport(ctx context.Context, tq trackerSchedule, run *TrackerQueryRun, accessTokens map[string]string) bool {
+	tr := lookupProvider(tq)
+	if tr == nil {
+		run.addError(errs.Errorf("unknown tracker type %s", tq.TrackerType))
+		return true
+	}
+	authToken := accessTokens[tq.TrackerType]
+
+	// In case of Jira, no auth token is needed hence the map wouldnt
+	// return anything. So effectively the authToken is optional.
+
+	if tq.WriteBack {
+		// Push the local changes before fetching so that they don't get
+		// overwritten by the import.
+		var err error
+		if w := lookupWriter(tq, authToken); w == nil {
+			err = errs.Errorf("tracker type %s does not support write back", tq.TrackerType)
+		} else {
+			_, err = WriteBack(ctx, s.db, tq.TrackerID, tq.TrackerType, tq.SpaceID, tq.fieldMapping(), w)
+		}
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"err":        err,
+				"tracker_id": tq.TrackerID,
+			}, "write back to remote tracker failed")
+			run.addError(errs.Wrap(err, "write back to remote tracker failed"))
+		}
+	}
+
+	fetchFailed := false
+	for i := range tr.Fetch(authToken) {
+		if i.Err != nil {
+			fetchFailed = true
+			run.addError(i.Err)
+			continue
+		}
+		run.ItemsFetched++
+		var wi *workitem.WorkItem
+		err := models.Transactional(s.db, func(tx *gorm.DB) error {
+			var err error
+			wi, err = Import(ctx, tx, tq.TrackerID, i, tq.TrackerType, tq.SpaceID, tq.fieldMapping())
+			return errs.WithStack(err)
+		})
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"err":            err,
+				"tracker_id":     tq.TrackerID,
+				"remote_item_id": i.ID,
+			}, "unable to import the remote item")
+			run.ItemsFailed++
+			run.addError(errs.Wrapf(err, "unable to import remote item %s", i.ID))
+			continue
+		}
+		// a work item that was just created has not been saved again yet
+		if wi.Version == 0 {
+			run.ItemsCreated++
+		} else {
+			run.ItemsUpdated++
+		}
+	}
+	return fetchFailed
+}
+
+const trackerScheduleSelect = "tracker_queries.id as tracker_query_id, trackers.id as tracker_id, trackers.url, trackers.type as tracker_type, tracker_queries.query, tracker_queries.schedule, tracker_queries.space_id, tracker_queries.write_back, tracker_queries.last_synced_at, trackers.field_mapping as tracker_field_mapping, tracker_queries.field_mapping as query_field_mapping"
+
 func fetchTrackerQueries(db *gorm.DB) []trackerSchedule {
 	tsList := []trackerSchedule{}
-	err := db.Table("tracker_queries").Select("trackers.id as tracker_id, trackers.url, trackers.type as tracker_type, tracker_queries.query, tracker_queries.schedule, tracker_queries.space_id").Joins("left join trackers on tracker_queries.tracker_id = trackers.id").Where("trackers.deleted_at is NULL AND tracker_queries.deleted_at is NULL").Scan(&tsList).Error
+	err := db.Table("tracker_queries").Select(trackerScheduleSelect).Joins("left join trackers on tracker_queries.tracker_id = trackers.id").Where("trackers.deleted_at is NULL AND tracker_queries.deleted_at is NULL").Scan(&tsList).Error
 	if err != nil {
 		log.Error(nil, map[string]interface{}{
 			"err": err,
@@ -87,13 +246,30 @@ func fetchTrackerQueries(db *gorm.DB) []trackerSchedule {
 	return tsList
 }
 
+// fetchTrackerQuery returns the current configuration of the given tracker
+// query
+// returns NotFoundError or InternalError
+func fetchTrackerQuery(db *gorm.DB, trackerQueryID uuid.UUID) (*trackerSchedule, error) {
+	tsList := []trackerSchedule{}
+	err := db.Table("tracker_queries").Select(trackerScheduleSelect).Joins("left join trackers on tracker_queries.tracker_id = trackers.id").Where("tracker_queries.id = ? AND trackers.deleted_at is NULL AND tracker_queries.deleted_at is NULL", trackerQueryID).Scan(&tsList).Error
+	if err != nil {
+		return nil, errors.NewInternalError(context.Background(), errs.Wrapf(err, "failed to load tracker query %s", trackerQueryID))
+	}
+	if len(tsList) == 0 {
+		return nil, errors.NewNotFoundError("tracker query", trackerQueryID.String())
+	}
+	return &tsList[0], nil
+}
+
 // lookupProvider provides the respective tracker based on the type
 func lookupProvider(ts trackerSchedule) TrackerProvider {
 	switch ts.TrackerType {
 	case ProviderGithub:
-		return &GithubTracker{URL: ts.URL, Query: ts.Query}
+		return &GithubTracker{URL: ts.URL, Query: ts.Query, Since: ts.LastSyncedAt}
 	case ProviderJira:
-		return &JiraTracker{URL: ts.URL, Query: ts.Query}
+		return &JiraTracker{URL: ts.URL, Query: ts.Query, Since: ts.LastSyncedAt}
+	case ProviderGitlab:
+		return &GitlabTracker{URL: ts.URL, Query: ts.Query, Since: ts.LastSyncedAt}
 	}
 	return nil
 }
@@ -102,6 +278,9 @@ func lookupProvider(ts trackerSchedule) TrackerProvider {
 type TrackerItemContent struct {
 	ID      string
 	Content []byte
+	// Err is set instead of the content when the item or the list of items
+	// could not be fetched
+	Err error
 }
 
 // TrackerProvider represents a remote tracker
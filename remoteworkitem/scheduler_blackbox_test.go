@@ -0,0 +1,158 @@
+package remoteworkitem_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/space"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchedulerSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestScheduler(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &SchedulerSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+// waitForRun waits until the given run of the tracker query is finished
+func (s *SchedulerSuite) waitForRun(t *testing.T, trackerQueryID, runID uuid.UUID) remoteworkitem.TrackerQueryRun {
+	repo := remoteworkitem.NewTrackerQueryRunRepository(s.DB)
+	for i := 0; i < 100; i++ {
+		runs, _, err := repo.List(s.Ctx, trackerQueryID, 0, 100)
+		require.NoError(t, err)
+		for _, run := range runs {
+			if run.ID == runID && run.Status != remoteworkitem.RunStatusRunning {
+				return run
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.FailNow(t, "run did not finish", "run %s", runID)
+	return remoteworkitem.TrackerQueryRun{}
+}
+
+func (s *SchedulerSuite) TestSync() {
+	// given a fake GitLab project with two issues of which only the second
+	// changes after the first run
+	var updatedAfter []string
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		updatedAfter = append(updatedAfter, r.URL.Query().Get("updated_after"))
+		issue := func(number int) string {
+			return fmt.Sprintf(`{"title":"issue %[1]d","state":"opened","web_url":"https://gitlab.example.com/group/project/issues/%[1]d","author":{"username":"jdoe","web_url":"https://gitlab.example.com/jdoe"},"updated_at":"2018-01-01T10:00:00.000Z"}`, number)
+		}
+		if r.URL.Query().Get("updated_after") != "" {
+			fmt.Fprintf(w, "[%s]", issue(2))
+			return
+		}
+		fmt.Fprintf(w, "[%s,%s]", issue(1), issue(2))
+	}))
+	defer server.Close()
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Trackers(1, func(fxt *tf.TestFixture, idx int) error {
+		fxt.Trackers[idx].Type = remoteworkitem.ProviderGitlab
+		fxt.Trackers[idx].URL = server.URL
+		return nil
+	}))
+	tq, err := remoteworkitem.NewTrackerQueryRepository(s.DB).Create(s.Ctx, remoteworkitem.TrackerQuery{
+		ID:        uuid.NewV4(),
+		Query:     "project=group/project",
+		Schedule:  "0 0 0 * * *",
+		TrackerID: fxt.Trackers[0].ID,
+		SpaceID:   space.SystemSpace,
+	})
+	require.NoError(s.T(), err)
+	scheduler := remoteworkitem.NewScheduler(s.DB)
+
+	var firstRun remoteworkitem.TrackerQueryRun
+	s.T().Run("first run fetches all items", func(t *testing.T) {
+		// when
+		run, err := scheduler.Sync(s.Ctx, tq.ID, map[string]string{})
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, remoteworkitem.RunStatusRunning, run.Status)
+		firstRun = s.waitForRun(t, tq.ID, run.ID)
+		assert.Equal(t, remoteworkitem.RunStatusSucceeded, firstRun.Status)
+		assert.Nil(t, firstRun.Since)
+		require.NotNil(t, firstRun.FinishedAt)
+		assert.Equal(t, 2, firstRun.ItemsFetched)
+		assert.Equal(t, 2, firstRun.ItemsCreated)
+		assert.Equal(t, 0, firstRun.ItemsUpdated)
+		assert.Equal(t, 0, firstRun.ItemsFailed)
+		assert.Empty(t, firstRun.Errors)
+		require.Equal(t, []string{""}, updatedAfter)
+		loaded, err := remoteworkitem.NewTrackerQueryRepository(s.DB).Load(s.Ctx, tq.ID)
+		require.NoError(t, err)
+		require.NotNil(t, loaded.LastSyncedAt)
+		assert.True(t, firstRun.StartedAt.Equal(*loaded.LastSyncedAt))
+	})
+
+	s.T().Run("next run fetches the changed items only", func(t *testing.T) {
+		// when
+		run, err := scheduler.Sync(s.Ctx, tq.ID, map[string]string{})
+		// then
+		require.NoError(t, err)
+		secondRun := s.waitForRun(t, tq.ID, run.ID)
+		assert.Equal(t, remoteworkitem.RunStatusSucceeded, secondRun.Status)
+		require.NotNil(t, secondRun.Since)
+		assert.True(t, firstRun.StartedAt.Equal(*secondRun.Since))
+		assert.Equal(t, 1, secondRun.ItemsFetched)
+		assert.Equal(t, 0, secondRun.ItemsCreated)
+		assert.Equal(t, 1, secondRun.ItemsUpdated)
+		require.Len(t, updatedAfter, 2)
+		assert.Equal(t, firstRun.StartedAt.UTC().Format(time.RFC3339), updatedAfter[1])
+	})
+
+	s.T().Run("failed run", func(t *testing.T) {
+		// given
+		failing = true
+		defer func() { failing = false }()
+		before, err := remoteworkitem.NewTrackerQueryRepository(s.DB).Load(s.Ctx, tq.ID)
+		require.NoError(t, err)
+		// when
+		run, err := scheduler.Sync(s.Ctx, tq.ID, map[string]string{})
+		// then
+		require.NoError(t, err)
+		failedRun := s.waitForRun(t, tq.ID, run.ID)
+		assert.Equal(t, remoteworkitem.RunStatusFailed, failedRun.Status)
+		assert.Equal(t, 0, failedRun.ItemsFetched)
+		require.Len(t, failedRun.Errors, 1)
+		assert.Contains(t, failedRun.Errors[0], "500")
+		after, err := remoteworkitem.NewTrackerQueryRepository(s.DB).Load(s.Ctx, tq.ID)
+		require.NoError(t, err)
+		assert.True(t, before.LastSyncedAt.Equal(*after.LastSyncedAt))
+	})
+
+	s.T().Run("run history", func(t *testing.T) {
+		runs, count, err := remoteworkitem.NewTrackerQueryRunRepository(s.DB).List(s.Ctx, tq.ID, 0, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		require.Len(t, runs, 2)
+		assert.Equal(t, remoteworkitem.RunStatusFailed, runs[0].Status)
+		assert.Equal(t, remoteworkitem.RunStatusSucceeded, runs[1].Status)
+	})
+
+	s.T().Run("unknown tracker query", func(t *testing.T) {
+		_, err := scheduler.Sync(s.Ctx, uuid.NewV4(), map[string]string{})
+		require.Error(t, err)
+		ok, _ := errors.IsNotFoundError(err)
+		assert.True(t, ok)
+	})
+}
@@ -11,8 +11,10 @@ type Tracker struct {
 	ID uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
 	// URL of the tracker
 	URL string
-	// Type of the tracker (jira, github, bugzilla, trello etc.)
+	// Type of the tracker (jira, github, gitlab, bugzilla, trello etc.)
 	Type string
+	// FieldMapping configures how the items of the tracker are imported
+	FieldMapping FieldMapping `sql:"type:jsonb"`
 }
 
 // TableName overrides the table name settings in Gorm to force a specific table name
@@ -1,6 +1,7 @@
 package remoteworkitem
 
 import (
+	"fmt"
 	"time"
 
 	"context"
@@ -53,6 +54,9 @@ func (r *GormTrackerRepository) Create(ctx context.Context, t *Tracker) error {
 	if present != true {
 		return BadParameterError{parameter: "type", value: t.Type}
 	}
+	if err := validateFieldMapping(ctx, r.db, t.FieldMapping, t.Type, nil); err != nil {
+		return err
+	}
 	if err := r.db.Create(&t).Error; err != nil {
 		return InternalError{simpleError{err.Error()}}
 	}
@@ -120,6 +124,22 @@ func (r *GormTrackerRepository) Save(ctx context.Context, t *Tracker) (*Tracker,
 	if present != true {
 		return nil, errors.NewBadParameterError("type", t.Type)
 	}
+	if err := validateFieldMapping(ctx, r.db, t.FieldMapping, t.Type, nil); err != nil {
+		return nil, err
+	}
+	// the mapping of the tracker applies to all of its queries
+	var queries []TrackerQuery
+	if err := r.db.Where("tracker_id = ?", t.ID).Find(&queries).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	for _, q := range queries {
+		if err := validateTrackerQuery(ctx, r.db, *t, q); err != nil {
+			if ok, _ := errors.IsBadParameterError(err); ok {
+				return nil, errors.NewBadParameterErrorFromString(fmt.Sprintf("the tracker no longer fits its query %s: %s", q.ID, err.Error()))
+			}
+			return nil, err
+		}
+	}
 
 	if err := tx.Save(&t).Error; err != nil {
 		log.Error(ctx, map[string]interface{}{
@@ -10,6 +10,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 	uuid "github.com/satori/go.uuid"
 
 	"github.com/stretchr/testify/assert"
@@ -59,6 +60,77 @@ func (test *TestTrackerRepository) TestTrackerCreate() {
 	assert.Equal(t, remoteworkitem.ProviderGithub, tracker2.Type)
 }
 
+func (test *TestTrackerRepository) TestTrackerFieldMapping() {
+	t := test.T()
+	resource.Require(t, resource.Database)
+
+	t.Run("valid mapping", func(t *testing.T) {
+		// given
+		tracker := remoteworkitem.Tracker{
+			URL:  "https://api.github.com/",
+			Type: remoteworkitem.ProviderGithub,
+			FieldMapping: remoteworkitem.FieldMapping{
+				States: map[string]string{"closed": workitem.SystemStateResolved},
+			},
+		}
+		// when
+		err := test.repo.Create(context.Background(), &tracker)
+		// then
+		require.NoError(t, err)
+		loaded, err := test.repo.Load(context.Background(), tracker.ID)
+		require.NoError(t, err)
+		assert.Equal(t, tracker.FieldMapping, loaded.FieldMapping)
+	})
+
+	t.Run("invalid mapping", func(t *testing.T) {
+		// given
+		tracker := remoteworkitem.Tracker{
+			URL:  "https://api.github.com/",
+			Type: remoteworkitem.ProviderGithub,
+			FieldMapping: remoteworkitem.FieldMapping{
+				Fields: []remoteworkitem.FieldMappingEntry{{Remote: "milestone.title", Field: "unknown.field"}},
+			},
+		}
+		// when
+		err := test.repo.Create(context.Background(), &tracker)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, err)
+	})
+
+	t.Run("unknown work item type", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, test.DB, tf.Trackers(1))
+		typeID := uuid.NewV4()
+		fxt.Trackers[0].FieldMapping = remoteworkitem.FieldMapping{WorkItemTypeID: &typeID}
+		// when
+		_, err := test.repo.Save(context.Background(), fxt.Trackers[0])
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, err)
+	})
+
+	t.Run("mapping that does not fit a query of the tracker", func(t *testing.T) {
+		// given a query in a space whose template has no bug type
+		fxt := tf.NewTestFixture(t, test.DB, tf.Trackers(1), tf.Spaces(1))
+		_, err := remoteworkitem.NewTrackerQueryRepository(test.DB).Create(context.Background(), remoteworkitem.TrackerQuery{
+			Query:     "abc",
+			Schedule:  "xyz",
+			TrackerID: fxt.Trackers[0].ID,
+			SpaceID:   fxt.Spaces[0].ID,
+		})
+		require.NoError(t, err)
+		fxt.Trackers[0].FieldMapping = remoteworkitem.FieldMapping{
+			States: map[string]string{"closed": workitem.SystemStateResolved},
+		}
+		// when
+		_, err = test.repo.Save(context.Background(), fxt.Trackers[0])
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, err)
+	})
+}
+
 func (test *TestTrackerRepository) TestExistsTracker() {
 	t := test.T()
 	resource.Require(t, resource.Database)
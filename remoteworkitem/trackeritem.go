@@ -1,6 +1,8 @@
 package remoteworkitem
 
 import (
+	"time"
+
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
 	uuid "github.com/satori/go.uuid"
 )
@@ -16,4 +18,12 @@ type TrackerItem struct {
 	Item string
 	// FK to tracker
 	TrackerID uuid.UUID `gorm:"ForeignKey:Tracker"`
+	// RemoteUpdatedAt is the last modification time of the remote item when
+	// it was last synced
+	RemoteUpdatedAt *time.Time
+	// SyncedVersion is the version of the local work item when it was last
+	// synced
+	SyncedVersion *int
+	// SyncedAt is the time of the last sync
+	SyncedAt *time.Time
 }
@@ -4,9 +4,12 @@ import (
 	"fmt"
 
 	"context"
+	"time"
 
 	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/label"
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 
@@ -32,29 +35,78 @@ func Upload(db *gorm.DB, tID uuid.UUID, item TrackerItemContent) error {
 	return db.Save(&ti).Error
 }
 
+// Import uploads the given remote item, converts it into a local work item
+// using the given field mapping and remembers the version of the work item
+// and the modification time of the remote item as the state of the last sync.
+func Import(ctx context.Context, db *gorm.DB, tID uuid.UUID, item TrackerItemContent, providerType string, spaceID uuid.UUID, mapping FieldMapping) (*workitem.WorkItem, error) {
+	// Save the remote items in a 'temporary' table.
+	if err := Upload(db, tID, item); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// Convert the remote item into a local work item and persist in the DB.
+	wi, err := convertToWorkItemModel(ctx, db, tID, item, providerType, spaceID, mapping)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var ti TrackerItem
+	if err := db.Where("remote_item_id = ? AND tracker_id = ?", item.ID, tID).Find(&ti).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to load tracker item %s", item.ID)
+	}
+	ti.RemoteUpdatedAt, err = remoteUpdatedAt(ti, providerType)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	ti.SyncedVersion = &wi.Version
+	ti.SyncedAt = &now
+	if err := db.Save(&ti).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to update the sync markers of tracker item %s", item.ID)
+	}
+	return wi, nil
+}
+
 // Map a remote work item into an WIT work item and persist it into the database.
 func ConvertToWorkItemModel(ctx context.Context, db *gorm.DB, tID uuid.UUID, item TrackerItemContent, providerType string, spaceID uuid.UUID) (*workitem.WorkItem, error) {
+	return convertToWorkItemModel(ctx, db, tID, item, providerType, spaceID, FieldMapping{})
+}
+
+// convertToWorkItemModel maps a remote work item into an WIT work item using
+// the given field mapping and persists it into the database.
+func convertToWorkItemModel(ctx context.Context, db *gorm.DB, tID uuid.UUID, item TrackerItemContent, providerType string, spaceID uuid.UUID, mapping FieldMapping) (*workitem.WorkItem, error) {
 	remoteID := item.ID
 	content := string(item.Content)
 	trackerItem := TrackerItem{Item: content, RemoteItemID: remoteID, TrackerID: tID}
 	// Converting the remote item to a local work item
-	remoteTrackerItemConvertFunc, ok := RemoteWorkItemImplRegistry[providerType]
-	if !ok {
-		return nil, BadParameterError{parameter: providerType, value: providerType}
+	remoteWorkItem, err := mapTrackerItem(trackerItem, providerType, mapping)
+	if err != nil {
+		return nil, err
 	}
-	remoteTrackerItem, err := remoteTrackerItemConvertFunc(trackerItem)
+	workItem, err := lookupIdentities(ctx, db, remoteWorkItem, providerType, spaceID)
 	if err != nil {
-		return nil, InternalError{simpleError{message: fmt.Sprintf(" Error parsing the tracker data: %s", err.Error())}}
+		return nil, InternalError{simpleError{message: fmt.Sprintf("Error bind assignees: %s", err.Error())}}
 	}
-	remoteWorkItem, err := Map(remoteTrackerItem, RemoteWorkItemKeyMaps[providerType])
+	if err := lookupLabelsAndIteration(ctx, db, workItem); err != nil {
+		return nil, InternalError{simpleError{message: fmt.Sprintf("Error bind labels and iteration: %s", err.Error())}}
+	}
+	return upsert(ctx, db, *workItem, mapping.TypeID())
+}
+
+// mapTrackerItem maps the content of the given tracker item into a remote
+// work item.
+func mapTrackerItem(ti TrackerItem, providerType string, mapping FieldMapping) (RemoteWorkItem, error) {
+	convertFunc, ok := RemoteWorkItemImplRegistry[providerType]
+	if !ok {
+		return RemoteWorkItem{}, BadParameterError{parameter: providerType, value: providerType}
+	}
+	keyMap, err := mapping.RemoteWorkItemMap(providerType)
 	if err != nil {
-		return nil, ConversionError{simpleError{message: fmt.Sprintf("Error mapping to local work item: %s", err.Error())}}
+		return RemoteWorkItem{}, ConversionError{simpleError{message: fmt.Sprintf("Error in field mapping: %s", err.Error())}}
 	}
-	workItem, err := lookupIdentities(ctx, db, remoteWorkItem, providerType, spaceID)
+	accessor, err := convertFunc(ti)
 	if err != nil {
-		return nil, InternalError{simpleError{message: fmt.Sprintf("Error bind assignees: %s", err.Error())}}
+		return RemoteWorkItem{}, InternalError{simpleError{message: fmt.Sprintf(" Error parsing the tracker data: %s", err.Error())}}
 	}
-	return upsert(ctx, db, *workItem)
+	return Map(accessor, keyMap)
 }
 
 // lookupIdentities looks up creator and assignee remote identities to local identities (already existing or to be created)
@@ -115,7 +167,60 @@ func lookupIdentities(ctx context.Context, db *gorm.DB, remoteWorkItem RemoteWor
 	return &workItem, nil
 }
 
-func upsert(ctx context.Context, db *gorm.DB, workItem workitem.WorkItem) (*workitem.WorkItem, error) {
+// lookupLabelsAndIteration replaces the remote label and iteration names of
+// the given work item with the IDs of the labels and the iteration of the
+// space that have these names. Missing labels are created, missing iterations
+// are created as children of the root iteration of the space.
+func lookupLabelsAndIteration(ctx context.Context, db *gorm.DB, workItem *workitem.WorkItem) error {
+	if names, ok := workItem.Fields[RemoteLabelNames]; ok {
+		delete(workItem.Fields, RemoteLabelNames)
+		labelNames, _ := names.([]string)
+		labelRepository := label.NewLabelRepository(db)
+		labelIDs := []string{}
+		for _, name := range labelNames {
+			var labels []label.Label
+			if err := db.Where("space_id = ? AND name = ?", workItem.SpaceID, name).Find(&labels).Error; err != nil {
+				return errors.Wrapf(err, "failed to look up label %s", name)
+			}
+			if len(labels) == 0 {
+				labels = append(labels, label.Label{SpaceID: workItem.SpaceID, Name: name})
+				if err := labelRepository.Create(ctx, &labels[0]); err != nil {
+					return errors.Wrapf(err, "failed to create label %s", name)
+				}
+			}
+			labelIDs = append(labelIDs, labels[0].ID.String())
+		}
+		workItem.Fields[workitem.SystemLabels] = labelIDs
+	}
+	if name, ok := workItem.Fields[RemoteIterationName]; ok {
+		delete(workItem.Fields, RemoteIterationName)
+		iterationName, _ := name.(string)
+		if iterationName == "" {
+			return nil
+		}
+		var iterations []iteration.Iteration
+		if err := db.Where("space_id = ? AND name = ?", workItem.SpaceID, iterationName).Order("created_at").Find(&iterations).Error; err != nil {
+			return errors.Wrapf(err, "failed to look up iteration %s", iterationName)
+		}
+		if len(iterations) == 0 {
+			iterationRepository := iteration.NewIterationRepository(db)
+			root, err := iterationRepository.Root(ctx, workItem.SpaceID)
+			if err != nil {
+				return errors.Wrapf(err, "failed to load the root iteration of space %s", workItem.SpaceID)
+			}
+			itr := iteration.Iteration{ID: uuid.NewV4(), SpaceID: workItem.SpaceID, Name: iterationName}
+			itr.MakeChildOf(*root)
+			if err := iterationRepository.Create(ctx, &itr); err != nil {
+				return errors.Wrapf(err, "failed to create iteration %s", iterationName)
+			}
+			iterations = append(iterations, itr)
+		}
+		workItem.Fields[workitem.SystemIteration] = iterations[0].ID.String()
+	}
+	return nil
+}
+
+func upsert(ctx context.Context, db *gorm.DB, workItem workitem.WorkItem, typeID uuid.UUID) (*workitem.WorkItem, error) {
 	wir := workitem.NewWorkItemRepository(db)
 	// Get the remote item identifier ( which is currently the url ) to check if the work item exists in the database.
 	workItemRemoteID := workItem.Fields[workitem.SystemRemoteItemID]
@@ -149,7 +254,7 @@ func upsert(ctx context.Context, db *gorm.DB, workItem workitem.WorkItem) (*work
 		}
 	} else {
 		log.Info(nil, nil, "Workitem does not exist, will be created")
-		resultWorkItem, _, err = wir.Create(ctx, workItem.SpaceID, workitem.SystemBug, workItem.Fields, creator)
+		resultWorkItem, _, err = wir.Create(ctx, workItem.SpaceID, typeID, workItem.Fields, creator)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
@@ -9,11 +9,14 @@ import (
 
 	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/label"
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/rendering"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 
 	"github.com/goadesign/goa"
@@ -290,3 +293,78 @@ func (s *TrackerItemRepositorySuite) TestConvertGithubIssue() {
 	assert.Equal(s.T(), identity.ID.String(), workItemGithub.Fields[workitem.SystemAssignees].([]interface{})[0])
 	assert.Equal(s.T(), "open", workItemGithub.Fields[workitem.SystemState])
 }
+
+func (s *TrackerItemRepositorySuite) TestImportWithFieldMapping() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Trackers(1))
+	typeID := workitem.SystemTask
+	mapping := remoteworkitem.FieldMapping{
+		WorkItemTypeID: &typeID,
+		Fields: []remoteworkitem.FieldMappingEntry{
+			{Remote: "milestone.title", Field: workitem.SystemTitle},
+		},
+		States: map[string]string{"Closed": workitem.SystemStateResolved},
+	}
+	remoteItemData := remoteworkitem.TrackerItemContent{
+		Content: []byte(`{"title":"linking","url":"http://github.com/sbose/api/testonly/2","state":"closed","body":"body of issue","milestone":{"title":"milestone 1"},"updated_at":"2018-01-01T10:00:00Z"}`),
+		ID:      `"http://github.com/sbose/api/testonly/2"`,
+	}
+	// when
+	workItem, err := remoteworkitem.Import(s.Ctx, s.DB, fxt.Trackers[0].ID, remoteItemData, remoteworkitem.ProviderGithub, s.trackerQuery.SpaceID, mapping)
+	// then
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), workitem.SystemTask, workItem.Type)
+	assert.Equal(s.T(), "milestone 1", workItem.Fields[workitem.SystemTitle])
+	assert.Equal(s.T(), workitem.SystemStateResolved, workItem.Fields[workitem.SystemState])
+}
+
+func (s *TrackerItemRepositorySuite) TestImportGitlabIssue() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.Trackers(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.Trackers[idx].Type = remoteworkitem.ProviderGitlab
+			fxt.Trackers[idx].URL = "https://gitlab.example.com"
+			return nil
+		}),
+		tf.WorkItemTypes(1),
+		tf.Iterations(1),
+		tf.Labels(1),
+	)
+	spaceID := fxt.Spaces[0].ID
+	mapping := remoteworkitem.FieldMapping{WorkItemTypeID: &fxt.WorkItemTypes[0].ID}
+	remoteItemData := remoteworkitem.TrackerItemContent{
+		Content: []byte(`{"title":"crash on start","description":"**it** crashes","state":"opened","web_url":"https://gitlab.example.com/group/project/issues/1","author":{"username":"jdoe","web_url":"https://gitlab.example.com/jdoe"},"assignees":[{"username":"jdoe","web_url":"https://gitlab.example.com/jdoe"}],"labels":["` + fxt.Labels[0].Name + `","new label"],"milestone":{"title":"Sprint 1"},"updated_at":"2018-01-01T10:00:00.000Z"}`),
+		ID:      `"https://gitlab.example.com/group/project/issues/1"`,
+	}
+	// when
+	workItem, err := remoteworkitem.Import(s.Ctx, s.DB, fxt.Trackers[0].ID, remoteItemData, remoteworkitem.ProviderGitlab, spaceID, mapping)
+	// then
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "crash on start", workItem.Fields[workitem.SystemTitle])
+	assert.Equal(s.T(), rendering.NewMarkupContent("**it** crashes", rendering.SystemMarkupMarkdown), workItem.Fields[workitem.SystemDescription])
+	assert.Equal(s.T(), "open", workItem.Fields[workitem.SystemState])
+	require.Len(s.T(), workItem.Fields[workitem.SystemAssignees], 1)
+	labels, ok := workItem.Fields[workitem.SystemLabels].([]interface{})
+	require.True(s.T(), ok)
+	require.Len(s.T(), labels, 2)
+	assert.Equal(s.T(), fxt.Labels[0].ID.String(), labels[0])
+	newLabel, err := label.NewLabelRepository(s.DB).Load(s.Ctx, uuid.FromStringOrNil(labels[1].(string)))
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "new label", newLabel.Name)
+	assert.Equal(s.T(), spaceID, newLabel.SpaceID)
+	iterationID := uuid.FromStringOrNil(workItem.Fields[workitem.SystemIteration].(string))
+	itr, err := iteration.NewIterationRepository(s.DB).Load(s.Ctx, iterationID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "Sprint 1", itr.Name)
+	assert.Equal(s.T(), fxt.Iterations[0].ID, itr.Path.ParentID())
+
+	s.T().Run("reimport reuses the labels and the iteration", func(t *testing.T) {
+		// when
+		reimported, err := remoteworkitem.Import(s.Ctx, s.DB, fxt.Trackers[0].ID, remoteItemData, remoteworkitem.ProviderGitlab, spaceID, mapping)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, workItem.ID, reimported.ID)
+		assert.Equal(t, labels, reimported.Fields[workitem.SystemLabels])
+		assert.Equal(t, iterationID.String(), reimported.Fields[workitem.SystemIteration])
+	})
+}
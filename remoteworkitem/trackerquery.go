@@ -1,6 +1,8 @@
 package remoteworkitem
 
 import (
+	"time"
+
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
 
 	uuid "github.com/satori/go.uuid"
@@ -18,6 +20,14 @@ type TrackerQuery struct {
 	TrackerID uuid.UUID `gorm:"ForeignKey:Tracker"`
 	// SpaceID is a foreign key for a space
 	SpaceID uuid.UUID `gorm:"ForeignKey:Space"`
+	// WriteBack enables pushing local edits of the imported work items back
+	// to the remote tracker
+	WriteBack bool
+	// FieldMapping overrides the field mapping of the tracker
+	FieldMapping FieldMapping `sql:"type:jsonb"`
+	// LastSyncedAt is the start of the last successful run. The next run only
+	// fetches the items that changed since then.
+	LastSyncedAt *time.Time
 }
 
 // TableName overrides the table name settings in Gorm to force a specific table name
@@ -2,10 +2,12 @@ package remoteworkitem
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/fabric8-services/fabric8-wit/application/repository"
 	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/goadesign/goa"
 
 	"github.com/fabric8-services/fabric8-wit/errors"
@@ -42,6 +44,13 @@ type TrackerQueryRepository interface {
 // Create creates a new tracker query in the repository
 // returns BadParameterError, ConversionError or InternalError
 func (r *GormTrackerQueryRepository) Create(ctx context.Context, tq TrackerQuery) (*TrackerQuery, error) {
+	tracker, err := NewTrackerRepository(r.db).Load(ctx, tq.TrackerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTrackerQuery(ctx, r.db, *tracker, tq); err != nil {
+		return nil, err
+	}
 	if err := r.db.Create(&tq).Error; err != nil {
 		return nil, errors.NewInternalError(ctx, r.db.Error)
 	}
@@ -100,7 +109,14 @@ func (r *GormTrackerQueryRepository) Save(ctx context.Context, tq TrackerQuery)
 		return nil, errors.NewNotFoundError("Tracker", tq.TrackerID.String())
 	}
 
-	if err := tx.Save(&res).Error; err != nil {
+	tracker, err := NewTrackerRepository(r.db).Load(ctx, tq.TrackerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTrackerQuery(ctx, r.db, *tracker, tq); err != nil {
+		return nil, err
+	}
+	if err := r.db.Save(&tq).Error; err != nil {
 		log.Error(ctx, map[string]interface{}{
 			"trackerquery_id": tq.ID,
 			"err":             err,
@@ -108,7 +124,26 @@ func (r *GormTrackerQueryRepository) Save(ctx context.Context, tq TrackerQuery)
 		return nil, errors.NewInternalError(ctx, err)
 	}
 
-	return &res, nil
+	return &tq, nil
+}
+
+// validateTrackerQuery checks that the given tracker supports writing back if
+// the given query of it has it enabled and checks the field mapping of the
+// query combined with the field mapping of the tracker against the space
+// template of the space of the query.
+func validateTrackerQuery(ctx context.Context, db *gorm.DB, tracker Tracker, tq TrackerQuery) error {
+	if tq.WriteBack && !SupportsWriteBack(tracker.Type) {
+		return errors.NewBadParameterError("write_back", tq.WriteBack).Expected(fmt.Sprintf("false for a tracker of type %s", tracker.Type))
+	}
+	m := tracker.FieldMapping.Merge(tq.FieldMapping)
+	if m.IsEmpty() {
+		return nil
+	}
+	s, err := space.NewRepository(db).Load(ctx, tq.SpaceID)
+	if err != nil {
+		return err
+	}
+	return validateFieldMapping(ctx, db, m, tracker.Type, &s.SpaceTemplateID)
 }
 
 // Delete deletes the tracker query with the given id
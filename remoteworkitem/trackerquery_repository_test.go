@@ -12,6 +12,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 	uuid "github.com/satori/go.uuid"
 
 	"github.com/goadesign/goa"
@@ -56,7 +57,7 @@ func (test *TestTrackerQueryRepository) TestTrackerQueryCreate() {
 		}
 		res, err := test.queryRepo.Create(ctx, tq)
 		require.Error(t, err)
-		assert.IsType(t, errors.InternalError{}, err)
+		assert.IsType(t, errors.NotFoundError{}, err)
 		require.Nil(t, res)
 	})
 
@@ -87,6 +88,55 @@ func (test *TestTrackerQueryRepository) TestTrackerQueryCreate() {
 		assert.Equal(t, res.ID, res2.ID)
 	})
 
+	t.Run("tracker query create - write back not supported", func(t *testing.T) {
+		req := &http.Request{Host: "localhost"}
+		params := url.Values{}
+		ctx := goa.NewContext(context.Background(), nil, req, params)
+
+		tracker := remoteworkitem.Tracker{
+			URL:  "https://gitlab.com",
+			Type: remoteworkitem.ProviderGitlab,
+		}
+		err := test.trackerRepo.Create(ctx, &tracker)
+		require.NoError(t, err)
+		fxt := tf.NewTestFixture(t, test.DB, tf.Spaces(1))
+
+		tq := remoteworkitem.TrackerQuery{
+			Query:     "abc",
+			Schedule:  "xyz",
+			TrackerID: tracker.ID,
+			SpaceID:   fxt.Spaces[0].ID,
+			WriteBack: true,
+		}
+		res, err := test.queryRepo.Create(ctx, tq)
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, err)
+		require.Nil(t, res)
+	})
+
+	t.Run("tracker query create - work item type of another space template", func(t *testing.T) {
+		req := &http.Request{Host: "localhost"}
+		params := url.Values{}
+		ctx := goa.NewContext(context.Background(), nil, req, params)
+
+		fxt := tf.NewTestFixture(t, test.DB, tf.Trackers(1), tf.Spaces(1))
+
+		// the bug type of the mapping is not part of the space template of
+		// the fixture
+		tq := remoteworkitem.TrackerQuery{
+			Query:     "abc",
+			Schedule:  "xyz",
+			TrackerID: fxt.Trackers[0].ID,
+			SpaceID:   fxt.Spaces[0].ID,
+			FieldMapping: remoteworkitem.FieldMapping{
+				States: map[string]string{"closed": workitem.SystemStateResolved},
+			},
+		}
+		res, err := test.queryRepo.Create(ctx, tq)
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, err)
+		require.Nil(t, res)
+	})
 }
 
 func (test *TestTrackerQueryRepository) TestExistsTrackerQuery() {
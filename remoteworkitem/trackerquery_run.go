@@ -0,0 +1,168 @@
+package remoteworkitem
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeTrackerQueryRun helps to avoid string literal
+const APIStringTypeTrackerQueryRun = "trackerqueryrun"
+
+const trackerQueryRunsTableName = "tracker_query_runs"
+
+// The states of a tracker query run
+const (
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+)
+
+// maxRunErrors is the number of errors that are kept for a run. Further
+// errors are only counted.
+const maxRunErrors = 20
+
+// RunErrors holds the error messages of a tracker query run
+type RunErrors []string
+
+// Ensure RunErrors implements the Valuer interface
+var _ driver.Valuer = RunErrors{}
+
+// Value implements the https://golang.org/pkg/database/sql/driver/#Valuer
+// interface
+func (e RunErrors) Value() (driver.Value, error) {
+	if len(e) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(e)
+}
+
+// Scan implements the https://golang.org/pkg/database/sql/#Scanner
+// interface
+func (e *RunErrors) Scan(src interface{}) error {
+	*e = nil
+	if src == nil {
+		return nil
+	}
+	bytes, ok := src.([]byte)
+	if !ok {
+		return errs.Errorf("scanned value is not a byte array: %+v (%[1]T)", src)
+	}
+	return json.Unmarshal(bytes, e)
+}
+
+// TrackerQueryRun records a single fetch and import of the items of a tracker
+// query
+type TrackerQueryRun struct {
+	ID             uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	TrackerQueryID uuid.UUID `sql:"type:uuid"`
+	Status         string
+	StartedAt      time.Time
+	FinishedAt     *time.Time
+	// Since is the time of the last successful sync the items were fetched
+	// from or nil if all items were fetched
+	Since        *time.Time
+	ItemsFetched int
+	ItemsCreated int
+	ItemsUpdated int
+	ItemsFailed  int
+	// Errors holds the first errors that occurred during the run
+	Errors RunErrors `sql:"type:jsonb"`
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (r TrackerQueryRun) TableName() string {
+	return trackerQueryRunsTableName
+}
+
+// addError records the given error of the run
+func (r *TrackerQueryRun) addError(err error) {
+	if len(r.Errors) < maxRunErrors {
+		r.Errors = append(r.Errors, err.Error())
+	}
+}
+
+// finish marks the run as done
+func (r *TrackerQueryRun) finish(fetchFailed bool) {
+	now := time.Now()
+	r.FinishedAt = &now
+	r.Status = RunStatusSucceeded
+	if fetchFailed || r.ItemsFailed > 0 || len(r.Errors) > 0 {
+		r.Status = RunStatusFailed
+	}
+}
+
+// TrackerQueryRunRepository encapsulate storage & retrieval of the runs of
+// tracker queries
+type TrackerQueryRunRepository interface {
+	Create(ctx context.Context, run *TrackerQueryRun) error
+	Save(ctx context.Context, run *TrackerQueryRun) error
+	// List returns the runs of the given tracker query, the most recent run
+	// first, and the total number of runs
+	List(ctx context.Context, trackerQueryID uuid.UUID, start int, limit int) ([]TrackerQueryRun, int, error)
+}
+
+// NewTrackerQueryRunRepository constructs a TrackerQueryRunRepository
+func NewTrackerQueryRunRepository(db *gorm.DB) *GormTrackerQueryRunRepository {
+	return &GormTrackerQueryRunRepository{db: db}
+}
+
+// GormTrackerQueryRunRepository implements TrackerQueryRunRepository using gorm
+type GormTrackerQueryRunRepository struct {
+	db *gorm.DB
+}
+
+// Create stores a new run
+// returns InternalError
+func (r *GormTrackerQueryRunRepository) Create(ctx context.Context, run *TrackerQueryRun) error {
+	defer goa.MeasureSince([]string{"goa", "db", "trackerqueryrun", "create"}, time.Now())
+	if err := r.db.Create(run).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"trackerquery_id": run.TrackerQueryID,
+			"err":             err,
+		}, "unable to create the tracker query run")
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to create the tracker query run"))
+	}
+	return nil
+}
+
+// Save updates the given run
+// returns InternalError
+func (r *GormTrackerQueryRunRepository) Save(ctx context.Context, run *TrackerQueryRun) error {
+	defer goa.MeasureSince([]string{"goa", "db", "trackerqueryrun", "save"}, time.Now())
+	if err := r.db.Save(run).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"trackerqueryrun_id": run.ID,
+			"err":                err,
+		}, "unable to save the tracker query run")
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to save the tracker query run %s", run.ID))
+	}
+	return nil
+}
+
+// List returns the runs of the given tracker query, the most recent run first
+// returns InternalError
+func (r *GormTrackerQueryRunRepository) List(ctx context.Context, trackerQueryID uuid.UUID, start int, limit int) ([]TrackerQueryRun, int, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "trackerqueryrun", "list"}, time.Now())
+	var count int
+	db := r.db.Model(&TrackerQueryRun{}).Where("tracker_query_id = ?", trackerQueryID)
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to count the runs of tracker query %s", trackerQueryID))
+	}
+	var runs []TrackerQueryRun
+	if err := db.Order("started_at DESC").Offset(start).Limit(limit).Find(&runs).Error; err != nil {
+		return nil, 0, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list the runs of tracker query %s", trackerQueryID))
+	}
+	return runs, count, nil
+}
@@ -0,0 +1,529 @@
+package remoteworkitem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// jiraTimeLayout is the format of the timestamps in the Jira REST API
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// RemoteChanges holds the local changes of a work item that are written back
+// to the remote tracker. Values are given in the vocabulary of the remote
+// tracker and nil values are left untouched.
+type RemoteChanges struct {
+	Title *string
+	State *string
+	// Assignees are the logins of the assignees on the remote tracker
+	Assignees []string
+}
+
+// IsEmpty returns true if there is nothing to write back
+func (c RemoteChanges) IsEmpty() bool {
+	return c.Title == nil && c.State == nil && c.Assignees == nil
+}
+
+// RemoteWriter writes local changes back to a remote tracker. Remote items are
+// addressed by their API URL which is what gets stored in the
+// system.remote_item_id field of the imported work items.
+type RemoteWriter interface {
+	// UpdatedAt returns the last modification time of the remote item
+	UpdatedAt(ctx context.Context, itemURL string) (time.Time, error)
+	// Update applies the given changes to the remote item
+	Update(ctx context.Context, itemURL string, changes RemoteChanges) error
+	// AddComment adds a comment with the given body to the remote item
+	AddComment(ctx context.Context, itemURL string, body string) error
+}
+
+// writeBackClient sends the requests to the remote trackers. Unlike with
+// http.DefaultClient, a remote tracker that doesn't answer can't block the
+// run of a tracker query forever.
+var writeBackClient = &http.Client{Timeout: 30 * time.Second}
+
+// SupportsWriteBack returns true if local changes can be written back to
+// remote trackers of the given type
+func SupportsWriteBack(providerType string) bool {
+	switch providerType {
+	case ProviderGithub, ProviderJira:
+		return true
+	}
+	return false
+}
+
+// lookupWriter provides the respective remote writer based on the type or nil
+// if the type doesn't support writing back (see SupportsWriteBack)
+func lookupWriter(ts trackerSchedule, authToken string) RemoteWriter {
+	switch ts.TrackerType {
+	case ProviderGithub:
+		return NewGithubWriter(writeBackClient, authToken)
+	case ProviderJira:
+		return NewJiraWriter(writeBackClient, authToken)
+	}
+	return nil
+}
+
+// restClient sends JSON requests to a remote tracker
+type restClient struct {
+	client        *http.Client
+	authorization string
+}
+
+func (c restClient) do(ctx context.Context, method, url string, payload interface{}, result interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal the payload of %s %s", method, url)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create request %s %s", method, url)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "request %s %s failed", method, url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return InternalError{simpleError{message: fmt.Sprintf("request %s %s failed with status %s", method, url, resp.Status)}}
+	}
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return errors.Wrapf(err, "failed to decode the response of %s %s", method, url)
+		}
+	}
+	return nil
+}
+
+// GithubWriter writes local changes back to Github issues
+type GithubWriter struct {
+	rest restClient
+}
+
+// NewGithubWriter creates a Github writer that authenticates with the given
+// OAuth token
+func NewGithubWriter(client *http.Client, authToken string) *GithubWriter {
+	w := GithubWriter{rest: restClient{client: client}}
+	if authToken != "" {
+		w.rest.authorization = "token " + authToken
+	}
+	return &w
+}
+
+// UpdatedAt returns the last modification time of the Github issue
+func (w *GithubWriter) UpdatedAt(ctx context.Context, itemURL string) (time.Time, error) {
+	var issue struct {
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+	if err := w.rest.do(ctx, http.MethodGet, itemURL, nil, &issue); err != nil {
+		return time.Time{}, err
+	}
+	return issue.UpdatedAt, nil
+}
+
+// Update edits the Github issue
+func (w *GithubWriter) Update(ctx context.Context, itemURL string, changes RemoteChanges) error {
+	payload := map[string]interface{}{}
+	if changes.Title != nil {
+		payload["title"] = *changes.Title
+	}
+	if changes.State != nil {
+		payload["state"] = *changes.State
+	}
+	if changes.Assignees != nil {
+		payload["assignees"] = changes.Assignees
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+	return w.rest.do(ctx, http.MethodPatch, itemURL, payload, nil)
+}
+
+// AddComment adds a comment to the Github issue
+func (w *GithubWriter) AddComment(ctx context.Context, itemURL string, body string) error {
+	return w.rest.do(ctx, http.MethodPost, itemURL+"/comments", map[string]string{"body": body}, nil)
+}
+
+// JiraWriter writes local changes back to Jira issues
+type JiraWriter struct {
+	rest restClient
+}
+
+// NewJiraWriter creates a Jira writer that authenticates with the given
+// personal access token
+func NewJiraWriter(client *http.Client, authToken string) *JiraWriter {
+	w := JiraWriter{rest: restClient{client: client}}
+	if authToken != "" {
+		w.rest.authorization = "Bearer " + authToken
+	}
+	return &w
+}
+
+// UpdatedAt returns the last modification time of the Jira issue
+func (w *JiraWriter) UpdatedAt(ctx context.Context, itemURL string) (time.Time, error) {
+	var issue struct {
+		Fields struct {
+			Updated string `json:"updated"`
+		} `json:"fields"`
+	}
+	if err := w.rest.do(ctx, http.MethodGet, itemURL+"?fields=updated", nil, &issue); err != nil {
+		return time.Time{}, err
+	}
+	updatedAt, err := time.Parse(jiraTimeLayout, issue.Fields.Updated)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to parse the update time of Jira issue %s", itemURL)
+	}
+	return updatedAt, nil
+}
+
+// Update edits the Jira issue. Jira does not allow to set the status of an
+// issue directly, so a state change is done by executing the first available
+// transition that leads to the wanted status.
+func (w *JiraWriter) Update(ctx context.Context, itemURL string, changes RemoteChanges) error {
+	fields := map[string]interface{}{}
+	if changes.Title != nil {
+		fields["summary"] = *changes.Title
+	}
+	if changes.Assignees != nil {
+		// Jira issues have a single assignee
+		var assignee interface{}
+		if len(changes.Assignees) > 0 {
+			assignee = map[string]string{"name": changes.Assignees[0]}
+		}
+		fields["assignee"] = assignee
+	}
+	if len(fields) > 0 {
+		if err := w.rest.do(ctx, http.MethodPut, itemURL, map[string]interface{}{"fields": fields}, nil); err != nil {
+			return err
+		}
+	}
+	if changes.State == nil {
+		return nil
+	}
+	var transitions struct {
+		Transitions []struct {
+			ID string `json:"id"`
+			To struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := w.rest.do(ctx, http.MethodGet, itemURL+"/transitions", nil, &transitions); err != nil {
+		return err
+	}
+	for _, t := range transitions.Transitions {
+		if strings.EqualFold(t.To.Name, *changes.State) {
+			payload := map[string]interface{}{"transition": map[string]string{"id": t.ID}}
+			return w.rest.do(ctx, http.MethodPost, itemURL+"/transitions", payload, nil)
+		}
+	}
+	return BadParameterError{parameter: "state", value: *changes.State}
+}
+
+// AddComment adds a comment to the Jira issue
+func (w *JiraWriter) AddComment(ctx context.Context, itemURL string, body string) error {
+	return w.rest.do(ctx, http.MethodPost, itemURL+"/comment", map[string]string{"body": body}, nil)
+}
+
+// WriteBackResult lists the remote items that were handled by a write-back
+type WriteBackResult struct {
+	// Pushed are the remote items to which local changes were written
+	Pushed []string
+	// Conflicts are the remote items that were changed both locally and
+	// remotely since the last sync. Their local field changes are not written
+	// back and get overwritten by the next import. New local comments are
+	// written back nevertheless.
+	Conflicts []string
+	// Failed are the remote items for which the write-back failed
+	Failed []string
+}
+
+// pushedComment records a local comment that was written back to a remote
+// tracker, so that it is never posted twice
+type pushedComment struct {
+	CommentID uuid.UUID `sql:"type:uuid" gorm:"primary_key"`
+	TrackerID uuid.UUID `sql:"type:uuid"`
+	PushedAt  time.Time
+}
+
+// TableName implements gorm.tabler
+func (pushedComment) TableName() string {
+	return "tracker_pushed_comments"
+}
+
+// WriteBack pushes the local changes of the title, state, assignees and
+// comments of all work items that were imported from the given tracker into
+// the given space with the given field mapping to the remote tracker. The
+// remote tracker is called outside of any transaction and every comment is
+// recorded as pushed right after it was posted, so a failing write-back
+// doesn't post a comment again when it is retried.
+func WriteBack(ctx context.Context, db *gorm.DB, trackerID uuid.UUID, providerType string, spaceID uuid.UUID, mapping FieldMapping, w RemoteWriter) (*WriteBackResult, error) {
+	if w == nil {
+		return nil, BadParameterError{parameter: "providerType", value: providerType}
+	}
+	var trackerItems []TrackerItem
+	if err := db.Where("tracker_id = ? AND synced_version IS NOT NULL", trackerID).Find(&trackerItems).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to load the items of tracker %s", trackerID)
+	}
+	result := WriteBackResult{Pushed: []string{}, Conflicts: []string{}, Failed: []string{}}
+	for _, ti := range trackerItems {
+		itemURL, conflict, pushed, err := writeBackItem(ctx, db, ti, providerType, spaceID, mapping, w)
+		switch {
+		case err != nil:
+			log.Error(ctx, map[string]interface{}{
+				"err":            err,
+				"remote_item_id": ti.RemoteItemID,
+				"tracker_id":     trackerID,
+			}, "failed to write back local changes")
+			result.Failed = append(result.Failed, ti.RemoteItemID)
+		case conflict:
+			log.Warn(ctx, map[string]interface{}{
+				"remote_item_id": itemURL,
+				"tracker_id":     trackerID,
+			}, "remote item was changed both locally and remotely, local changes will be overwritten")
+			result.Conflicts = append(result.Conflicts, itemURL)
+		case pushed:
+			result.Pushed = append(result.Pushed, itemURL)
+		}
+	}
+	return &result, nil
+}
+
+// writeBackItem pushes the local changes of the work item imported from the
+// given tracker item and updates the sync markers of the tracker item. The
+// comments that were not pushed yet are pushed even if the remote item is in
+// conflict, as the next import would not bring them back either.
+func writeBackItem(ctx context.Context, db *gorm.DB, ti TrackerItem, providerType string, spaceID uuid.UUID, mapping FieldMapping, w RemoteWriter) (itemURL string, conflict bool, pushed bool, err error) {
+	remoteWorkItem, err := mapTrackerItem(ti, providerType, mapping)
+	if err != nil {
+		return "", false, false, err
+	}
+	itemURL, _ = remoteWorkItem.Fields[remoteItemID].(string)
+	rawState, err := remoteStateOf(ti, providerType, mapping)
+	if err != nil {
+		return itemURL, false, false, err
+	}
+	wir := workitem.NewWorkItemRepository(db)
+	wi, err := wir.Fetch(ctx, spaceID, criteria.Equals(criteria.Field(workitem.SystemRemoteItemID), criteria.Literal(itemURL)))
+	if err != nil {
+		return itemURL, false, false, errors.WithStack(err)
+	}
+	if wi == nil {
+		return itemURL, false, false, nil
+	}
+	changes := RemoteChanges{}
+	if ti.SyncedVersion == nil || wi.Version != *ti.SyncedVersion {
+		changes, err = localChanges(ctx, db, *wi, remoteWorkItem, rawState, providerType, mapping)
+		if err != nil {
+			return itemURL, false, false, err
+		}
+	}
+	var comments []comment.Comment
+	err = db.Where("parent_id = ? AND id NOT IN (SELECT comment_id FROM "+pushedComment{}.TableName()+")", wi.ID).
+		Order("created_at").
+		Find(&comments).Error
+	if err != nil {
+		return itemURL, false, false, errors.Wrapf(err, "failed to load the comments of work item %s", wi.ID)
+	}
+	if changes.IsEmpty() && len(comments) == 0 {
+		return itemURL, false, false, nil
+	}
+	remoteUpdatedAt, err := w.UpdatedAt(ctx, itemURL)
+	if err != nil {
+		return itemURL, false, false, err
+	}
+	conflict = ti.RemoteUpdatedAt != nil && remoteUpdatedAt.After(*ti.RemoteUpdatedAt)
+	if !conflict && !changes.IsEmpty() {
+		if err := w.Update(ctx, itemURL, changes); err != nil {
+			return itemURL, false, false, err
+		}
+	}
+	for _, c := range comments {
+		if err := w.AddComment(ctx, itemURL, c.Body); err != nil {
+			return itemURL, conflict, false, err
+		}
+		if err := db.Create(&pushedComment{CommentID: c.ID, TrackerID: ti.TrackerID, PushedAt: time.Now()}).Error; err != nil {
+			return itemURL, conflict, false, errors.Wrapf(err, "failed to record comment %s as pushed to %s", c.ID, itemURL)
+		}
+	}
+	if conflict {
+		// the next import overwrites the local changes and updates the
+		// sync markers
+		return itemURL, true, false, nil
+	}
+	remoteUpdatedAt, err = w.UpdatedAt(ctx, itemURL)
+	if err != nil {
+		return itemURL, false, false, err
+	}
+	now := time.Now()
+	ti.RemoteUpdatedAt = &remoteUpdatedAt
+	ti.SyncedVersion = &wi.Version
+	ti.SyncedAt = &now
+	if err := db.Save(&ti).Error; err != nil {
+		return itemURL, false, false, errors.Wrapf(err, "failed to update the sync markers of %s", itemURL)
+	}
+	return itemURL, false, true, nil
+}
+
+// localChanges compares the given work item with the content of the remote
+// item it was imported from. rawState is the state of the remote item before
+// the state mapping was applied.
+func localChanges(ctx context.Context, db *gorm.DB, wi workitem.WorkItem, remoteWorkItem RemoteWorkItem, rawState string, providerType string, mapping FieldMapping) (RemoteChanges, error) {
+	changes := RemoteChanges{}
+	title, _ := wi.Fields[workitem.SystemTitle].(string)
+	if rt, _ := remoteWorkItem.Fields[remoteTitle].(string); title != rt {
+		changes.Title = &title
+	}
+	wit, err := workitem.NewWorkItemTypeRepository(db).Load(ctx, wi.Type)
+	if err != nil {
+		return changes, errors.Wrapf(err, "failed to load work item type %s", wi.Type)
+	}
+	metaStates, err := wit.MetaStateMapping()
+	if err != nil {
+		// without a meta-state mapping no state counts as resolved
+		metaStates = workitem.MetaStateMapping{}
+	}
+	localState, _ := wi.Fields[workitem.SystemState].(string)
+	var stateUpToDate bool
+	if providerType == ProviderGithub {
+		stateUpToDate = strings.EqualFold(rawState, "closed") == metaStates.IsResolved(localState)
+	} else {
+		// several remote states can be mapped to the local state, any of
+		// them is up to date
+		stateUpToDate = strings.EqualFold(mapping.localState(rawState), localState)
+	}
+	if !stateUpToDate {
+		state := toRemoteState(providerType, localState, metaStates, mapping)
+		changes.State = &state
+	}
+	// only identities of the remote tracker can be assigned remotely
+	assignees := []string{}
+	identityRepository := account.NewIdentityRepository(db)
+	localAssignees, _ := wi.Fields[workitem.SystemAssignees].([]interface{})
+	for _, a := range localAssignees {
+		identityID, err := uuid.FromString(fmt.Sprint(a))
+		if err != nil {
+			return changes, errors.Wrapf(err, "failed to convert assignee id into a UUID: %v", a)
+		}
+		identity, err := identityRepository.Load(ctx, identityID)
+		if err != nil {
+			return changes, errors.Wrapf(err, "failed to load assignee %s", identityID)
+		}
+		if identity.ProviderType == providerType {
+			assignees = append(assignees, identity.Username)
+		}
+	}
+	remoteAssignees, _ := remoteWorkItem.Fields[RemoteAssigneeLogins].([]string)
+	if !equalStrings(assignees, remoteAssignees) {
+		changes.Assignees = assignees
+	}
+	return changes, nil
+}
+
+// toRemoteState converts a local state into the state of the remote tracker
+// by reversing the state mapping (see FieldMapping.RemoteState). Github issues
+// are either open or closed, depending on whether the local state is resolved
+// according to the given meta-state mapping of the work item type.
+func toRemoteState(providerType string, state string, metaStates workitem.MetaStateMapping, mapping FieldMapping) string {
+	if providerType == ProviderGithub {
+		if metaStates.IsResolved(state) {
+			return "closed"
+		}
+		return "open"
+	}
+	if remote, ok := mapping.RemoteState(state); ok {
+		return remote
+	}
+	return state
+}
+
+// remoteStateOf returns the state of the given tracker item as the remote
+// tracker names it, i.e. before the state mapping is applied.
+func remoteStateOf(ti TrackerItem, providerType string, mapping FieldMapping) (string, error) {
+	keyMap, err := mapping.RemoteWorkItemMap(providerType)
+	if err != nil {
+		return "", err
+	}
+	accessor, err := RemoteWorkItemImplRegistry[providerType](ti)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the content of %s", ti.RemoteItemID)
+	}
+	for from, to := range keyMap {
+		if to == remoteState {
+			s, _ := accessor.Get(from.Expression).(string)
+			return s, nil
+		}
+	}
+	return "", nil
+}
+
+// equalStrings returns true if both slices contain the same strings
+// regardless of their order.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string{}, a...)
+	b = append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// remoteUpdatedAt returns the last modification time found in the content of
+// the given tracker item or nil if it is unknown.
+func remoteUpdatedAt(ti TrackerItem, providerType string) (*time.Time, error) {
+	convertFunc, ok := RemoteWorkItemImplRegistry[providerType]
+	if !ok {
+		return nil, BadParameterError{parameter: providerType, value: providerType}
+	}
+	accessor, err := convertFunc(ti)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	key, layout := GithubUpdatedAt, time.RFC3339
+	if providerType == ProviderJira {
+		key, layout = JiraUpdatedAt, jiraTimeLayout
+	}
+	value, ok := accessor.Get(AttributeExpression(key)).(string)
+	if !ok {
+		return nil, nil
+	}
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the update time of remote item %s", ti.RemoteItemID)
+	}
+	return &t, nil
+}
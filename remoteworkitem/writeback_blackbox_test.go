@@ -0,0 +1,140 @@
+package remoteworkitem_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/space"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type WriteBackSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestWriteBack(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &WriteBackSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *WriteBackSuite) TestWriteBack() {
+	// given a fake Github issue that was imported as a work item
+	updatedAt := "2018-01-01T10:00:00Z"
+	var requests []string
+	patches := []map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintf(w, `{"updated_at":"%s"}`, updatedAt)
+		case http.MethodPatch:
+			b, _ := ioutil.ReadAll(r.Body)
+			patch := map[string]interface{}{}
+			json.Unmarshal(b, &patch)
+			patches = append(patches, patch)
+		}
+	}))
+	defer server.Close()
+	issueURL := server.URL + "/repos/o/r/issues/1"
+
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Trackers(1), tf.Identities(1))
+	trackerID := fxt.Trackers[0].ID
+	content := fmt.Sprintf(`{"title":"original title","url":"%s","state":"open","body":"desc","updated_at":"%s","user":{"login":"jdoe","url":"https://api.github.com/users/jdoe"}}`, issueURL, updatedAt)
+	wi, err := remoteworkitem.Import(s.Ctx, s.DB, trackerID, remoteworkitem.TrackerItemContent{ID: fmt.Sprintf("%q", issueURL), Content: []byte(content)}, remoteworkitem.ProviderGithub, space.SystemSpace, remoteworkitem.FieldMapping{})
+	require.NoError(s.T(), err)
+	writer := remoteworkitem.NewGithubWriter(server.Client(), "")
+	wiRepo := workitem.NewWorkItemRepository(s.DB)
+
+	s.T().Run("nothing to write back", func(t *testing.T) {
+		// when
+		res, err := remoteworkitem.WriteBack(s.Ctx, s.DB, trackerID, remoteworkitem.ProviderGithub, space.SystemSpace, remoteworkitem.FieldMapping{}, writer)
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, res.Pushed)
+		assert.Empty(t, res.Conflicts)
+		assert.Empty(t, res.Failed)
+		assert.Empty(t, requests)
+	})
+
+	s.T().Run("local changes are written back", func(t *testing.T) {
+		// given
+		wi.Fields[workitem.SystemTitle] = "local title"
+		wi, _, err = wiRepo.Save(s.Ctx, space.SystemSpace, *wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		err = comment.NewRepository(s.DB).Create(s.Ctx, &comment.Comment{ParentID: wi.ID, Body: "local comment", Markup: rendering.SystemMarkupMarkdown}, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		requests = nil
+		// when
+		res, err := remoteworkitem.WriteBack(s.Ctx, s.DB, trackerID, remoteworkitem.ProviderGithub, space.SystemSpace, remoteworkitem.FieldMapping{}, writer)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{issueURL}, res.Pushed)
+		assert.Empty(t, res.Conflicts)
+		assert.Equal(t, []string{
+			"GET /repos/o/r/issues/1",
+			"PATCH /repos/o/r/issues/1",
+			"POST /repos/o/r/issues/1/comments",
+			"GET /repos/o/r/issues/1",
+		}, requests)
+		require.Len(t, patches, 1)
+		assert.Equal(t, map[string]interface{}{"title": "local title"}, patches[0])
+	})
+
+	s.T().Run("conflicting changes are not written back", func(t *testing.T) {
+		// given the work item was changed locally and remotely
+		wi.Fields[workitem.SystemTitle] = "another local title"
+		wi, _, err = wiRepo.Save(s.Ctx, space.SystemSpace, *wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		updatedAt = "2018-01-02T10:00:00Z"
+		requests = nil
+		// when
+		res, err := remoteworkitem.WriteBack(s.Ctx, s.DB, trackerID, remoteworkitem.ProviderGithub, space.SystemSpace, remoteworkitem.FieldMapping{}, writer)
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, res.Pushed)
+		assert.Equal(t, []string{issueURL}, res.Conflicts)
+		assert.Equal(t, []string{"GET /repos/o/r/issues/1"}, requests)
+		assert.Len(t, patches, 1)
+	})
+
+	s.T().Run("comments are written back despite a conflict", func(t *testing.T) {
+		// given a new local comment on the conflicting work item
+		err = comment.NewRepository(s.DB).Create(s.Ctx, &comment.Comment{ParentID: wi.ID, Body: "another local comment", Markup: rendering.SystemMarkupMarkdown}, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		requests = nil
+		// when
+		res, err := remoteworkitem.WriteBack(s.Ctx, s.DB, trackerID, remoteworkitem.ProviderGithub, space.SystemSpace, remoteworkitem.FieldMapping{}, writer)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{issueURL}, res.Conflicts)
+		assert.Equal(t, []string{
+			"GET /repos/o/r/issues/1",
+			"POST /repos/o/r/issues/1/comments",
+		}, requests)
+		assert.Len(t, patches, 1)
+	})
+
+	s.T().Run("pushed comments are not posted again", func(t *testing.T) {
+		// given
+		requests = nil
+		// when
+		res, err := remoteworkitem.WriteBack(s.Ctx, s.DB, trackerID, remoteworkitem.ProviderGithub, space.SystemSpace, remoteworkitem.FieldMapping{}, writer)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{issueURL}, res.Conflicts)
+		assert.Equal(t, []string{"GET /repos/o/r/issues/1"}, requests)
+	})
+}
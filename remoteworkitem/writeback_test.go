@@ -0,0 +1,151 @@
+package remoteworkitem
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTracker records the requests it receives and replies with the response
+// registered for the method and path of the request. Unknown items are not
+// found.
+type fakeTracker struct {
+	bodies    map[string]map[string]interface{}
+	responses map[string]string
+	auth      string
+}
+
+func newFakeTracker(responses map[string]string) (*fakeTracker, *httptest.Server) {
+	f := &fakeTracker{bodies: map[string]map[string]interface{}{}, responses: responses}
+	return f, httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		f.auth = r.Header.Get("Authorization")
+		if b, _ := ioutil.ReadAll(r.Body); len(b) > 0 {
+			body := map[string]interface{}{}
+			json.Unmarshal(b, &body)
+			f.bodies[key] = body
+		}
+		if res, ok := f.responses[key]; ok {
+			w.Write([]byte(res))
+			return
+		}
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+func TestGithubWriter(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	// given
+	f, server := newFakeTracker(map[string]string{
+		"GET /repos/o/r/issues/1": `{"updated_at":"2018-01-02T10:00:00Z"}`,
+	})
+	defer server.Close()
+	w := NewGithubWriter(server.Client(), "secret")
+	itemURL := server.URL + "/repos/o/r/issues/1"
+
+	t.Run("updated at", func(t *testing.T) {
+		// when
+		updatedAt, err := w.UpdatedAt(context.Background(), itemURL)
+		// then
+		require.NoError(t, err)
+		assert.True(t, time.Date(2018, time.January, 2, 10, 0, 0, 0, time.UTC).Equal(updatedAt))
+		assert.Equal(t, "token secret", f.auth)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		// given
+		title, state := "new title", "closed"
+		// when
+		err := w.Update(context.Background(), itemURL, RemoteChanges{Title: &title, State: &state, Assignees: []string{}})
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"title": "new title", "state": "closed", "assignees": []interface{}{}}, f.bodies["PATCH /repos/o/r/issues/1"])
+	})
+
+	t.Run("add comment", func(t *testing.T) {
+		// when
+		err := w.AddComment(context.Background(), itemURL, "hello")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"body": "hello"}, f.bodies["POST /repos/o/r/issues/1/comments"])
+	})
+
+	t.Run("remote error", func(t *testing.T) {
+		// when
+		_, err := w.UpdatedAt(context.Background(), server.URL+"/repos/o/r/issues/2")
+		// then
+		require.Error(t, err)
+	})
+}
+
+func TestJiraWriter(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	// given
+	f, server := newFakeTracker(map[string]string{
+		"GET /rest/api/2/issue/1":             `{"fields":{"updated":"2018-01-02T10:00:00.000+0000"}}`,
+		"GET /rest/api/2/issue/1/transitions": `{"transitions":[{"id":"11","to":{"name":"In Progress"}},{"id":"21","to":{"name":"Closed"}}]}`,
+	})
+	defer server.Close()
+	w := NewJiraWriter(server.Client(), "secret")
+	itemURL := server.URL + "/rest/api/2/issue/1"
+
+	t.Run("updated at", func(t *testing.T) {
+		// when
+		updatedAt, err := w.UpdatedAt(context.Background(), itemURL)
+		// then
+		require.NoError(t, err)
+		assert.True(t, time.Date(2018, time.January, 2, 10, 0, 0, 0, time.UTC).Equal(updatedAt))
+		assert.Equal(t, "Bearer secret", f.auth)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		// given
+		title, state := "new title", "closed"
+		// when
+		err := w.Update(context.Background(), itemURL, RemoteChanges{Title: &title, State: &state, Assignees: []string{"jdoe"}})
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"fields": map[string]interface{}{
+			"summary":  "new title",
+			"assignee": map[string]interface{}{"name": "jdoe"},
+		}}, f.bodies["PUT /rest/api/2/issue/1"])
+		assert.Equal(t, map[string]interface{}{"transition": map[string]interface{}{"id": "21"}}, f.bodies["POST /rest/api/2/issue/1/transitions"])
+	})
+
+	t.Run("update to unknown state", func(t *testing.T) {
+		// given
+		state := "unknown"
+		// when
+		err := w.Update(context.Background(), itemURL, RemoteChanges{State: &state})
+		// then
+		require.Error(t, err)
+		assert.IsType(t, BadParameterError{}, err)
+	})
+
+	t.Run("add comment", func(t *testing.T) {
+		// when
+		err := w.AddComment(context.Background(), itemURL, "hello")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"body": "hello"}, f.bodies["POST /rest/api/2/issue/1/comment"])
+	})
+}
+
+func TestLookupWriter(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	require.NotNil(t, lookupWriter(trackerSchedule{TrackerType: ProviderGithub}, ""))
+	require.NotNil(t, lookupWriter(trackerSchedule{TrackerType: ProviderJira}, ""))
+	require.Nil(t, lookupWriter(trackerSchedule{TrackerType: "unknown"}, ""))
+}
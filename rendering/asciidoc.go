@@ -0,0 +1,290 @@
+package rendering
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	asciiDocHeadingPattern     = regexp.MustCompile(`^(={1,6})\s+(.+?)(?:\s+=+)?$`)
+	asciiDocListPattern        = regexp.MustCompile(`^(\*+|-|\.+)\s+(.*)$`)
+	asciiDocAttributesPattern  = regexp.MustCompile(`^\[([^\]]*)\]$`)
+	asciiDocAttrEntryPattern   = regexp.MustCompile(`^:[\w-]+!?:`)
+	asciiDocTitlePattern       = regexp.MustCompile(`^\.([^.\s].*)$`)
+	asciiDocAdmonitionPattern  = regexp.MustCompile(`^(NOTE|TIP|IMPORTANT|WARNING|CAUTION):\s+(.*)$`)
+	asciiDocPassthroughPattern = regexp.MustCompile(`\+([^+\s](?:[^+]*[^+\s])?)\+`)
+	asciiDocMonospacePattern   = regexp.MustCompile("`([^`]+)`")
+	asciiDocImagePattern       = regexp.MustCompile(`image::?([^\s\[]+)\[([^\]]*)\]`)
+	asciiDocMailtoPattern      = regexp.MustCompile(`(?:link:)?(mailto:[^\s\[\]<>"]+)\[([^\]]*)\]`)
+	asciiDocURLPattern         = regexp.MustCompile(`(?:link:)?((?:https?|ftp)://[^\s\[\]<>"]*[^\s\[\]<>".,;:!?)'])(?:\[([^\]]*)\])?`)
+)
+
+// asciiDocSpans are the delimiters of the inline formatting in AsciiDoc. The
+// unconstrained delimiters come first since they contain the constrained ones.
+var asciiDocSpans = []struct {
+	delim       string
+	element     string
+	constrained bool
+}{
+	{"**", "strong", false},
+	{"*", "strong", true},
+	{"__", "em", false},
+	{"_", "em", true},
+	{"##", "mark", false},
+	{"#", "mark", true},
+	{"^", "sup", false},
+	{"~", "sub", false},
+}
+
+// RenderAsciiDocToHTML converts the given content written in AsciiDoc to HTML.
+// Only the commonly used subset of AsciiDoc is supported and macros and
+// includes are ignored. The HTML is not sanitized.
+func RenderAsciiDocToHTML(content string) string {
+	content = strings.Replace(content, "\x00", "", -1)
+	lines := strings.Split(strings.Replace(content, "\r\n", "\n", -1), "\n")
+	var out bytes.Buffer
+	renderAsciiDocBlocks(&out, lines)
+	return out.String()
+}
+
+// asciiDocDelimiter returns the character of the given line if it delimits a
+// block or 0
+func asciiDocDelimiter(line string) byte {
+	if len(line) < 4 || !strings.ContainsRune("-._=*/", rune(line[0])) {
+		return 0
+	}
+	if strings.Trim(line, line[:1]) != "" {
+		return 0
+	}
+	return line[0]
+}
+
+// renderAsciiDocBlocks writes the HTML of the given lines
+func renderAsciiDocBlocks(out *bytes.Buffer, lines []string) {
+	var paragraph []string
+	var list []listItem
+	// attributes holds the attribute list of the next block, e.g. "source,go"
+	attributes := ""
+	// admonition is the label of the paragraph, e.g. "NOTE"
+	admonition := ""
+	flush := func() {
+		if len(paragraph) > 0 {
+			text := renderAsciiDocInline(strings.Join(paragraph, "\n"))
+			// a line ending with " +" is followed by a line break
+			text = strings.Replace(strings.TrimSuffix(text, " +"), " +\n", "<br />\n", -1)
+			if admonition != "" {
+				text = "<strong>" + strings.Title(strings.ToLower(admonition)) + ":</strong> " + text
+			}
+			out.WriteString("<p>" + text + "</p>\n")
+			paragraph = nil
+			admonition = ""
+		}
+		if len(list) > 0 {
+			renderList(out, list)
+			list = nil
+		}
+	}
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		if delimiter := asciiDocDelimiter(line); delimiter != 0 {
+			flush()
+			var block []string
+			for i++; i < len(lines) && strings.TrimRight(lines[i], " \t") != line; i++ {
+				block = append(block, lines[i])
+			}
+			switch delimiter {
+			case '-':
+				language := ""
+				if parts := strings.Split(attributes, ","); len(parts) > 1 && strings.TrimSpace(parts[0]) == "source" {
+					language = strings.TrimSpace(parts[1])
+				}
+				out.WriteString(codeBlock(block, language))
+			case '.':
+				out.WriteString(codeBlock(block, ""))
+			case '_':
+				out.WriteString("<blockquote>\n")
+				renderAsciiDocBlocks(out, block)
+				out.WriteString("</blockquote>\n")
+			case '=', '*':
+				out.WriteString("<div>\n")
+				renderAsciiDocBlocks(out, block)
+				out.WriteString("</div>\n")
+			}
+			// "////" delimits a comment
+			attributes = ""
+			continue
+		}
+		if line == "|===" {
+			flush()
+			var table []string
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "|==="; i++ {
+				table = append(table, lines[i])
+			}
+			renderAsciiDocTable(out, table)
+			attributes = ""
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		startsBlock := len(paragraph) == 0 && len(list) == 0
+		keepAttributes := trimmed == ""
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "//"):
+			// a comment
+		case startsBlock && asciiDocAttrEntryPattern.MatchString(trimmed):
+			// document attributes are not supported
+		case startsBlock && asciiDocAttributesPattern.MatchString(trimmed):
+			attributes = asciiDocAttributesPattern.FindStringSubmatch(trimmed)[1]
+			keepAttributes = true
+		case startsBlock && asciiDocTitlePattern.MatchString(trimmed):
+			keepAttributes = true
+			out.WriteString("<p><strong>" + renderAsciiDocInline(asciiDocTitlePattern.FindStringSubmatch(trimmed)[1]) + "</strong></p>\n")
+		case asciiDocHeadingPattern.MatchString(line):
+			flush()
+			m := asciiDocHeadingPattern.FindStringSubmatch(line)
+			level := strconv.Itoa(len(m[1]))
+			out.WriteString("<h" + level + ">" + renderAsciiDocInline(m[2]) + "</h" + level + ">\n")
+		case trimmed == "'''":
+			flush()
+			out.WriteString("<hr />\n")
+		case asciiDocListPattern.MatchString(trimmed):
+			if len(list) == 0 {
+				flush()
+			}
+			m := asciiDocListPattern.FindStringSubmatch(trimmed)
+			kind := "ul"
+			if m[1][0] == '.' {
+				kind = "ol"
+			}
+			kinds := make([]string, len(m[1]))
+			for j := range kinds {
+				kinds[j] = kind
+			}
+			list = append(list, listItem{kinds: kinds, html: renderAsciiDocInline(m[2])})
+		case len(list) > 0:
+			// a line following a list item continues the item and a "+"
+			// attaches the next line
+			if trimmed != "+" {
+				list[len(list)-1].html += " " + renderAsciiDocInline(trimmed)
+			}
+		case startsBlock && line != trimmed:
+			// an indented paragraph is a literal
+			var literal []string
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+				literal = append(literal, strings.TrimRight(lines[i], " \t"))
+			}
+			out.WriteString(codeBlock(unindent(literal), ""))
+		default:
+			if startsBlock {
+				if m := asciiDocAdmonitionPattern.FindStringSubmatch(trimmed); m != nil {
+					admonition, trimmed = m[1], m[2]
+				} else if asciiDocAdmonitionPattern.MatchString(attributes + ": ") {
+					admonition = attributes
+				}
+			}
+			paragraph = append(paragraph, trimmed)
+		}
+		if !keepAttributes {
+			attributes = ""
+		}
+	}
+	flush()
+}
+
+// unindent removes the indentation common to the given lines
+func unindent(lines []string) []string {
+	indent := -1
+	for _, line := range lines {
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	res := make([]string, len(lines))
+	for i, line := range lines {
+		res[i] = line[indent:]
+	}
+	return res
+}
+
+// renderAsciiDocTable writes the HTML of the table with the given lines. The
+// number of columns is taken from the first line and the first line is the
+// header if it is followed by an empty line.
+func renderAsciiDocTable(out *bytes.Buffer, lines []string) {
+	var cells []string
+	cols := 0
+	hasHeader := false
+	for j, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "|") {
+			// the text continues the previous cell
+			if len(cells) > 0 {
+				cells[len(cells)-1] += " " + line
+			}
+			continue
+		}
+		lineCells := strings.Split(line[1:], "|")
+		if cols == 0 {
+			cols = len(lineCells)
+			hasHeader = j+1 < len(lines) && strings.TrimSpace(lines[j+1]) == ""
+		}
+		for _, cell := range lineCells {
+			cells = append(cells, strings.TrimSpace(cell))
+		}
+	}
+	if cols == 0 {
+		return
+	}
+	var rows [][]string
+	var header [][]bool
+	for j := 0; j < len(cells); j += cols {
+		end := j + cols
+		if end > len(cells) {
+			end = len(cells)
+		}
+		row := make([]string, end-j)
+		headerCells := make([]bool, end-j)
+		for k := range row {
+			row[k] = renderAsciiDocInline(cells[j+k])
+			headerCells[k] = hasHeader && j == 0
+		}
+		rows = append(rows, row)
+		header = append(header, headerCells)
+	}
+	renderTable(out, rows, header)
+}
+
+// renderAsciiDocInline returns the HTML of the given text with the inline
+// formatting of AsciiDoc
+func renderAsciiDocInline(s string) string {
+	var p placeholders
+	s = asciiDocMonospacePattern.ReplaceAllStringFunc(s, func(m string) string {
+		return p.add("<code>" + escapeText(asciiDocMonospacePattern.FindStringSubmatch(m)[1]) + "</code>")
+	})
+	s = asciiDocImagePattern.ReplaceAllStringFunc(s, func(m string) string {
+		sm := asciiDocImagePattern.FindStringSubmatch(m)
+		alt := strings.TrimSpace(strings.Split(sm[2], ",")[0])
+		return p.add(`<img src="` + escapeText(sm[1]) + `" alt="` + escapeText(alt) + `" />`)
+	})
+	for _, pattern := range []*regexp.Regexp{asciiDocMailtoPattern, asciiDocURLPattern} {
+		pattern := pattern
+		s = pattern.ReplaceAllStringFunc(s, func(m string) string {
+			sm := pattern.FindStringSubmatch(m)
+			return p.add(link(escapeText(sm[1]), escapeText(sm[2])))
+		})
+	}
+	s = asciiDocPassthroughPattern.ReplaceAllStringFunc(s, func(m string) string {
+		return p.add(escapeText(asciiDocPassthroughPattern.FindStringSubmatch(m)[1]))
+	})
+	s = escapeText(s)
+	for _, span := range asciiDocSpans {
+		s = formatSpans(s, span.delim, span.element, span.constrained)
+	}
+	return p.restore(s)
+}
@@ -0,0 +1,235 @@
+package rendering
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+var (
+	jiraHeadingPattern   = regexp.MustCompile(`^h([1-6])\.\s+(.*)$`)
+	jiraListPattern      = regexp.MustCompile(`^([*#]+|-)\s+(.*)$`)
+	jiraCodeStartPattern = regexp.MustCompile(`^\{(code|noformat)(?::([^}]*))?\}(.*)$`)
+	jiraMonospacePattern = regexp.MustCompile(`\{\{(.+?)\}\}`)
+	jiraLinkPattern      = regexp.MustCompile(`\[(?:([^\[\]|]*)\|)?([^\[\]|]+)\]`)
+	jiraImagePattern     = regexp.MustCompile(`!([a-z]+://[^\s!|]+)(?:\|[^!]*)?!`)
+	jiraColorPattern     = regexp.MustCompile(`\{color(?::[^}]*)?\}`)
+	jiraURLPattern       = regexp.MustCompile(`^(https?|ftp)://|^mailto:`)
+)
+
+// jiraSpans are the delimiters of the inline formatting in JiraWiki.
+// Superscripts and subscripts can be part of a word, e.g. "m^2^".
+var jiraSpans = []struct {
+	delim       string
+	element     string
+	constrained bool
+}{
+	{"*", "strong", true},
+	{"_", "em", true},
+	{"??", "cite", true},
+	{"-", "del", true},
+	{"+", "ins", true},
+	{"^", "sup", false},
+	{"~", "sub", false},
+}
+
+// RenderJiraWikiToHTML converts the given content written in the JIRA wiki
+// markup to HTML. The HTML is not sanitized.
+func RenderJiraWikiToHTML(content string) string {
+	content = strings.Replace(content, "\x00", "", -1)
+	lines := strings.Split(strings.Replace(content, "\r\n", "\n", -1), "\n")
+	var out bytes.Buffer
+	renderJiraWikiBlocks(&out, lines)
+	return out.String()
+}
+
+// renderJiraWikiBlocks writes the HTML of the given lines
+func renderJiraWikiBlocks(out *bytes.Buffer, lines []string) {
+	var paragraph []string
+	var list []listItem
+	var rows [][]string
+	var header [][]bool
+	flush := func() {
+		if len(paragraph) > 0 {
+			// line breaks within paragraphs are kept in the JIRA wiki markup
+			text := renderJiraWikiInline(strings.Join(paragraph, "\n"))
+			out.WriteString("<p>" + strings.Replace(text, "\n", "<br />\n", -1) + "</p>\n")
+			paragraph = nil
+		}
+		if len(list) > 0 {
+			renderList(out, list)
+			list = nil
+		}
+		if len(rows) > 0 {
+			renderTable(out, rows, header)
+			rows, header = nil, nil
+		}
+	}
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if m := jiraCodeStartPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			language := ""
+			if m[1] == "code" && m[2] != "" {
+				// the parameters are either the language or "key=value" pairs
+				language = strings.TrimSpace(strings.Split(m[2], "|")[0])
+				if strings.Contains(language, "=") {
+					language = ""
+				}
+			}
+			var code []string
+			i = collectUntil(lines, i, m[3], "{"+m[1]+"}", &code)
+			out.WriteString(codeBlock(code, language))
+			continue
+		}
+		if strings.HasPrefix(line, "{quote}") {
+			flush()
+			var quote []string
+			i = collectUntil(lines, i, strings.TrimPrefix(line, "{quote}"), "{quote}", &quote)
+			out.WriteString("<blockquote>\n")
+			renderJiraWikiBlocks(out, quote)
+			out.WriteString("</blockquote>\n")
+			continue
+		}
+		switch m := jiraHeadingPattern.FindStringSubmatch(line); {
+		case line == "":
+			flush()
+		case m != nil:
+			flush()
+			out.WriteString("<h" + m[1] + ">" + renderJiraWikiInline(m[2]) + "</h" + m[1] + ">\n")
+		case strings.HasPrefix(line, "bq. "):
+			flush()
+			out.WriteString("<blockquote><p>" + renderJiraWikiInline(strings.TrimPrefix(line, "bq. ")) + "</p></blockquote>\n")
+		case line == "----":
+			flush()
+			out.WriteString("<hr />\n")
+		case jiraListPattern.MatchString(line):
+			if len(list) == 0 {
+				flush()
+			}
+			m := jiraListPattern.FindStringSubmatch(line)
+			kinds := make([]string, len(m[1]))
+			for j, marker := range m[1] {
+				kinds[j] = "ul"
+				if marker == '#' {
+					kinds[j] = "ol"
+				}
+			}
+			list = append(list, listItem{kinds: kinds, html: renderJiraWikiInline(m[2])})
+		case strings.HasPrefix(line, "|"):
+			if len(rows) == 0 {
+				flush()
+			}
+			cells, headerCells := splitJiraWikiRow(line)
+			rows = append(rows, cells)
+			header = append(header, headerCells)
+		case len(list) > 0:
+			// a line following a list item continues the item
+			list[len(list)-1].html += "<br />" + renderJiraWikiInline(line)
+		default:
+			if len(rows) > 0 {
+				flush()
+			}
+			paragraph = append(paragraph, line)
+		}
+	}
+	flush()
+}
+
+// collectUntil collects the lines of a block, starting with the given rest of
+// its first line, until the line holding the given end marker and returns the
+// index of that line. The block extends to the end of the content if the end
+// marker is missing.
+func collectUntil(lines []string, start int, rest string, end string, block *[]string) int {
+	if i := strings.Index(rest, end); i >= 0 {
+		*block = append(*block, rest[:i])
+		return start
+	}
+	if strings.TrimSpace(rest) != "" {
+		*block = append(*block, rest)
+	}
+	for i := start + 1; i < len(lines); i++ {
+		if j := strings.Index(lines[i], end); j >= 0 {
+			if strings.TrimSpace(lines[i][:j]) != "" {
+				*block = append(*block, lines[i][:j])
+			}
+			return i
+		}
+		*block = append(*block, lines[i])
+	}
+	return len(lines)
+}
+
+// splitJiraWikiRow returns the HTML of the cells of the given table row and
+// whether they are header cells. Cells start with "||" in header rows and with
+// "|" otherwise. Separators within links are ignored.
+func splitJiraWikiRow(line string) ([]string, []bool) {
+	var cells []string
+	var headerCells []bool
+	depth := 0
+	start := -1
+	isHeader := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '[':
+			depth++
+			continue
+		case ']':
+			if depth > 0 {
+				depth--
+			}
+			continue
+		case '|':
+			if depth > 0 {
+				continue
+			}
+		default:
+			continue
+		}
+		if start >= 0 {
+			cells = append(cells, renderJiraWikiInline(strings.TrimSpace(line[start:i])))
+			headerCells = append(headerCells, isHeader)
+		}
+		isHeader = i+1 < len(line) && line[i+1] == '|'
+		if isHeader {
+			i++
+		}
+		start = i + 1
+	}
+	if start >= 0 && start < len(line) && strings.TrimSpace(line[start:]) != "" {
+		cells = append(cells, renderJiraWikiInline(strings.TrimSpace(line[start:])))
+		headerCells = append(headerCells, isHeader)
+	}
+	return cells, headerCells
+}
+
+// renderJiraWikiInline returns the HTML of the given text with the inline
+// formatting of the JIRA wiki markup
+func renderJiraWikiInline(s string) string {
+	var p placeholders
+	s = escapeText(s)
+	s = jiraMonospacePattern.ReplaceAllStringFunc(s, func(m string) string {
+		return p.add("<code>" + jiraMonospacePattern.FindStringSubmatch(m)[1] + "</code>")
+	})
+	s = jiraImagePattern.ReplaceAllStringFunc(s, func(m string) string {
+		return p.add(`<img src="` + jiraImagePattern.FindStringSubmatch(m)[1] + `" alt="" />`)
+	})
+	s = jiraLinkPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sm := jiraLinkPattern.FindStringSubmatch(m)
+		text, target := strings.TrimSpace(sm[1]), strings.TrimSpace(sm[2])
+		switch {
+		case strings.HasPrefix(target, "~"):
+			// a mention of a user
+			return p.add("@" + strings.TrimPrefix(target, "~"))
+		case jiraURLPattern.MatchString(target):
+			return p.add(link(target, text))
+		}
+		return m
+	})
+	s = jiraColorPattern.ReplaceAllString(s, "")
+	s = strings.Replace(s, `\\`, "<br />", -1)
+	for _, span := range jiraSpans {
+		s = formatSpans(s, span.delim, span.element, span.constrained)
+	}
+	return p.restore(s)
+}
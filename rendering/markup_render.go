@@ -3,38 +3,80 @@ package rendering
 import (
 	"html"
 	"regexp"
+	"sort"
 
 	"github.com/microcosm-cc/bluemonday"
 )
 
+// RenderFunc converts content written in a markup language to HTML. The HTML
+// does not need to be safe since it is sanitized by RenderMarkupToHTML.
+type RenderFunc func(content string) string
+
+// renderers holds the renderers of the supported markups except PlainText,
+// which is only escaped
+var renderers = map[string]RenderFunc{}
+
+// RegisterRenderer adds support for the given markup, whose content is
+// rendered with the given function. It must be called during the
+// initialization of the program since the renderers are not guarded against
+// concurrent access.
+func RegisterRenderer(markup string, render RenderFunc) {
+	renderers[markup] = render
+}
+
+func init() {
+	RegisterRenderer(SystemMarkupMarkdown, func(content string) string {
+		return string(MarkdownCommonHighlighter([]byte(content)))
+	})
+	RegisterRenderer(SystemMarkupJiraWiki, RenderJiraWikiToHTML)
+	RegisterRenderer(SystemMarkupAsciiDoc, RenderAsciiDocToHTML)
+}
+
+// sanitizer is the policy applied to the HTML of all renderers
+var sanitizer = newSanitizer()
+
+func newSanitizer() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile("^language-[a-zA-Z0-9]+$|prettyprint")).OnElements("code")
+	p.AllowAttrs("class").OnElements("span")
+	p.AllowElements("input")
+	p.AllowAttrs("type").OnElements("input")
+	p.AllowAttrs("checked").OnElements("input")
+	p.AllowAttrs("disabled").OnElements("input")
+	p.AllowAttrs("data-checkbox-index").OnElements("input")
+	p.AllowAttrs("class").OnElements("input")
+	return p
+}
+
 // IsMarkupSupported indicates if the given markup is supported
 func IsMarkupSupported(markup string) bool {
-	if markup == SystemMarkupDefault || markup == SystemMarkupMarkdown {
+	if markup == SystemMarkupPlainText {
 		return true
 	}
-	return false
+	_, ok := renderers[markup]
+	return ok
+}
+
+// SupportedMarkups returns the names of the supported markups in alphabetical
+// order
+func SupportedMarkups() []string {
+	markups := []string{SystemMarkupPlainText}
+	for markup := range renderers {
+		markups = append(markups, markup)
+	}
+	sort.Strings(markups)
+	return markups
 }
 
-// RenderMarkupToHTML converts the given `content` in HTML using the markup tool corresponding to the given `markup` argument
-// or return nil if no tool for the given `markup` is available, or returns an `error` if the command was not found or failed.
+// RenderMarkupToHTML converts the given `content` in HTML using the renderer registered for the given `markup` argument
+// and sanitizes the result, or returns an empty string if no renderer is registered for the given `markup`.
 func RenderMarkupToHTML(content, markup string) string {
-	switch markup {
-	case SystemMarkupPlainText:
+	if markup == SystemMarkupPlainText {
 		return html.EscapeString(content)
-	case SystemMarkupMarkdown:
-		unsafe := MarkdownCommonHighlighter([]byte(content))
-		p := bluemonday.UGCPolicy()
-		p.AllowAttrs("class").Matching(regexp.MustCompile("^language-[a-zA-Z0-9]+$|prettyprint")).OnElements("code")
-		p.AllowAttrs("class").OnElements("span")
-		p.AllowElements("input")
-		p.AllowAttrs("type").OnElements("input")
-		p.AllowAttrs("checked").OnElements("input")
-		p.AllowAttrs("disabled").OnElements("input")
-		p.AllowAttrs("data-checkbox-index").OnElements("input")
-		p.AllowAttrs("class").OnElements("input")
-		html := string(p.SanitizeBytes(unsafe))
-		return html
-	default:
+	}
+	render, ok := renderers[markup]
+	if !ok {
 		return ""
 	}
+	return sanitizer.Sanitize(render(content))
 }
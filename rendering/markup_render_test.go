@@ -71,6 +71,98 @@ func TestIsMarkupSupported(t *testing.T) {
 	assert.True(t, rendering.IsMarkupSupported(rendering.SystemMarkupDefault))
 	assert.True(t, rendering.IsMarkupSupported(rendering.SystemMarkupPlainText))
 	assert.True(t, rendering.IsMarkupSupported(rendering.SystemMarkupMarkdown))
+	assert.True(t, rendering.IsMarkupSupported(rendering.SystemMarkupJiraWiki))
+	assert.True(t, rendering.IsMarkupSupported(rendering.SystemMarkupAsciiDoc))
 	assert.False(t, rendering.IsMarkupSupported(""))
 	assert.False(t, rendering.IsMarkupSupported("foo"))
 }
+
+func TestSupportedMarkups(t *testing.T) {
+	assert.Equal(t, []string{
+		rendering.SystemMarkupAsciiDoc,
+		rendering.SystemMarkupJiraWiki,
+		rendering.SystemMarkupMarkdown,
+		rendering.SystemMarkupPlainText,
+	}, rendering.SupportedMarkups())
+}
+
+func TestRenderJiraWikiContent(t *testing.T) {
+	render := func(content string) string {
+		return rendering.RenderMarkupToHTML(content, rendering.SystemMarkupJiraWiki)
+	}
+	t.Run("headings", func(t *testing.T) {
+		assert.Equal(t, "<h1>Title</h1>\n<h3>Sub <strong>title</strong></h3>\n", render("h1. Title\nh3. Sub *title*"))
+	})
+	t.Run("inline formatting", func(t *testing.T) {
+		result := render("*bold* _italic_ -deleted- +inserted+ {{some_code}} m^2^ H~2~O snake_case_name")
+		assert.Equal(t, "<p><strong>bold</strong> <em>italic</em> <del>deleted</del> <ins>inserted</ins> <code>some_code</code> m<sup>2</sup> H<sub>2</sub>O snake_case_name</p>\n", result)
+	})
+	t.Run("links", func(t *testing.T) {
+		result := render("[Example|https://example.com] [https://example.com/foo] [~jdoe] [not a link]")
+		assert.Contains(t, result, `<a href="https://example.com" rel="nofollow">Example</a>`)
+		assert.Contains(t, result, `<a href="https://example.com/foo" rel="nofollow">https://example.com/foo</a>`)
+		assert.Contains(t, result, "@jdoe")
+		assert.Contains(t, result, "[not a link]")
+	})
+	t.Run("lists", func(t *testing.T) {
+		result := render("* one\n** one.one\n* two\n\n# first\n# second")
+		assert.Equal(t, "<ul>\n<li>one<ul>\n<li>one.one</li></ul>\n</li>\n<li>two</li></ul>\n<ol>\n<li>first</li>\n<li>second</li></ol>\n", result)
+	})
+	t.Run("code", func(t *testing.T) {
+		result := render("{code:java}\nif (a < b) {}\n{code}\n{noformat}\n*raw*\n{noformat}")
+		assert.Equal(t, "<pre><code class=\"language-java\">if (a &lt; b) {}</code></pre>\n<pre><code>*raw*</code></pre>\n", result)
+	})
+	t.Run("table", func(t *testing.T) {
+		result := render("||name||link||\n|foo|[bar|https://example.com]|")
+		assert.Contains(t, result, "<tr><th>name</th><th>link</th></tr>")
+		assert.Contains(t, result, `<tr><td>foo</td><td><a href="https://example.com" rel="nofollow">bar</a></td></tr>`)
+	})
+	t.Run("quotes", func(t *testing.T) {
+		assert.Equal(t, "<blockquote><p>quoted</p></blockquote>\n", render("bq. quoted"))
+		assert.Equal(t, "<blockquote>\n<p>quoted</p>\n</blockquote>\n", render("{quote}\nquoted\n{quote}"))
+	})
+	t.Run("sanitized", func(t *testing.T) {
+		result := render("<script>alert('foo')</script> [click|javascript:alert(1)]")
+		assert.NotContains(t, result, "<script>")
+		assert.NotContains(t, result, "javascript:alert(1)\"")
+	})
+}
+
+func TestRenderAsciiDocContent(t *testing.T) {
+	render := func(content string) string {
+		return rendering.RenderMarkupToHTML(content, rendering.SystemMarkupAsciiDoc)
+	}
+	t.Run("headings", func(t *testing.T) {
+		assert.Equal(t, "<h1>Title</h1>\n<h2>Section</h2>\n", render("= Title\n\n== Section"))
+	})
+	t.Run("inline formatting", func(t *testing.T) {
+		result := render("*bold* _italic_ **un**constrained `some_code` +*literal*+ E=mc^2^ H~2~O snake_case_name")
+		assert.Equal(t, "<p><strong>bold</strong> <em>italic</em> <strong>un</strong>constrained <code>some_code</code> *literal* E=mc<sup>2</sup> H<sub>2</sub>O snake_case_name</p>\n", result)
+	})
+	t.Run("links", func(t *testing.T) {
+		result := render("https://example.com[Example] and https://example.com/foo.")
+		assert.Contains(t, result, `<a href="https://example.com" rel="nofollow">Example</a>`)
+		assert.Contains(t, result, `<a href="https://example.com/foo" rel="nofollow">https://example.com/foo</a>.`)
+	})
+	t.Run("lists", func(t *testing.T) {
+		result := render("* one\n** one.one\n* two\n\n. first\n. second")
+		assert.Equal(t, "<ul>\n<li>one<ul>\n<li>one.one</li></ul>\n</li>\n<li>two</li></ul>\n<ol>\n<li>first</li>\n<li>second</li></ol>\n", result)
+	})
+	t.Run("code", func(t *testing.T) {
+		result := render("[source,go]\n----\nif a < b {}\n----\n\n....\n*raw*\n....")
+		assert.Equal(t, "<pre><code class=\"language-go\">if a &lt; b {}</code></pre>\n<pre><code>*raw*</code></pre>\n", result)
+	})
+	t.Run("table", func(t *testing.T) {
+		result := render("|===\n|name |value\n\n|foo |bar\n|===")
+		assert.Contains(t, result, "<tr><th>name</th><th>value</th></tr>")
+		assert.Contains(t, result, "<tr><td>foo</td><td>bar</td></tr>")
+	})
+	t.Run("admonition", func(t *testing.T) {
+		assert.Equal(t, "<p><strong>Note:</strong> careful</p>\n", render("NOTE: careful"))
+	})
+	t.Run("sanitized", func(t *testing.T) {
+		result := render("<script>alert('foo')</script> link:javascript:alert(1)[click]")
+		assert.NotContains(t, result, "<script>")
+		assert.NotContains(t, result, "href=\"javascript")
+	})
+}
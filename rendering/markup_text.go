@@ -0,0 +1,184 @@
+package rendering
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// This file holds the helpers shared by the JiraWiki and AsciiDoc renderers.
+
+// placeholders keeps the HTML of already rendered inline elements, e.g. code
+// spans and links, out of the way of the subsequent inline formatting.
+type placeholders []string
+
+var placeholderPattern = regexp.MustCompile("\x00([0-9]+)\x00")
+
+// add returns the placeholder of the given HTML
+func (p *placeholders) add(html string) string {
+	*p = append(*p, html)
+	return fmt.Sprintf("\x00%d\x00", len(*p)-1)
+}
+
+// restore replaces the placeholders in the given text with their HTML. The
+// HTML can hold placeholders itself, e.g. a link with a code span as text.
+func (p placeholders) restore(s string) string {
+	for i := 0; i <= len(p) && placeholderPattern.MatchString(s); i++ {
+		s = placeholderPattern.ReplaceAllStringFunc(s, func(m string) string {
+			n, err := strconv.Atoi(m[1 : len(m)-1])
+			if err != nil || n >= len(p) {
+				return ""
+			}
+			return p[n]
+		})
+	}
+	return s
+}
+
+// escapeText escapes the special characters of HTML in text. Unlike
+// html.EscapeString it does not produce "#" which is a delimiter of the inline
+// formatting in AsciiDoc.
+var escapeText = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;").Replace
+
+// isWordByte returns true if the given byte belongs to a word
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// isSpaceByte returns true if the given byte is a white space
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// formatSpans wraps the text between pairs of the given delimiter in the given
+// HTML element. A constrained span must start at the beginning and end at the
+// end of a word, so that e.g. the underscores of snake_case are left alone.
+func formatSpans(s, delim, element string, constrained bool) string {
+	var out bytes.Buffer
+	for {
+		start := openingDelimiter(s, delim, constrained)
+		if start < 0 {
+			break
+		}
+		end := closingDelimiter(s, delim, start+len(delim), constrained)
+		if end < 0 {
+			break
+		}
+		out.WriteString(s[:start])
+		out.WriteString("<" + element + ">")
+		out.WriteString(s[start+len(delim) : end])
+		out.WriteString("</" + element + ">")
+		s = s[end+len(delim):]
+	}
+	out.WriteString(s)
+	return out.String()
+}
+
+// openingDelimiter returns the position of the first delimiter in s that can
+// open a span or -1
+func openingDelimiter(s, delim string, constrained bool) int {
+	for from := 0; from < len(s); {
+		i := strings.Index(s[from:], delim)
+		if i < 0 {
+			return -1
+		}
+		i += from
+		next := i + len(delim)
+		if next < len(s) && !isSpaceByte(s[next]) && !strings.HasPrefix(s[next:], delim[:1]) &&
+			(!constrained || i == 0 || !isWordByte(s[i-1])) {
+			return i
+		}
+		from = i + 1
+	}
+	return -1
+}
+
+// closingDelimiter returns the position of the first delimiter in s after the
+// given position that can close a span or -1
+func closingDelimiter(s, delim string, from int, constrained bool) int {
+	for i := from; i < len(s); {
+		j := strings.Index(s[i:], delim)
+		if j < 0 {
+			return -1
+		}
+		j += i
+		next := j + len(delim)
+		if j > from && !isSpaceByte(s[j-1]) && (!constrained || next == len(s) || !isWordByte(s[next])) {
+			return j
+		}
+		i = j + 1
+	}
+	return -1
+}
+
+// link returns the HTML of a link to the given (escaped) URL
+func link(url, text string) string {
+	if text == "" {
+		text = url
+	}
+	return fmt.Sprintf(`<a href="%s">%s</a>`, url, text)
+}
+
+// codeBlock returns the HTML of a block of preformatted text in the given
+// language, which may be empty
+func codeBlock(lines []string, language string) string {
+	code := escapeText(strings.Join(lines, "\n"))
+	if language == "" {
+		return "<pre><code>" + code + "</code></pre>\n"
+	}
+	return fmt.Sprintf("<pre><code class=\"language-%s\">%s</code></pre>\n", escapeText(language), code)
+}
+
+// listItem is an item of a possibly nested list. The kinds hold the list
+// element, either "ul" or "ol", of each level down to the item.
+type listItem struct {
+	kinds []string
+	html  string
+}
+
+// renderList writes the HTML of the given list items
+func renderList(out *bytes.Buffer, items []listItem) {
+	var open []string
+	for _, item := range items {
+		common := 0
+		for common < len(open) && common < len(item.kinds) && open[common] == item.kinds[common] {
+			common++
+		}
+		for len(open) > common {
+			out.WriteString("</li></" + open[len(open)-1] + ">\n")
+			open = open[:len(open)-1]
+		}
+		if len(open) > 0 && len(open) == len(item.kinds) {
+			out.WriteString("</li>\n")
+		}
+		for len(open) < len(item.kinds) {
+			out.WriteString("<" + item.kinds[len(open)] + ">\n")
+			open = append(open, item.kinds[len(open)])
+		}
+		out.WriteString("<li>" + item.html)
+	}
+	for len(open) > 0 {
+		out.WriteString("</li></" + open[len(open)-1] + ">\n")
+		open = open[:len(open)-1]
+	}
+}
+
+// renderTable writes the HTML of a table whose cells already are HTML. The
+// header cells are marked in the given flags.
+func renderTable(out *bytes.Buffer, rows [][]string, header [][]bool) {
+	out.WriteString("<table>\n")
+	for i, row := range rows {
+		out.WriteString("<tr>")
+		for j, cell := range row {
+			element := "td"
+			if header[i][j] {
+				element = "th"
+			}
+			out.WriteString("<" + element + ">" + cell + "</" + element + ">")
+		}
+		out.WriteString("</tr>\n")
+	}
+	out.WriteString("</table>\n")
+}
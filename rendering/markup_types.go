@@ -9,4 +9,6 @@ const (
 	SystemMarkupMarkdown = "Markdown"
 	// SystemMarkupJiraWiki JIRA Wiki
 	SystemMarkupJiraWiki = "JiraWiki"
+	// SystemMarkupAsciiDoc AsciiDoc
+	SystemMarkupAsciiDoc = "AsciiDoc"
 )
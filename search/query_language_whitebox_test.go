@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"runtime/debug"
 	"testing"
+	"time"
 
 	c "github.com/fabric8-services/fabric8-wit/criteria"
 	"github.com/fabric8-services/fabric8-wit/resource"
@@ -316,6 +317,61 @@ func TestParseMap(t *testing.T) {
 		assert.Equal(t, expectedQuery, actualQuery)
 	})
 
+	t.Run(GTE, func(t *testing.T) {
+		t.Parallel()
+		// given
+		input := fmt.Sprintf(`{"updated_at": { "%s": "2018-01-01T00:00:00Z"}}`, GTE)
+		// Parsing/Unmarshalling JSON encoding/json
+		fm := map[string]interface{}{}
+		err := json.Unmarshal([]byte(input), &fm)
+		require.NoError(t, err)
+		// when
+		actualQuery := Query{}
+		parseMap(fm, &actualQuery)
+		// then
+		updated := "2018-01-01T00:00:00Z"
+		expectedQuery := Query{Name: "updated_at", Value: &updated, Comparison: GTE}
+		assert.Equal(t, expectedQuery, actualQuery)
+	})
+
+	t.Run(LT+" with number", func(t *testing.T) {
+		t.Parallel()
+		// given
+		input := fmt.Sprintf(`{"number": { "%s": 42}}`, LT)
+		// Parsing/Unmarshalling JSON encoding/json
+		fm := map[string]interface{}{}
+		err := json.Unmarshal([]byte(input), &fm)
+		require.NoError(t, err)
+		// when
+		actualQuery := Query{}
+		parseMap(fm, &actualQuery)
+		// then
+		number := "42"
+		expectedQuery := Query{Name: "number", Value: &number, Comparison: LT}
+		assert.Equal(t, expectedQuery, actualQuery)
+	})
+
+	t.Run(BETWEEN, func(t *testing.T) {
+		t.Parallel()
+		// given
+		input := fmt.Sprintf(`{"created_at": { "%s": ["2018-01-01", "2018-02-01"]}}`, BETWEEN)
+		// Parsing/Unmarshalling JSON encoding/json
+		fm := map[string]interface{}{}
+		err := json.Unmarshal([]byte(input), &fm)
+		require.NoError(t, err)
+		// when
+		actualQuery := Query{}
+		parseMap(fm, &actualQuery)
+		// then
+		lower := "2018-01-01"
+		upper := "2018-02-01"
+		expectedQuery := Query{Name: AND, Children: []Query{
+			{Name: "created_at", Value: &lower, Comparison: GTE},
+			{Name: "created_at", Value: &upper, Comparison: LTE}},
+		}
+		assert.Equal(t, expectedQuery, actualQuery)
+	})
+
 	t.Run(OPTS, func(t *testing.T) {
 		t.Parallel()
 		// given
@@ -574,6 +630,160 @@ func TestGenerateExpression(t *testing.T) {
 
 }
 
+func TestGenerateComparisonExpression(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Parallel()
+	t.Run(GT+" (top-level)", func(t *testing.T) {
+		t.Parallel()
+		// given
+		updated := "2018-01-01T00:00:00Z"
+		q := Query{Name: "updated_at", Value: &updated, Comparison: GT}
+		// when
+		actualExpr, err := q.generateExpression()
+		// then
+		require.NoError(t, err)
+		expectedExpr := c.GreaterThan(
+			c.Field(workitem.SystemUpdatedAt),
+			c.Literal(time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		)
+		expectEqualExpr(t, expectedExpr, actualExpr)
+	})
+	t.Run(BETWEEN, func(t *testing.T) {
+		t.Parallel()
+		// given
+		actualExpr, _, err := ParseFilterString(context.Background(), `{"created_at": {"$BETWEEN": ["2018-01-01", "2018-02-01"]}}`)
+		// then
+		require.NoError(t, err)
+		expectedExpr := c.And(
+			c.GreaterThanOrEqual(
+				c.Field(workitem.SystemCreatedAt),
+				c.Literal(time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)),
+			),
+			c.LessThanOrEqual(
+				c.Field(workitem.SystemCreatedAt),
+				c.Literal(time.Date(2018, time.February, 1, 0, 0, 0, 0, time.UTC)),
+			),
+		)
+		expectEqualExpr(t, expectedExpr, actualExpr)
+	})
+	t.Run(LTE+" with number", func(t *testing.T) {
+		t.Parallel()
+		// given
+		number := "42"
+		q := Query{Name: "number", Value: &number, Comparison: LTE}
+		// when
+		actualExpr, err := q.generateExpression()
+		// then
+		require.NoError(t, err)
+		expectedExpr := c.LessThanOrEqual(c.Field("Number"), c.Literal(float64(42)))
+		expectEqualExpr(t, expectedExpr, actualExpr)
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		t.Parallel()
+		// given
+		value := "yesterday"
+		q := Query{Name: "updated_at", Value: &value, Comparison: LT}
+		// when
+		actualExpr, err := q.generateExpression()
+		// then
+		require.Error(t, err)
+		require.Nil(t, actualExpr)
+	})
+	t.Run(BETWEEN+" without bounds", func(t *testing.T) {
+		t.Parallel()
+		// when
+		actualExpr, _, err := ParseFilterString(context.Background(), `{"created_at": {"$BETWEEN": "2018-01-01"}}`)
+		// then
+		require.Error(t, err)
+		require.Nil(t, actualExpr)
+	})
+}
+
+func TestGenerateExpressionWithLookup(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Parallel()
+	lookup := func(key string) (workitem.FieldType, error) {
+		switch key {
+		case "effort":
+			return workitem.SimpleType{Kind: workitem.KindFloat}, nil
+		case "components":
+			return workitem.ListType{
+				SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+				ComponentType: workitem.SimpleType{Kind: workitem.KindString},
+			}, nil
+		}
+		return nil, nil
+	}
+	t.Run("float field", func(t *testing.T) {
+		t.Parallel()
+		// given
+		effort := "2.5"
+		q := Query{Name: "effort", Value: &effort}
+		// when
+		actualExpr, err := q.generateExpressionWithLookup(lookup)
+		// then
+		require.NoError(t, err)
+		clause, params, _, compileErrs := workitem.Compile(actualExpr)
+		require.Empty(t, compileErrs)
+		require.Equal(t, `("work_items"."fields" @> '{"effort" : 2.5}')`, clause)
+		require.Empty(t, params)
+	})
+	t.Run("list field", func(t *testing.T) {
+		t.Parallel()
+		// given
+		component := "ui"
+		q := Query{Name: "components", Value: &component}
+		// when
+		actualExpr, err := q.generateExpressionWithLookup(lookup)
+		// then
+		require.NoError(t, err)
+		clause, _, _, compileErrs := workitem.Compile(actualExpr)
+		require.Empty(t, compileErrs)
+		require.Equal(t, `("work_items"."fields" @> '{"components" : ["ui"]}')`, clause)
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		t.Parallel()
+		// given
+		effort := "a lot"
+		q := Query{Name: "effort", Value: &effort}
+		// when
+		actualExpr, err := q.generateExpressionWithLookup(lookup)
+		// then
+		require.Error(t, err)
+		require.Nil(t, actualExpr)
+	})
+	t.Run("unknown key", func(t *testing.T) {
+		t.Parallel()
+		// given
+		value := "foo"
+		q := Query{Name: "unknown", Value: &value}
+		// when
+		actualExpr, err := q.generateExpressionWithLookup(lookup)
+		// then
+		require.Error(t, err)
+		require.Nil(t, actualExpr)
+	})
+}
+
+func TestQuerySpaceIDs(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Parallel()
+	// given
+	space1, space2, space3, effort := "a", "b", "c", "2.5"
+	q := Query{Name: AND, Children: []Query{
+		{Name: "space", Value: &space1},
+		{Name: "effort", Value: &effort},
+		{Name: OR, Children: []Query{
+			{Name: "space", Value: &space2},
+			{Name: "space", Value: &space3, Negate: true},
+		}},
+	}}
+	// when
+	ids := q.spaceIDs()
+	// then
+	require.Equal(t, []string{space1, space2}, ids)
+}
+
 func expectEqualExpr(t *testing.T, expectedExpr, actualExpr c.Expression) {
 	require.NotNil(t, expectedExpr)
 	require.NotNil(t, actualExpr)
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
 
@@ -19,6 +21,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/criteria"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	"github.com/jinzhu/gorm"
@@ -40,11 +43,19 @@ const (
 	SUBSTR = "$SUBSTR"
 	OPTS   = "$OPTS"
 
+	// Comparison operators for numeric and instant fields.
+	GT      = "$GT"
+	GTE     = "$GTE"
+	LT      = "$LT"
+	LTE     = "$LTE"
+	BETWEEN = "$BETWEEN"
+
 	// This is the replacement for $WITGROUP.
 	TypeGroupName = "typegroup.name"
 
 	OptParentExistsKey = "parent-exists"
 	OptTreeViewKey     = "tree-view"
+	OptAsOfKey         = "as-of"
 )
 
 // GormSearchRepository provides a Gorm based repository
@@ -326,6 +337,30 @@ func parseMap(queryMap map[string]interface{}, q *Query) {
 				s := v.(string)
 				q.Value = &s
 				q.Substring = true
+			} else if v, ok := concreteVal[BETWEEN]; ok {
+				// {"key": {"$BETWEEN": [lower, upper]}} is the same as
+				// {"$AND": [{"key": {"$GTE": lower}}, {"key": {"$LTE": upper}}]}
+				if bounds, ok := v.([]interface{}); ok && len(bounds) == 2 {
+					q.Name = AND
+					for i, op := range []string{GTE, LTE} {
+						s := comparisonValue(bounds[i])
+						q.Children = append(q.Children, Query{Name: key, Value: &s, Comparison: op})
+					}
+				} else {
+					// reported as a bad parameter when generating the expression
+					s := fmt.Sprintf("%v", v)
+					q.Value = &s
+					q.Comparison = BETWEEN
+				}
+			} else {
+				for _, op := range []string{GT, GTE, LT, LTE} {
+					if v, ok := concreteVal[op]; ok {
+						s := comparisonValue(v)
+						q.Value = &s
+						q.Comparison = op
+						break
+					}
+				}
 			}
 		default:
 			log.Error(nil, nil, "Unexpected value: %#v", val)
@@ -333,6 +368,15 @@ func parseMap(queryMap map[string]interface{}, q *Query) {
 	}
 }
 
+// comparisonValue converts the given JSON value of a comparison operator to
+// a string. Numbers are given as float64 by the JSON decoder.
+func comparisonValue(v interface{}) string {
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
 func parseOptions(queryMap map[string]interface{}) *QueryOptions {
 	for key, val := range queryMap {
 		if ifArr, ok := val.(map[string]interface{}); key == OPTS && ok {
@@ -351,6 +395,25 @@ func parseOptions(queryMap map[string]interface{}) *QueryOptions {
 	return nil
 }
 
+// parseAsOf returns the time of the "as-of" option in the given query map or
+// nil if the option is not set.
+func parseAsOf(queryMap map[string]interface{}) (*time.Time, error) {
+	opts, ok := queryMap[OPTS].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	v, ok := opts[OptAsOfKey]
+	if !ok {
+		return nil, nil
+	}
+	s, _ := v.(string)
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil, errors.NewBadParameterError(OPTS+"."+OptAsOfKey, v).Expected("RFC 3339 timestamp")
+	}
+	return &t, nil
+}
+
 func parseArray(anArray []interface{}, l *[]Query) {
 	for _, val := range anArray {
 		if o, ok := val.(map[string]interface{}); ok {
@@ -365,6 +428,9 @@ func parseArray(anArray []interface{}, l *[]Query) {
 type QueryOptions struct {
 	TreeView     bool
 	ParentExists bool
+	// AsOf is the time to evaluate the query at or nil for the current
+	// state of the work items.
+	AsOf *time.Time
 }
 
 // Query represents tree structure of the filter query
@@ -386,6 +452,9 @@ type Query struct {
 	// If Substring is true, instead of exact match, anything that matches partially
 	// will be considered.
 	Substring bool
+	// Comparison holds one of the operators "$GT", "$GTE", "$LT" or "$LTE"
+	// if the Value is not checked for equality but compared with the field.
+	Comparison string
 	// A Query is expected to have child queries only if the Name field contains
 	// an operator like "$AND", or "$OR". If the Name is not an operator, the
 	// Children slice MUST be empty.
@@ -413,105 +482,205 @@ var searchKeyMap = map[string]string{
 	"workitemtype": "Type", // same as 'type' - added for compatibility. (Ref. #1564)
 	"space":        "SpaceID",
 	"number":       "Number",
+	"created_at":   workitem.SystemCreatedAt,
+	"updated_at":   workitem.SystemUpdatedAt,
+	"created":      workitem.SystemCreatedAt, // same as 'created_at' - shorter for text queries
+	"updated":      workitem.SystemUpdatedAt, // same as 'updated_at' - shorter for text queries
+	"blocked":      "Blocked",
 }
 
 func (q Query) determineLiteralType(key string, val string) criteria.Expression {
 	switch key {
 	case workitem.SystemAssignees, workitem.SystemLabels, workitem.SystemBoardcolumns, workitem.SystemBoard:
 		return criteria.Literal([]string{val})
+	case "Blocked":
+		if b, err := strconv.ParseBool(val); err == nil {
+			return criteria.Literal(b)
+		}
+		return criteria.Literal(val)
 	default:
 		return criteria.Literal(val)
 	}
 }
 
+// parseComparisonValue parses the given value of a comparison which must be either a
+// number or a time in RFC3339 or "2006-01-02" format.
+func parseComparisonValue(value string) (interface{}, bool) {
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f, true
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// comparisonExpression returns the expression that compares the given field
+// with the given value using the given comparison operator. The value must be
+// either a number or a time in RFC3339 or "2006-01-02" format.
+func comparisonExpression(left criteria.Expression, key, comparison, value string) (criteria.Expression, error) {
+	v, ok := parseComparisonValue(value)
+	if !ok {
+		return nil, errors.NewBadParameterError(key, value).Expected("number or time in RFC3339 format")
+	}
+	right := criteria.Literal(v)
+	switch comparison {
+	case GT:
+		return criteria.GreaterThan(left, right), nil
+	case GTE:
+		return criteria.GreaterThanOrEqual(left, right), nil
+	case LT:
+		return criteria.LessThan(left, right), nil
+	case LTE:
+		return criteria.LessThanOrEqual(left, right), nil
+	default:
+		return nil, errors.NewBadParameterError(comparison, value).Expected(BETWEEN + " with [lower, upper] bounds")
+	}
+}
+
+// fieldLookup returns the type of the work item type field with the given key
+// or nil if no work item type has such a field.
+type fieldLookup func(key string) (workitem.FieldType, error)
+
+// resolveKey returns the name of the field to which the given search key
+// refers. Search keys that are neither in the searchKeyMap nor handled by a
+// table join are looked up as work item type fields with the given lookup
+// function (if any), in which case the type of the field is returned too.
+func resolveKey(name string, lookup fieldLookup) (string, workitem.FieldType, error) {
+	// check that none of the default table joins handles this column:
+	joins := workitem.DefaultTableJoins()
+	for _, j := range joins {
+		if j.HandlesFieldName(name) {
+			return name, nil, nil
+		}
+	}
+	if key, ok := searchKeyMap[name]; ok {
+		return key, nil, nil
+	}
+	if lookup != nil && name != "" {
+		fieldType, err := lookup(name)
+		if err != nil {
+			return "", nil, errs.Wrapf(err, "failed to look up field %s", name)
+		}
+		if fieldType != nil {
+			return name, fieldType, nil
+		}
+	}
+	return "", nil, errors.NewBadParameterError("key not found", name)
+}
+
+// fieldLiteral converts the given search value to the representation of the
+// given field type in the jsonb "fields" column.
+func fieldLiteral(key string, fieldType workitem.FieldType, val string) (criteria.Expression, error) {
+	simpleKind := fieldType.GetKind()
+	switch t := fieldType.(type) {
+	case workitem.EnumType:
+		simpleKind = t.BaseType.GetKind()
+	case workitem.ListType:
+		// like for the assignees, the value must be contained in the list.
+		if _, err := fieldLiteral(key, t.ComponentType, val); err != nil {
+			return nil, err
+		}
+		return criteria.Literal([]string{val}), nil
+	}
+	var v interface{} = val
+	var err error
+	switch simpleKind {
+	case workitem.KindFloat:
+		v, err = strconv.ParseFloat(val, 64)
+	case workitem.KindInteger:
+		v, err = strconv.Atoi(val)
+	case workitem.KindBoolean:
+		v, err = strconv.ParseBool(val)
+	case workitem.KindInstant:
+		v, err = time.Parse(time.RFC3339Nano, val)
+		if err != nil {
+			v, err = time.Parse("2006-01-02", val)
+		}
+	}
+	if err == nil {
+		v, err = fieldType.ConvertToModel(v)
+	}
+	if err != nil {
+		return nil, errors.NewBadParameterError(key, val).Expected(fmt.Sprintf("value of kind %s", fieldType.GetKind()))
+	}
+	return criteria.Literal(v), nil
+}
+
+// fieldExpression returns the expression for a query that is no operator,
+// like {"state": "open"} or {"effort": {"$GT": "3"}}.
+func (q Query) fieldExpression(lookup fieldLookup) (criteria.Expression, error) {
+	key, fieldType, err := resolveKey(q.Name, lookup)
+	if err != nil {
+		return nil, err
+	}
+	left := criteria.Field(key)
+	if fieldType != nil {
+		left = workitem.JSONField(key)
+	}
+	if q.Value == nil {
+		if q.Negate {
+			return nil, errors.NewBadParameterError("negate for null not supported", q.Name)
+		}
+		if fieldType != nil {
+			return workitem.JSONIsNull(key), nil
+		}
+		return criteria.IsNull(key), nil
+	}
+	if q.Comparison != "" {
+		return comparisonExpression(left, q.Name, q.Comparison, *q.Value)
+	}
+	right := q.determineLiteralType(key, *q.Value)
+	if fieldType != nil && !q.Substring {
+		right, err = fieldLiteral(q.Name, fieldType, *q.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case q.Negate:
+		return criteria.Not(left, right), nil
+	case q.Substring:
+		return criteria.Substring(left, right), nil
+	case q.Child:
+		return criteria.Child(left, right), nil
+	default:
+		return criteria.Equals(left, right), nil
+	}
+}
+
 func (q Query) generateExpression() (criteria.Expression, error) {
+	return q.generateExpressionWithLookup(nil)
+}
+
+// generateExpressionWithLookup generates the expression for the query. Keys
+// that are no search keys are looked up as work item type fields using the
+// given lookup function.
+func (q Query) generateExpressionWithLookup(lookup fieldLookup) (criteria.Expression, error) {
 	var myexpr []criteria.Expression
 	currentOperator := q.Name
 
 	if !isOperator(currentOperator) || currentOperator == OPTS {
-		key, ok := searchKeyMap[q.Name]
-		// check that none of the default table joins handles this column:
-		var handledByJoin bool
-		joins := workitem.DefaultTableJoins()
-		for _, j := range joins {
-			if j.HandlesFieldName(q.Name) {
-				handledByJoin = true
-				key = q.Name
-				break
-			}
-		}
-		if !ok && !handledByJoin {
-			return nil, errors.NewBadParameterError("key not found", q.Name)
-		}
-		left := criteria.Field(key)
-		if q.Value != nil {
-			right := q.determineLiteralType(key, *q.Value)
-			if q.Negate {
-				myexpr = append(myexpr, criteria.Not(left, right))
-			} else {
-				if q.Substring {
-					myexpr = append(myexpr, criteria.Substring(left, right))
-				} else {
-					if q.Child {
-						myexpr = append(myexpr, criteria.Child(left, right))
-					} else {
-						myexpr = append(myexpr, criteria.Equals(left, right))
-					}
-				}
-			}
-		} else {
-			if q.Negate {
-				return nil, errors.NewBadParameterError("negate for null not supported", q.Name)
-			}
-			myexpr = append(myexpr, criteria.IsNull(key))
+		exp, err := q.fieldExpression(lookup)
+		if err != nil {
+			return nil, err
 		}
+		myexpr = append(myexpr, exp)
 	}
 	for _, child := range q.Children {
+		var exp criteria.Expression
+		var err error
 		if isOperator(child.Name) || currentOperator == OPTS {
-			exp, err := child.generateExpression()
-			if err != nil {
-				return nil, err
-			}
-			myexpr = append(myexpr, exp)
+			exp, err = child.generateExpressionWithLookup(lookup)
 		} else {
-			key, ok := searchKeyMap[child.Name]
-			// check that none of the default table joins handles this column:
-			var handledByJoin bool
-			joins := workitem.DefaultTableJoins()
-			for _, j := range joins {
-				if j.HandlesFieldName(child.Name) {
-					handledByJoin = true
-					key = child.Name
-					break
-				}
-			}
-			if !ok && !handledByJoin {
-				return nil, errors.NewBadParameterError("key not found", child.Name)
-			}
-			left := criteria.Field(key)
-			if child.Value != nil {
-				right := q.determineLiteralType(key, *child.Value)
-				if child.Negate {
-					myexpr = append(myexpr, criteria.Not(left, right))
-				} else {
-					if child.Substring {
-						myexpr = append(myexpr, criteria.Substring(left, right))
-					} else {
-						if child.Child {
-							myexpr = append(myexpr, criteria.Child(left, right))
-						} else {
-							myexpr = append(myexpr, criteria.Equals(left, right))
-						}
-					}
-				}
-			} else {
-				if child.Negate {
-					return nil, errors.NewBadParameterError("negate for null not supported", child.Name)
-				}
-				myexpr = append(myexpr, criteria.IsNull(key))
-
-			}
+			exp, err = child.fieldExpression(lookup)
+		}
+		if err != nil {
+			return nil, err
 		}
+		myexpr = append(myexpr, exp)
 	}
 	var res criteria.Expression
 	switch currentOperator {
@@ -541,8 +710,47 @@ func (q Query) generateExpression() (criteria.Expression, error) {
 	return res, nil
 }
 
-// ParseFilterString accepts a raw string and generates a criteria expression
+// ParseFilterString accepts a raw string and generates a criteria expression.
+// The raw string is either a JSON filter expression or a text query (see
+// ParseTextQuery).
 func ParseFilterString(ctx context.Context, rawSearchString string) (criteria.Expression, *QueryOptions, error) {
+	return parseFilterString(ctx, rawSearchString, nil)
+}
+
+// FilterSpaceIDs returns the IDs of the spaces that the given raw filter
+// string selects work items by. These spaces determine the work item type
+// fields that can be used in the filter (see GormSearchRepository.Filter).
+func FilterSpaceIDs(ctx context.Context, rawSearchString string) ([]string, error) {
+	var res []string
+	_, _, err := parseFilterString(ctx, rawSearchString, func(spaceIDs []string) fieldLookup {
+		res = spaceIDs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// parseFilterString accepts a raw string and generates a criteria expression.
+// Keys that are no search keys are looked up as work item type fields using
+// the lookup function that newLookup returns for the spaces selected by the
+// query.
+func parseFilterString(ctx context.Context, rawSearchString string, newLookup func(spaceIDs []string) fieldLookup) (criteria.Expression, *QueryOptions, error) {
+	lookupFor := func(q Query) fieldLookup {
+		if newLookup == nil {
+			return nil
+		}
+		return newLookup(q.spaceIDs())
+	}
+	if IsTextQuery(rawSearchString) {
+		q, err := ParseTextQuery(rawSearchString, textQueryEnvFromContext(ctx))
+		if err != nil {
+			return nil, nil, err
+		}
+		exp, err := q.generateExpressionWithLookup(lookupFor(*q))
+		return exp, nil, err
+	}
 	fm := map[string]interface{}{}
 	// Parsing/Unmarshalling JSON encoding/json
 	err := json.Unmarshal([]byte(rawSearchString), &fm)
@@ -558,11 +766,61 @@ func ParseFilterString(ctx context.Context, rawSearchString string) (criteria.Ex
 	parseMap(fm, &q)
 
 	q.Options = parseOptions(fm)
+	asOf, err := parseAsOf(fm)
+	if err != nil {
+		return nil, nil, err
+	}
+	if asOf != nil {
+		q.Options.AsOf = asOf
+	}
 
-	exp, err := q.generateExpression()
+	exp, err := q.generateExpressionWithLookup(lookupFor(q))
 	return exp, q.Options, err
 }
 
+// spaceIDs returns the values of all keys in the query that select work items
+// by their space.
+func (q Query) spaceIDs() []string {
+	var res []string
+	if q.Name == "space" && q.Value != nil && !q.Negate && !q.Substring && q.Comparison == "" {
+		res = append(res, *q.Value)
+	}
+	for _, child := range q.Children {
+		res = append(res, child.spaceIDs()...)
+	}
+	return res
+}
+
+// lookupField returns a function that returns the type of the work item type
+// field with the given key. Only the work item types of the space templates
+// of the given spaces are considered, so fields can only be used in queries
+// that select one or more spaces. If multiple of these work item types define
+// such a field, the type from the oldest work item type is used.
+func (r *GormSearchRepository) lookupField(ctx context.Context, spaceIDs []string) fieldLookup {
+	if len(spaceIDs) == 0 {
+		return nil
+	}
+	return func(key string) (workitem.FieldType, error) {
+		var wits []workitem.WorkItemType
+		db := r.db.Model(&workitem.WorkItemType{}).
+			Where("fields->CAST(? AS text) IS NOT NULL", key).
+			Where(fmt.Sprintf("space_template_id IN (SELECT space_template_id FROM %s WHERE id::text IN (?))", space.Space{}.TableName()), spaceIDs).
+			Order("created_at").Limit(1).Find(&wits)
+		if db.Error != nil {
+			log.Error(ctx, map[string]interface{}{
+				"err":       db.Error,
+				"field":     key,
+				"space_ids": spaceIDs,
+			}, "failed to find work item types with field")
+			return nil, errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to find work item types with field %s", key))
+		}
+		if len(wits) == 0 {
+			return nil, nil
+		}
+		return wits[0].Fields[key].Type, nil
+	}
+}
+
 // generateSQLSearchInfo accepts searchKeyword and join them in a way that can be used in sql
 func generateSQLSearchInfo(keywords searchKeyword) (sqlParameter string) {
 	numberStr := strings.Join(keywords.number, " & ")
@@ -700,7 +958,7 @@ func (r *GormSearchRepository) SearchFullText(ctx context.Context, rawSearchStri
 	return result, count, nil
 }
 
-func (r *GormSearchRepository) listItemsFromDB(ctx context.Context, criteria criteria.Expression, parentExists *bool, start *int, limit *int) ([]workitem.WorkItemStorage, int, error) {
+func (r *GormSearchRepository) listItemsFromDB(ctx context.Context, criteria criteria.Expression, parentExists *bool, asOf *time.Time, start *int, limit *int, sort workitem.SortWorkItemsBy) ([]workitem.WorkItemStorage, int, error) {
 	where, parameters, joins, compileError := workitem.Compile(criteria)
 	if compileError != nil {
 		log.Error(ctx, map[string]interface{}{
@@ -711,15 +969,25 @@ func (r *GormSearchRepository) listItemsFromDB(ctx context.Context, criteria cri
 	}
 
 	if parentExists != nil && !*parentExists {
+		linkExists := "wil.deleted_at IS NULL"
+		if asOf != nil {
+			linkExists = "wil.created_at <= ? AND (wil.deleted_at IS NULL OR wil.deleted_at > ?)"
+			parameters = append(parameters, *asOf, *asOf)
+		}
 		where += fmt.Sprintf(` AND
 			NOT EXISTS (
 				SELECT wil.target_id FROM work_item_links wil
 				WHERE wil.link_type_id = '%[1]s'
 				AND wil.target_id = work_items.id
-				AND wil.deleted_at IS NULL)`, link.SystemWorkItemLinkTypeParentChildID)
+				AND %[2]s)`, link.SystemWorkItemLinkTypeParentChildID, linkExists)
 	}
 
-	db := r.db.Model(&workitem.WorkItemStorage{}).Where(where, parameters...)
+	db := r.db.Model(&workitem.WorkItemStorage{})
+	if asOf != nil {
+		// deleted work items are handled by the table expression
+		db = r.db.Unscoped().Table(workitem.WorkItemsAsOf(*asOf))
+	}
+	db = db.Where(where, parameters...)
 	for _, j := range joins {
 		if err := j.Validate(db); err != nil {
 			log.Error(ctx, map[string]interface{}{"expression": criteria, "err": err}, "table join not valid")
@@ -741,7 +1009,10 @@ func (r *GormSearchRepository) listItemsFromDB(ctx context.Context, criteria cri
 		db = db.Limit(*limit)
 	}
 
-	db = db.Select("count(*) over () as cnt2 , *").Order(workitem.Column(workitem.WorkItemStorage{}.TableName(), "execution_order") + " desc")
+	if sort == "" {
+		sort = workitem.SortWorkItemsByDefault
+	}
+	db = db.Select("count(*) over () as cnt2 , *").Order(string(sort))
 
 	rows, err := db.Rows()
 	defer closeable.Close(ctx, rows)
@@ -808,12 +1079,18 @@ func (r *GormSearchRepository) listItemsFromDB(ctx context.Context, criteria cri
 // create a list of ancestors as well as a list of links. The ancestors exist in
 // order to list the parent of each matching work item up to its root work item.
 // The child links are there in order to know what siblings to load for matching
-// work items.
-func (r *GormSearchRepository) Filter(ctx context.Context, rawFilterString string, parentExists *bool, start *int, limit *int) (matches []workitem.WorkItem, count int, ancestors link.AncestorList, childLinks link.WorkItemLinkList, err error) {
+// work items. The matches are ordered by the given sort order or by the default
+// sort order if it is empty. If the filter specifies the "as-of" option, it is
+// evaluated against the work items as they were at that time (see
+// workitem.WorkItemsAsOf) and the ancestors and child links are the ones that
+// existed at that time.
+func (r *GormSearchRepository) Filter(ctx context.Context, rawFilterString string, parentExists *bool, start *int, limit *int, sort workitem.SortWorkItemsBy) (matches []workitem.WorkItem, count int, ancestors link.AncestorList, childLinks link.WorkItemLinkList, err error) {
 	// parse
 	// generateSearchQuery
 	// ....
-	exp, opts, err := ParseFilterString(ctx, rawFilterString)
+	exp, opts, err := parseFilterString(ctx, rawFilterString, func(spaceIDs []string) fieldLookup {
+		return r.lookupField(ctx, spaceIDs)
+	})
 	if err != nil {
 		return nil, 0, nil, nil, errs.Wrap(err, "failed to parse filter string")
 	}
@@ -830,7 +1107,11 @@ func (r *GormSearchRepository) Filter(ctx context.Context, rawFilterString strin
 		return nil, 0, nil, nil, errors.NewBadParameterError("rawFilterString", rawFilterString)
 	}
 
-	result, count, err := r.listItemsFromDB(ctx, exp, parentExists, start, limit)
+	var asOf *time.Time
+	if opts != nil {
+		asOf = opts.AsOf
+	}
+	result, count, err := r.listItemsFromDB(ctx, exp, parentExists, asOf, start, limit, sort)
 	if err != nil {
 		return nil, 0, nil, nil, errs.WithStack(err)
 	}
@@ -842,7 +1123,11 @@ func (r *GormSearchRepository) Filter(ctx context.Context, rawFilterString strin
 		for i, wi := range result {
 			matchingIDs[i] = wi.ID
 		}
-		ancestors, err = linkRepo.GetAncestors(ctx, link.SystemWorkItemLinkTypeParentChildID, link.AncestorLevelAll, matchingIDs...)
+		if asOf != nil {
+			ancestors, err = linkRepo.GetAncestorsAsOf(ctx, link.SystemWorkItemLinkTypeParentChildID, link.AncestorLevelAll, *asOf, matchingIDs...)
+		} else {
+			ancestors, err = linkRepo.GetAncestors(ctx, link.SystemWorkItemLinkTypeParentChildID, link.AncestorLevelAll, matchingIDs...)
+		}
 		if err != nil {
 			log.Error(ctx, map[string]interface{}{
 				"expression":  exp,
@@ -871,7 +1156,11 @@ func (r *GormSearchRepository) Filter(ctx context.Context, rawFilterString strin
 				}
 			}
 		}
-		childLinks, err = linkRepo.ListChildLinks(ctx, link.SystemWorkItemLinkTypeParentChildID, includeChildrenFor...)
+		if asOf != nil {
+			childLinks, err = linkRepo.ListChildLinksAsOf(ctx, link.SystemWorkItemLinkTypeParentChildID, *asOf, includeChildrenFor...)
+		} else {
+			childLinks, err = linkRepo.ListChildLinks(ctx, link.SystemWorkItemLinkTypeParentChildID, includeChildrenFor...)
+		}
 		if err != nil {
 			log.Error(ctx, map[string]interface{}{
 				"expression": exp,
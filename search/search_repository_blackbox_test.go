@@ -7,9 +7,11 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/fabric8-services/fabric8-common/id"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/rendering"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	"github.com/fabric8-services/fabric8-wit/search"
@@ -101,44 +103,44 @@ func (s *searchRepositoryBlackboxTest) TestFilterWithChildIterationWorkItems() {
 		)
 		t.Run("without child iteration", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"$AND": [{"iteration": "%s", "child": true}, {"space": "%s"}]}`, fxt.Iterations[2].ID, fxt.Spaces[0].ID)
-			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			require.NoError(t, err)
 			assert.Equal(t, 4, count)
 		})
 
 		t.Run("with one child iteration", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"iteration": "%s", "child": true}`, fxt.Iterations[1].ID)
-			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			require.NoError(t, err)
 			assert.Equal(t, 6, count)
 		})
 		t.Run("with two child iteration", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"iteration": "%s", "child": true}`, fxt.Iterations[0].ID)
-			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			require.NoError(t, err)
 			assert.Equal(t, 9, count)
 		})
 		t.Run("without child iteration - child false", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"iteration": "%s", "child": true}`, fxt.Iterations[2].ID)
-			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			require.NoError(t, err)
 			assert.Equal(t, 4, count)
 		})
 		t.Run("with one child iteration - child false", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"iteration": "%s", "child": false}`, fxt.Iterations[1].ID)
-			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			require.NoError(t, err)
 			assert.Equal(t, 2, count)
 		})
 		t.Run("with two child iteration - child false", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"iteration": "%s", "child": false}`, fxt.Iterations[0].ID)
-			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			require.NoError(t, err)
 			assert.Equal(t, 3, count)
 		})
 		t.Run("with two child iteration and space", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"$AND": [{"iteration": "%s", "child": true},{"space": "%s"}]}`, fxt.Iterations[0].ID, fxt.Spaces[0].ID)
-			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			require.NoError(t, err)
 			assert.Equal(t, 9, count)
 		})
@@ -165,7 +167,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterWithJoin() {
 		t.Run("iteration name", func(t *testing.T) {
 			// when
 			filter := fmt.Sprintf(`{"iteration.name": "%s"}`, fxt.Iterations[0].Name)
-			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			// then
 			require.NoError(t, err)
 			assert.Equal(t, 7, count)
@@ -188,7 +190,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterWithJoin() {
 		t.Run("iteration number", func(t *testing.T) {
 			// when
 			filter := fmt.Sprintf(`{"iteration.number": "%d"}`, fxt.Iterations[1].Number)
-			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			// then
 			require.NoError(t, err)
 			assert.Equal(t, 3, count)
@@ -207,7 +209,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterWithJoin() {
 		t.Run("area number", func(t *testing.T) {
 			// when
 			filter := fmt.Sprintf(`{"area.number": "%d"}`, fxt.Areas[1].Number)
-			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			// then
 			require.NoError(t, err)
 			assert.Equal(t, 3, count)
@@ -244,7 +246,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterWithJoin() {
 		t.Run("matching name", func(t *testing.T) {
 			// when
 			filter := fmt.Sprintf(`{"typegroup.name": "%s"}`, fxt.WorkItemTypeGroups[0].Name)
-			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			// then
 			require.NoError(t, err)
 			toBeFound := id.MapFromSlice(id.Slice{
@@ -289,7 +291,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterWithJoin() {
 		t.Run("matching name", func(t *testing.T) {
 			// when
 			filter := fmt.Sprintf(`{"label.name": "%s"}`, fxt.Labels[0].Name)
-			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			// then
 			require.NoError(t, err)
 			assert.Equal(t, 7, count)
@@ -341,7 +343,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterBoardColumnID() {
 		)
 		t.Run("single match", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"boardcolumn": "%s"}`, fxt.WorkItemBoards[1].Columns[0].ID.String())
-			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			require.NoError(t, err)
 			require.Equal(t, 1, count)
 			require.Len(t, res, count)
@@ -349,7 +351,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterBoardColumnID() {
 		})
 		t.Run("multiple match, atomic expression", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"boardcolumn": "%s"}`, fxt.WorkItemBoards[1].Columns[1].ID.String())
-			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			require.NoError(t, err)
 			require.Equal(t, 2, count)
 			require.Len(t, res, count)
@@ -368,7 +370,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterBoardColumnID() {
 				fxt.WorkItemBoards[1].Columns[0].ID.String(),
 				fxt.WorkItemBoards[0].Columns[1].ID.String(),
 			)
-			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			require.NoError(t, err)
 			require.Equal(t, 1, count)
 			require.Len(t, res, count)
@@ -380,7 +382,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterBoardColumnID() {
 				fxt.WorkItemBoards[0].Columns[0].ID.String(),
 				fxt.WorkItemBoards[1].Columns[1].ID.String(),
 			)
-			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			require.NoError(t, err)
 			require.Equal(t, 2, count)
 			require.Len(t, res, count)
@@ -414,7 +416,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterBoardColumnID() {
 			require.Equal(t, int64(1), db.RowsAffected)
 			// when
 			filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}, {"board.id":{"$EQ":"%s"}}]}`, fxt.Spaces[0].ID, fxt.WorkItemBoards[0].ID)
-			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			// then
 			require.NoError(t, err)
 			require.Equal(t, 0, count)
@@ -446,7 +448,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterByParent() {
 	)
 	s.T().Run("search for children of grandparent by ID", func(t *testing.T) {
 		filter := fmt.Sprintf(`{"parent.id": "%s"}`, fxt.WorkItemByTitle("grandparent").ID)
-		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 		require.NoError(t, err)
 		require.Equal(t, 1, count)
 		require.Len(t, res, count)
@@ -454,7 +456,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterByParent() {
 	})
 	s.T().Run("search for children of parent by ID", func(t *testing.T) {
 		filter := fmt.Sprintf(`{"parent.id": "%s"}`, fxt.WorkItemByTitle("parent").ID)
-		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 		require.NoError(t, err)
 		require.Equal(t, 2, count)
 		require.Len(t, res, count)
@@ -463,7 +465,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterByParent() {
 	})
 	s.T().Run("search for children of grandparent by number", func(t *testing.T) {
 		filter := fmt.Sprintf(`{"parent.number": "%d"}`, fxt.WorkItemByTitle("grandparent").Number)
-		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 		require.NoError(t, err)
 		require.Equal(t, 1, count)
 		require.Len(t, res, count)
@@ -471,7 +473,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterByParent() {
 	})
 	s.T().Run("search for children of parent by number", func(t *testing.T) {
 		filter := fmt.Sprintf(`{"parent.number": "%d"}`, fxt.WorkItemByTitle("parent").Number)
-		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 		require.NoError(t, err)
 		require.Equal(t, 2, count)
 		require.Len(t, res, count)
@@ -480,7 +482,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterByParent() {
 	})
 	s.T().Run("search for children of not existing item by ID", func(t *testing.T) {
 		filter := fmt.Sprintf(`{"parent.id": "%s"}`, uuid.NewV4())
-		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 		require.NoError(t, err)
 		require.Equal(t, 0, count)
 		require.Len(t, res, count)
@@ -488,7 +490,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterByParent() {
 	})
 	s.T().Run("search for children of not existing item by number", func(t *testing.T) {
 		filter := fmt.Sprintf(`{"parent.number": "%d"}`, 12334)
-		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 		require.NoError(t, err)
 		require.Equal(t, 0, count)
 		require.Len(t, res, count)
@@ -522,7 +524,7 @@ func (s *searchRepositoryBlackboxTest) TestFilterBoardID() {
 		)
 		t.Run("multiple match, atomic expression", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"board.id": "%s"}`, fxt.WorkItemBoards[0].ID.String())
-			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			require.NoError(t, err)
 			require.Equal(t, 2, count)
 			require.Len(t, res, count)
@@ -545,7 +547,7 @@ func (s *searchRepositoryBlackboxTest) TestFilter() {
 			fxt := s.getTestFixture()
 			// when
 			filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}]}`, fxt.Spaces[0].ID)
-			res, count, ancestors, childLinks, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			res, count, ancestors, childLinks, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			// when
 			require.NoError(t, err)
 			assert.Equal(t, 2, count)
@@ -560,7 +562,7 @@ func (s *searchRepositoryBlackboxTest) TestFilter() {
 			// when
 			filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}]}`, fxt.Spaces[0].ID)
 			start := 3
-			res, count, ancestors, childLinks, err := s.searchRepo.Filter(context.Background(), filter, nil, &start, nil)
+			res, count, ancestors, childLinks, err := s.searchRepo.Filter(context.Background(), filter, nil, &start, nil, workitem.SortWorkItemsByDefault)
 			// then
 			require.NoError(t, err)
 			assert.Equal(t, 2, count)
@@ -575,7 +577,7 @@ func (s *searchRepositoryBlackboxTest) TestFilter() {
 			// when
 			filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}]}`, fxt.Spaces[0].ID)
 			limit := 1
-			res, count, ancestors, childLinks, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, &limit)
+			res, count, ancestors, childLinks, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, &limit, workitem.SortWorkItemsByDefault)
 			// then
 			require.NoError(s.T(), err)
 			assert.Equal(t, 2, count)
@@ -589,14 +591,14 @@ func (s *searchRepositoryBlackboxTest) TestFilter() {
 		// given
 		t.Run("integer instead of UUID", func(t *testing.T) {
 			filter := `{"space": 123}`
-			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			require.Error(t, err)
 			assert.Equal(t, 0, count)
 		})
 
 		t.Run("string instead of UUID", func(t *testing.T) {
 			filter := `{"space": "foo"}`
-			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			require.Error(t, err)
 			assert.Equal(t, 0, count)
 
@@ -605,20 +607,20 @@ func (s *searchRepositoryBlackboxTest) TestFilter() {
 		// Regression test for https://github.com/openshiftio/openshift.io/issues/4429
 		t.Run("string instead of integer", func(t *testing.T) {
 			filter := `{"number":{"$EQ":"asd"}}`
-			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			// when
 			require.Error(t, err)
 			assert.Equal(t, 0, count)
 
 			filter = `{"number":{"$EQ":"*"}}`
-			_, count, _, _, err = s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			_, count, _, _, err = s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			// when
 			require.Error(t, err)
 			assert.Equal(t, 0, count)
 		})
 		t.Run("UUID instead of integer", func(t *testing.T) {
 			filter := fmt.Sprintf(`{"number":{"$EQ":"%s"}}`, uuid.NewV4())
-			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+			_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
 			// when
 			require.Error(t, err)
 			assert.Equal(t, 0, count)
@@ -633,7 +635,7 @@ func (s *searchRepositoryBlackboxTest) TestFilter() {
 			// when
 			filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}]}`, fxt.Spaces[0].ID)
 			parentExists := false
-			res, count, ancestors, childLinks, err := s.searchRepo.Filter(context.Background(), filter, &parentExists, nil, nil)
+			res, count, ancestors, childLinks, err := s.searchRepo.Filter(context.Background(), filter, &parentExists, nil, nil, workitem.SortWorkItemsByDefault)
 			// then both work items should be returned
 			require.NoError(t, err)
 			assert.Equal(t, 3, count)
@@ -654,7 +656,7 @@ func (s *searchRepositoryBlackboxTest) TestFilter() {
 			// when
 			filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}]}`, fxt.Spaces[0].ID)
 			parentExists := false
-			res, count, ancestors, childLinks, err := s.searchRepo.Filter(context.Background(), filter, &parentExists, nil, nil)
+			res, count, ancestors, childLinks, err := s.searchRepo.Filter(context.Background(), filter, &parentExists, nil, nil, workitem.SortWorkItemsByDefault)
 			// then only parent work item should be returned
 			require.NoError(t, err)
 			assert.Equal(t, 2, count)
@@ -680,7 +682,7 @@ func (s *searchRepositoryBlackboxTest) TestFilter() {
 			// when
 			filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}]}`, fxt.Spaces[0].ID)
 			parentExists := false
-			res, count, ancestors, childLinks, err := s.searchRepo.Filter(context.Background(), filter, &parentExists, nil, nil)
+			res, count, ancestors, childLinks, err := s.searchRepo.Filter(context.Background(), filter, &parentExists, nil, nil, workitem.SortWorkItemsByDefault)
 			// then both work items should be returned
 			require.NoError(t, err)
 			assert.Equal(t, 3, count)
@@ -691,6 +693,204 @@ func (s *searchRepositoryBlackboxTest) TestFilter() {
 	})
 }
 
+func (s *searchRepositoryBlackboxTest) TestFilterByWorkItemTypeField() {
+	// the field names are specific to this test so that no other work item
+	// type in the database defines them with another type.
+	const effortField = "search_test_effort"
+	const priorityField = "search_test_priority"
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Fields[effortField] = workitem.FieldDefinition{
+				Label: "Effort",
+				Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+			}
+			fxt.WorkItemTypes[idx].Fields[priorityField] = workitem.FieldDefinition{
+				Label: "Priority",
+				Type: workitem.EnumType{
+					SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
+					BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+					Values:     []interface{}{"high", "low"},
+				},
+			}
+			return nil
+		}),
+		tf.WorkItems(4, func(fxt *tf.TestFixture, idx int) error {
+			switch idx {
+			case 0:
+				fxt.WorkItems[idx].Fields[effortField] = 3.0
+				fxt.WorkItems[idx].Fields[priorityField] = "high"
+			case 1:
+				fxt.WorkItems[idx].Fields[effortField] = 1.5
+				fxt.WorkItems[idx].Fields[priorityField] = "low"
+			case 2:
+				fxt.WorkItems[idx].Fields[effortField] = 2.0
+				fxt.WorkItems[idx].Fields[priorityField] = "high"
+			}
+			return nil
+		}),
+	)
+	spaceID := fxt.Spaces[0].ID
+
+	s.T().Run("equals float", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}, {"%s": "1.5"}]}`, spaceID, effortField)
+		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		require.Equal(t, fxt.WorkItems[1].ID, res[0].ID)
+	})
+	s.T().Run("equals enum", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}, {"%s": "high"}]}`, spaceID, priorityField)
+		_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+	})
+	s.T().Run("comparison", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}, {"%s": {"$GTE": 2}}]}`, spaceID, effortField)
+		_, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+	})
+	s.T().Run("null", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}, {"%s": null}]}`, spaceID, effortField)
+		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		require.Equal(t, fxt.WorkItems[3].ID, res[0].ID)
+	})
+	s.T().Run("sorted", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"space": "%s"}`, spaceID)
+		sortBy, err := workitem.ParseSortWorkItemsBy(ptr.String("-"+effortField), []workitem.WorkItemType{*fxt.WorkItemTypes[0]})
+		require.NoError(t, err)
+		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, sortBy)
+		require.NoError(t, err)
+		require.Equal(t, 4, count)
+		for i, v := range []int{0, 2, 1, 3} {
+			require.Equal(t, fxt.WorkItems[v].ID, res[i].ID)
+		}
+	})
+	s.T().Run("invalid value", func(t *testing.T) {
+		for _, filter := range []string{
+			fmt.Sprintf(`{"$AND": [{"space": "%s"}, {"%s": "much"}]}`, spaceID, effortField),
+			fmt.Sprintf(`{"$AND": [{"space": "%s"}, {"%s": "medium"}]}`, spaceID, priorityField),
+		} {
+			_, _, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
+			require.Error(t, err)
+		}
+	})
+	s.T().Run("unknown field", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}, {"search_test_unknown": "1"}]}`, spaceID)
+		_, _, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
+		require.Error(t, err)
+	})
+	s.T().Run("field of another space template", func(t *testing.T) {
+		// the work item types of the other space template don't define the field
+		otherFxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}, {"%s": "1.5"}]}`, otherFxt.Spaces[0].ID, effortField)
+		_, _, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
+		require.Error(t, err)
+	})
+	s.T().Run("no space", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"%s": "1.5"}`, effortField)
+		_, _, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
+		require.Error(t, err)
+	})
+}
+
+func (s *searchRepositoryBlackboxTest) TestFilterBlocked() {
+	// A -> B -> C with A being closed and D -> E
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItems(5,
+			tf.SetWorkItemTitles("A", "B", "C", "D", "E"),
+			tf.SetWorkItemField(workitem.SystemState, workitem.SystemStateClosed, workitem.SystemStateNew, workitem.SystemStateNew, workitem.SystemStateNew, workitem.SystemStateNew),
+		),
+		tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyDependency)),
+		tf.WorkItemLinksCustom(3, tf.BuildLinks(tf.L("A", "B"), tf.L("B", "C"), tf.L("D", "E"))),
+	)
+	spaceID := fxt.Spaces[0].ID
+
+	s.T().Run("blocked", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}, {"blocked": "true"}]}`, spaceID)
+		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+		require.ElementsMatch(t, []uuid.UUID{fxt.WorkItemByTitle("C").ID, fxt.WorkItemByTitle("E").ID}, []uuid.UUID{res[0].ID, res[1].ID})
+	})
+	s.T().Run("not blocked", func(t *testing.T) {
+		filter := fmt.Sprintf(`space:%s blocked:false`, spaceID)
+		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+		require.ElementsMatch(t, []uuid.UUID{fxt.WorkItemByTitle("A").ID, fxt.WorkItemByTitle("B").ID, fxt.WorkItemByTitle("D").ID}, []uuid.UUID{res[0].ID, res[1].ID, res[2].ID})
+	})
+	s.T().Run("blocked resolved", func(t *testing.T) {
+		// resolving D unblocks E
+		fxt.WorkItemByTitle("D").Fields[workitem.SystemState] = workitem.SystemStateResolved
+		_, _, err := workitem.NewWorkItemRepository(s.DB).Save(s.Ctx, spaceID, *fxt.WorkItemByTitle("D"), fxt.Identities[0].ID)
+		require.NoError(t, err)
+		filter := fmt.Sprintf(`space:%s blocked:true`, spaceID)
+		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		require.Equal(t, fxt.WorkItemByTitle("C").ID, res[0].ID)
+	})
+}
+
+func (s *searchRepositoryBlackboxTest) TestFilterAsOf() {
+	// given three new work items of which A is closed and made the parent of
+	// C and B is deleted later
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItems(3,
+			tf.SetWorkItemTitles("A", "B", "C"),
+			tf.SetWorkItemField(workitem.SystemState, workitem.SystemStateNew, workitem.SystemStateNew, workitem.SystemStateNew),
+		),
+	)
+	spaceID := fxt.Spaces[0].ID
+	asOf := time.Now()
+	repo := workitem.NewWorkItemRepository(s.DB)
+	fxt.WorkItemByTitle("A").Fields[workitem.SystemState] = workitem.SystemStateClosed
+	_, _, err := repo.Save(s.Ctx, spaceID, *fxt.WorkItemByTitle("A"), fxt.Identities[0].ID)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), repo.Delete(s.Ctx, fxt.WorkItemByTitle("B").ID, fxt.Identities[0].ID))
+	_, err = link.NewWorkItemLinkRepository(s.DB).Create(s.Ctx, fxt.WorkItemByTitle("A").ID, fxt.WorkItemByTitle("C").ID, link.SystemWorkItemLinkTypeParentChildID, fxt.Identities[0].ID)
+	require.NoError(s.T(), err)
+	filterAsOf := func(t time.Time) string {
+		return fmt.Sprintf(`{"$AND": [{"space": "%s"}, {"state": "%s"}], "$OPTS": {"as-of": "%s", "tree-view": true}}`, spaceID, workitem.SystemStateNew, t.UTC().Format(time.RFC3339Nano))
+	}
+
+	s.T().Run("as of the past", func(t *testing.T) {
+		res, count, ancestors, _, err := s.searchRepo.Filter(context.Background(), filterAsOf(asOf), nil, nil, nil, workitem.SortWorkItemsByDefault)
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+		require.Len(t, res, 3)
+		ids := []uuid.UUID{res[0].ID, res[1].ID, res[2].ID}
+		require.ElementsMatch(t, []uuid.UUID{fxt.WorkItemByTitle("A").ID, fxt.WorkItemByTitle("B").ID, fxt.WorkItemByTitle("C").ID}, ids)
+		for _, wi := range res {
+			assert.Equal(t, workitem.SystemStateNew, wi.Fields[workitem.SystemState])
+		}
+		// C got its parent later
+		assert.Empty(t, ancestors)
+	})
+	s.T().Run("as of now", func(t *testing.T) {
+		res, count, ancestors, _, err := s.searchRepo.Filter(context.Background(), filterAsOf(time.Now()), nil, nil, nil, workitem.SortWorkItemsByDefault)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		require.Equal(t, fxt.WorkItemByTitle("C").ID, res[0].ID)
+		require.NotNil(t, ancestors.GetParentOf(fxt.WorkItemByTitle("C").ID))
+		assert.Equal(t, fxt.WorkItemByTitle("A").ID, ancestors.GetParentOf(fxt.WorkItemByTitle("C").ID).ID)
+	})
+	s.T().Run("before the work items existed", func(t *testing.T) {
+		before := fxt.WorkItemByTitle("A").Fields[workitem.SystemCreatedAt].(time.Time).Add(-time.Hour)
+		_, count, _, _, err := s.searchRepo.Filter(context.Background(), filterAsOf(before), nil, nil, nil, workitem.SortWorkItemsByDefault)
+		require.NoError(t, err)
+		require.Equal(t, 0, count)
+	})
+	s.T().Run("invalid time", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"space": "%s", "$OPTS": {"as-of": "yesterday"}}`, spaceID)
+		_, _, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil, workitem.SortWorkItemsByDefault)
+		require.Error(t, err)
+	})
+}
+
 func (s *searchRepositoryBlackboxTest) TestSearchFullText() {
 	var start, limit int = 0, 100
 
@@ -0,0 +1,379 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// The text query language is a human friendly alternative to the JSON filter
+// expressions. A text query is a list of conditions which are combined with
+// "AND" (which can be left out) and "OR" and can be grouped with parentheses.
+// "AND" binds stronger than "OR". Example:
+//
+//	state:open AND assignee:me AND (label:ui OR label:ux) AND updated>-7d
+//
+// A condition consists of a search key (see searchKeyMap) or the key of a
+// work item type field, an operator and a value:
+//
+//	key:value   key=value  the field equals the value
+//	key!=value             the field doesn't equal the value
+//	key~value              the field contains the value
+//	key>value   key>=value the field is greater than (or equal to) the value
+//	key<value   key<=value the field is less than (or equal to) the value
+//
+// Values that contain whitespace or parentheses need to be quoted with double
+// quotes. The unquoted value "null" matches fields without a value. A
+// condition can be negated with a leading "NOT". The value "me" of the
+// "assignee" and "creator" keys refers to the current user. The values of
+// comparisons can be given relative to the current time, e.g. "-7d" for seven
+// days ago, "+2w" for in two weeks, "now" or "today".
+const (
+	textQueryAnd = "AND"
+	textQueryOr  = "OR"
+	textQueryNot = "NOT"
+)
+
+// TextQueryEnv holds the values that text queries can refer to.
+type TextQueryEnv struct {
+	// Now is the time relative dates are resolved against.
+	Now time.Time
+	// CurrentIdentity returns the identity that is referred to as "me". It
+	// is only called if the query uses "me". If it is nil or fails, "me"
+	// cannot be used.
+	CurrentIdentity func() (*uuid.UUID, error)
+}
+
+type textQueryEnvKey struct{}
+
+// ContextWithTextQueryEnv returns a copy of the given context that carries
+// the given environment for text queries.
+func ContextWithTextQueryEnv(ctx context.Context, env TextQueryEnv) context.Context {
+	return context.WithValue(ctx, textQueryEnvKey{}, env)
+}
+
+// textQueryEnvFromContext returns the environment for text queries stored in
+// the given context. If there is none, the current time is used and "me"
+// cannot be resolved.
+func textQueryEnvFromContext(ctx context.Context) TextQueryEnv {
+	env, ok := ctx.Value(textQueryEnvKey{}).(TextQueryEnv)
+	if !ok {
+		env = TextQueryEnv{}
+	}
+	if env.Now.IsZero() {
+		env.Now = time.Now()
+	}
+	return env
+}
+
+// IsTextQuery returns true if the given filter string is not a JSON filter
+// expression.
+func IsTextQuery(rawFilterString string) bool {
+	return !strings.HasPrefix(strings.TrimSpace(rawFilterString), "{")
+}
+
+// relativeTimeRegex matches relative times like "-7d" or "+12h".
+var relativeTimeRegex = regexp.MustCompile(`^([+-])(\d+)([mhdw])$`)
+
+// textQueryOperators maps the operators of the text query language to the
+// properties of a Query. Longer operators must come first.
+var textQueryOperators = []struct {
+	op         string
+	negate     bool
+	substring  bool
+	comparison string
+}{
+	{op: "!=", negate: true},
+	{op: ">=", comparison: GTE},
+	{op: "<=", comparison: LTE},
+	{op: ">", comparison: GT},
+	{op: "<", comparison: LT},
+	{op: ":"},
+	{op: "="},
+	{op: "~", substring: true},
+}
+
+// textQueryParser is a recursive descent parser for the text query language.
+type textQueryParser struct {
+	input []rune
+	pos   int
+	env   TextQueryEnv
+}
+
+// ParseTextQuery parses the given text query into a Query tree. If the query
+// is malformed, a BadParameterError is returned that tells the column (the
+// position of the character starting at 1) at which parsing failed.
+func ParseTextQuery(rawQuery string, env TextQueryEnv) (*Query, error) {
+	p := textQueryParser{input: []rune(rawQuery), env: env}
+	p.skipWhitespace()
+	if p.eof() {
+		return nil, p.errorf(p.pos, "empty query")
+	}
+	q, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.eof() {
+		// only a closing parenthesis without opening one ends a list of
+		// conditions early.
+		return nil, p.errorf(p.pos, "unexpected ')'")
+	}
+	return &q, nil
+}
+
+// errorf returns a BadParameterError for the given column (starting at 0).
+func (p *textQueryParser) errorf(pos int, format string, args ...interface{}) error {
+	return errors.NewBadParameterErrorFromString(fmt.Sprintf("failed to parse query %q at column %d: %s", string(p.input), pos+1, fmt.Sprintf(format, args...)))
+}
+
+func (p *textQueryParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *textQueryParser) skipWhitespace() {
+	for !p.eof() && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+// peekKeyword returns true if the given keyword follows at the current
+// position as a word of its own.
+func (p *textQueryParser) peekKeyword(keyword string) bool {
+	end := p.pos + len(keyword)
+	if end > len(p.input) || string(p.input[p.pos:end]) != keyword {
+		return false
+	}
+	return end == len(p.input) || unicode.IsSpace(p.input[end]) || p.input[end] == '('
+}
+
+// parseOr parses conditions that are combined with OR.
+func (p *textQueryParser) parseOr() (Query, error) {
+	var children []Query
+	for {
+		q, err := p.parseAnd()
+		if err != nil {
+			return Query{}, err
+		}
+		children = append(children, q)
+		if !p.peekKeyword(textQueryOr) {
+			break
+		}
+		p.pos += len(textQueryOr)
+		p.skipWhitespace()
+	}
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return Query{Name: OR, Children: children}, nil
+}
+
+// parseAnd parses conditions that are combined with AND or just listed one
+// after another.
+func (p *textQueryParser) parseAnd() (Query, error) {
+	var children []Query
+	for {
+		q, err := p.parseUnary()
+		if err != nil {
+			return Query{}, err
+		}
+		children = append(children, q)
+		if p.peekKeyword(textQueryAnd) {
+			p.pos += len(textQueryAnd)
+			p.skipWhitespace()
+			continue
+		}
+		if p.eof() || p.input[p.pos] == ')' || p.peekKeyword(textQueryOr) {
+			break
+		}
+	}
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return Query{Name: AND, Children: children}, nil
+}
+
+// parseUnary parses a single condition (which may be negated) or a group of
+// conditions in parentheses.
+func (p *textQueryParser) parseUnary() (Query, error) {
+	if p.eof() {
+		return Query{}, p.errorf(p.pos, "expected condition but found end of query")
+	}
+	if p.input[p.pos] == '(' {
+		start := p.pos
+		p.pos++
+		p.skipWhitespace()
+		q, err := p.parseOr()
+		if err != nil {
+			return Query{}, err
+		}
+		if p.eof() {
+			return Query{}, p.errorf(start, "missing ')' for this '('")
+		}
+		p.pos++
+		p.skipWhitespace()
+		return q, nil
+	}
+	if p.peekKeyword(textQueryNot) {
+		notPos := p.pos
+		p.pos += len(textQueryNot)
+		p.skipWhitespace()
+		if !p.eof() && p.input[p.pos] == '(' {
+			return Query{}, p.errorf(notPos, "NOT can only be applied to a single condition")
+		}
+		q, err := p.parseCondition()
+		if err != nil {
+			return Query{}, err
+		}
+		if q.Comparison != "" || q.Substring || q.Negate || q.Value == nil {
+			return Query{}, p.errorf(notPos, "NOT can only be applied to conditions using ':' or '=' with a value other than null")
+		}
+		q.Negate = true
+		return q, nil
+	}
+	return p.parseCondition()
+}
+
+// parseCondition parses a condition like "state:open".
+func (p *textQueryParser) parseCondition() (Query, error) {
+	if p.eof() {
+		return Query{}, p.errorf(p.pos, "expected condition but found end of query")
+	}
+	keyPos := p.pos
+	for !p.eof() && (unicode.IsLetter(p.input[p.pos]) || unicode.IsDigit(p.input[p.pos]) || p.input[p.pos] == '_' || p.input[p.pos] == '.') {
+		p.pos++
+	}
+	key := string(p.input[keyPos:p.pos])
+	if key == "" {
+		return Query{}, p.errorf(keyPos, "expected field name but found %q", p.input[keyPos])
+	}
+	q := Query{Name: key}
+	opPos := p.pos
+	found := false
+	for _, o := range textQueryOperators {
+		end := p.pos + len(o.op)
+		if end <= len(p.input) && string(p.input[p.pos:end]) == o.op {
+			p.pos = end
+			q.Negate = o.negate
+			q.Substring = o.substring
+			q.Comparison = o.comparison
+			found = true
+			break
+		}
+	}
+	if !found {
+		return Query{}, p.errorf(opPos, "expected one of the operators ':', '=', '!=', '~', '>', '>=', '<' or '<=' after field name %q", key)
+	}
+	valuePos := p.pos
+	value, quoted, err := p.parseValue()
+	if err != nil {
+		return Query{}, err
+	}
+	p.skipWhitespace()
+	if !quoted && value == "null" {
+		if q.Comparison != "" || q.Substring || q.Negate {
+			return Query{}, p.errorf(valuePos, "null can only be used with ':' or '='")
+		}
+		return q, nil
+	}
+	if !quoted && value == "me" && (key == "assignee" || key == "creator") {
+		if p.env.CurrentIdentity == nil {
+			return Query{}, p.errorf(valuePos, "'me' can only be used when logged in")
+		}
+		currentIdentityID, err := p.env.CurrentIdentity()
+		if err != nil || currentIdentityID == nil {
+			return Query{}, p.errorf(valuePos, "'me' can only be used when logged in")
+		}
+		value = currentIdentityID.String()
+	}
+	if q.Comparison != "" {
+		value, err = p.resolveComparisonValue(value, valuePos)
+		if err != nil {
+			return Query{}, err
+		}
+	}
+	if key == "iteration" || key == "area" {
+		// same default as for JSON filter expressions
+		q.Child = true
+	}
+	q.Value = &value
+	return q, nil
+}
+
+// parseValue parses a quoted or unquoted value and tells if it was quoted.
+func (p *textQueryParser) parseValue() (string, bool, error) {
+	if p.eof() || unicode.IsSpace(p.input[p.pos]) || p.input[p.pos] == ')' {
+		return "", false, p.errorf(p.pos, "expected value")
+	}
+	if p.input[p.pos] != '"' {
+		start := p.pos
+		for !p.eof() && !unicode.IsSpace(p.input[p.pos]) && p.input[p.pos] != '(' && p.input[p.pos] != ')' && p.input[p.pos] != '"' {
+			p.pos++
+		}
+		if !p.eof() && (p.input[p.pos] == '(' || p.input[p.pos] == '"') {
+			return "", false, p.errorf(p.pos, "unexpected %q in value, use quotes for such values", p.input[p.pos])
+		}
+		return string(p.input[start:p.pos]), false, nil
+	}
+	start := p.pos
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		r := p.input[p.pos]
+		switch {
+		case r == '\\' && p.pos+1 < len(p.input):
+			b.WriteRune(p.input[p.pos+1])
+			p.pos += 2
+		case r == '"':
+			p.pos++
+			return b.String(), true, nil
+		default:
+			b.WriteRune(r)
+			p.pos++
+		}
+	}
+	return "", false, p.errorf(start, "missing closing '\"' for this quoted value")
+}
+
+// resolveComparisonValue resolves relative times in the value of a comparison
+// and makes sure the value can be compared.
+func (p *textQueryParser) resolveComparisonValue(value string, pos int) (string, error) {
+	switch value {
+	case "now":
+		return p.env.Now.UTC().Format(time.RFC3339Nano), nil
+	case "today":
+		y, m, d := p.env.Now.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, p.env.Now.Location()).UTC().Format(time.RFC3339Nano), nil
+	}
+	if match := relativeTimeRegex.FindStringSubmatch(value); match != nil {
+		n, err := strconv.Atoi(match[2])
+		if err != nil {
+			return "", p.errorf(pos, "invalid relative time %q", value)
+		}
+		if match[1] == "-" {
+			n = -n
+		}
+		var t time.Time
+		switch match[3] {
+		case "m":
+			t = p.env.Now.Add(time.Duration(n) * time.Minute)
+		case "h":
+			t = p.env.Now.Add(time.Duration(n) * time.Hour)
+		case "d":
+			t = p.env.Now.AddDate(0, 0, n)
+		case "w":
+			t = p.env.Now.AddDate(0, 0, 7*n)
+		}
+		return t.UTC().Format(time.RFC3339Nano), nil
+	}
+	if _, ok := parseComparisonValue(value); !ok {
+		return "", p.errorf(pos, "expected number, date or relative time (e.g. -7d) but found %q", value)
+	}
+	return value, nil
+}
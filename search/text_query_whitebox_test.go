@@ -0,0 +1,210 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	c "github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTextQuery(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Parallel()
+
+	currentUserID := uuid.NewV4()
+	env := TextQueryEnv{
+		Now:             time.Date(2018, time.January, 8, 12, 0, 0, 0, time.UTC),
+		CurrentIdentity: func() (*uuid.UUID, error) { return &currentUserID, nil },
+	}
+	str := func(s string) *string { return &s }
+
+	t.Run("single condition", func(t *testing.T) {
+		t.Parallel()
+		// when
+		actualQuery, err := ParseTextQuery("state:open", env)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, Query{Name: "state", Value: str("open")}, *actualQuery)
+	})
+
+	t.Run("AND, OR and parentheses", func(t *testing.T) {
+		t.Parallel()
+		// when
+		actualQuery, err := ParseTextQuery("state:open AND assignee:me AND (label:ui OR label:ux) AND updated>-7d", env)
+		// then
+		require.NoError(t, err)
+		expectedQuery := Query{Name: AND, Children: []Query{
+			{Name: "state", Value: str("open")},
+			{Name: "assignee", Value: str(currentUserID.String())},
+			{Name: OR, Children: []Query{
+				{Name: "label", Value: str("ui")},
+				{Name: "label", Value: str("ux")},
+			}},
+			{Name: "updated", Value: str("2018-01-01T12:00:00Z"), Comparison: GT},
+		}}
+		assert.Equal(t, expectedQuery, *actualQuery)
+	})
+
+	t.Run("AND binds stronger than OR", func(t *testing.T) {
+		t.Parallel()
+		// when
+		actualQuery, err := ParseTextQuery("state:open label:ui OR state:new", env)
+		// then
+		require.NoError(t, err)
+		expectedQuery := Query{Name: OR, Children: []Query{
+			{Name: AND, Children: []Query{
+				{Name: "state", Value: str("open")},
+				{Name: "label", Value: str("ui")},
+			}},
+			{Name: "state", Value: str("new")},
+		}}
+		assert.Equal(t, expectedQuery, *actualQuery)
+	})
+
+	t.Run("operators", func(t *testing.T) {
+		t.Parallel()
+		// when
+		actualQuery, err := ParseTextQuery(`NOT state:closed state!=new title~"login page" effort>=2.5 effort<5 iteration=sprint1 assignee:null`, env)
+		// then
+		require.NoError(t, err)
+		expectedQuery := Query{Name: AND, Children: []Query{
+			{Name: "state", Value: str("closed"), Negate: true},
+			{Name: "state", Value: str("new"), Negate: true},
+			{Name: "title", Value: str("login page"), Substring: true},
+			{Name: "effort", Value: str("2.5"), Comparison: GTE},
+			{Name: "effort", Value: str("5"), Comparison: LT},
+			{Name: "iteration", Value: str("sprint1"), Child: true},
+			{Name: "assignee"},
+		}}
+		assert.Equal(t, expectedQuery, *actualQuery)
+	})
+
+	t.Run("quoted values", func(t *testing.T) {
+		t.Parallel()
+		// when
+		actualQuery, err := ParseTextQuery(`title:"say \"hello\" (again)" label:"null" assignee:"me"`, env)
+		// then
+		require.NoError(t, err)
+		expectedQuery := Query{Name: AND, Children: []Query{
+			{Name: "title", Value: str(`say "hello" (again)`)},
+			{Name: "label", Value: str("null")},
+			{Name: "assignee", Value: str("me")},
+		}}
+		assert.Equal(t, expectedQuery, *actualQuery)
+	})
+
+	t.Run("relative times", func(t *testing.T) {
+		t.Parallel()
+		for input, expected := range map[string]string{
+			"created>now":        "2018-01-08T12:00:00Z",
+			"created>today":      "2018-01-08T00:00:00Z",
+			"created>-30m":       "2018-01-08T11:30:00Z",
+			"created>+2h":        "2018-01-08T14:00:00Z",
+			"created>-1w":        "2018-01-01T12:00:00Z",
+			"created>2017-12-24": "2017-12-24",
+		} {
+			t.Run(input, func(t *testing.T) {
+				// when
+				actualQuery, err := ParseTextQuery(input, env)
+				// then
+				require.NoError(t, err)
+				assert.Equal(t, Query{Name: "created", Value: str(expected), Comparison: GT}, *actualQuery)
+			})
+		}
+	})
+
+	t.Run("me without current user", func(t *testing.T) {
+		t.Parallel()
+		// when
+		_, err := ParseTextQuery("assignee:me", TextQueryEnv{Now: env.Now})
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "at column 10")
+	})
+
+	t.Run("current user is only looked up for me", func(t *testing.T) {
+		t.Parallel()
+		// given
+		lookups := 0
+		env := TextQueryEnv{Now: env.Now, CurrentIdentity: func() (*uuid.UUID, error) {
+			lookups++
+			return &currentUserID, nil
+		}}
+		// when
+		_, err := ParseTextQuery("state:open AND title~me", env)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 0, lookups)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		for input, column := range map[string]string{
+			"":                         "column 1",
+			"state open":               "column 6",
+			"state:open AND (label:ui": "column 16",
+			"state:open)":              "column 11",
+			"state:open AND":           "column 15",
+			`title:"unterminated`:      "column 7",
+			"title:":                   "column 7",
+			"created>yesterday":        "column 9",
+			"NOT (state:open)":         "column 1",
+			"NOT state~open":           "column 1",
+			"state!=null":              "column 8",
+			":open":                    "column 1",
+		} {
+			t.Run(input, func(t *testing.T) {
+				// when
+				actualQuery, err := ParseTextQuery(input, env)
+				// then
+				require.Error(t, err)
+				require.Nil(t, actualQuery)
+				assert.Contains(t, err.Error(), column)
+			})
+		}
+	})
+}
+
+func TestParseFilterStringWithTextQuery(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Parallel()
+
+	currentUserID := uuid.NewV4()
+	ctx := ContextWithTextQueryEnv(context.Background(), TextQueryEnv{
+		Now:             time.Now(),
+		CurrentIdentity: func() (*uuid.UUID, error) { return &currentUserID, nil },
+	})
+	// when
+	actualExpr, options, err := ParseFilterString(ctx, "state:open AND assignee:me AND (label:ui OR title~login)")
+	// then
+	require.NoError(t, err)
+	assert.Nil(t, options)
+	expectedExpr := c.And(
+		c.And(
+			c.Equals(
+				c.Field("system.state"),
+				c.Literal("open"),
+			),
+			c.Equals(
+				c.Field("system.assignees"),
+				c.Literal([]string{currentUserID.String()}),
+			),
+		),
+		c.Or(
+			c.Equals(
+				c.Field("system.labels"),
+				c.Literal([]string{"ui"}),
+			),
+			c.Substring(
+				c.Field("system.title"),
+				c.Literal("login"),
+			),
+		),
+	)
+	expectEqualExpr(t, expectedExpr, actualExpr)
+}
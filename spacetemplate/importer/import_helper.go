@@ -1,6 +1,7 @@
 package importer
 
 import (
+	"github.com/fabric8-services/fabric8-wit/actions/rules"
 	"github.com/fabric8-services/fabric8-wit/convert"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/log"
@@ -19,6 +20,7 @@ type ImportHelper struct {
 	WILTs    []*link.WorkItemLinkType      `gorm:"-" json:"work_item_link_types,omitempty"`
 	WITGs    []*workitem.WorkItemTypeGroup `gorm:"-" json:"work_item_type_groups,omitempty"`
 	WIBs     []*workitem.Board             `gorm:"-" json:"work_item_boards,omitempty"`
+	WIARs    []*workitem.ActionRule        `gorm:"-" json:"work_item_action_rules,omitempty"`
 }
 
 // Validate ensures that all inner-document references of the given space
@@ -54,6 +56,26 @@ func (s *ImportHelper) Validate() error {
 			return errors.NewBadParameterError("work item board's space template ID", wibs.SpaceTemplateID.String()).Expected(s.Template.ID.String())
 		}
 	}
+	witIDs := map[uuid.UUID]struct{}{}
+	for _, wit := range s.WITs {
+		witIDs[wit.ID] = struct{}{}
+	}
+	for _, wiar := range s.WIARs {
+		if wiar.SpaceTemplateID != s.Template.ID {
+			return errors.NewBadParameterError("work item action rule's space template ID", wiar.SpaceTemplateID.String()).Expected(s.Template.ID.String())
+		}
+		if err := wiar.Validate(); err != nil {
+			return errs.Wrapf(err, `failed to validate work item action rule "%s"`, wiar.Name)
+		}
+		if err := rules.ValidateConfig(wiar.ActionKey, wiar.ActionConfig); err != nil {
+			return errs.Wrapf(err, `failed to validate the action of work item action rule "%s"`, wiar.Name)
+		}
+		if wiar.WorkItemTypeID != nil {
+			if _, ok := witIDs[*wiar.WorkItemTypeID]; !ok {
+				return errors.NewBadParameterError("work item action rule's work item type ID", wiar.WorkItemTypeID.String()).Expected("work item type of the same space template")
+			}
+		}
+	}
 
 	return nil
 }
@@ -109,6 +131,9 @@ func (s *ImportHelper) SetID(id uuid.UUID) {
 	for _, wib := range s.WIBs {
 		wib.SpaceTemplateID = s.Template.ID
 	}
+	for _, wiar := range s.WIARs {
+		wiar.SpaceTemplateID = s.Template.ID
+	}
 }
 
 // Ensure ImportHelper implements the Equaler interface
@@ -170,6 +195,17 @@ func (s ImportHelper) Equal(u convert.Equaler) bool {
 			return false
 		}
 	}
+	if len(s.WIARs) != len(other.WIARs) {
+		return false
+	}
+	for k := range s.WIARs {
+		if other.WIARs[k] == nil {
+			return false
+		}
+		if !convert.CascadeEqual(s.WIARs[k], *other.WIARs[k]) {
+			return false
+		}
+	}
 	return true
 }
 
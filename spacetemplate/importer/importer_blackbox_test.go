@@ -45,6 +45,46 @@ work_item_link_types:
 			require.Equal(t, "bar", *templ.Template.Description)
 			require.NoError(t, templ.Validate())
 		})
+		t.Run("with action rules", func(t *testing.T) {
+			t.Parallel()
+			// given
+			yaml := `
+space_template:
+  id: "038e4d23-4e52-45e7-b0d9-5d736109845f"
+  name: "foo"
+  description: "bar"
+work_item_types:
+- id: &bugID "a7e4c5a4-3a7c-4a9b-8b5a-3b7d7e4f2d11"
+  name: Bug
+  extends: "86af5178-9b41-469b-9096-57e5155c3f31"
+work_item_action_rules:
+- id: "1d8a4d31-5b2c-4b73-9a3c-2e1c7f0e6b21"
+  name: close resolved bugs
+  work_item_type_id: *bugID
+  attribute: system.state
+  value: resolved
+  action: FieldSet
+  config: "{ \"system.state\": \"closed\" }"
+- name: do nothing
+  attribute: system.title
+  action: Nil`
+			// when
+			templ, err := importer.FromString(yaml)
+			// then
+			require.NoError(t, err)
+			require.Len(t, templ.WIARs, 2)
+			bugID := uuid.FromStringOrNil("a7e4c5a4-3a7c-4a9b-8b5a-3b7d7e4f2d11")
+			require.Equal(t, &bugID, templ.WIARs[0].WorkItemTypeID)
+			require.Equal(t, workitem.SystemState, templ.WIARs[0].AttributeName)
+			require.Equal(t, ptr.String("resolved"), templ.WIARs[0].AttributeValue)
+			require.Equal(t, "FieldSet", templ.WIARs[0].ActionKey)
+			require.Equal(t, `{ "system.state": "closed" }`, templ.WIARs[0].ActionConfig)
+			require.Nil(t, templ.WIARs[1].WorkItemTypeID)
+			require.Nil(t, templ.WIARs[1].AttributeValue)
+			for _, rule := range templ.WIARs {
+				require.Equal(t, templ.Template.ID, rule.SpaceTemplateID)
+			}
+		})
 	})
 
 	t.Run("invalid", func(t *testing.T) {
@@ -60,6 +100,90 @@ space_template:
 			_, err := importer.FromString(yaml)
 			require.Error(t, err)
 		})
+
+		t.Run("action rule with unknown work item type", func(t *testing.T) {
+			t.Parallel()
+			// given
+			yaml := `
+space_template:
+  id: "038e4d23-4e52-45e7-b0d9-5d736109845f"
+  name: "foo"
+  description: "bar"
+work_item_action_rules:
+- name: close resolved bugs
+  work_item_type_id: "a7e4c5a4-3a7c-4a9b-8b5a-3b7d7e4f2d11"
+  attribute: system.state
+  action: Nil`
+			// when
+			_, err := importer.FromString(yaml)
+			require.Error(t, err)
+		})
+
+		t.Run("action rule without action", func(t *testing.T) {
+			t.Parallel()
+			// given
+			yaml := `
+space_template:
+  id: "038e4d23-4e52-45e7-b0d9-5d736109845f"
+  name: "foo"
+  description: "bar"
+work_item_action_rules:
+- name: close resolved bugs
+  attribute: system.state`
+			// when
+			_, err := importer.FromString(yaml)
+			require.Error(t, err)
+		})
+
+		t.Run("action rule with unknown action", func(t *testing.T) {
+			t.Parallel()
+			// given
+			yaml := `
+space_template:
+  id: "038e4d23-4e52-45e7-b0d9-5d736109845f"
+  name: "foo"
+  description: "bar"
+work_item_action_rules:
+- name: close resolved bugs
+  attribute: system.state
+  action: DoesNotExist`
+			// when
+			_, err := importer.FromString(yaml)
+			require.Error(t, err)
+		})
+
+		t.Run("action rule with malformed config", func(t *testing.T) {
+			testData := []struct {
+				name   string
+				action string
+				config string
+			}{
+				{"field set without JSON object", "FieldSet", `"{ \"system.state\": "`},
+				{"cascade without fields", "Cascade", `"{ \"linkType\": \"25c326a7-6d03-4f5a-b23b-86a9ee4171e9\", \"direction\": \"children\" }"`},
+				{"cascade with unknown direction", "Cascade", `"{ \"linkType\": \"25c326a7-6d03-4f5a-b23b-86a9ee4171e9\", \"direction\": \"sideways\", \"fields\": { \"system.state\": \"closed\" } }"`},
+				{"iteration rollover with array", "IterationRollover", `"[]"`},
+			}
+			for _, td := range testData {
+				td := td
+				t.Run(td.name, func(t *testing.T) {
+					t.Parallel()
+					// given
+					yaml := `
+space_template:
+  id: "038e4d23-4e52-45e7-b0d9-5d736109845f"
+  name: "foo"
+  description: "bar"
+work_item_action_rules:
+- name: broken rule
+  attribute: system.state
+  action: ` + td.action + `
+  config: ` + td.config
+					// when
+					_, err := importer.FromString(yaml)
+					require.Error(t, err)
+				})
+			}
+		})
 	})
 }
 
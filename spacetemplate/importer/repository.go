@@ -85,6 +85,7 @@ func (r *GormRepository) Import(ctx context.Context, s ImportHelper) (*ImportHel
 	res.WITs = s.WITs
 	res.WITGs = s.WITGs
 	res.WIBs = s.WIBs
+	res.WIARs = s.WIARs
 
 	// Create or update work item types
 	if err := r.createOrUpdateWITs(ctx, res); err != nil {
@@ -110,6 +111,12 @@ func (r *GormRepository) Import(ctx context.Context, s ImportHelper) (*ImportHel
 		return nil, errs.Wrapf(err, "failed to create or update work item boards")
 	}
 
+	// Create or update work item action rules
+	if err := r.createOrUpdateWIARs(ctx, res); err != nil {
+		log.Error(ctx, map[string]interface{}{"space_template": res, "err": err}, "failed to create or update work item action rules")
+		return nil, errs.Wrapf(err, "failed to create or update work item action rules")
+	}
+
 	log.Info(ctx, map[string]interface{}{"space_template_id": s.Template.ID}, "space template imported successfully")
 	return res, nil
 }
@@ -360,3 +367,22 @@ func (r *GormRepository) createOrUpdateWIBs(ctx context.Context, s *ImportHelper
 	}
 	return nil
 }
+
+func (r *GormRepository) createOrUpdateWIARs(ctx context.Context, s *ImportHelper) error {
+	// Delete old work item action rules (if any) associated with this space
+	// template. The rules are entirely defined by the template, so there's no
+	// need to retain information about old rules.
+	db := r.db.Unscoped().Delete(workitem.ActionRule{}, "space_template_id = ?", s.Template.ID)
+	if db.Error != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to delete previous work item action rules for space template '%s'", s.Template.ID))
+	}
+	repo := workitem.NewActionRuleRepository(r.db)
+	for pos, rule := range s.WIARs {
+		rule.Position = pos
+		_, err := repo.Create(ctx, *rule)
+		if err != nil {
+			return errs.Wrapf(err, "failed to create work item action rule '%s' from space template '%s'", rule.Name, s.Template.ID)
+		}
+	}
+	return nil
+}
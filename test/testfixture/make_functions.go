@@ -383,7 +383,7 @@ func makeWorkItemBoards(fxt *TestFixture) error {
 					Name:              testsupport.CreateRandomValidTestName("New"),
 					Order:             0,
 					TransRuleKey:      "updateStateFromColumnMove",
-					TransRuleArgument: "{ 'metastate': 'mNew' }",
+					TransRuleArgument: "{ \"metaState\": \"mNew\" }",
 					BoardID:           fxt.WorkItemBoards[i].ID,
 				},
 				{
@@ -391,7 +391,7 @@ func makeWorkItemBoards(fxt *TestFixture) error {
 					Name:              testsupport.CreateRandomValidTestName("In Progress"),
 					Order:             1,
 					TransRuleKey:      "updateStateFromColumnMove",
-					TransRuleArgument: "{ 'metastate': 'mInprogress' }",
+					TransRuleArgument: "{ \"metaState\": \"mInprogress\" }",
 					BoardID:           fxt.WorkItemBoards[i].ID,
 				},
 				{
@@ -399,7 +399,7 @@ func makeWorkItemBoards(fxt *TestFixture) error {
 					Name:              testsupport.CreateRandomValidTestName("Resolved"),
 					Order:             2,
 					TransRuleKey:      "updateStateFromColumnMove",
-					TransRuleArgument: "{ 'metastate': 'mResolved' }",
+					TransRuleArgument: "{ \"metaState\": \"mResolved\" }",
 					BoardID:           fxt.WorkItemBoards[i].ID,
 				},
 				{
@@ -407,7 +407,7 @@ func makeWorkItemBoards(fxt *TestFixture) error {
 					Name:              testsupport.CreateRandomValidTestName("Approved"),
 					Order:             3,
 					TransRuleKey:      "updateStateFromColumnMove",
-					TransRuleArgument: "{ 'metastate': 'mResolved' }",
+					TransRuleArgument: "{ \"metaState\": \"mResolved\" }",
 					BoardID:           fxt.WorkItemBoards[i].ID,
 				},
 			}
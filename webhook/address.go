@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	errs "github.com/pkg/errors"
+)
+
+// privateNetworks are the networks that are not reachable from the internet
+// and that webhooks must not post to, next to the loopback, link-local and
+// multicast addresses.
+var privateNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("10.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("172.16.0.0/12"),
+	mustParseCIDR("192.168.0.0/16"),
+	mustParseCIDR("fc00::/7"),
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// allowPrivateAddresses disables the address checks, see
+// SetAllowPrivateAddresses
+var allowPrivateAddresses = false
+
+// SetAllowPrivateAddresses allows webhooks to post to private, loopback and
+// link-local addresses. Only enable this for development and tests: it lets
+// the users of a space make the server send requests into its own network.
+func SetAllowPrivateAddresses(allow bool) {
+	allowPrivateAddresses = allow
+}
+
+// checkIP fails if webhooks must not post to the given address
+func checkIP(ip net.IP) error {
+	if allowPrivateAddresses {
+		return nil
+	}
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return errs.Errorf("address %s is not public", ip)
+	}
+	for _, n := range privateNetworks {
+		if n.Contains(ip) {
+			return errs.Errorf("address %s is not public", ip)
+		}
+	}
+	return nil
+}
+
+// lookupPublicIPs resolves the given host and fails unless all of its
+// addresses are public
+func lookupPublicIPs(ctx context.Context, host string) ([]net.IP, error) {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to resolve host %s", host)
+	}
+	if len(addrs) == 0 {
+		return nil, errs.Errorf("host %s has no address", host)
+	}
+	ips := make([]net.IP, len(addrs))
+	for i, addr := range addrs {
+		if err := checkIP(addr.IP); err != nil {
+			return nil, errs.Wrapf(err, "invalid address of host %s", host)
+		}
+		ips[i] = addr.IP
+	}
+	return ips, nil
+}
+
+// NewHTTPClient returns a client to post to webhooks with. It resolves the
+// host of every connection itself and refuses to connect to addresses that
+// are not public. This also covers hosts whose addresses changed after the
+// webhook was saved and redirects to other hosts.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				host, port, err := net.SplitHostPort(addr)
+				if err != nil {
+					return nil, errs.WithStack(err)
+				}
+				ips, err := lookupPublicIPs(ctx, host)
+				if err != nil {
+					return nil, err
+				}
+				// dial the checked addresses rather than the host so that
+				// the host is not resolved a second time
+				for _, ip := range ips {
+					var conn net.Conn
+					conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+					if err == nil {
+						return conn, nil
+					}
+				}
+				return nil, errs.Wrapf(err, "failed to connect to %s", addr)
+			},
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
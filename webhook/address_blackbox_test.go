@@ -0,0 +1,36 @@
+package webhook_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/webhook"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	t.Run("loopback address is refused", func(t *testing.T) {
+		// when
+		_, err := webhook.NewHTTPClient(time.Second).Get(server.URL)
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("loopback address is allowed for development", func(t *testing.T) {
+		// given
+		webhook.SetAllowPrivateAddresses(true)
+		defer webhook.SetAllowPrivateAddresses(false)
+		// when
+		resp, err := webhook.NewHTTPClient(time.Second).Get(server.URL)
+		// then
+		require.NoError(t, err)
+		resp.Body.Close()
+	})
+}
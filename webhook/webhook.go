@@ -0,0 +1,239 @@
+package webhook
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/application/repository"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeWebhook helps to avoid string literal
+const APIStringTypeWebhook = "webhooks"
+
+// WebhookTableName constant that holds table name of Webhooks
+const WebhookTableName = "webhooks"
+
+// EventTypes holds the types of the events a webhook is subscribed to. An
+// empty list subscribes the webhook to all events.
+type EventTypes []string
+
+// Ensure EventTypes implements the Scanner and Valuer interfaces
+var _ driver.Valuer = EventTypes{}
+var _ driver.Valuer = (*EventTypes)(nil)
+
+// Value implements the https://golang.org/pkg/database/sql/driver/#Valuer
+// interface
+func (t EventTypes) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(t)
+}
+
+// Scan implements the https://golang.org/pkg/database/sql/#Scanner
+// interface
+func (t *EventTypes) Scan(src interface{}) error {
+	*t = nil
+	if src == nil {
+		return nil
+	}
+	bytes, ok := src.([]byte)
+	if !ok {
+		return errs.Errorf("scanned value is not a byte array: %+v (%[1]T)", src)
+	}
+	return json.Unmarshal(bytes, t)
+}
+
+// Webhook describes a subscription of an external URL to the events of a
+// space. The payloads sent to the URL are signed with the Secret.
+type Webhook struct {
+	gormsupport.Lifecycle
+	ID         uuid.UUID  `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	SpaceID    uuid.UUID  `sql:"type:uuid"`
+	URL        string     `gorm:"column:url"`
+	EventTypes EventTypes `sql:"type:jsonb"`
+	Secret     string
+	Version    int
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (w Webhook) TableName() string {
+	return WebhookTableName
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (w Webhook) GetETagData() []interface{} {
+	return []interface{}{w.ID, strconv.FormatInt(w.UpdatedAt.Unix(), 10)}
+}
+
+// GetLastModified returns the last modification time
+func (w Webhook) GetLastModified() time.Time {
+	return w.UpdatedAt.Truncate(time.Second)
+}
+
+// Accepts returns true if the webhook is subscribed to the given event type
+func (w Webhook) Accepts(eventType string) bool {
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks that the webhook has an absolute http(s) URL of a host
+// with public addresses only and a secret
+func (w Webhook) validate(ctx context.Context) error {
+	u, err := url.Parse(w.URL)
+	if err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.NewBadParameterError("url", w.URL).Expected("absolute http or https URL")
+	}
+	if _, err := lookupPublicIPs(ctx, u.Hostname()); err != nil {
+		return errors.NewBadParameterError("url", w.URL).Expected(fmt.Sprintf("URL of a host with public addresses (%s)", err))
+	}
+	if strings.TrimSpace(w.Secret) == "" {
+		return errors.NewBadParameterError("secret", w.Secret).Expected("non empty string")
+	}
+	for _, t := range w.EventTypes {
+		if strings.TrimSpace(t) == "" {
+			return errors.NewBadParameterError("event_types", w.EventTypes).Expected("non empty event types")
+		}
+	}
+	return nil
+}
+
+// Repository describes interactions with Webhooks.
+type Repository interface {
+	repository.Exister
+	Create(ctx context.Context, w *Webhook) error
+	List(ctx context.Context, spaceID uuid.UUID) ([]Webhook, error)
+	Load(ctx context.Context, ID uuid.UUID, spaceID uuid.UUID) (*Webhook, error)
+	Save(ctx context.Context, w Webhook) (*Webhook, error)
+	Delete(ctx context.Context, ID uuid.UUID) error
+}
+
+// NewWebhookRepository creates a new storage type.
+func NewWebhookRepository(db *gorm.DB) Repository {
+	return &GormWebhookRepository{db: db}
+}
+
+// GormWebhookRepository is the implementation of the storage interface for Webhooks.
+type GormWebhookRepository struct {
+	db *gorm.DB
+}
+
+// CheckExists returns nil if the given ID exists otherwise returns an error
+func (r *GormWebhookRepository) CheckExists(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "exists"}, time.Now())
+	return repository.CheckExists(ctx, r.db, WebhookTableName, id)
+}
+
+// Create a new webhook
+func (r *GormWebhookRepository) Create(ctx context.Context, w *Webhook) error {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "create"}, time.Now())
+	if err := w.validate(ctx); err != nil {
+		return err
+	}
+	w.ID = uuid.NewV4()
+	if err := r.db.Create(w).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"space_id": w.SpaceID,
+			"err":      err,
+		}, "unable to create the webhook")
+		return errors.NewInternalError(ctx, err)
+	}
+	return nil
+}
+
+// List all webhooks in a space
+func (r *GormWebhookRepository) List(ctx context.Context, spaceID uuid.UUID) ([]Webhook, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "list"}, time.Now())
+	var objs []Webhook
+	err := r.db.Where("space_id = ?", spaceID).Order("created_at").Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return objs, nil
+}
+
+// Load a webhook in a space
+func (r *GormWebhookRepository) Load(ctx context.Context, ID uuid.UUID, spaceID uuid.UUID) (*Webhook, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "show"}, time.Now())
+	w := Webhook{}
+	tx := r.db.Where("id = ? and space_id = ?", ID, spaceID).First(&w)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("webhook", ID.String())
+	}
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":        tx.Error,
+			"webhook_id": ID.String(),
+		}, "unable to load the webhook by ID")
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	return &w, nil
+}
+
+// Save updates the given webhook
+func (r *GormWebhookRepository) Save(ctx context.Context, w Webhook) (*Webhook, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "save"}, time.Now())
+	if err := w.validate(ctx); err != nil {
+		return nil, err
+	}
+	existing := Webhook{}
+	tx := r.db.Where("id = ?", w.ID).First(&existing)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("webhook", w.ID.String())
+	}
+	if err := tx.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	oldVersion := w.Version
+	w.Version = existing.Version + 1
+	tx = r.db.Where("version = ?", oldVersion).Save(&w)
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"webhook_id": w.ID,
+			"err":        err,
+		}, "unable to save the webhook")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	if tx.RowsAffected == 0 {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	return &w, nil
+}
+
+// Delete deletes the webhook with the given id, returns NotFoundError or InternalError
+func (r *GormWebhookRepository) Delete(ctx context.Context, ID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "delete"}, time.Now())
+	tx := r.db.Delete(Webhook{ID: ID})
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"webhook_id": ID.String(),
+			"err":        err,
+		}, "unable to delete the webhook")
+		return errors.NewInternalError(ctx, err)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("webhook", ID.String())
+	}
+	return nil
+}
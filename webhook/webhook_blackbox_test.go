@@ -0,0 +1,138 @@
+package webhook_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/webhook"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWebhookRepository struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunWebhookRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestWebhookRepository{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *TestWebhookRepository) TestCreate() {
+	repo := webhook.NewWebhookRepository(s.DB)
+
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		w := webhook.Webhook{
+			SpaceID:    fxt.Spaces[0].ID,
+			URL:        "https://example.com/hook",
+			EventTypes: webhook.EventTypes{"workitem.create", "link.delete"},
+			Secret:     "s3cr3t",
+		}
+		// when
+		err := repo.Create(s.Ctx, &w)
+		// then
+		require.NoError(t, err)
+		require.NotEqual(t, uuid.Nil, w.ID)
+		loaded, err := repo.Load(s.Ctx, w.ID, fxt.Spaces[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, w.URL, loaded.URL)
+		assert.Equal(t, w.EventTypes, loaded.EventTypes)
+		assert.Equal(t, w.Secret, loaded.Secret)
+	})
+
+	s.T().Run("fail", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		for name, w := range map[string]webhook.Webhook{
+			"relative url":       {URL: "/hook", Secret: "s3cr3t"},
+			"unsupported url":    {URL: "ftp://example.com/hook", Secret: "s3cr3t"},
+			"empty secret":       {URL: "https://example.com/hook", Secret: " "},
+			"empty event type":   {URL: "https://example.com/hook", Secret: "s3cr3t", EventTypes: webhook.EventTypes{""}},
+			"loopback address":   {URL: "http://127.0.0.1:8080/hook", Secret: "s3cr3t"},
+			"private address":    {URL: "http://10.1.2.3/hook", Secret: "s3cr3t"},
+			"link-local address": {URL: "http://169.254.169.254/latest/meta-data", Secret: "s3cr3t"},
+			"ipv6 loopback":      {URL: "http://[::1]/hook", Secret: "s3cr3t"},
+			"localhost":          {URL: "http://localhost/hook", Secret: "s3cr3t"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				w.SpaceID = fxt.Spaces[0].ID
+				err := repo.Create(s.Ctx, &w)
+				require.Error(t, err)
+				ok, _ := errors.IsBadParameterError(err)
+				assert.True(t, ok, "expected a BadParameterError but got %T", errs.Cause(err))
+			})
+		}
+	})
+}
+
+func (s *TestWebhookRepository) TestList() {
+	// given
+	repo := webhook.NewWebhookRepository(s.DB)
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Spaces(2))
+	for _, spaceID := range []uuid.UUID{fxt.Spaces[0].ID, fxt.Spaces[0].ID, fxt.Spaces[1].ID} {
+		require.NoError(s.T(), repo.Create(s.Ctx, &webhook.Webhook{SpaceID: spaceID, URL: "https://example.com/hook", Secret: "s3cr3t"}))
+	}
+	// when
+	hooks, err := repo.List(s.Ctx, fxt.Spaces[0].ID)
+	// then
+	require.NoError(s.T(), err)
+	require.Len(s.T(), hooks, 2)
+	for _, w := range hooks {
+		assert.Equal(s.T(), fxt.Spaces[0].ID, w.SpaceID)
+		assert.Empty(s.T(), w.EventTypes)
+	}
+}
+
+func (s *TestWebhookRepository) TestSaveAndDelete() {
+	// given
+	repo := webhook.NewWebhookRepository(s.DB)
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Spaces(1))
+	w := webhook.Webhook{SpaceID: fxt.Spaces[0].ID, URL: "https://example.com/hook", Secret: "s3cr3t"}
+	require.NoError(s.T(), repo.Create(s.Ctx, &w))
+
+	s.T().Run("save", func(t *testing.T) {
+		// when
+		w.URL = "https://example.com/other"
+		w.EventTypes = webhook.EventTypes{"comment.create"}
+		saved, err := repo.Save(s.Ctx, w)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, w.Version+1, saved.Version)
+		assert.Equal(t, "https://example.com/other", saved.URL)
+		assert.Equal(t, webhook.EventTypes{"comment.create"}, saved.EventTypes)
+
+		t.Run("version conflict", func(t *testing.T) {
+			_, err := repo.Save(s.Ctx, w)
+			require.Error(t, err)
+			ok, _ := errors.IsVersionConflictError(err)
+			assert.True(t, ok, "expected a VersionConflictError but got %T", errs.Cause(err))
+		})
+	})
+
+	s.T().Run("delete", func(t *testing.T) {
+		// when
+		err := repo.Delete(s.Ctx, w.ID)
+		// then
+		require.NoError(t, err)
+		_, err = repo.Load(s.Ctx, w.ID, fxt.Spaces[0].ID)
+		notFound, _ := errors.IsNotFoundError(err)
+		require.True(t, notFound)
+		notFound, _ = errors.IsNotFoundError(repo.Delete(s.Ctx, w.ID))
+		require.True(t, notFound)
+	})
+}
+
+func TestWebhookAccepts(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	assert.True(t, webhook.Webhook{}.Accepts("workitem.create"))
+	w := webhook.Webhook{EventTypes: webhook.EventTypes{"workitem.create"}}
+	assert.True(t, w.Accepts("workitem.create"))
+	assert.False(t, w.Accepts("workitem.update"))
+}
@@ -0,0 +1,160 @@
+package workitem
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/actions/change"
+	"github.com/fabric8-services/fabric8-wit/convert"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ActionRule is a user configurable connection between a change of a work
+// item attribute and an action from the actions system. It reads like "when
+// attribute X of a work item of type Y changes to Z, run action A with
+// configuration C". Action rules are defined per space template (see the
+// "work_item_action_rules" section in the space template YAML files).
+type ActionRule struct {
+	gormsupport.Lifecycle `json:"lifecycle"`
+	ID                    uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key" json:"id"`
+	SpaceTemplateID       uuid.UUID `sql:"type:uuid" json:"space_template_id"`
+	Name                  string    `json:"name"`
+	Description           *string   `json:"description,omitempty"`
+	// WorkItemTypeID restricts the rule to work items of the given type. If
+	// it is nil, the rule applies to work items of all types.
+	WorkItemTypeID *uuid.UUID `sql:"type:uuid" gorm:"column:work_item_type_id" json:"work_item_type_id,omitempty"`
+	// AttributeName is the name of the attribute (e.g. "system.state") whose
+	// change triggers the rule. The names of several attributes can be
+	// separated by commas, in which case the rule runs once for a change of
+	// any of them.
+	AttributeName string `json:"attribute"`
+	// AttributeValue is the new value of the attribute that triggers the rule.
+	// If it is nil, any change of the attribute triggers the rule.
+	AttributeValue *string `json:"value,omitempty"`
+	// ActionKey is the key of the action to run (e.g. "FieldSet").
+	ActionKey string `json:"action"`
+	// ActionConfig is the configuration handed over to the action.
+	ActionConfig string `json:"config"`
+	// Position defines the order in which the rules of a space template are
+	// executed.
+	Position int `json:"-"`
+}
+
+// TableName implements gorm.tabler
+func (r ActionRule) TableName() string {
+	return "work_item_action_rules"
+}
+
+// Validate ensures that the mandatory values of the rule are set.
+func (r ActionRule) Validate() error {
+	if r.Name == "" {
+		return errors.NewBadParameterError("name", r.Name).Expected("not empty")
+	}
+	if r.AttributeName == "" {
+		return errors.NewBadParameterError("attribute", r.AttributeName).Expected("not empty")
+	}
+	if r.ActionKey == "" {
+		return errors.NewBadParameterError("action", r.ActionKey).Expected("not empty")
+	}
+	return nil
+}
+
+// Matches returns true if the given work item type and the given change
+// trigger this rule.
+func (r ActionRule) Matches(witID uuid.UUID, c change.Change) bool {
+	if r.WorkItemTypeID != nil && *r.WorkItemTypeID != witID {
+		return false
+	}
+	if !r.watches(c.AttributeName) {
+		return false
+	}
+	if r.AttributeValue == nil {
+		return true
+	}
+	if c.NewValue == nil {
+		return false
+	}
+	return *r.AttributeValue == fmt.Sprintf("%v", c.NewValue)
+}
+
+// watches returns true if the given attribute is one of the attributes of the
+// rule.
+func (r ActionRule) watches(attributeName string) bool {
+	for _, name := range strings.Split(r.AttributeName, ",") {
+		if strings.TrimSpace(name) == attributeName {
+			return true
+		}
+	}
+	return false
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (r ActionRule) GetETagData() []interface{} {
+	return []interface{}{r.ID, r.UpdatedAt}
+}
+
+// GetLastModified returns the last modification time
+func (r ActionRule) GetLastModified() time.Time {
+	return r.UpdatedAt
+}
+
+// Ensure ActionRule implements the Equaler interface
+var _ convert.Equaler = ActionRule{}
+var _ convert.Equaler = (*ActionRule)(nil)
+
+// Equal returns true if two ActionRule objects are equal; otherwise false is
+// returned.
+func (r ActionRule) Equal(u convert.Equaler) bool {
+	other, ok := u.(ActionRule)
+	if !ok {
+		return false
+	}
+	if r.ID != other.ID {
+		return false
+	}
+	if r.SpaceTemplateID != other.SpaceTemplateID {
+		return false
+	}
+	if !convert.CascadeEqual(r.Lifecycle, other.Lifecycle) {
+		return false
+	}
+	if r.Name != other.Name {
+		return false
+	}
+	if !reflect.DeepEqual(r.Description, other.Description) {
+		return false
+	}
+	if !reflect.DeepEqual(r.WorkItemTypeID, other.WorkItemTypeID) {
+		return false
+	}
+	if r.AttributeName != other.AttributeName {
+		return false
+	}
+	if !reflect.DeepEqual(r.AttributeValue, other.AttributeValue) {
+		return false
+	}
+	if r.ActionKey != other.ActionKey {
+		return false
+	}
+	if r.ActionConfig != other.ActionConfig {
+		return false
+	}
+	if r.Position != other.Position {
+		return false
+	}
+	return true
+}
+
+// EqualValue implements convert.Equaler
+func (r ActionRule) EqualValue(u convert.Equaler) bool {
+	other, ok := u.(ActionRule)
+	if !ok {
+		return false
+	}
+	r.Lifecycle = other.Lifecycle
+	return r.Equal(u)
+}
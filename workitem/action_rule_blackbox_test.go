@@ -0,0 +1,60 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/actions/change"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionRule_Matches(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Parallel()
+	witID := uuid.NewV4()
+	stateChange := change.Change{
+		AttributeName: workitem.SystemState,
+		OldValue:      workitem.SystemStateOpen,
+		NewValue:      workitem.SystemStateClosed,
+	}
+
+	t.Run("any change of attribute", func(t *testing.T) {
+		r := workitem.ActionRule{AttributeName: workitem.SystemState}
+		require.True(t, r.Matches(witID, stateChange))
+	})
+	t.Run("change to specific value", func(t *testing.T) {
+		r := workitem.ActionRule{AttributeName: workitem.SystemState, AttributeValue: ptr.String(workitem.SystemStateClosed)}
+		require.True(t, r.Matches(witID, stateChange))
+	})
+	t.Run("change to other value", func(t *testing.T) {
+		r := workitem.ActionRule{AttributeName: workitem.SystemState, AttributeValue: ptr.String(workitem.SystemStateNew)}
+		require.False(t, r.Matches(witID, stateChange))
+	})
+	t.Run("other attribute", func(t *testing.T) {
+		r := workitem.ActionRule{AttributeName: workitem.SystemTitle}
+		require.False(t, r.Matches(witID, stateChange))
+	})
+	t.Run("one of several attributes", func(t *testing.T) {
+		r := workitem.ActionRule{AttributeName: workitem.SystemBoardcolumns + ", " + workitem.SystemState}
+		require.True(t, r.Matches(witID, stateChange))
+	})
+	t.Run("none of several attributes", func(t *testing.T) {
+		r := workitem.ActionRule{AttributeName: workitem.SystemBoardcolumns + ", " + workitem.SystemTitle}
+		require.False(t, r.Matches(witID, stateChange))
+	})
+	t.Run("matching work item type", func(t *testing.T) {
+		r := workitem.ActionRule{AttributeName: workitem.SystemState, WorkItemTypeID: &witID}
+		require.True(t, r.Matches(witID, stateChange))
+	})
+	t.Run("other work item type", func(t *testing.T) {
+		r := workitem.ActionRule{AttributeName: workitem.SystemState, WorkItemTypeID: &witID}
+		require.False(t, r.Matches(uuid.NewV4(), stateChange))
+	})
+	t.Run("value set to nil", func(t *testing.T) {
+		r := workitem.ActionRule{AttributeName: workitem.SystemState, AttributeValue: ptr.String(workitem.SystemStateClosed)}
+		require.False(t, r.Matches(witID, change.Change{AttributeName: workitem.SystemState}))
+	})
+}
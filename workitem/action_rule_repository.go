@@ -0,0 +1,114 @@
+package workitem
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/application/repository"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ActionRuleRepository encapsulates storage & retrieval of work item action
+// rules.
+type ActionRuleRepository interface {
+	repository.Exister
+	Create(ctx context.Context, rule ActionRule) (*ActionRule, error)
+	Load(ctx context.Context, ruleID uuid.UUID) (*ActionRule, error)
+	List(ctx context.Context, spaceTemplateID uuid.UUID) ([]*ActionRule, error)
+	ListBySpace(ctx context.Context, spaceID uuid.UUID) ([]*ActionRule, error)
+}
+
+// NewActionRuleRepository creates a work item action rule repository based on
+// gorm.
+func NewActionRuleRepository(db *gorm.DB) *GormActionRuleRepository {
+	return &GormActionRuleRepository{db}
+}
+
+// GormActionRuleRepository implements ActionRuleRepository using gorm.
+type GormActionRuleRepository struct {
+	db *gorm.DB
+}
+
+// Load returns the action rule for the given id.
+func (r *GormActionRuleRepository) Load(ctx context.Context, ruleID uuid.UUID) (*ActionRule, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemactionrule", "load"}, time.Now())
+	log.Debug(ctx, map[string]interface{}{"action_rule_id": ruleID}, "loading work item action rule")
+	res := ActionRule{}
+	db := r.db.Model(&res).Where("id=?", ruleID).First(&res)
+	if db.RecordNotFound() {
+		log.Error(ctx, map[string]interface{}{"action_rule_id": ruleID}, "work item action rule not found")
+		return nil, errors.NewNotFoundError("work item action rule", ruleID.String())
+	}
+	if err := db.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return &res, nil
+}
+
+// List returns all action rules for the given space template ID ordered by
+// their position value.
+func (r *GormActionRuleRepository) List(ctx context.Context, spaceTemplateID uuid.UUID) ([]*ActionRule, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemactionrule", "list"}, time.Now())
+	log.Debug(ctx, map[string]interface{}{"space_template_id": spaceTemplateID}, "loading work item action rules for space template")
+	// check space template exists
+	if err := spacetemplate.NewRepository(r.db).CheckExists(ctx, spaceTemplateID); err != nil {
+		return nil, errors.NewNotFoundError("space template", spaceTemplateID.String())
+	}
+	res := []*ActionRule{}
+	db := r.db.Model(&res).Where("space_template_id=?", spaceTemplateID).Order("position ASC").Find(&res)
+	if err := db.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return res, nil
+}
+
+// ListBySpace returns all action rules of the space template of the given
+// space ordered by their position value. Unlike List it doesn't check that
+// the space exists and loads the rules in a single query, which makes it
+// cheap enough to be called on every change of a work item.
+func (r *GormActionRuleRepository) ListBySpace(ctx context.Context, spaceID uuid.UUID) ([]*ActionRule, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemactionrule", "listbyspace"}, time.Now())
+	log.Debug(ctx, map[string]interface{}{"space_id": spaceID}, "loading work item action rules for space")
+	res := []*ActionRule{}
+	table := ActionRule{}.TableName()
+	db := r.db.Model(&res).
+		Select(table+".*").
+		Joins("JOIN spaces ON spaces.space_template_id = "+table+".space_template_id AND spaces.deleted_at IS NULL").
+		Where("spaces.id=?", spaceID).
+		Order(table + ".position ASC").
+		Find(&res)
+	if err := db.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return res, nil
+}
+
+// CheckExists returns nil if the given ID exists otherwise returns an error
+func (r *GormActionRuleRepository) CheckExists(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemactionrule", "exists"}, time.Now())
+	log.Debug(ctx, map[string]interface{}{"action_rule_id": id}, "checking if work item action rule exists")
+	return repository.CheckExists(ctx, r.db, ActionRule{}.TableName(), id)
+}
+
+// Create creates a new work item action rule in the repository
+func (r *GormActionRuleRepository) Create(ctx context.Context, rule ActionRule) (*ActionRule, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemactionrule", "create"}, time.Now())
+	if err := rule.Validate(); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if rule.ID == uuid.Nil {
+		rule.ID = uuid.NewV4()
+	}
+	db := r.db.Create(&rule)
+	if db.Error != nil {
+		return nil, errors.NewInternalError(ctx, db.Error)
+	}
+	log.Debug(ctx, map[string]interface{}{"action_rule_id": rule.ID}, "created work item action rule")
+	return &rule, nil
+}
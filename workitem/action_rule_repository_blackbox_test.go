@@ -0,0 +1,174 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type actionRuleRepoTest struct {
+	gormtestsupport.DBTestSuite
+	repo workitem.ActionRuleRepository
+}
+
+func TestActionRuleRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &actionRuleRepoTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *actionRuleRepoTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = workitem.NewActionRuleRepository(s.DB)
+}
+
+func (s *actionRuleRepoTest) TestCreate() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		expected := workitem.ActionRule{
+			ID:              uuid.NewV4(),
+			SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+			Name:            "close on resolve",
+			WorkItemTypeID:  &fxt.WorkItemTypes[0].ID,
+			AttributeName:   workitem.SystemState,
+			AttributeValue:  ptr.String(workitem.SystemStateResolved),
+			ActionKey:       "FieldSet",
+			ActionConfig:    `{ "system.state": "closed" }`,
+		}
+		// when
+		actual, err := s.repo.Create(s.Ctx, expected)
+		// then
+		require.NoError(t, err)
+		require.True(t, expected.EqualValue(*actual))
+		loaded, err := s.repo.Load(s.Ctx, expected.ID)
+		require.NoError(t, err)
+		require.True(t, expected.EqualValue(*loaded))
+	})
+
+	s.T().Run("invalid", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.SpaceTemplates(1))
+		rule := workitem.ActionRule{
+			SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+			Name:            "no attribute",
+			ActionKey:       "Nil",
+		}
+		// when
+		_, err := s.repo.Create(s.Ctx, rule)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (s *actionRuleRepoTest) TestList() {
+	s.T().Run("ordered by position", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.SpaceTemplates(1))
+		for _, pos := range []int{2, 0, 1} {
+			_, err := s.repo.Create(s.Ctx, workitem.ActionRule{
+				SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+				Name:            uuid.NewV4().String(),
+				AttributeName:   workitem.SystemState,
+				ActionKey:       "Nil",
+				Position:        pos,
+			})
+			require.NoError(t, err)
+		}
+		// when
+		rules, err := s.repo.List(s.Ctx, fxt.SpaceTemplates[0].ID)
+		// then
+		require.NoError(t, err)
+		require.Len(t, rules, 3)
+		for i, rule := range rules {
+			require.Equal(t, i, rule.Position)
+		}
+	})
+
+	s.T().Run("empty list for template without rules", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.SpaceTemplates(1))
+		// when
+		rules, err := s.repo.List(s.Ctx, fxt.SpaceTemplates[0].ID)
+		// then
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+
+	s.T().Run("not existing space template", func(t *testing.T) {
+		// when
+		_, err := s.repo.List(s.Ctx, uuid.NewV4())
+		// then
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *actionRuleRepoTest) TestListBySpace() {
+	s.T().Run("rules of the space template of the space", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		for _, pos := range []int{1, 0} {
+			_, err := s.repo.Create(s.Ctx, workitem.ActionRule{
+				SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+				Name:            uuid.NewV4().String(),
+				AttributeName:   workitem.SystemState,
+				ActionKey:       "Nil",
+				Position:        pos,
+			})
+			require.NoError(t, err)
+		}
+		// when
+		rules, err := s.repo.ListBySpace(s.Ctx, fxt.Spaces[0].ID)
+		// then
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		for i, rule := range rules {
+			require.Equal(t, i, rule.Position)
+			require.Equal(t, fxt.SpaceTemplates[0].ID, rule.SpaceTemplateID)
+		}
+	})
+
+	s.T().Run("empty list for space without rules", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		// when
+		rules, err := s.repo.ListBySpace(s.Ctx, fxt.Spaces[0].ID)
+		// then
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+}
+
+func (s *actionRuleRepoTest) TestExists() {
+	s.T().Run("action rule exists", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.SpaceTemplates(1))
+		rule, err := s.repo.Create(s.Ctx, workitem.ActionRule{
+			SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+			Name:            "some rule",
+			AttributeName:   workitem.SystemState,
+			ActionKey:       "Nil",
+		})
+		require.NoError(t, err)
+		// when
+		err = s.repo.CheckExists(s.Ctx, rule.ID)
+		// then
+		require.NoError(t, err)
+	})
+
+	s.T().Run("action rule doesn't exist", func(t *testing.T) {
+		// when
+		err := s.repo.CheckExists(s.Ctx, uuid.NewV4())
+		// then
+		require.IsType(t, errors.NotFoundError{}, err)
+	})
+}
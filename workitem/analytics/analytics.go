@@ -0,0 +1,157 @@
+// Package analytics computes the cumulative flow and the lead and cycle times
+// of the work items of a space from the state transitions recorded in the
+// revisions of the work items.
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeAnalytics represents the type of the analytics of a space
+const APIStringTypeAnalytics = "spaceanalytics"
+
+// Report holds the analytics of the work items of a space between Start and
+// End
+type Report struct {
+	Start          time.Time
+	End            time.Time
+	CumulativeFlow []CumulativeFlowPoint
+	FlowTimes      []FlowTimes
+}
+
+// CumulativeFlowPoint holds the number of work items per state at a point in
+// time
+type CumulativeFlowPoint struct {
+	Time   time.Time
+	Counts map[string]int
+}
+
+// FlowTimes holds the lead and cycle times of the work items of a type that
+// were closed between the start and the end of a report
+type FlowTimes struct {
+	WorkItemTypeID uuid.UUID
+	// LeadTime is the time from the creation of a work item until it is
+	// closed
+	LeadTime Percentiles
+	// CycleTime is the time from the first time a work item is in progress
+	// until it is closed. Work items that were never in progress are left
+	// out.
+	CycleTime Percentiles
+}
+
+// Percentiles holds the number and the percentiles of a set of durations
+type Percentiles struct {
+	Count int
+	P50   time.Duration
+	P85   time.Duration
+	P95   time.Duration
+}
+
+// stateChange is a revision of a work item reduced to its state
+type stateChange struct {
+	WorkItemID     uuid.UUID
+	WorkItemTypeID uuid.UUID
+	Time           time.Time
+	Type           workitem.RevisionType
+	State          string
+}
+
+// newPercentiles returns the percentiles of the given durations using the
+// nearest-rank method
+func newPercentiles(durations []time.Duration) Percentiles {
+	res := Percentiles{Count: len(durations)}
+	if len(durations) == 0 {
+		return res
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	rank := func(p float64) time.Duration {
+		return durations[int(math.Ceil(p/100*float64(len(durations))))-1]
+	}
+	res.P50, res.P85, res.P95 = rank(50), rank(85), rank(95)
+	return res
+}
+
+// computeReport computes the report from the given state changes, which are
+// ordered by work item and time. Only work items of the given types are
+// considered unless types is nil. The given meta-state mappings of the work
+// item types tell which states are in progress or closed.
+func computeReport(changes []stateChange, types map[uuid.UUID]bool, mappings map[uuid.UUID]workitem.MetaStateMapping, start, end time.Time) *Report {
+	inTypes := func(c stateChange) bool {
+		return types == nil || types[c.WorkItemTypeID]
+	}
+	times := workitem.DailyTimes(start, end)
+	res := &Report{
+		Start:          start,
+		End:            end,
+		CumulativeFlow: make([]CumulativeFlowPoint, len(times)),
+		FlowTimes:      []FlowTimes{},
+	}
+	for i, t := range times {
+		res.CumulativeFlow[i] = CumulativeFlowPoint{Time: t, Counts: map[string]int{}}
+	}
+	leadTimes := map[uuid.UUID][]time.Duration{}
+	cycleTimes := map[uuid.UUID][]time.Duration{}
+	for from := 0; from < len(changes); {
+		to := from + 1
+		for to < len(changes) && changes[to].WorkItemID == changes[from].WorkItemID {
+			to++
+		}
+		workItemChanges := changes[from:to]
+		from = to
+
+		// the state of the work item at each point in time
+		j := -1
+		for i, t := range times {
+			for j+1 < len(workItemChanges) && !workItemChanges[j+1].Time.After(t) {
+				j++
+			}
+			if j < 0 {
+				continue
+			}
+			c := workItemChanges[j]
+			if c.Type != workitem.RevisionTypeDelete && c.State != "" && inTypes(c) {
+				res.CumulativeFlow[i].Counts[c.State]++
+			}
+		}
+
+		// the last time the work item was closed within the date range
+		created := workItemChanges[0].Time
+		var inProgress *time.Time
+		var closed *stateChange
+		wasClosed := false
+		for k, c := range workItemChanges {
+			mapping := mappings[c.WorkItemTypeID]
+			if mapping.IsInProgress(c.State) && inProgress == nil {
+				inProgress = &workItemChanges[k].Time
+			}
+			isClosed := mapping.IsClosed(c.State)
+			if isClosed && !wasClosed && !c.Time.Before(start) && !c.Time.After(end) {
+				closed = &workItemChanges[k]
+			}
+			wasClosed = isClosed
+		}
+		if closed == nil || !inTypes(*closed) {
+			continue
+		}
+		leadTimes[closed.WorkItemTypeID] = append(leadTimes[closed.WorkItemTypeID], closed.Time.Sub(created))
+		if inProgress != nil && inProgress.Before(closed.Time) {
+			cycleTimes[closed.WorkItemTypeID] = append(cycleTimes[closed.WorkItemTypeID], closed.Time.Sub(*inProgress))
+		}
+	}
+	for typeID, durations := range leadTimes {
+		res.FlowTimes = append(res.FlowTimes, FlowTimes{
+			WorkItemTypeID: typeID,
+			LeadTime:       newPercentiles(durations),
+			CycleTime:      newPercentiles(cycleTimes[typeID]),
+		})
+	}
+	sort.Slice(res.FlowTimes, func(i, j int) bool {
+		return res.FlowTimes[i].WorkItemTypeID.String() < res.FlowTimes[j].WorkItemTypeID.String()
+	})
+	return res
+}
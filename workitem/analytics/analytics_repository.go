@@ -0,0 +1,158 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/closeable"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Repository encapsulates the computation of the analytics of work items
+type Repository interface {
+	// Load returns the cumulative flow and the lead and cycle times of the
+	// work items of the given space between start and end. Only the work
+	// items of the types in the given type group are considered unless the
+	// type group is nil.
+	Load(ctx context.Context, spaceID uuid.UUID, typeGroupID *uuid.UUID, start, end time.Time) (*Report, error)
+}
+
+// NewRepository creates a work item analytics repository based on gorm
+func NewRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db:            db,
+		spaceRepo:     space.NewRepository(db),
+		typeGroupRepo: workitem.NewWorkItemTypeGroupRepository(db),
+		witRepo:       workitem.NewWorkItemTypeRepository(db),
+	}
+}
+
+// GormRepository implements Repository using gorm
+type GormRepository struct {
+	db            *gorm.DB
+	spaceRepo     *space.GormRepository
+	typeGroupRepo *workitem.GormWorkItemTypeGroupRepository
+	witRepo       *workitem.GormWorkItemTypeRepository
+}
+
+// Load implements Repository interface
+func (r *GormRepository) Load(ctx context.Context, spaceID uuid.UUID, typeGroupID *uuid.UUID, start, end time.Time) (*Report, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "analytics", "load"}, time.Now())
+	if !start.Before(end) {
+		return nil, errors.NewBadParameterError("end", end).Expected(fmt.Sprintf("a time after %s", start))
+	}
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+	s, err := r.spaceRepo.Load(ctx, spaceID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	var types map[uuid.UUID]bool
+	if typeGroupID != nil {
+		group, err := r.typeGroupRepo.Load(ctx, *typeGroupID)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		if group.SpaceTemplateID != s.SpaceTemplateID {
+			return nil, errors.NewBadParameterError("typegroup", *typeGroupID).Expected("a type group of the space template of the space")
+		}
+		types = map[uuid.UUID]bool{}
+		for _, id := range group.TypeList {
+			types[id] = true
+		}
+	}
+	changes, err := r.listStateChanges(ctx, spaceID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	mappings := map[uuid.UUID]workitem.MetaStateMapping{}
+	for _, c := range changes {
+		if _, ok := mappings[c.WorkItemTypeID]; ok {
+			continue
+		}
+		wit, err := r.witRepo.Load(ctx, c.WorkItemTypeID)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to load work item type %s", c.WorkItemTypeID)
+		}
+		mapping, err := wit.MetaStateMapping()
+		if err != nil {
+			// none of the states of the type counts as in progress or
+			// closed
+			log.Info(ctx, map[string]interface{}{
+				"wit_id": c.WorkItemTypeID,
+				"err":    err,
+			}, "work item type has no meta-state mapping")
+			mapping = workitem.MetaStateMapping{}
+		}
+		mappings[c.WorkItemTypeID] = mapping
+	}
+	return computeReport(changes, types, mappings, start, end), nil
+}
+
+// listStateChanges returns the states of the work items of the given space
+// according to their revisions between start and end, ordered by work item and
+// time. Of the revisions before start, only the ones that the report needs
+// are returned: the first revision of every work item for its lead time, its
+// first revision in progress for its cycle time and its latest revision for
+// its state at the start.
+func (r *GormRepository) listStateChanges(ctx context.Context, spaceID uuid.UUID, start, end time.Time) ([]stateChange, error) {
+	query := fmt.Sprintf(`SELECT work_item_id, work_item_type_id, revision_time, revision_type, state FROM (
+			(SELECT DISTINCT ON (r.work_item_id) %[1]s
+			FROM %[2]s r JOIN %[3]s wi ON wi.id = r.work_item_id
+			WHERE wi.space_id = $1 AND r.revision_time < $2
+			ORDER BY r.work_item_id, r.revision_time)
+		UNION
+			(SELECT DISTINCT ON (r.work_item_id) %[1]s
+			FROM %[2]s r JOIN %[3]s wi ON wi.id = r.work_item_id
+			WHERE wi.space_id = $1 AND r.revision_time < $2 AND %[4]s
+			ORDER BY r.work_item_id, r.revision_time)
+		UNION
+			(SELECT DISTINCT ON (r.work_item_id) %[1]s
+			FROM %[2]s r JOIN %[3]s wi ON wi.id = r.work_item_id
+			WHERE wi.space_id = $1 AND r.revision_time < $2
+			ORDER BY r.work_item_id, r.revision_time DESC)
+		UNION
+			(SELECT %[1]s
+			FROM %[2]s r JOIN %[3]s wi ON wi.id = r.work_item_id
+			WHERE wi.space_id = $1 AND r.revision_time >= $2 AND r.revision_time <= $3)
+		) AS changes
+		ORDER BY work_item_id, revision_time`,
+		fmt.Sprintf("r.id, r.work_item_id, r.work_item_type_id, r.revision_time, r.revision_type, r.work_item_fields->>'%s' AS state", workitem.SystemState),
+		workitem.Revision{}.TableName(),
+		workitem.WorkItemStorage{}.TableName(),
+		workitem.MetaStateCondition("r.work_item_type_id", "r.work_item_fields", workitem.SystemMetaStateInProgress),
+	)
+	rows, err := r.db.Raw(query, spaceID, start, end).Rows()
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"space_id": spaceID,
+			"err":      err,
+		}, "unable to list the revisions of the work items of the space")
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list the revisions of the work items of space %s", spaceID))
+	}
+	defer closeable.Close(ctx, rows)
+	changes := []stateChange{}
+	for rows.Next() {
+		var c stateChange
+		var state sql.NullString
+		if err := rows.Scan(&c.WorkItemID, &c.WorkItemTypeID, &c.Time, &c.Type, &state); err != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to scan the revisions of the work items"))
+		}
+		c.State = state.String
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to list the revisions of the work items"))
+	}
+	return changes, nil
+}
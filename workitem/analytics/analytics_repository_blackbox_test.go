@@ -0,0 +1,136 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/analytics"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type analyticsRepoBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo   analytics.Repository
+	wiRepo workitem.WorkItemRepository
+}
+
+func TestRunAnalyticsRepoBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &analyticsRepoBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *analyticsRepoBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = analytics.NewRepository(s.DB)
+	s.wiRepo = workitem.NewWorkItemRepository(s.DB)
+}
+
+func (s *analyticsRepoBlackBoxTest) TestLoad() {
+	now := time.Now().UTC()
+	start := now.Add(-48 * time.Hour)
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItems(2, tf.SetWorkItemField(workitem.SystemState, workitem.SystemStateNew, workitem.SystemStateNew)),
+		tf.WorkItemTypeGroups(1),
+	)
+	// setRevisionTime moves the latest revision of the given work item to the
+	// given time
+	setRevisionTime := func(id uuid.UUID, t time.Time) {
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, id)
+		require.NoError(s.T(), err)
+		require.NoError(s.T(), s.DB.Exec("UPDATE work_item_revisions SET revision_time = ? WHERE id = ?", t, revisions[len(revisions)-1].ID).Error)
+	}
+	// both work items exist before the date range starts
+	for _, wi := range fxt.WorkItems {
+		setRevisionTime(wi.ID, start.Add(-24*time.Hour))
+	}
+	// the first one is in progress one hour after the start and closed two
+	// hours later
+	for i, state := range []string{workitem.SystemStateInProgress, workitem.SystemStateClosed} {
+		fxt.WorkItems[0].Fields[workitem.SystemState] = state
+		wi, _, err := s.wiRepo.Save(s.Ctx, fxt.Spaces[0].ID, *fxt.WorkItems[0], fxt.Identities[0].ID)
+		require.NoError(s.T(), err)
+		fxt.WorkItems[0] = wi
+		setRevisionTime(wi.ID, start.Add(time.Duration(1+2*i)*time.Hour))
+	}
+
+	s.T().Run("ok", func(t *testing.T) {
+		// when
+		report, err := s.repo.Load(s.Ctx, fxt.Spaces[0].ID, nil, start, now)
+		// then
+		require.NoError(t, err)
+		require.NotEmpty(t, report.CumulativeFlow)
+		assert.Equal(t, map[string]int{workitem.SystemStateNew: 2}, report.CumulativeFlow[0].Counts)
+		assert.Equal(t, map[string]int{workitem.SystemStateNew: 1, workitem.SystemStateClosed: 1}, report.CumulativeFlow[len(report.CumulativeFlow)-1].Counts)
+		require.Len(t, report.FlowTimes, 1)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, report.FlowTimes[0].WorkItemTypeID)
+		assert.Equal(t, analytics.Percentiles{Count: 1, P50: 27 * time.Hour, P85: 27 * time.Hour, P95: 27 * time.Hour}, report.FlowTimes[0].LeadTime)
+		assert.Equal(t, analytics.Percentiles{Count: 1, P50: 2 * time.Hour, P85: 2 * time.Hour, P95: 2 * time.Hour}, report.FlowTimes[0].CycleTime)
+	})
+
+	s.T().Run("ok - type group", func(t *testing.T) {
+		// when
+		report, err := s.repo.Load(s.Ctx, fxt.Spaces[0].ID, &fxt.WorkItemTypeGroups[0].ID, start, now)
+		// then
+		require.NoError(t, err)
+		require.NotEmpty(t, report.CumulativeFlow)
+		assert.Equal(t, map[string]int{workitem.SystemStateNew: 2}, report.CumulativeFlow[0].Counts)
+		require.Len(t, report.FlowTimes, 1)
+	})
+
+	s.T().Run("ok - work item in progress before the start", func(t *testing.T) {
+		// when
+		report, err := s.repo.Load(s.Ctx, fxt.Spaces[0].ID, nil, start.Add(2*time.Hour), now)
+		// then
+		require.NoError(t, err)
+		require.NotEmpty(t, report.CumulativeFlow)
+		assert.Equal(t, map[string]int{workitem.SystemStateNew: 1, workitem.SystemStateInProgress: 1}, report.CumulativeFlow[0].Counts)
+		require.Len(t, report.FlowTimes, 1)
+		assert.Equal(t, analytics.Percentiles{Count: 1, P50: 27 * time.Hour, P85: 27 * time.Hour, P95: 27 * time.Hour}, report.FlowTimes[0].LeadTime)
+		assert.Equal(t, analytics.Percentiles{Count: 1, P50: 2 * time.Hour, P85: 2 * time.Hour, P95: 2 * time.Hour}, report.FlowTimes[0].CycleTime)
+	})
+
+	s.T().Run("end before start", func(t *testing.T) {
+		// when
+		_, err := s.repo.Load(s.Ctx, fxt.Spaces[0].ID, nil, now, start)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("unknown space", func(t *testing.T) {
+		// when
+		_, err := s.repo.Load(s.Ctx, uuid.NewV4(), nil, start, now)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+
+	s.T().Run("unknown type group", func(t *testing.T) {
+		// given
+		id := uuid.NewV4()
+		// when
+		_, err := s.repo.Load(s.Ctx, fxt.Spaces[0].ID, &id, start, now)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+
+	s.T().Run("type group of another space template", func(t *testing.T) {
+		// given
+		otherFxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypeGroups(1))
+		// when
+		_, err := s.repo.Load(s.Ctx, fxt.Spaces[0].ID, &otherFxt.WorkItemTypeGroups[0].ID, start, now)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
@@ -0,0 +1,107 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPercentiles(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Run("no durations", func(t *testing.T) {
+		assert.Equal(t, Percentiles{}, newPercentiles(nil))
+	})
+	t.Run("nearest rank", func(t *testing.T) {
+		// given the durations from 20 hours down to 1 hour
+		var durations []time.Duration
+		for i := 20; i > 0; i-- {
+			durations = append(durations, time.Duration(i)*time.Hour)
+		}
+		// when
+		p := newPercentiles(durations)
+		// then
+		assert.Equal(t, Percentiles{Count: 20, P50: 10 * time.Hour, P85: 17 * time.Hour, P95: 19 * time.Hour}, p)
+	})
+}
+
+func TestComputeReport(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	day := func(d, h int) time.Time {
+		return time.Date(2018, time.January, d, h, 0, 0, 0, time.UTC)
+	}
+	typeA, typeB := uuid.NewV4(), uuid.NewV4()
+	a, b, c, d := uuid.NewV4(), uuid.NewV4(), uuid.NewV4(), uuid.NewV4()
+	changes := []stateChange{
+		// a is in progress on the first day and closed on the second day
+		{WorkItemID: a, WorkItemTypeID: typeA, Time: day(0, 0), Type: workitem.RevisionTypeCreate, State: workitem.SystemStateNew},
+		{WorkItemID: a, WorkItemTypeID: typeA, Time: day(1, 18), Type: workitem.RevisionTypeUpdate, State: workitem.SystemStateInProgress},
+		{WorkItemID: a, WorkItemTypeID: typeA, Time: day(2, 6), Type: workitem.RevisionTypeUpdate, State: workitem.SystemStateClosed},
+		// b is created on the first day and closed on the third day without
+		// ever being in progress
+		{WorkItemID: b, WorkItemTypeID: typeA, Time: day(1, 13), Type: workitem.RevisionTypeCreate, State: workitem.SystemStateNew},
+		{WorkItemID: b, WorkItemTypeID: typeA, Time: day(3, 10), Type: workitem.RevisionTypeUpdate, State: workitem.SystemStateClosed},
+		// c is deleted on the second day
+		{WorkItemID: c, WorkItemTypeID: typeB, Time: day(-1, 0), Type: workitem.RevisionTypeCreate, State: workitem.SystemStateNew},
+		{WorkItemID: c, WorkItemTypeID: typeB, Time: day(2, 12), Type: workitem.RevisionTypeDelete},
+		// d was closed before the date range
+		{WorkItemID: d, WorkItemTypeID: typeB, Time: day(-20, 0), Type: workitem.RevisionTypeCreate, State: workitem.SystemStateInProgress},
+		{WorkItemID: d, WorkItemTypeID: typeB, Time: day(-15, 0), Type: workitem.RevisionTypeUpdate, State: workitem.SystemStateClosed},
+	}
+	start, end := day(1, 12), day(4, 0)
+	mapping := workitem.MetaStateMapping{
+		workitem.SystemStateNew:        workitem.SystemMetaStateNew,
+		workitem.SystemStateInProgress: workitem.SystemMetaStateInProgress,
+		workitem.SystemStateClosed:     workitem.SystemMetaStateClosed,
+	}
+	mappings := map[uuid.UUID]workitem.MetaStateMapping{typeA: mapping, typeB: mapping}
+
+	t.Run("all types", func(t *testing.T) {
+		// when
+		report := computeReport(changes, nil, mappings, start, end)
+		// then
+		require.Len(t, report.CumulativeFlow, 4)
+		expected := []CumulativeFlowPoint{
+			{Time: day(1, 12), Counts: map[string]int{workitem.SystemStateNew: 2, workitem.SystemStateClosed: 1}},
+			{Time: day(2, 0), Counts: map[string]int{workitem.SystemStateNew: 2, workitem.SystemStateInProgress: 1, workitem.SystemStateClosed: 1}},
+			{Time: day(3, 0), Counts: map[string]int{workitem.SystemStateNew: 1, workitem.SystemStateClosed: 2}},
+			{Time: day(4, 0), Counts: map[string]int{workitem.SystemStateClosed: 3}},
+		}
+		assert.Equal(t, expected, report.CumulativeFlow)
+		require.Len(t, report.FlowTimes, 1)
+		assert.Equal(t, FlowTimes{
+			WorkItemTypeID: typeA,
+			LeadTime:       Percentiles{Count: 2, P50: 45 * time.Hour, P85: 54 * time.Hour, P95: 54 * time.Hour},
+			CycleTime:      Percentiles{Count: 1, P50: 12 * time.Hour, P85: 12 * time.Hour, P95: 12 * time.Hour},
+		}, report.FlowTimes[0])
+	})
+
+	t.Run("type group", func(t *testing.T) {
+		// when
+		report := computeReport(changes, map[uuid.UUID]bool{typeB: true}, mappings, start, end)
+		// then
+		require.Len(t, report.CumulativeFlow, 4)
+		assert.Equal(t, map[string]int{workitem.SystemStateNew: 1, workitem.SystemStateClosed: 1}, report.CumulativeFlow[0].Counts)
+		assert.Equal(t, map[string]int{workitem.SystemStateClosed: 1}, report.CumulativeFlow[3].Counts)
+		assert.Empty(t, report.FlowTimes)
+	})
+	t.Run("closed state of another name", func(t *testing.T) {
+		// given a type whose closed meta-state is called done
+		mappings := map[uuid.UUID]workitem.MetaStateMapping{
+			typeA: {workitem.SystemStateNew: workitem.SystemMetaStateNew, "done": workitem.SystemMetaStateClosed},
+		}
+		changes := []stateChange{
+			{WorkItemID: a, WorkItemTypeID: typeA, Time: day(1, 0), Type: workitem.RevisionTypeCreate, State: workitem.SystemStateNew},
+			{WorkItemID: a, WorkItemTypeID: typeA, Time: day(2, 0), Type: workitem.RevisionTypeUpdate, State: "done"},
+		}
+		// when
+		report := computeReport(changes, nil, mappings, start, end)
+		// then
+		require.Len(t, report.FlowTimes, 1)
+		assert.Equal(t, Percentiles{Count: 1, P50: 24 * time.Hour, P85: 24 * time.Hour, P95: 24 * time.Hour}, report.FlowTimes[0].LeadTime)
+	})
+}
@@ -15,6 +15,9 @@ type Event struct {
 	Modifier       uuid.UUID
 	Old            interface{}
 	New            interface{}
+	// TargetID is the ID of the link or comment of a link or comment event.
+	// It is uuid.Nil for all other events.
+	TargetID uuid.UUID
 }
 
 // GetETagData returns the field values to use to generate the ETag
@@ -3,14 +3,19 @@ package event
 import (
 	"context"
 	"reflect"
+	"sort"
+	"time"
 
 	"github.com/jinzhu/gorm"
 	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 
 	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/criteria"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 )
 
 // APIStringTypeEvents represent the type of event
@@ -19,10 +24,26 @@ const APIStringTypeEvents = "events"
 // WorkitemTypeChangeEvent represents the attribute name for type change event
 const WorkitemTypeChangeEvent = "workitemtype"
 
+// The names of the events for the links and comments of a work item
+const (
+	LinkAddedEvent      = "link.added"
+	LinkRemovedEvent    = "link.removed"
+	CommentAddedEvent   = "comment.added"
+	CommentEditedEvent  = "comment.edited"
+	CommentDeletedEvent = "comment.deleted"
+)
+
 // Repository encapsulates retrieval of work item events
 type Repository interface {
-	// List returns all events for a work item.
+	// List returns all events for a work item, including the changes of its
+	// links and comments, ordered by time.
 	List(ctx context.Context, wiID uuid.UUID) (List, error)
+	// ListChanges returns the changes of a space after the given position.
+	ListChanges(ctx context.Context, spaceID uuid.UUID, filter criteria.Expression, after *Position, limit int) ([]Change, error)
+	// ChangePosition returns the position of a change in a space.
+	ChangePosition(ctx context.Context, spaceID uuid.UUID, changeID uuid.UUID) (*Position, error)
+	// LatestPosition returns the position of the latest change in a space.
+	LatestPosition(ctx context.Context, spaceID uuid.UUID) (*Position, error)
 }
 
 // NewEventRepository creates a work item event repository based on gorm
@@ -60,125 +81,238 @@ func (r *GormEventRepository) List(ctx context.Context, wiID uuid.UUID) (List, e
 
 	eventList := List{}
 	for k := 1; k < len(revisionList); k++ {
-
-		oldRev := revisionList[k-1]
-		newRev := revisionList[k]
-
-		wit, err := r.workItemTypeRepo.Load(ctx, oldRev.WorkItemTypeID)
+		events, err := r.diff(ctx, revisionList[k-1], revisionList[k])
 		if err != nil {
-			return nil, errs.Wrapf(err, "failed to load old work item type: %s", oldRev.WorkItemTypeID)
+			return nil, err
 		}
+		eventList = append(eventList, events...)
+	}
 
-		modifierID, err := r.identityRepo.Load(ctx, newRev.ModifierIdentity)
-		if err != nil {
-			return nil, errs.Wrapf(err, "failed to load modifier identity %s", newRev.ModifierIdentity)
+	linkEvents, err := r.linkEvents(ctx, wiID, revisionList)
+	if err != nil {
+		return nil, err
+	}
+	commentEvents, err := r.commentEvents(ctx, wiID, revisionList)
+	if err != nil {
+		return nil, err
+	}
+	eventList = append(eventList, linkEvents...)
+	eventList = append(eventList, commentEvents...)
+	sort.SliceStable(eventList, func(i, j int) bool {
+		return eventList[i].Timestamp.Before(eventList[j].Timestamp)
+	})
+	return eventList, nil
+}
+
+// workItemTypeAt returns the type of a work item at the given time according
+// to the given revisions of the work item, which are ordered by time.
+func workItemTypeAt(revisions []workitem.Revision, t time.Time) uuid.UUID {
+	if len(revisions) == 0 {
+		return uuid.Nil
+	}
+	res := revisions[0].WorkItemTypeID
+	for _, rev := range revisions {
+		if rev.Time.After(t) {
+			break
 		}
+		res = rev.WorkItemTypeID
+	}
+	return res
+}
 
-		// TODO(kwk): make sure we have a proper "changed work item type"
-		// revision entry in one way or another.
-		// TODO(ibrahim): type change event should have more information than just the new and old type IDs
-		if oldRev.WorkItemTypeID != newRev.WorkItemTypeID {
-			event := Event{
-				RevisionID:     newRev.ID,
-				Name:           WorkitemTypeChangeEvent,
-				WorkItemTypeID: newRev.WorkItemTypeID,
-				Timestamp:      newRev.Time,
-				Modifier:       modifierID.ID,
-				Old:            oldRev.WorkItemTypeID,
-				New:            newRev.WorkItemTypeID,
-			}
-			eventList = append(eventList, event)
-			// We do not compare any of the fields since this is a type change event.
+// linkEvents returns the events for the links that were added to or removed
+// from the given work item. The old or new value of an event is the ID of the
+// work item at the other end of the link.
+func (r *GormEventRepository) linkEvents(ctx context.Context, wiID uuid.UUID, revisions []workitem.Revision) (List, error) {
+	var linkRevisions []link.Revision
+	err := r.db.Where("work_item_link_source_id = ? OR work_item_link_target_id = ?", wiID, wiID).Order("revision_time asc").Find(&linkRevisions).Error
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list the link revisions of work item %s", wiID))
+	}
+	eventList := List{}
+	for _, rev := range linkRevisions {
+		other := rev.WorkItemLinkTargetID
+		if other == wiID {
+			other = rev.WorkItemLinkSourceID
+		}
+		event := Event{
+			RevisionID:     rev.ID,
+			WorkItemTypeID: workItemTypeAt(revisions, rev.Time),
+			Timestamp:      rev.Time,
+			Modifier:       rev.ModifierIdentity,
+			TargetID:       rev.WorkItemLinkID,
+		}
+		switch rev.Type {
+		case link.RevisionTypeCreate:
+			event.Name = LinkAddedEvent
+			event.New = other
+		case link.RevisionTypeDelete:
+			event.Name = LinkRemovedEvent
+			event.Old = other
+		default:
+			// links are no longer updated
 			continue
 		}
+		eventList = append(eventList, event)
+	}
+	return eventList, nil
+}
 
-		for fieldName, fieldDef := range wit.Fields {
+// commentEvents returns the events for the comments that were added to,
+// edited or deleted from the given work item. The old and new values of an
+// event are the bodies of the comment before and after the change.
+func (r *GormEventRepository) commentEvents(ctx context.Context, wiID uuid.UUID, revisions []workitem.Revision) (List, error) {
+	var commentRevisions []comment.Revision
+	err := r.db.Where("comment_parent_id = ?", wiID).Order("revision_time asc").Find(&commentRevisions).Error
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list the comment revisions of work item %s", wiID))
+	}
+	eventList := List{}
+	bodies := map[uuid.UUID]*string{}
+	for _, rev := range commentRevisions {
+		event := Event{
+			RevisionID:     rev.ID,
+			WorkItemTypeID: workItemTypeAt(revisions, rev.Time),
+			Timestamp:      rev.Time,
+			Modifier:       rev.ModifierIdentity,
+			TargetID:       rev.CommentID,
+		}
+		switch rev.Type {
+		case comment.RevisionTypeCreate:
+			event.Name = CommentAddedEvent
+		case comment.RevisionTypeUpdate:
+			event.Name = CommentEditedEvent
+		case comment.RevisionTypeDelete:
+			event.Name = CommentDeletedEvent
+		}
+		if old := bodies[rev.CommentID]; old != nil {
+			event.Old = *old
+		}
+		if rev.CommentBody != nil {
+			event.New = *rev.CommentBody
+		}
+		bodies[rev.CommentID] = rev.CommentBody
+		eventList = append(eventList, event)
+	}
+	return eventList, nil
+}
 
-			oldVal := oldRev.WorkItemFields[fieldName]
-			newVal := newRev.WorkItemFields[fieldName]
+// diff returns the events that happened between the two given revisions of a
+// work item
+func (r *GormEventRepository) diff(ctx context.Context, oldRev, newRev workitem.Revision) (List, error) {
+	wit, err := r.workItemTypeRepo.Load(ctx, oldRev.WorkItemTypeID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load old work item type: %s", oldRev.WorkItemTypeID)
+	}
 
-			event := Event{
-				RevisionID:     newRev.ID,
-				Name:           fieldName,
-				WorkItemTypeID: newRev.WorkItemTypeID,
-				Timestamp:      newRev.Time,
-				Modifier:       modifierID.ID,
-				Old:            oldVal,
-				New:            newVal,
-			}
+	modifierID, err := r.identityRepo.Load(ctx, newRev.ModifierIdentity)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load modifier identity %s", newRev.ModifierIdentity)
+	}
 
-			// The enum type can be handled by the simple type since it's just a
-			// single value after all.
-			ft := fieldDef.Type
-			enumType, isEnumType := ft.(workitem.EnumType)
-			if isEnumType {
-				ft = enumType.BaseType
-			}
+	// TODO(kwk): make sure we have a proper "changed work item type"
+	// revision entry in one way or another.
+	// TODO(ibrahim): type change event should have more information than just the new and old type IDs
+	if oldRev.WorkItemTypeID != newRev.WorkItemTypeID {
+		event := Event{
+			RevisionID:     newRev.ID,
+			Name:           WorkitemTypeChangeEvent,
+			WorkItemTypeID: newRev.WorkItemTypeID,
+			Timestamp:      newRev.Time,
+			Modifier:       modifierID.ID,
+			Old:            oldRev.WorkItemTypeID,
+			New:            newRev.WorkItemTypeID,
+		}
+		// We do not compare any of the fields since this is a type change event.
+		return List{event}, nil
+	}
 
-			switch fieldType := ft.(type) {
-			case workitem.ListType:
-				var p, n []interface{}
-				var ok bool
-
-				switch t := oldVal.(type) {
-				case nil:
-					p = []interface{}{}
-				case []interface{}:
-					converted, err := fieldType.ConvertFromModel(t)
-					if err != nil {
-						return nil, errs.Wrapf(err, "failed to convert old value for field %s from storage representation: %+v", fieldName, t)
-					}
-					p, ok = converted.([]interface{})
-					if !ok {
-						return nil, errs.Errorf("failed to convert old value for field %s from to []interface{}: %+v", fieldName, t)
-					}
-				}
+	eventList := List{}
+	for fieldName, fieldDef := range wit.Fields {
 
-				switch t := newVal.(type) {
-				case nil:
-					n = []interface{}{}
-				case []interface{}:
-					converted, err := fieldType.ConvertFromModel(t)
-					if err != nil {
-						return nil, errs.Wrapf(err, "failed to convert new value for field %s from storage representation: %+v", fieldName, t)
-					}
-					n, ok = converted.([]interface{})
-					if !ok {
-						return nil, errs.Errorf("failed to convert new value for field %s from to []interface{}: %+v", fieldName, t)
-					}
-				}
+		oldVal := oldRev.WorkItemFields[fieldName]
+		newVal := newRev.WorkItemFields[fieldName]
 
-				// Avoid duplicate entries for empty labels or assignees, etc.
-				if !reflect.DeepEqual(p, n) {
-					event.Old = p
-					event.New = n
-					eventList = append(eventList, event)
-				}
-			case workitem.SimpleType:
-				// compensate conversion from storage if this really was an enum field
-				converter := fieldType.ConvertFromModel
-				if isEnumType {
-					converter = enumType.ConvertFromModel
-				}
+		event := Event{
+			RevisionID:     newRev.ID,
+			Name:           fieldName,
+			WorkItemTypeID: newRev.WorkItemTypeID,
+			Timestamp:      newRev.Time,
+			Modifier:       modifierID.ID,
+			Old:            oldVal,
+			New:            newVal,
+		}
+
+		// The enum type can be handled by the simple type since it's just a
+		// single value after all.
+		ft := fieldDef.Type
+		enumType, isEnumType := ft.(workitem.EnumType)
+		if isEnumType {
+			ft = enumType.BaseType
+		}
+
+		switch fieldType := ft.(type) {
+		case workitem.ListType:
+			var p, n []interface{}
+			var ok bool
 
-				p, err := converter(oldVal)
+			switch t := oldVal.(type) {
+			case nil:
+				p = []interface{}{}
+			case []interface{}:
+				converted, err := fieldType.ConvertFromModel(t)
 				if err != nil {
-					return nil, errs.Wrapf(err, "failed to convert old value for field %s from storage representation: %+v", fieldName, oldVal)
+					return nil, errs.Wrapf(err, "failed to convert old value for field %s from storage representation: %+v", fieldName, t)
 				}
-				n, err := converter(newVal)
+				p, ok = converted.([]interface{})
+				if !ok {
+					return nil, errs.Errorf("failed to convert old value for field %s from to []interface{}: %+v", fieldName, t)
+				}
+			}
+
+			switch t := newVal.(type) {
+			case nil:
+				n = []interface{}{}
+			case []interface{}:
+				converted, err := fieldType.ConvertFromModel(t)
 				if err != nil {
-					return nil, errs.Wrapf(err, "failed to convert new value for field %s from storage representation: %+v", fieldName, newVal)
+					return nil, errs.Wrapf(err, "failed to convert new value for field %s from storage representation: %+v", fieldName, t)
 				}
-				if !reflect.DeepEqual(p, n) {
-					event.Old = p
-					event.New = n
-					eventList = append(eventList, event)
+				n, ok = converted.([]interface{})
+				if !ok {
+					return nil, errs.Errorf("failed to convert new value for field %s from to []interface{}: %+v", fieldName, t)
 				}
-			default:
-				return nil, errors.NewNotFoundError("unknown field type", fieldType.GetKind().String())
 			}
+
+			// Avoid duplicate entries for empty labels or assignees, etc.
+			if !reflect.DeepEqual(p, n) {
+				event.Old = p
+				event.New = n
+				eventList = append(eventList, event)
+			}
+		case workitem.SimpleType:
+			// compensate conversion from storage if this really was an enum field
+			converter := fieldType.ConvertFromModel
+			if isEnumType {
+				converter = enumType.ConvertFromModel
+			}
+
+			p, err := converter(oldVal)
+			if err != nil {
+				return nil, errs.Wrapf(err, "failed to convert old value for field %s from storage representation: %+v", fieldName, oldVal)
+			}
+			n, err := converter(newVal)
+			if err != nil {
+				return nil, errs.Wrapf(err, "failed to convert new value for field %s from storage representation: %+v", fieldName, newVal)
+			}
+			if !reflect.DeepEqual(p, n) {
+				event.Old = p
+				event.New = n
+				eventList = append(eventList, event)
+			}
+		default:
+			return nil, errors.NewNotFoundError("unknown field type", fieldType.GetKind().String())
 		}
 	}
-
 	return eventList, nil
 }
@@ -3,12 +3,14 @@ package event_test
 import (
 	"testing"
 
+	"github.com/fabric8-services/fabric8-wit/comment"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
 	"github.com/fabric8-services/fabric8-wit/rendering"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/event"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -414,4 +416,63 @@ func (s *eventRepoBlackBoxTest) TestList() {
 		assert.Equal(t, fxt.WorkItemTypes[0].ID, eventList[0].Old)
 		assert.Equal(t, fxt.WorkItemTypes[1].ID, eventList[0].New)
 	})
+
+	s.T().Run("link events", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(2), tf.WorkItemLinks(1))
+		err := link.NewWorkItemLinkRepository(s.DB).Delete(s.Ctx, fxt.WorkItemLinks[0].ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// the link shows up in the events of both work items
+		for i, other := range []uuid.UUID{fxt.WorkItems[1].ID, fxt.WorkItems[0].ID} {
+			eventList, err := s.wiEventRepo.List(s.Ctx, fxt.WorkItems[i].ID)
+			require.NoError(t, err)
+			require.Len(t, eventList, 2)
+			assert.Equal(t, event.LinkAddedEvent, eventList[0].Name)
+			assert.Equal(t, fxt.WorkItemLinks[0].ID, eventList[0].TargetID)
+			assert.Equal(t, fxt.WorkItems[i].Type, eventList[0].WorkItemTypeID)
+			assert.Nil(t, eventList[0].Old)
+			assert.Equal(t, other, eventList[0].New)
+			assert.Equal(t, event.LinkRemovedEvent, eventList[1].Name)
+			assert.Equal(t, fxt.WorkItemLinks[0].ID, eventList[1].TargetID)
+			assert.Equal(t, other, eventList[1].Old)
+			assert.Nil(t, eventList[1].New)
+			assert.Equal(t, fxt.Identities[0].ID, eventList[1].Modifier)
+		}
+	})
+
+	s.T().Run("comment events", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1), tf.Comments(1))
+		commentRepo := comment.NewRepository(s.DB)
+		oldBody := fxt.Comments[0].Body
+		fxt.Comments[0].Body = "edited comment"
+		require.NoError(t, commentRepo.Save(s.Ctx, fxt.Comments[0], fxt.Identities[0].ID))
+		require.NoError(t, commentRepo.Delete(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID))
+		eventList, err := s.wiEventRepo.List(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		require.Len(t, eventList, 3)
+		for _, e := range eventList {
+			assert.Equal(t, fxt.Comments[0].ID, e.TargetID)
+		}
+		assert.Equal(t, event.CommentAddedEvent, eventList[0].Name)
+		assert.Nil(t, eventList[0].Old)
+		assert.Equal(t, oldBody, eventList[0].New)
+		assert.Equal(t, event.CommentEditedEvent, eventList[1].Name)
+		assert.Equal(t, oldBody, eventList[1].Old)
+		assert.Equal(t, "edited comment", eventList[1].New)
+		assert.Equal(t, event.CommentDeletedEvent, eventList[2].Name)
+		assert.Equal(t, "edited comment", eventList[2].Old)
+		assert.Nil(t, eventList[2].New)
+	})
+
+	s.T().Run("merged timeline", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1), tf.Comments(1))
+		fxt.WorkItems[0].Fields[workitem.SystemState] = workitem.SystemStateResolved
+		_, _, err := s.wiRepo.Save(s.Ctx, fxt.WorkItems[0].SpaceID, *fxt.WorkItems[0], fxt.Identities[0].ID)
+		require.NoError(t, err)
+		eventList, err := s.wiEventRepo.List(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		require.Len(t, eventList, 2)
+		// the comment was added before the state changed
+		assert.Equal(t, event.CommentAddedEvent, eventList[0].Name)
+		assert.Equal(t, workitem.SystemState, eventList[1].Name)
+	})
 }
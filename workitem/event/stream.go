@@ -0,0 +1,242 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// The names of the changes in the change stream of a space
+const (
+	ChangeWorkItemCreate = "workitem.create"
+	ChangeWorkItemUpdate = "workitem.update"
+	ChangeWorkItemDelete = "workitem.delete"
+	ChangeLinkCreate     = "link.create"
+	ChangeLinkUpdate     = "link.update"
+	ChangeLinkDelete     = "link.delete"
+	ChangeCommentCreate  = "comment.create"
+	ChangeCommentUpdate  = "comment.update"
+	ChangeCommentDelete  = "comment.delete"
+)
+
+// changeNames maps the kind and revision type of a change to its name. The
+// work item, link and comment revision types share the same values.
+var changeNames = map[string]map[workitem.RevisionType]string{
+	"workitem": {
+		workitem.RevisionTypeCreate: ChangeWorkItemCreate,
+		workitem.RevisionTypeUpdate: ChangeWorkItemUpdate,
+		workitem.RevisionTypeDelete: ChangeWorkItemDelete,
+	},
+	"link": {
+		workitem.RevisionTypeCreate: ChangeLinkCreate,
+		workitem.RevisionTypeUpdate: ChangeLinkUpdate,
+		workitem.RevisionTypeDelete: ChangeLinkDelete,
+	},
+	"comment": {
+		workitem.RevisionTypeCreate: ChangeCommentCreate,
+		workitem.RevisionTypeUpdate: ChangeCommentUpdate,
+		workitem.RevisionTypeDelete: ChangeCommentDelete,
+	},
+}
+
+// Change represents a single entry in the change stream of a space. It is
+// built from a work item, link or comment revision.
+type Change struct {
+	// ID is the ID of the revision the change was built from. It is used to
+	// resume the stream after the change.
+	ID         uuid.UUID
+	Name       string
+	Timestamp  time.Time
+	Modifier   uuid.UUID
+	WorkItemID uuid.UUID
+	// TargetID is the ID of the link or comment that changed. It is
+	// uuid.Nil for work item changes.
+	TargetID uuid.UUID
+	// Events holds the field changes of a work item update
+	Events List
+}
+
+// Position is a position in the change stream of a space, whose changes are
+// ordered by their time and ID. A change that commits late can still get a
+// position before changes that were already listed, so the stream must look
+// at the changes before its last position again.
+type Position struct {
+	Time time.Time
+	ID   uuid.UUID
+}
+
+// Position returns the position of the change in the change stream of its
+// space
+func (c Change) Position() Position {
+	return Position{Time: c.Timestamp, ID: c.ID}
+}
+
+// changesQuery selects the revisions of the work items, links and comments
+// of a space that were recorded at or after a given time. The time bound is
+// applied to every revision table so that their revision time indexes can be
+// used. The source of a link determines the space of the link.
+const changesQuery = `WITH changes AS (
+	SELECT r.id, r.revision_time, r.revision_type, r.modifier_id, 'workitem' AS kind, r.work_item_id, NULL::uuid AS target_id
+	FROM work_item_revisions r JOIN work_items wi ON wi.id = r.work_item_id
+	WHERE wi.space_id = ? AND r.revision_time >= ?
+	UNION ALL
+	SELECT r.id, r.revision_time, r.revision_type, r.modifier_id, 'link', r.work_item_link_source_id, r.work_item_link_id
+	FROM work_item_link_revisions r JOIN work_items wi ON wi.id = r.work_item_link_source_id
+	WHERE wi.space_id = ? AND r.revision_time >= ?
+	UNION ALL
+	SELECT r.id, r.revision_time, r.revision_type, r.modifier_id, 'comment', r.comment_parent_id, r.comment_id
+	FROM comment_revisions r JOIN work_items wi ON wi.id = r.comment_parent_id
+	WHERE wi.space_id = ? AND r.revision_time >= ?
+)`
+
+// changesParams returns the parameters of the changesQuery
+func changesParams(spaceID uuid.UUID, since time.Time) []interface{} {
+	return []interface{}{spaceID, since, spaceID, since, spaceID, since}
+}
+
+// positionQuery selects the position of a revision of the work items, links
+// or comments of a space by its primary key
+const positionQuery = `SELECT r.id, r.revision_time
+	FROM work_item_revisions r JOIN work_items wi ON wi.id = r.work_item_id
+	WHERE r.id = ? AND wi.space_id = ?
+	UNION ALL
+	SELECT r.id, r.revision_time
+	FROM work_item_link_revisions r JOIN work_items wi ON wi.id = r.work_item_link_source_id
+	WHERE r.id = ? AND wi.space_id = ?
+	UNION ALL
+	SELECT r.id, r.revision_time
+	FROM comment_revisions r JOIN work_items wi ON wi.id = r.comment_parent_id
+	WHERE r.id = ? AND wi.space_id = ?`
+
+type changeRow struct {
+	ID           uuid.UUID
+	RevisionTime time.Time
+	RevisionType workitem.RevisionType
+	ModifierID   uuid.UUID
+	Kind         string
+	WorkItemID   uuid.UUID
+	TargetID     *uuid.UUID
+}
+
+// LatestPosition returns the position of the latest change in the given
+// space or nil if nothing changed in the space yet.
+func (r *GormEventRepository) LatestPosition(ctx context.Context, spaceID uuid.UUID) (*Position, error) {
+	var rows []changeRow
+	err := r.db.Raw(changesQuery+` SELECT id, revision_time FROM changes ORDER BY revision_time DESC, id DESC LIMIT 1`, changesParams(spaceID, time.Time{})...).Scan(&rows).Error
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to find the latest change in space %s", spaceID))
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &Position{Time: rows[0].RevisionTime, ID: rows[0].ID}, nil
+}
+
+// ChangePosition returns the position of the change with the given ID in the
+// change stream of the given space.
+func (r *GormEventRepository) ChangePosition(ctx context.Context, spaceID uuid.UUID, changeID uuid.UUID) (*Position, error) {
+	var rows []changeRow
+	err := r.db.Raw(positionQuery, changeID, spaceID, changeID, spaceID, changeID, spaceID).Scan(&rows).Error
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to load change %s", changeID))
+	}
+	if len(rows) == 0 {
+		return nil, errors.NewBadParameterError("after", changeID).Expected("ID of a change in the space")
+	}
+	return &Position{Time: rows[0].RevisionTime, ID: rows[0].ID}, nil
+}
+
+// ListChanges returns up to limit changes of the given space in the order of
+// their position, starting after the given position or at the beginning if
+// after is nil. If a filter is given, only the changes of work items that
+// currently match the filter are returned.
+func (r *GormEventRepository) ListChanges(ctx context.Context, spaceID uuid.UUID, filter criteria.Expression, after *Position, limit int) ([]Change, error) {
+	var since time.Time
+	if after != nil {
+		since = after.Time
+	}
+	query := changesQuery + ` SELECT * FROM changes WHERE true`
+	params := changesParams(spaceID, since)
+	if filter != nil {
+		where, parameters, joins, compileErrs := workitem.Compile(filter)
+		if compileErrs != nil {
+			log.Error(ctx, map[string]interface{}{
+				"err":        compileErrs,
+				"expression": filter,
+			}, "failed to compile expression")
+			return nil, errors.NewBadParameterError("expression", filter)
+		}
+		from := workitem.WorkItemStorage{}.TableName()
+		for _, j := range joins {
+			if err := j.Validate(r.db); err != nil {
+				log.Error(ctx, map[string]interface{}{"expression": filter, "err": err}, "table join not valid")
+				return nil, errors.NewBadParameterError("expression", filter).Expected("valid table join")
+			}
+			from += " " + j.GetJoinExpression()
+		}
+		query += fmt.Sprintf(` AND work_item_id IN (SELECT %[1]s.id FROM %[2]s WHERE %[3]s)`, workitem.WorkItemStorage{}.TableName(), from, where)
+		params = append(params, parameters...)
+	}
+	if after != nil {
+		query += ` AND (revision_time, id) > (?, ?)`
+		params = append(params, after.Time, after.ID)
+	}
+	query += ` ORDER BY revision_time, id LIMIT ?`
+	params = append(params, limit)
+
+	var rows []changeRow
+	if err := r.db.Raw(query, params...).Scan(&rows).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"space_id": spaceID,
+			"err":      err,
+		}, "failed to list the changes of the space")
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list the changes of space %s", spaceID))
+	}
+	changes := make([]Change, len(rows))
+	for i, row := range rows {
+		changes[i] = Change{
+			ID:         row.ID,
+			Name:       changeNames[row.Kind][row.RevisionType],
+			Timestamp:  row.RevisionTime,
+			Modifier:   row.ModifierID,
+			WorkItemID: row.WorkItemID,
+		}
+		if row.TargetID != nil {
+			changes[i].TargetID = *row.TargetID
+		}
+		if changes[i].Name != ChangeWorkItemUpdate {
+			continue
+		}
+		events, err := r.updateEvents(ctx, row.ID)
+		if err != nil {
+			return nil, err
+		}
+		changes[i].Events = events
+	}
+	return changes, nil
+}
+
+// updateEvents returns the field changes of the work item update recorded in
+// the revision with the given ID
+func (r *GormEventRepository) updateEvents(ctx context.Context, revisionID uuid.UUID) (List, error) {
+	var newRev workitem.Revision
+	if err := r.db.Where("id = ?", revisionID).First(&newRev).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to load work item revision %s", revisionID))
+	}
+	var oldRevs []workitem.Revision
+	err := r.db.Where("work_item_id = ? AND revision_time < ?", newRev.WorkItemID, newRev.Time).Order("revision_time desc").Limit(1).Find(&oldRevs).Error
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to load the revision before %s", revisionID))
+	}
+	if len(oldRevs) == 0 {
+		return List{}, nil
+	}
+	return r.diff(ctx, oldRevs[0], newRev)
+}
@@ -0,0 +1,111 @@
+package event_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/event"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *eventRepoBlackBoxTest) TestListChanges() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(2), tf.WorkItemLinks(1), tf.Comments(1))
+	spaceID := fxt.Spaces[0].ID
+
+	s.T().Run("all changes", func(t *testing.T) {
+		changes, err := s.wiEventRepo.ListChanges(s.Ctx, spaceID, nil, nil, 100)
+		require.NoError(t, err)
+		require.Len(t, changes, 4)
+		assert.Equal(t, event.ChangeWorkItemCreate, changes[0].Name)
+		assert.Equal(t, fxt.WorkItems[0].ID, changes[0].WorkItemID)
+		assert.Equal(t, uuid.Nil, changes[0].TargetID)
+		assert.Equal(t, event.ChangeWorkItemCreate, changes[1].Name)
+		assert.Equal(t, fxt.WorkItems[1].ID, changes[1].WorkItemID)
+		assert.Equal(t, event.ChangeLinkCreate, changes[2].Name)
+		assert.Equal(t, fxt.WorkItemLinks[0].ID, changes[2].TargetID)
+		assert.Equal(t, fxt.WorkItems[0].ID, changes[2].WorkItemID)
+		assert.Equal(t, event.ChangeCommentCreate, changes[3].Name)
+		assert.Equal(t, fxt.Comments[0].ID, changes[3].TargetID)
+
+		latest, err := s.wiEventRepo.LatestPosition(s.Ctx, spaceID)
+		require.NoError(t, err)
+		require.NotNil(t, latest)
+		assert.Equal(t, changes[3].Position(), *latest)
+	})
+
+	s.T().Run("resume after a change", func(t *testing.T) {
+		changes, err := s.wiEventRepo.ListChanges(s.Ctx, spaceID, nil, nil, 2)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		position, err := s.wiEventRepo.ChangePosition(s.Ctx, spaceID, changes[1].ID)
+		require.NoError(t, err)
+		rest, err := s.wiEventRepo.ListChanges(s.Ctx, spaceID, nil, position, 100)
+		require.NoError(t, err)
+		require.Len(t, rest, 2)
+		assert.Equal(t, event.ChangeLinkCreate, rest[0].Name)
+		assert.Equal(t, event.ChangeCommentCreate, rest[1].Name)
+	})
+
+	s.T().Run("list again from a time", func(t *testing.T) {
+		changes, err := s.wiEventRepo.ListChanges(s.Ctx, spaceID, nil, nil, 100)
+		require.NoError(t, err)
+		require.Len(t, changes, 4)
+		// a position without an ID includes the changes recorded at its time
+		rest, err := s.wiEventRepo.ListChanges(s.Ctx, spaceID, nil, &event.Position{Time: changes[2].Timestamp}, 100)
+		require.NoError(t, err)
+		require.Len(t, rest, 2)
+		assert.Equal(t, changes[2].ID, rest[0].ID)
+		assert.Equal(t, changes[3].ID, rest[1].ID)
+	})
+
+	s.T().Run("update with field events", func(t *testing.T) {
+		latest, err := s.wiEventRepo.LatestPosition(s.Ctx, spaceID)
+		require.NoError(t, err)
+		wi := *fxt.WorkItems[1]
+		wi.Fields[workitem.SystemTitle] = "updated title"
+		_, _, err = s.wiRepo.Save(s.Ctx, spaceID, wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		changes, err := s.wiEventRepo.ListChanges(s.Ctx, spaceID, nil, latest, 100)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, event.ChangeWorkItemUpdate, changes[0].Name)
+		assert.Equal(t, fxt.WorkItems[1].ID, changes[0].WorkItemID)
+		assert.Equal(t, fxt.Identities[0].ID, changes[0].Modifier)
+		require.Len(t, changes[0].Events, 1)
+		assert.Equal(t, workitem.SystemTitle, changes[0].Events[0].Name)
+		assert.Equal(t, "updated title", changes[0].Events[0].New)
+	})
+
+	s.T().Run("filter", func(t *testing.T) {
+		filter := criteria.Equals(criteria.Field(workitem.SystemTitle), criteria.Literal(fxt.WorkItems[0].Fields[workitem.SystemTitle]))
+		changes, err := s.wiEventRepo.ListChanges(s.Ctx, spaceID, filter, nil, 100)
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+		for _, change := range changes {
+			assert.Equal(t, fxt.WorkItems[0].ID, change.WorkItemID)
+		}
+	})
+
+	s.T().Run("other space", func(t *testing.T) {
+		changes, err := s.wiEventRepo.ListChanges(s.Ctx, uuid.NewV4(), nil, nil, 100)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+		latest, err := s.wiEventRepo.LatestPosition(s.Ctx, spaceID)
+		require.NoError(t, err)
+		_, err = s.wiEventRepo.ChangePosition(s.Ctx, uuid.NewV4(), latest.ID)
+		require.Error(t, err)
+	})
+
+	s.T().Run("unknown change to resume from", func(t *testing.T) {
+		_, err := s.wiEventRepo.ChangePosition(s.Ctx, spaceID, uuid.NewV4())
+		require.Error(t, err)
+		ok, _ := errors.IsBadParameterError(err)
+		assert.True(t, ok)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fabric8-services/fabric8-wit/criteria"
 	errs "github.com/pkg/errors"
@@ -54,7 +55,14 @@ func Compile(where criteria.Expression) (whereClause string, parameters []interf
 func bubbleUpJSONContext(c *expressionCompiler) func(exp criteria.Expression) bool {
 	return func(exp criteria.Expression) bool {
 		switch t := exp.(type) {
+		case *criteria.IsNullExpression:
+			if t.Annotation(jsonAnnotation) == true {
+				c.jsonFields[t.FieldName] = struct{}{}
+			}
 		case *criteria.FieldExpression:
+			if t.Annotation(jsonAnnotation) == true {
+				c.jsonFields[t.FieldName] = struct{}{}
+			}
 			_, isJSONField := c.getFieldName(t.FieldName)
 			if isJSONField {
 				t.SetAnnotation(jsonAnnotation, true)
@@ -76,6 +84,23 @@ func bubbleUpJSONContext(c *expressionCompiler) func(exp criteria.Expression) bo
 	}
 }
 
+// JSONField constructs a field expression for a field that is stored in the
+// jsonb "fields" column even if its name contains no dot (e.g. the fields
+// defined in space templates like "effort").
+func JSONField(name string) criteria.Expression {
+	f := criteria.Field(name)
+	f.SetAnnotation(jsonAnnotation, true)
+	return f
+}
+
+// JSONIsNull constructs an IsNull expression for a field that is stored in the
+// jsonb "fields" column even if its name contains no dot (see JSONField).
+func JSONIsNull(name string) criteria.Expression {
+	e := criteria.IsNull(name)
+	e.SetAnnotation(jsonAnnotation, true)
+	return e
+}
+
 // Column returns a proper column name from the given column name in the given
 // table.
 func Column(table, column string) string {
@@ -92,6 +117,27 @@ var fieldMap = map[string]string{
 	"Version": "version",
 	"Number":  "number",
 	"SpaceID": "space_id",
+	// these system fields are not stored in the jsonb "fields" column.
+	SystemCreatedAt: "created_at",
+	SystemUpdatedAt: "updated_at",
+}
+
+// derivedFieldMap tells how to compute fields that are not stored for a work
+// item but derived from other tables.
+var derivedFieldMap = map[string]string{
+	// a work item is blocked if it is the target of a link with a dependency
+	// topology whose source is not yet resolved. Importing the link package
+	// here to get the table names is not possible because of an import cycle.
+	"Blocked": `EXISTS (
+		SELECT 1 FROM work_item_links blocker_link
+		JOIN work_item_link_types blocker_link_type ON blocker_link_type.id = blocker_link.link_type_id
+		JOIN ` + WorkItemStorage{}.TableName() + ` blocker ON blocker.id = blocker_link.source_id
+		WHERE blocker_link.target_id = ` + Column(WorkItemStorage{}.TableName(), "id") + `
+		AND blocker_link_type.topology = 'dependency'
+		AND blocker_link.deleted_at IS NULL
+		AND blocker_link_type.deleted_at IS NULL
+		AND blocker.deleted_at IS NULL
+		AND NOT ` + ResolvedCondition("blocker") + `)`,
 }
 
 // getFieldName applies any potentially necessary mapping to field names (e.g.
@@ -106,6 +152,14 @@ func (c *expressionCompiler) getFieldName(fieldName string) (mappedFieldName str
 		}
 	}
 
+	if _, ok := c.jsonFields[fieldName]; ok {
+		return fieldName, true
+	}
+
+	if derivedField, ok := derivedFieldMap[fieldName]; ok {
+		return "(" + derivedField + ")", false
+	}
+
 	mappedFieldName, isColumnField := fieldMap[fieldName]
 	if isColumnField {
 		return Column(WorkItemStorage{}.TableName(), mappedFieldName), false
@@ -242,7 +296,8 @@ func newExpressionCompiler() expressionCompiler {
 	return expressionCompiler{
 		parameters: []interface{}{},
 		// Define all possible join scenarios here
-		joins: DefaultTableJoins(),
+		joins:      DefaultTableJoins(),
+		jsonFields: map[string]struct{}{},
 	}
 }
 
@@ -252,6 +307,9 @@ type expressionCompiler struct {
 	parameters []interface{} // records the number of parameter expressions encountered
 	err        []error       // record any errors found in the expression
 	joins      TableJoinMap  // map of table joins keyed by table name
+	// names of fields that are explicitly marked as being stored in the jsonb
+	// "fields" column (see JSONField)
+	jsonFields map[string]struct{}
 }
 
 // Ensure expressionCompiler implements the ExpressionVisitor interface
@@ -401,6 +459,72 @@ func (c *expressionCompiler) Substring(e *criteria.SubstringExpression) interfac
 	return c.binary(e, "ILIKE")
 }
 
+func (c *expressionCompiler) GreaterThan(e *criteria.GreaterThanExpression) interface{} {
+	return c.ordering(e, ">")
+}
+
+func (c *expressionCompiler) GreaterThanOrEqual(e *criteria.GreaterThanOrEqualExpression) interface{} {
+	return c.ordering(e, ">=")
+}
+
+func (c *expressionCompiler) LessThan(e *criteria.LessThanExpression) interface{} {
+	return c.ordering(e, "<")
+}
+
+func (c *expressionCompiler) LessThanOrEqual(e *criteria.LessThanOrEqualExpression) interface{} {
+	return c.ordering(e, "<=")
+}
+
+// ordering compiles the comparison of a field with a numeric or time literal
+// using the given ordering operator (e.g. ">="). JSON fields are compared as
+// numbers; instant values are stored as nanoseconds since the epoch in the
+// jsonb "fields" column and are converted accordingly.
+func (c *expressionCompiler) ordering(e criteria.BinaryExpression, op string) interface{} {
+	left, ok := e.Left().(*criteria.FieldExpression)
+	if !ok {
+		c.err = append(c.err, errs.Errorf("invalid left expression (not a field expression): %+v", e.Left()))
+		return nil
+	}
+	litExp, ok := e.Right().(*criteria.LiteralExpression)
+	if !ok {
+		c.err = append(c.err, errs.Errorf("failed to convert right expression to literal expression: %+v", e.Right()))
+		return nil
+	}
+	switch litExp.Value.(type) {
+	case float64, int, int64, time.Time:
+	default:
+		c.err = append(c.err, errs.Errorf(`value of "%s" must be a number or a time but is "%T": %+v`, left.FieldName, litExp.Value, litExp.Value))
+		return nil
+	}
+	if strings.Contains(left.FieldName, "'") || strings.Contains(left.FieldName, `"`) {
+		c.err = append(c.err, errs.Errorf("quotes are not allowed in field name: %s", left.FieldName))
+		return nil
+	}
+	mappedFieldName, isJSONField := c.getFieldName(left.FieldName)
+	if join, isJoinedRef := c.expressionRefersToJoinedData(left); isJoinedRef {
+		col, err := join.TranslateFieldName(left.FieldName)
+		if err != nil {
+			c.err = append(c.err, errs.Wrapf(err, `failed to translate field name: "%s"`, left.FieldName))
+			return nil
+		}
+		c.parameters = append(c.parameters, litExp.Value)
+		return "(" + col + " " + op + " ?)"
+	}
+	if !isJSONField {
+		c.parameters = append(c.parameters, litExp.Value)
+		return "(" + mappedFieldName + " " + op + " ?)"
+	}
+	value := litExp.Value
+	if t, ok := value.(time.Time); ok {
+		value = t.UnixNano()
+	}
+	c.parameters = append(c.parameters, value)
+	// only compare numeric values, the CASE makes sure we never cast anything
+	// else to a number.
+	fields := Column(WorkItemStorage{}.TableName(), "fields")
+	return fmt.Sprintf(`((CASE WHEN jsonb_typeof(%[1]s->'%[2]s') = 'number' THEN (%[1]s->>'%[2]s')::numeric END) %[3]s ?)`, fields, mappedFieldName, op)
+}
+
 func (c *expressionCompiler) IsNull(e *criteria.IsNullExpression) interface{} {
 	mappedFieldName, isJSONField := c.getFieldName(e.FieldName)
 	if isJSONField {
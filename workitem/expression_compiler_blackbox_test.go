@@ -2,6 +2,7 @@ package workitem_test
 
 import (
 	"testing"
+	"time"
 
 	c "github.com/fabric8-services/fabric8-wit/criteria"
 	"github.com/fabric8-services/fabric8-wit/resource"
@@ -200,6 +201,42 @@ func TestIsNull(t *testing.T) {
 	expect(t, c.IsNull("SpaceID"), `(`+workitem.Column(wiTbl, "space_id")+` IS NULL)`, []interface{}{}, nil)
 }
 
+func TestOrdering(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	wiTbl := workitem.WorkItemStorage{}.TableName()
+	fields := workitem.Column(wiTbl, "fields")
+	t.Run("numeric JSON field", func(t *testing.T) {
+		expect(t, c.GreaterThan(c.Field("system.order"), c.Literal(1.5)), `((CASE WHEN jsonb_typeof(`+fields+`->'system.order') = 'number' THEN (`+fields+`->>'system.order')::numeric END) > ?)`, []interface{}{1.5}, nil)
+		expect(t, c.GreaterThanOrEqual(c.Field("system.order"), c.Literal(2)), `((CASE WHEN jsonb_typeof(`+fields+`->'system.order') = 'number' THEN (`+fields+`->>'system.order')::numeric END) >= ?)`, []interface{}{2}, nil)
+	})
+	t.Run("instant JSON field", func(t *testing.T) {
+		d := time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+		expect(t, c.LessThan(c.Field("foo.due"), c.Literal(d)), `((CASE WHEN jsonb_typeof(`+fields+`->'foo.due') = 'number' THEN (`+fields+`->>'foo.due')::numeric END) < ?)`, []interface{}{d.UnixNano()}, nil)
+	})
+	t.Run("column fields", func(t *testing.T) {
+		d := time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+		expect(t, c.GreaterThanOrEqual(c.Field(workitem.SystemCreatedAt), c.Literal(d)), `(`+workitem.Column(wiTbl, "created_at")+` >= ?)`, []interface{}{d}, nil)
+		expect(t, c.LessThanOrEqual(c.Field(workitem.SystemUpdatedAt), c.Literal(d)), `(`+workitem.Column(wiTbl, "updated_at")+` <= ?)`, []interface{}{d}, nil)
+		expect(t, c.LessThan(c.Field("Number"), c.Literal(42)), `(`+workitem.Column(wiTbl, "number")+` < ?)`, []interface{}{42}, nil)
+	})
+	t.Run("joined field", func(t *testing.T) {
+		j := *workitem.DefaultTableJoins()["iteration"]
+		j.Active = true
+		j.HandledFields = []string{"created_at"}
+		d := time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+		expect(t, c.GreaterThan(c.Field("iteration.created_at"), c.Literal(d)), `(`+workitem.Column("iter", "created_at")+` > ?)`, []interface{}{d}, []*workitem.TableJoin{&j})
+	})
+	t.Run("non-numeric value - error", func(t *testing.T) {
+		_, _, _, compileErrors := workitem.Compile(c.GreaterThan(c.Field("system.order"), c.Literal("abc")))
+		require.NotEmpty(t, compileErrors)
+	})
+	t.Run("quote in field name - error", func(t *testing.T) {
+		_, _, _, compileErrors := workitem.Compile(c.GreaterThan(c.Field("foo'bar.x"), c.Literal(1)))
+		require.NotEmpty(t, compileErrors)
+	})
+}
+
 func TestChild(t *testing.T) {
 	t.Parallel()
 	resource.Require(t, resource.UnitTest)
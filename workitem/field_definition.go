@@ -294,12 +294,23 @@ func ConvertStringToKind(k string) (*Kind, error) {
 	return nil, errs.Errorf("kind '%s' is not a simple type", k)
 }
 
-// compatibleFields returns true if the existing and new field are compatible;
-// otherwise false is returned. It does so by comparing all members of the field
-// definition except for the label and description.
-func compatibleFields(existing FieldDefinition, new FieldDefinition) bool {
+// compatibleFields returns true if the existing and new field with the given
+// name are compatible; otherwise false is returned. It does so by comparing all
+// members of the field definition except for the label and description. The
+// system.metastate enum of a type is also compatible with one that only uses
+// some of its values since every type maps its states to some of the
+// meta-states.
+func compatibleFields(name string, existing FieldDefinition, new FieldDefinition) bool {
 	if existing.Required != new.Required {
 		return false
 	}
-	return existing.Type.Equal(new.Type)
+	if existing.Type.Equal(new.Type) {
+		return true
+	}
+	if name != SystemMetaState {
+		return false
+	}
+	existingEnum, ok1 := existing.Type.(EnumType)
+	newEnum, ok2 := new.Type.(EnumType)
+	return ok1 && ok2 && existingEnum.EqualEnclosing(newEnum)
 }
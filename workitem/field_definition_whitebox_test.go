@@ -36,7 +36,7 @@ func TestCompatibleFields(t *testing.T) {
 			},
 		}
 		// then
-		assert.True(t, compatibleFields(a, b), "fields %+v and %+v are not detected as being compatible", a, b)
+		assert.True(t, compatibleFields("a", a, b), "fields %+v and %+v are not detected as being compatible", a, b)
 	})
 	t.Run("incompatible field definition (incompatible fields)", func(t *testing.T) {
 		t.Parallel()
@@ -51,7 +51,7 @@ func TestCompatibleFields(t *testing.T) {
 			},
 		}
 		// then
-		assert.False(t, compatibleFields(a, c), "fields %+v and %+v are not detected as being incompatible", a, c)
+		assert.False(t, compatibleFields("a", a, c), "fields %+v and %+v are not detected as being incompatible", a, c)
 	})
 	t.Run("incompatible field definition (different required field)", func(t *testing.T) {
 		t.Parallel()
@@ -66,7 +66,40 @@ func TestCompatibleFields(t *testing.T) {
 			},
 		}
 		// then
-		assert.False(t, compatibleFields(a, d), "fields %+v and %+v are not detected as being incompatible", a, d)
+		assert.False(t, compatibleFields("a", a, d), "fields %+v and %+v are not detected as being incompatible", a, d)
+	})
+	enum := func(values ...interface{}) FieldDefinition {
+		return FieldDefinition{
+			Type: EnumType{
+				SimpleType: SimpleType{Kind: KindEnum},
+				BaseType:   SimpleType{Kind: KindString},
+				Values:     values,
+			},
+		}
+	}
+	t.Run("compatible field definition (enum using some of the values)", func(t *testing.T) {
+		t.Parallel()
+		// given
+		e := enum("mNew", "mOpen", "mClosed")
+		f := enum("mNew", "mClosed", "mClosed")
+		// then
+		assert.True(t, compatibleFields(SystemMetaState, e, f), "fields %+v and %+v are not detected as being compatible", e, f)
+	})
+	t.Run("incompatible field definition (enum with other values)", func(t *testing.T) {
+		t.Parallel()
+		// given
+		e := enum("mNew", "mOpen", "mClosed")
+		f := enum("mNew", "mDone")
+		// then
+		assert.False(t, compatibleFields(SystemMetaState, e, f), "fields %+v and %+v are not detected as being incompatible", e, f)
+	})
+	t.Run("incompatible field definition (other enum using some of the values)", func(t *testing.T) {
+		t.Parallel()
+		// given
+		e := enum("new", "open", "closed")
+		f := enum("new", "closed")
+		// then
+		assert.False(t, compatibleFields(SystemState, e, f), "fields %+v and %+v are not detected as being incompatible", e, f)
 	})
 }
 
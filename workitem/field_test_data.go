@@ -238,11 +238,12 @@ func GetFieldTypeTestData(t *testing.T) FieldTypeTestDataMap {
 				rendering.MarkupContent{Content: "plain text", Markup: rendering.SystemMarkupPlainText},
 				rendering.MarkupContent{Content: "default", Markup: rendering.SystemMarkupDefault},
 				rendering.MarkupContent{Content: "# markdown", Markup: rendering.SystemMarkupMarkdown},
+				rendering.MarkupContent{Content: "h1. jira", Markup: rendering.SystemMarkupJiraWiki},
+				rendering.MarkupContent{Content: "= asciidoc", Markup: rendering.SystemMarkupAsciiDoc},
 			},
 			Invalid: []interface{}{
 				0,
-				rendering.MarkupContent{Content: "jira", Markup: rendering.SystemMarkupJiraWiki}, // TODO(kwk): not supported yet
-				rendering.MarkupContent{Content: "", Markup: ""},                                 // NOTE: We allow allow empty strings
+				rendering.MarkupContent{Content: "", Markup: ""}, // NOTE: We allow allow empty strings
 				rendering.MarkupContent{Content: "foo", Markup: "unknown markup type"},
 				"",
 				"foo",
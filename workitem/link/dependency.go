@@ -0,0 +1,283 @@
+package link
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fabric8-services/fabric8-common/id"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Dependency is a link of a link type with a dependency topology. The source
+// of such a link blocks the target.
+//
+// NOTE: The sql columns noted here are purely virtual and not persistent, see
+// the query in listDependencies() to find out more.
+type Dependency struct {
+	BlockerID uuid.UUID `gorm:"column:source_id" sql:"type:uuid"`
+	BlockedID uuid.UUID `gorm:"column:target_id" sql:"type:uuid"`
+}
+
+// DependencyGraph is the part of the graph formed by all links with a
+// dependency topology that is reachable from a set of work items.
+type DependencyGraph struct {
+	// WorkItemIDs are the work items for which the graph was built.
+	WorkItemIDs id.Slice
+	// Dependencies are all links of the graph.
+	Dependencies []Dependency
+	// Resolved tells for every work item of the graph if its state maps to
+	// one of the workitem.ResolvedMetaStates.
+	Resolved map[uuid.UUID]bool
+}
+
+// blockersOf returns the direct blockers of the given work item.
+func (g DependencyGraph) blockersOf(wiID uuid.UUID) id.Slice {
+	res := id.Slice{}
+	for _, d := range g.Dependencies {
+		if d.BlockedID == wiID {
+			res = append(res, d.BlockerID)
+		}
+	}
+	return res
+}
+
+// blockedBy returns the work items that are directly blocked by the given
+// work item.
+func (g DependencyGraph) blockedBy(wiID uuid.UUID) id.Slice {
+	res := id.Slice{}
+	for _, d := range g.Dependencies {
+		if d.BlockerID == wiID {
+			res = append(res, d.BlockedID)
+		}
+	}
+	return res
+}
+
+// traverse returns all work items that can be reached from the graph's work
+// items by following the given neighbour function. The graph's work items
+// themselves are not returned. If follow is not nil, only the work items for
+// which it returns true are returned and visited.
+func (g DependencyGraph) traverse(neighbours func(uuid.UUID) id.Slice, follow func(uuid.UUID) bool) id.Slice {
+	visited := g.WorkItemIDs.ToMap()
+	res := id.Slice{}
+	queue := append(id.Slice{}, g.WorkItemIDs...)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, n := range neighbours(current) {
+			if _, ok := visited[n]; ok {
+				continue
+			}
+			visited[n] = struct{}{}
+			if follow != nil && !follow(n) {
+				continue
+			}
+			res = append(res, n)
+			queue = append(queue, n)
+		}
+	}
+	return res
+}
+
+// Blockers returns all work items that directly or transitively block one of
+// the graph's work items.
+func (g DependencyGraph) Blockers() id.Slice {
+	return g.traverse(g.blockersOf, nil)
+}
+
+// OpenBlockers returns the unresolved work items that directly or
+// transitively block one of the graph's work items. Blockers of resolved
+// work items are not considered.
+func (g DependencyGraph) OpenBlockers() id.Slice {
+	return g.traverse(g.blockersOf, func(wiID uuid.UUID) bool {
+		return !g.Resolved[wiID]
+	})
+}
+
+// Blocking returns all work items that are directly or transitively blocked
+// by one of the graph's work items.
+func (g DependencyGraph) Blocking() id.Slice {
+	return g.traverse(g.blockedBy, nil)
+}
+
+// CriticalPath returns the longest chain of unresolved work items that ends
+// in one of the graph's work items. The chain starts with the work item that
+// has to be resolved first and ends with one of the graph's work items. If
+// all of the graph's work items are resolved, the path is empty.
+func (g DependencyGraph) CriticalPath() id.Slice {
+	// length of the longest chain of unresolved blockers ending in a work
+	// item (including the work item itself) and the next work item to
+	// follow on that chain.
+	length := map[uuid.UUID]int{}
+	next := map[uuid.UUID]uuid.UUID{}
+	var longest func(wiID uuid.UUID, visiting id.Map) int
+	longest = func(wiID uuid.UUID, visiting id.Map) int {
+		if l, ok := length[wiID]; ok {
+			return l
+		}
+		// cycles are not allowed in dependency topologies but better be safe
+		// than sorry.
+		visiting[wiID] = struct{}{}
+		defer delete(visiting, wiID)
+		best := 0
+		for _, b := range g.blockersOf(wiID) {
+			if _, ok := visiting[b]; ok || g.Resolved[b] {
+				continue
+			}
+			if l := longest(b, visiting); l > best {
+				best = l
+				next[wiID] = b
+			}
+		}
+		length[wiID] = best + 1
+		return best + 1
+	}
+	var start *uuid.UUID
+	best := 0
+	for i, wiID := range g.WorkItemIDs {
+		if g.Resolved[wiID] {
+			continue
+		}
+		if l := longest(wiID, id.Map{}); l > best {
+			best = l
+			start = &g.WorkItemIDs[i]
+		}
+	}
+	res := id.Slice{}
+	if start == nil {
+		return res
+	}
+	for current, ok := *start, true; ok; current, ok = next[current] {
+		res = append(id.Slice{current}, res...)
+	}
+	return res
+}
+
+// GetDependencyGraph returns the graph of all links with a dependency topology
+// that directly or transitively block or are blocked by the given work items.
+func (r *GormWorkItemLinkRepository) GetDependencyGraph(ctx context.Context, workItemIDs ...uuid.UUID) (*DependencyGraph, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "get", "dependencies"}, time.Now())
+
+	var idArr id.Slice = workItemIDs
+	g := DependencyGraph{
+		WorkItemIDs:  idArr.Unique(),
+		Dependencies: []Dependency{},
+		Resolved:     map[uuid.UUID]bool{},
+	}
+	if len(g.WorkItemIDs) == 0 {
+		return &g, nil
+	}
+	// links between the given work items are found in both directions.
+	seen := map[Dependency]struct{}{}
+	for _, upstream := range []bool{true, false} {
+		deps, err := r.listDependencies(ctx, upstream, g.WorkItemIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range deps {
+			if _, ok := seen[d]; !ok {
+				seen[d] = struct{}{}
+				g.Dependencies = append(g.Dependencies, d)
+			}
+		}
+	}
+
+	nodes := append(id.Slice{}, g.WorkItemIDs...)
+	for _, d := range g.Dependencies {
+		nodes = append(nodes, d.BlockerID, d.BlockedID)
+	}
+	nodeStr := nodes.Unique().ToString(",", func(ID uuid.UUID) string { return fmt.Sprintf("'%s'", ID) })
+	var states []struct {
+		ID       uuid.UUID `gorm:"column:id" sql:"type:uuid"`
+		Resolved bool      `gorm:"column:resolved"`
+	}
+	query := fmt.Sprintf(`SELECT id, %[1]s AS resolved FROM %[2]s WHERE id IN ( %[3]s ) AND deleted_at IS NULL`,
+		workitem.ResolvedCondition(workitem.WorkItemStorage{}.TableName()),
+		workitem.WorkItemStorage{}.TableName(),
+		nodeStr,
+	)
+	if err := r.db.Raw(query).Scan(&states).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "failed to find states of work items: %s", nodeStr)
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to find states of work items: %s", nodeStr))
+	}
+	for _, s := range states {
+		g.Resolved[s.ID] = s.Resolved
+	}
+	return &g, nil
+}
+
+// listDependencies returns all links with a dependency topology that can be
+// reached by walking the links from the given work items upstream (from the
+// blocked to the blocking work item) or downstream.
+func (r *GormWorkItemLinkRepository) listDependencies(ctx context.Context, upstream bool, workItemIDs id.Slice) ([]Dependency, error) {
+	from, to := "source_id", "target_id"
+	if upstream {
+		from, to = to, from
+	}
+	idStr := workItemIDs.ToString(",", func(ID uuid.UUID) string { return fmt.Sprintf("'%s'", ID) })
+
+	// Postgres Common Table Expression (https://www.postgresql.org/docs/current/static/queries-with.html)
+	query := fmt.Sprintf(`
+		WITH RECURSIVE working_table(id, source_id, target_id, already_visited, cycle) AS (
+
+			-- non recursive term: Find the dependency links that start at
+			-- the given items.
+
+			SELECT
+				l.id,
+				l.source_id,
+				l.target_id,
+				ARRAY[l.id],
+				false
+			FROM %[1]s l JOIN %[2]s t ON t.id = l.link_type_id
+			WHERE
+				l.%[3]s IN ( %[5]s )
+				AND t.topology = $1
+				AND l.deleted_at IS NULL
+				AND t.deleted_at IS NULL
+		UNION
+
+			-- recursive term: Find the dependency links that continue where
+			-- the links from the "working table" end.
+
+			SELECT
+				l.id,
+				l.source_id,
+				l.target_id,
+				already_visited || l.id,
+				l.id = ANY(already_visited)
+			FROM working_table w, %[1]s l JOIN %[2]s t ON t.id = l.link_type_id
+			WHERE
+				l.%[3]s = w.%[4]s
+				AND t.topology = $1
+				AND l.deleted_at IS NULL
+				AND t.deleted_at IS NULL
+				AND NOT cycle -- recursive termination criteria
+		)
+		SELECT DISTINCT source_id, target_id FROM working_table ORDER BY source_id, target_id;`,
+		WorkItemLink{}.TableName(),
+		WorkItemLinkType{}.TableName(),
+		from,
+		to,
+		idStr,
+	)
+	deps := []Dependency{}
+	db := r.db.Raw(query, string(TopologyDependency)).Scan(&deps)
+	if db.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":      db.Error,
+			"upstream": upstream,
+		}, "failed to find dependencies of work items: %s", idStr)
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to find dependencies of work items: %s", idStr))
+	}
+	return deps, nil
+}
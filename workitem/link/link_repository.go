@@ -41,10 +41,19 @@ type WorkItemLinkRepository interface {
 	DeleteRelatedLinks(ctx context.Context, wiID uuid.UUID, suppressorID uuid.UUID) error
 	Delete(ctx context.Context, ID uuid.UUID, suppressorID uuid.UUID) error
 	ListChildLinks(ctx context.Context, linkTypeID uuid.UUID, parentIDs ...uuid.UUID) (WorkItemLinkList, error)
+	// ListChildLinksAsOf works like ListChildLinks but returns the links that
+	// existed at the given time.
+	ListChildLinksAsOf(ctx context.Context, linkTypeID uuid.UUID, asOf time.Time, parentIDs ...uuid.UUID) (WorkItemLinkList, error)
 	ListWorkItemChildren(ctx context.Context, parentID uuid.UUID, start *int, limit *int) ([]workitem.WorkItem, int, error)
 	WorkItemHasChildren(ctx context.Context, parentID uuid.UUID) (bool, error)
 	// GetAncestors returns all ancestors for the given work items.
 	GetAncestors(ctx context.Context, linkTypeID uuid.UUID, upToLevel int, workItemIDs ...uuid.UUID) (ancestors AncestorList, err error)
+	// GetAncestorsAsOf returns all ancestors for the given work items as they
+	// were linked at the given time.
+	GetAncestorsAsOf(ctx context.Context, linkTypeID uuid.UUID, upToLevel int, asOf time.Time, workItemIDs ...uuid.UUID) (ancestors AncestorList, err error)
+	// GetDependencyGraph returns the graph of dependency links that directly
+	// or transitively block or are blocked by the given work items.
+	GetDependencyGraph(ctx context.Context, workItemIDs ...uuid.UUID) (*DependencyGraph, error)
 }
 
 // NewWorkItemLinkRepository creates a work item link repository based on gorm
@@ -409,9 +418,25 @@ func (r *GormWorkItemLinkRepository) deleteLink(ctx context.Context, lnk WorkIte
 // ListChildLinks gets all links to children for the given parents
 func (r *GormWorkItemLinkRepository) ListChildLinks(ctx context.Context, linkTypeID uuid.UUID, parentIDs ...uuid.UUID) (WorkItemLinkList, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "list", "children", "ids"}, time.Now())
+	return r.listChildLinks(ctx, linkTypeID, nil, parentIDs...)
+}
+
+// ListChildLinksAsOf gets all links to children for the given parents that
+// existed at the given time
+func (r *GormWorkItemLinkRepository) ListChildLinksAsOf(ctx context.Context, linkTypeID uuid.UUID, asOf time.Time, parentIDs ...uuid.UUID) (WorkItemLinkList, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "list", "children", "ids", "asof"}, time.Now())
+	return r.listChildLinks(ctx, linkTypeID, &asOf, parentIDs...)
+}
 
+// listChildLinks implements ListChildLinks and ListChildLinksAsOf. asOf is nil
+// for the current links.
+func (r *GormWorkItemLinkRepository) listChildLinks(ctx context.Context, linkTypeID uuid.UUID, asOf *time.Time, parentIDs ...uuid.UUID) (WorkItemLinkList, error) {
 	var results WorkItemLinkList
-	db := r.db.Model(&WorkItemLink{}).Where("source_id IN (?) AND link_type_id = ?", parentIDs, linkTypeID).Scan(&results)
+	db := r.db.Model(&WorkItemLink{})
+	if asOf != nil {
+		db = r.db.Unscoped().Model(&WorkItemLink{}).Where(linkExistsAt(WorkItemLink{}.TableName(), asOf))
+	}
+	db = db.Where("source_id IN (?) AND link_type_id = ?", parentIDs, linkTypeID).Scan(&results)
 	if db.Error != nil {
 		log.Error(ctx, map[string]interface{}{
 			"err": db.Error,
@@ -550,7 +575,30 @@ func (r *GormWorkItemLinkRepository) WorkItemHasChildren(ctx context.Context, pa
 // the given work item and mapped to an array of root IDs.
 func (r *GormWorkItemLinkRepository) GetAncestors(ctx context.Context, linkTypeID uuid.UUID, upToLevel int, workItemIDs ...uuid.UUID) (ancestors AncestorList, err error) {
 	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "get", "ancestors"}, time.Now())
+	return r.getAncestors(ctx, linkTypeID, upToLevel, nil, workItemIDs...)
+}
+
+// GetAncestorsAsOf works like GetAncestors but follows the links that existed
+// at the given time.
+func (r *GormWorkItemLinkRepository) GetAncestorsAsOf(ctx context.Context, linkTypeID uuid.UUID, upToLevel int, asOf time.Time, workItemIDs ...uuid.UUID) (ancestors AncestorList, err error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "get", "ancestors", "asof"}, time.Now())
+	return r.getAncestors(ctx, linkTypeID, upToLevel, &asOf, workItemIDs...)
+}
+
+// linkExistsAt returns an SQL condition that is true for the links in the
+// given table (or table alias) that existed at the given time or, if it is
+// nil, that exist now.
+func linkExistsAt(table string, asOf *time.Time) string {
+	if asOf == nil {
+		return table + ".deleted_at IS NULL"
+	}
+	return fmt.Sprintf("%[1]s.created_at <= '%[2]s' AND (%[1]s.deleted_at IS NULL OR %[1]s.deleted_at > '%[2]s')",
+		table, asOf.UTC().Format(time.RFC3339Nano))
+}
 
+// getAncestors implements GetAncestors and GetAncestorsAsOf. asOf is nil for
+// the current links.
+func (r *GormWorkItemLinkRepository) getAncestors(ctx context.Context, linkTypeID uuid.UUID, upToLevel int, asOf *time.Time, workItemIDs ...uuid.UUID) (ancestors AncestorList, err error) {
 	if len(workItemIDs) < 1 {
 		return nil, nil
 	}
@@ -568,6 +616,11 @@ func (r *GormWorkItemLinkRepository) GetAncestors(ctx context.Context, linkTypeI
 		levelLimitation = fmt.Sprintf(" AND array_length(already_visited, 1) < %d ", upToLevel)
 	}
 
+	rootLinkExists := ""
+	if asOf != nil {
+		rootLinkExists = " AND " + linkExistsAt("l", asOf)
+	}
+
 	// Postgres Common Table Expression (https://www.postgresql.org/docs/current/static/queries-with.html)
 	// TODO(kwk): We should probably measure performance for this.
 	query := fmt.Sprintf(`
@@ -588,7 +641,7 @@ func (r *GormWorkItemLinkRepository) GetAncestors(ctx context.Context, linkTypeI
 			WHERE
 				l.target_id IN ( %[2]s ) 
 				AND l.link_type_id = $1
-				AND l.deleted_at IS NULL
+				AND %[4]s
 		UNION
 			
 			-- recursive term: Only this one can query the "tree" table.
@@ -606,7 +659,7 @@ func (r *GormWorkItemLinkRepository) GetAncestors(ctx context.Context, linkTypeI
 			WHERE
 				l.target_id = w.ancestor
 				AND l.link_type_id = $1
-				AND l.deleted_at IS NULL
+				AND %[4]s
 				AND NOT cycle -- recursive termination criteria
 				%[3]s
 		)
@@ -614,13 +667,15 @@ func (r *GormWorkItemLinkRepository) GetAncestors(ctx context.Context, linkTypeI
 			ancestor,
 			direct_child,
 			original_child,
-			(SELECT NOT EXISTS (SELECT 1 FROM work_item_links l WHERE l.target_id = ancestor AND l.link_type_id = $1)) as "is_root",
+			(SELECT NOT EXISTS (SELECT 1 FROM work_item_links l WHERE l.target_id = ancestor AND l.link_type_id = $1%[5]s)) as "is_root",
 			array_length(already_visited, 1) as "ancestor_level"
 		FROM working_table
 		;`,
 		WorkItemLink{}.TableName(),
 		idStr,
 		levelLimitation,
+		linkExistsAt("l", asOf),
+		rootLinkExists,
 	)
 
 	// Convert SQL results to instances of ancestor objects
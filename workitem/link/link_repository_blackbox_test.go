@@ -905,3 +905,89 @@ func (s *linkRepoBlackBoxTest) TestDeleteLink() {
 		require.IsType(t, errors.NotFoundError{}, err)
 	})
 }
+
+func (s *linkRepoBlackBoxTest) TestGetDependencyGraph() {
+	// A -> B -> C -> D with B being closed
+	//      E -/
+	// and a network link F -> C that is no dependency.
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItems(6,
+			tf.SetWorkItemTitles("A", "B", "C", "D", "E", "F"),
+			tf.SetWorkItemField(workitem.SystemState, workitem.SystemStateNew, workitem.SystemStateClosed, workitem.SystemStateNew, workitem.SystemStateNew, workitem.SystemStateNew, workitem.SystemStateNew),
+		),
+		tf.WorkItemLinkTypes(2,
+			tf.SetWorkItemLinkTypeNames("dependency", "network"),
+			tf.SetTopologies(link.TopologyDependency, link.TopologyNetwork),
+		),
+		tf.WorkItemLinksCustom(5, tf.BuildLinks(
+			tf.L("A", "B", "dependency"),
+			tf.L("B", "C", "dependency"),
+			tf.L("C", "D", "dependency"),
+			tf.L("E", "C", "dependency"),
+			tf.L("F", "C", "network"),
+		)),
+	)
+	ids := func(titles ...string) []uuid.UUID {
+		res := make([]uuid.UUID, len(titles))
+		for i, title := range titles {
+			res[i] = fxt.WorkItemByTitle(title).ID
+		}
+		return res
+	}
+
+	s.T().Run("work item in the middle", func(t *testing.T) {
+		// when
+		g, err := s.workitemLinkRepo.GetDependencyGraph(s.Ctx, fxt.WorkItemByTitle("C").ID)
+		// then
+		require.NoError(t, err)
+		require.Len(t, g.Dependencies, 4)
+		require.ElementsMatch(t, ids("A", "B", "E"), g.Blockers())
+		require.ElementsMatch(t, ids("E"), g.OpenBlockers())
+		require.ElementsMatch(t, ids("D"), g.Blocking())
+		require.Equal(t, ids("E", "C"), []uuid.UUID(g.CriticalPath()))
+		require.True(t, g.Resolved[fxt.WorkItemByTitle("B").ID])
+		require.False(t, g.Resolved[fxt.WorkItemByTitle("C").ID])
+	})
+
+	s.T().Run("work item at the end", func(t *testing.T) {
+		// when
+		g, err := s.workitemLinkRepo.GetDependencyGraph(s.Ctx, fxt.WorkItemByTitle("D").ID)
+		// then
+		require.NoError(t, err)
+		require.ElementsMatch(t, ids("A", "B", "C", "E"), g.Blockers())
+		require.ElementsMatch(t, ids("C", "E"), g.OpenBlockers())
+		require.Empty(t, g.Blocking())
+		require.Equal(t, ids("E", "C", "D"), []uuid.UUID(g.CriticalPath()))
+	})
+
+	s.T().Run("multiple work items", func(t *testing.T) {
+		// when
+		g, err := s.workitemLinkRepo.GetDependencyGraph(s.Ctx, ids("B", "C")...)
+		// then
+		require.NoError(t, err)
+		require.ElementsMatch(t, ids("A", "E"), g.Blockers())
+		// A is still open and blocks B even though B is closed
+		require.ElementsMatch(t, ids("A", "E"), g.OpenBlockers())
+		require.ElementsMatch(t, ids("D"), g.Blocking())
+	})
+
+	s.T().Run("work item without dependencies", func(t *testing.T) {
+		// when
+		g, err := s.workitemLinkRepo.GetDependencyGraph(s.Ctx, fxt.WorkItemByTitle("F").ID)
+		// then
+		require.NoError(t, err)
+		require.Empty(t, g.Dependencies)
+		require.Empty(t, g.Blockers())
+		require.Empty(t, g.Blocking())
+		require.Equal(t, ids("F"), []uuid.UUID(g.CriticalPath()))
+	})
+
+	s.T().Run("no work items", func(t *testing.T) {
+		// when
+		g, err := s.workitemLinkRepo.GetDependencyGraph(s.Ctx)
+		// then
+		require.NoError(t, err)
+		require.Empty(t, g.Dependencies)
+		require.Empty(t, g.CriticalPath())
+	})
+}
@@ -0,0 +1,122 @@
+package workitem
+
+import (
+	"fmt"
+	"strings"
+
+	errs "github.com/pkg/errors"
+)
+
+// The meta-states that the values of the system.state field of a work item
+// type are mapped to by the values of its system.metastate field.
+const (
+	SystemMetaStateNew        = "mNew"
+	SystemMetaStateOpen       = "mOpen"
+	SystemMetaStateInProgress = "mInprogress"
+	SystemMetaStateResolved   = "mResolved"
+	SystemMetaStateClosed     = "mClosed"
+)
+
+// ResolvedMetaStates are the meta-states of work items that are done and
+// therefore no longer block other work items.
+var ResolvedMetaStates = []string{SystemMetaStateResolved, SystemMetaStateClosed}
+
+// MetaStateMapping maps the states of a work item type to their meta-states.
+// It is the single place that tells whether a state counts as in progress,
+// resolved or closed, regardless of how the state is named.
+type MetaStateMapping map[string]string
+
+// MetaStateMapping returns the mapping between the values of the
+// system.state and the system.metastate enums of the work item type. The
+// values of both enums are mapped by their position.
+func (wit WorkItemType) MetaStateMapping() (MetaStateMapping, error) {
+	stateValues, err := wit.EnumValues(SystemState)
+	if err != nil {
+		return nil, err
+	}
+	metaStateValues, err := wit.EnumValues(SystemMetaState)
+	if err != nil {
+		return nil, err
+	}
+	if len(stateValues) != len(metaStateValues) {
+		return nil, errs.Errorf("work item type %s has %d values for %s but %d values for %s", wit.ID, len(stateValues), SystemState, len(metaStateValues), SystemMetaState)
+	}
+	res := MetaStateMapping{}
+	for i := range stateValues {
+		res[stateValues[i]] = metaStateValues[i]
+	}
+	return res, nil
+}
+
+// EnumValues returns the values of the given enum field as strings.
+func (wit WorkItemType) EnumValues(fieldName string) ([]string, error) {
+	fieldDef, ok := wit.Fields[fieldName]
+	if !ok {
+		return nil, errs.Errorf("work item type %s has no field %s", wit.ID, fieldName)
+	}
+	enumType, ok := fieldDef.Type.(EnumType)
+	if !ok {
+		return nil, errs.Errorf("field %s of work item type %s is not an enum", fieldName, wit.ID)
+	}
+	res := make([]string, len(enumType.Values))
+	for i, v := range enumType.Values {
+		res[i] = fmt.Sprintf("%v", v)
+	}
+	return res, nil
+}
+
+// MetaState returns the meta-state of the given state or an empty string if
+// the state is not mapped.
+func (m MetaStateMapping) MetaState(state string) string {
+	return m[state]
+}
+
+// IsInProgress returns true if the given state maps to the in progress
+// meta-state.
+func (m MetaStateMapping) IsInProgress(state string) bool {
+	return m[state] == SystemMetaStateInProgress
+}
+
+// IsClosed returns true if the given state maps to the closed meta-state.
+func (m MetaStateMapping) IsClosed(state string) bool {
+	return m[state] == SystemMetaStateClosed
+}
+
+// IsResolved returns true if the given state maps to one of the
+// ResolvedMetaStates.
+func (m MetaStateMapping) IsResolved(state string) bool {
+	for _, metaState := range ResolvedMetaStates {
+		if m[state] == metaState {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolvedCondition returns an SQL condition that is true if the state of the
+// work item in the given table (or table alias) maps to one of the
+// ResolvedMetaStates in the type of the work item. It is the SQL counterpart
+// of MetaStateMapping.IsResolved.
+func ResolvedCondition(table string) string {
+	return MetaStateCondition(Column(table, "type"), Column(table, "fields"), ResolvedMetaStates...)
+}
+
+// MetaStateCondition returns an SQL condition that is true if the state in the
+// given fields column maps to one of the given meta-states in the work item
+// type referenced by the given type column. The columns can be the ones of a
+// work item or of a revision of a work item.
+func MetaStateCondition(typeColumn, fieldsColumn string, metaStates ...string) string {
+	quoted := make([]string, len(metaStates))
+	for i, s := range metaStates {
+		quoted[i] = "'" + s + "'"
+	}
+	return fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM %[1]s meta_wit,
+		jsonb_array_elements_text(meta_wit.fields->'%[2]s'->'type'->'values') WITH ORDINALITY AS meta_state(value, position),
+		jsonb_array_elements_text(meta_wit.fields->'%[3]s'->'type'->'values') WITH ORDINALITY AS meta_metastate(value, position)
+		WHERE meta_wit.id = %[4]s
+		AND meta_state.position = meta_metastate.position
+		AND meta_state.value = %[5]s->>'%[2]s'
+		AND meta_metastate.value IN (%[6]s))`,
+		WorkItemType{}.TableName(), SystemState, SystemMetaState, typeColumn, fieldsColumn, strings.Join(quoted, ","))
+}
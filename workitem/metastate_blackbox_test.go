@@ -0,0 +1,62 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	w "github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkItemType_MetaStateMapping(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	enum := func(values ...interface{}) w.FieldDefinition {
+		return w.FieldDefinition{
+			Type: w.EnumType{
+				SimpleType: w.SimpleType{Kind: w.KindEnum},
+				BaseType:   w.SimpleType{Kind: w.KindString},
+				Values:     values,
+			},
+		}
+	}
+
+	t.Run("states are mapped by position", func(t *testing.T) {
+		t.Parallel()
+		wit := w.WorkItemType{Fields: w.FieldDefinitions{
+			w.SystemState:     enum("todo", "doing", "review", "done"),
+			w.SystemMetaState: enum(w.SystemMetaStateNew, w.SystemMetaStateInProgress, w.SystemMetaStateResolved, w.SystemMetaStateClosed),
+		}}
+		mapping, err := wit.MetaStateMapping()
+		require.NoError(t, err)
+		assert.Equal(t, w.SystemMetaStateInProgress, mapping.MetaState("doing"))
+		assert.True(t, mapping.IsInProgress("doing"))
+		assert.False(t, mapping.IsResolved("doing"))
+		assert.True(t, mapping.IsResolved("review"))
+		assert.False(t, mapping.IsClosed("review"))
+		assert.True(t, mapping.IsResolved("done"))
+		assert.True(t, mapping.IsClosed("done"))
+		assert.False(t, mapping.IsClosed(w.SystemStateClosed))
+	})
+
+	t.Run("no meta-state field", func(t *testing.T) {
+		t.Parallel()
+		wit := w.WorkItemType{Fields: w.FieldDefinitions{
+			w.SystemState: enum("todo", "done"),
+		}}
+		_, err := wit.MetaStateMapping()
+		require.Error(t, err)
+	})
+
+	t.Run("different number of values", func(t *testing.T) {
+		t.Parallel()
+		wit := w.WorkItemType{Fields: w.FieldDefinitions{
+			w.SystemState:     enum("todo", "done"),
+			w.SystemMetaState: enum(w.SystemMetaStateNew),
+		}}
+		_, err := wit.MetaStateMapping()
+		require.Error(t, err)
+	})
+}
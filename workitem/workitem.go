@@ -38,6 +38,21 @@ type WICountsPerIteration struct {
 	Closed      int
 }
 
+// BurndownPoint holds the work items of an iteration at a point in time
+type BurndownPoint struct {
+	Time   time.Time
+	Open   int
+	Closed int
+	// Remaining and Completed hold the sums of a numeric field of the open
+	// and of the closed work items
+	Remaining float64
+	Completed float64
+	// Added and Removed count the work items that were added to or removed
+	// from the iteration since the previous point in time
+	Added   int
+	Removed int
+}
+
 // GetETagData returns the field values to use to generate the ETag
 func (wi WorkItem) GetETagData() []interface{} {
 	return []interface{}{wi.ID, wi.Version, wi.relationShipsChangedAt}
@@ -87,10 +102,6 @@ func (wi WorkItem) ChangeSet(older change.Detector) (change.Set, error) {
 		return nil, errs.New("Other entity has not the same ID: " + olderWorkItem.ID.String())
 	}
 	changes := []change.Change{}
-	// CAUTION: we're only supporting changes to the system.state and to the
-	// board position relationship for now. If we need to support more
-	// attribute changes, this has to be added here. This will be likely
-	// necessary when adding new Actions.
 	// compare system.state
 	if wi.Fields[SystemState] != olderWorkItem.Fields[SystemState] {
 		changes = append(changes, change.Change{
@@ -100,69 +111,86 @@ func (wi WorkItem) ChangeSet(older change.Detector) (change.Set, error) {
 		})
 	}
 	// compare system.boardcolumns
-	// this field looks like this:
-	// system.boardcolumns": ["43f9e838-3b4b-45e8-85eb-dd402e8324b5", "69699af8-cb28-4b90-b829-24c1aad12797"]
-	if wi.Fields[SystemBoardcolumns] == nil && olderWorkItem.Fields[SystemBoardcolumns] == nil {
-		return changes, nil
+	boardcolumnsChanged, err := wi.boardcolumnsChanged(olderWorkItem)
+	if err != nil {
+		return nil, errs.WithStack(err)
 	}
-	if wi.Fields[SystemBoardcolumns] == nil || olderWorkItem.Fields[SystemBoardcolumns] == nil {
+	if boardcolumnsChanged {
 		changes = append(changes, change.Change{
 			AttributeName: SystemBoardcolumns,
 			NewValue:      wi.Fields[SystemBoardcolumns],
 			OldValue:      olderWorkItem.Fields[SystemBoardcolumns],
 		})
-		return changes, nil
 	}
-	if len(wi.Fields[SystemBoardcolumns].([]interface{})) == 0 || len(olderWorkItem.Fields[SystemBoardcolumns].([]interface{})) == 0 {
-		if len(wi.Fields[SystemBoardcolumns].([]interface{})) == 0 && len(olderWorkItem.Fields[SystemBoardcolumns].([]interface{})) == 0 {
-			// both lists are empty, return no change.
-			return changes, nil
+	// compare all other fields in a stable order so that action rules can be
+	// connected to any attribute of a work item.
+	fieldNames := map[string]struct{}{}
+	for k := range wi.Fields {
+		fieldNames[k] = struct{}{}
+	}
+	for k := range olderWorkItem.Fields {
+		fieldNames[k] = struct{}{}
+	}
+	delete(fieldNames, SystemState)
+	delete(fieldNames, SystemBoardcolumns)
+	sortedFieldNames := make([]string, 0, len(fieldNames))
+	for k := range fieldNames {
+		sortedFieldNames = append(sortedFieldNames, k)
+	}
+	sort.Strings(sortedFieldNames)
+	for _, k := range sortedFieldNames {
+		if !reflect.DeepEqual(wi.Fields[k], olderWorkItem.Fields[k]) {
+			changes = append(changes, change.Change{
+				AttributeName: k,
+				NewValue:      wi.Fields[k],
+				OldValue:      olderWorkItem.Fields[k],
+			})
 		}
-		// one of the lists is empty, do return a change.
-		changes = append(changes, change.Change{
-			AttributeName: SystemBoardcolumns,
-			NewValue:      wi.Fields[SystemBoardcolumns],
-			OldValue:      olderWorkItem.Fields[SystemBoardcolumns],
-		})
-		return changes, nil
+	}
+	return changes, nil
+}
+
+// boardcolumnsChanged returns true if the system.boardcolumns values of this
+// work item and the given work item differ. The order of the columns is not
+// relevant.
+func (wi WorkItem) boardcolumnsChanged(olderWorkItem WorkItem) (bool, error) {
+	// this field looks like this:
+	// system.boardcolumns": ["43f9e838-3b4b-45e8-85eb-dd402e8324b5", "69699af8-cb28-4b90-b829-24c1aad12797"]
+	if wi.Fields[SystemBoardcolumns] == nil && olderWorkItem.Fields[SystemBoardcolumns] == nil {
+		return false, nil
+	}
+	if wi.Fields[SystemBoardcolumns] == nil || olderWorkItem.Fields[SystemBoardcolumns] == nil {
+		return true, nil
 	}
 	bcThis, ok1 := wi.Fields[SystemBoardcolumns].([]interface{})
 	bcOlder, ok2 := olderWorkItem.Fields[SystemBoardcolumns].([]interface{})
 	if !ok1 || !ok2 {
-		return nil, errs.New("Boardcolumn slice is not a interface{} slice")
+		return false, errs.New("Boardcolumn slice is not a interface{} slice")
+	}
+	if len(bcThis) == 0 && len(bcOlder) == 0 {
+		// both lists are empty, return no change.
+		return false, nil
 	}
 	if len(bcThis) != len(bcOlder) {
-		changes = append(changes, change.Change{
-			AttributeName: SystemBoardcolumns,
-			NewValue:      wi.Fields[SystemBoardcolumns],
-			OldValue:      olderWorkItem.Fields[SystemBoardcolumns],
-		})
-		return changes, nil
+		return true, nil
 	}
 	// because of the handing of interface{}, we need to do manual conversion here.
+	var ok bool
 	thisCopyStr := make([]string, len(bcThis))
 	for i := range bcThis {
 		thisCopyStr[i], ok = bcThis[i].(string)
 		if !ok {
-			return nil, errs.New("Boardcolumn slice values are not of type string")
+			return false, errs.New("Boardcolumn slice values are not of type string")
 		}
 	}
 	olderCopyStr := make([]string, len(bcOlder))
 	for i := range bcOlder {
 		olderCopyStr[i], ok = bcOlder[i].(string)
 		if !ok {
-			return nil, errs.New("Boardcolumn slice values are not of type string")
+			return false, errs.New("Boardcolumn slice values are not of type string")
 		}
 	}
 	sort.Strings(thisCopyStr)
 	sort.Strings(olderCopyStr)
-	if !reflect.DeepEqual(thisCopyStr, olderCopyStr) {
-		changes = append(changes, change.Change{
-			AttributeName: SystemBoardcolumns,
-			NewValue:      wi.Fields[SystemBoardcolumns],
-			OldValue:      olderWorkItem.Fields[SystemBoardcolumns],
-		})
-		return changes, nil
-	}
-	return changes, nil
+	return !reflect.DeepEqual(thisCopyStr, olderCopyStr), nil
 }
@@ -3,7 +3,9 @@ package workitem
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -48,18 +50,41 @@ type SortWorkItemsBy string
 
 // Available sort orders
 var (
-	SortWorkItemsByExecutionAsc  = SortWorkItemsBy("execution_order ASC")
-	SortWorkItemsByExecutionDesc = SortWorkItemsBy("execution_order DESC")
-	SortWorkItemsByCreatedAtAsc  = SortWorkItemsBy("created_at ASC")
-	SortWorkItemsByCreatedAtDesc = SortWorkItemsBy("created_at DESC")
-	SortWorkItemsByUpdatedAtAsc  = SortWorkItemsBy("updated_at ASC")
-	SortWorkItemsByUpdatedAtDesc = SortWorkItemsBy("updated_at DESC")
+	SortWorkItemsByExecutionAsc  = SortWorkItemsBy(Column(WorkItemStorage{}.TableName(), "execution_order") + " ASC")
+	SortWorkItemsByExecutionDesc = SortWorkItemsBy(Column(WorkItemStorage{}.TableName(), "execution_order") + " DESC")
+	SortWorkItemsByCreatedAtAsc  = SortWorkItemsBy(Column(WorkItemStorage{}.TableName(), "created_at") + " ASC")
+	SortWorkItemsByCreatedAtDesc = SortWorkItemsBy(Column(WorkItemStorage{}.TableName(), "created_at") + " DESC")
+	SortWorkItemsByUpdatedAtAsc  = SortWorkItemsBy(Column(WorkItemStorage{}.TableName(), "updated_at") + " ASC")
+	SortWorkItemsByUpdatedAtDesc = SortWorkItemsBy(Column(WorkItemStorage{}.TableName(), "updated_at") + " DESC")
 	SortWorkItemsByDefault       = SortWorkItemsByExecutionDesc
 )
 
+// sortFieldNameRegex restricts the names of fields that can be used for
+// sorting so that they can safely be put into the ORDER BY clause.
+var sortFieldNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+// SortWorkItemsByField returns the sort order for the field with the given
+// key that is stored in the jsonb "fields" column (e.g. "effort" or
+// "system.title"). The values are compared as JSON values so numbers are
+// ordered numerically and strings lexically. Work items without a value for
+// the field always come last.
+func SortWorkItemsByField(key string, descending bool) (SortWorkItemsBy, error) {
+	if !sortFieldNameRegex.MatchString(key) {
+		return SortWorkItemsBy(""), errors.NewBadParameterError("sort", key).Expected("field name consisting of letters, digits, '_' and '.'")
+	}
+	direction := "ASC"
+	if descending {
+		direction = "DESC"
+	}
+	return SortWorkItemsBy(fmt.Sprintf("%s->'%s' %s NULLS LAST", Column(WorkItemStorage{}.TableName(), "fields"), key, direction)), nil
+}
+
 // ParseSortWorkItemsBy parses the string input and returns object of type SortWorkItemsBy
 // which can directly be used while querying database to order the output.
-func ParseSortWorkItemsBy(s *string) (SortWorkItemsBy, error) {
+// Besides the predefined orders, the key of any field of the given work item
+// types can be given (e.g. "effort"). A leading "-" sorts in descending order.
+// Returns a BadParameterError for any other key.
+func ParseSortWorkItemsBy(s *string, wits []WorkItemType) (SortWorkItemsBy, error) {
 	if s == nil {
 		// this is the default case
 		// which returns workitems with highest execution order
@@ -81,7 +106,13 @@ func ParseSortWorkItemsBy(s *string) (SortWorkItemsBy, error) {
 	case "-updated":
 		sort = SortWorkItemsByUpdatedAtDesc
 	default:
-		return SortWorkItemsBy(""), errors.NewBadParameterError("sort", *s)
+		key := strings.TrimPrefix(*s, "-")
+		for _, wit := range wits {
+			if _, ok := wit.Fields[key]; ok {
+				return SortWorkItemsByField(key, strings.HasPrefix(*s, "-"))
+			}
+		}
+		return SortWorkItemsBy(""), errors.NewBadParameterError("sort", *s).Expected("execution, created, updated or the key of a work item type field, optionally prefixed with '-'")
 	}
 	return sort, nil
 }
@@ -95,13 +126,16 @@ type WorkItemRepository interface {
 	LoadByIteration(ctx context.Context, id uuid.UUID) ([]*WorkItem, error)
 	LookupIDByNamedSpaceAndNumber(ctx context.Context, ownerName, spaceName string, wiNumber int) (*uuid.UUID, *uuid.UUID, error)
 	Save(ctx context.Context, spaceID uuid.UUID, wi WorkItem, modifierID uuid.UUID) (*WorkItem, *Revision, error)
+	Revert(ctx context.Context, id uuid.UUID, revisionID uuid.UUID, version int, restoreType bool, modifierID uuid.UUID) (*WorkItem, *Revision, error)
 	Reorder(ctx context.Context, spaceID uuid.UUID, direction DirectionType, targetID *uuid.UUID, wi WorkItem, modifierID uuid.UUID) (*WorkItem, error)
 	Delete(ctx context.Context, id uuid.UUID, suppressorID uuid.UUID) error
 	Create(ctx context.Context, spaceID uuid.UUID, typeID uuid.UUID, fields map[string]interface{}, creatorID uuid.UUID) (*WorkItem, *Revision, error)
 	List(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, start *int, length *int, sort SortWorkItemsBy) ([]WorkItem, int, error)
+	ListAsOf(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, asOf time.Time, start *int, length *int, sort SortWorkItemsBy) ([]WorkItem, int, error)
 	Fetch(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (*WorkItem, error)
 	GetCountsPerIteration(ctx context.Context, spaceID uuid.UUID) (map[string]WICountsPerIteration, error)
 	GetCountsForIteration(ctx context.Context, itr *iteration.Iteration) (map[string]WICountsPerIteration, error)
+	GetBurndownForIteration(ctx context.Context, itr *iteration.Iteration, field string) ([]BurndownPoint, error)
 	Count(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (int, error)
 	ChangeWorkItemType(ctx context.Context, wiStorage *WorkItemStorage, oldWIType *WorkItemType, newWIType *WorkItemType, spaceID uuid.UUID) error
 }
@@ -580,6 +614,14 @@ func (r *GormWorkItemRepository) Reorder(ctx context.Context, spaceID uuid.UUID,
 // returns NotFoundError, VersionConflictError, ConversionError or InternalError
 func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, updatedWorkItem WorkItem, modifierID uuid.UUID) (*WorkItem, *Revision, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "workitem", "save"}, time.Now())
+	return r.save(ctx, spaceID, updatedWorkItem, false, modifierID)
+}
+
+// save updates the given work item in storage like Save. If the type of the
+// work item changes and restoreType is true, the fields of the given work item
+// are taken as the fields of the new type instead of being converted from the
+// current type by ChangeWorkItemType.
+func (r *GormWorkItemRepository) save(ctx context.Context, spaceID uuid.UUID, updatedWorkItem WorkItem, restoreType bool, modifierID uuid.UUID) (*WorkItem, *Revision, error) {
 	wiStorage, wiType, err := r.loadWorkItemStorage(ctx, spaceID, updatedWorkItem.Number, true)
 	if err != nil {
 		return nil, nil, err
@@ -587,9 +629,20 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 	if wiStorage.Version != updatedWorkItem.Version {
 		return nil, nil, errors.NewVersionConflictError("version conflict")
 	}
+	var newWiType *WorkItemType
+	if wiStorage.Type != updatedWorkItem.Type {
+		newWiType, err = r.witr.Load(ctx, updatedWorkItem.Type)
+		if err != nil {
+			return nil, nil, errs.Wrapf(err, "failed to load workitemtype: %s ", updatedWorkItem.Type)
+		}
+	}
+	fieldsType := wiType
+	if newWiType != nil && restoreType {
+		fieldsType = newWiType
+	}
 	wiStorage.Version = wiStorage.Version + 1
 	wiStorage.Fields = Fields{}
-	for fieldName, fieldDef := range wiType.Fields {
+	for fieldName, fieldDef := range fieldsType.Fields {
 		if fieldDef.ReadOnly {
 			continue
 		}
@@ -615,12 +668,17 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 		}
 	}
 	// Change of Work Item Type
-	if wiStorage.Type != updatedWorkItem.Type {
-		newWiType, err := r.witr.Load(ctx, updatedWorkItem.Type)
-		if err != nil {
-			return nil, nil, errs.Wrapf(err, "failed to load workitemtype: %s ", updatedWorkItem.Type)
-		}
-		if err := r.ChangeWorkItemType(ctx, wiStorage, wiType, newWiType, spaceID); err != nil {
+	if newWiType != nil {
+		if restoreType {
+			allowedWIT, err := r.CheckTypeAndSpaceShareTemplate(ctx, newWiType, spaceID)
+			if err != nil {
+				return nil, nil, errs.Wrap(err, "failed to check workitem type")
+			}
+			if !allowedWIT {
+				return nil, nil, errors.NewBadParameterError("typeID", newWiType.ID)
+			}
+			wiStorage.Type = newWiType.ID
+		} else if err := r.ChangeWorkItemType(ctx, wiStorage, wiType, newWiType, spaceID); err != nil {
 			return nil, nil, errs.Wrapf(err, "unable to change workitem type from %s (ID: %s) to %s (ID: %s)", wiType.Name, wiType.ID, newWiType.Name, newWiType.ID)
 		}
 		// This will be used by the ConvertWorkItemStorageToModel function
@@ -655,6 +713,105 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 	return w, &rev, nil
 }
 
+// referencedEntities holds the entities that fields of the given kinds refer
+// to by their ID.
+var referencedEntities = map[Kind]struct {
+	name  string
+	model interface{}
+}{
+	KindIteration: {"iteration", &iteration.Iteration{}},
+	KindArea:      {"area", &area.Area{}},
+	KindLabel:     {"label", &label.Label{}},
+	KindUser:      {"user", &account.Identity{}},
+}
+
+// checkReferences returns a BadParameterError if a value of the given fields
+// refers to an iteration, area, label or user that does not exist (anymore).
+func (r *GormWorkItemRepository) checkReferences(ctx context.Context, wiType *WorkItemType, fields map[string]interface{}) error {
+	for fieldName, fieldDef := range wiType.Fields {
+		kind := fieldDef.Type.GetKind()
+		values := []interface{}{fields[fieldName]}
+		if listType, ok := fieldDef.Type.(ListType); ok {
+			kind = listType.ComponentType.GetKind()
+			values, _ = fields[fieldName].([]interface{})
+		}
+		entity, ok := referencedEntities[kind]
+		if !ok {
+			continue
+		}
+		ids := map[string]struct{}{}
+		for _, v := range values {
+			if v != nil {
+				ids[fmt.Sprint(v)] = struct{}{}
+			}
+		}
+		for id := range ids {
+			var count int
+			if err := r.db.Model(entity.model).Where("id = ?", id).Count(&count).Error; err != nil {
+				return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to check if the %s %s exists", entity.name, id))
+			}
+			if count == 0 {
+				return errors.NewBadParameterError(fieldName, id).Expected(fmt.Sprintf("an existing %s", entity.name))
+			}
+		}
+	}
+	return nil
+}
+
+// Revert restores the fields of the work item with the given ID and, if
+// restoreType is true, its type from the given revision of the work item. The
+// work item is saved like any other update, so the given version must be the
+// current one. Read-only fields keep their current values. A revision that
+// refers to an iteration, area, label or user that no longer exists cannot be
+// restored.
+func (r *GormWorkItemRepository) Revert(ctx context.Context, id uuid.UUID, revisionID uuid.UUID, version int, restoreType bool, modifierID uuid.UUID) (*WorkItem, *Revision, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "revert"}, time.Now())
+	wi, err := r.LoadByID(ctx, id)
+	if err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	rev, err := r.wirr.Load(ctx, revisionID)
+	if err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	if rev.WorkItemID != id {
+		return nil, nil, errors.NewNotFoundError("work item revision", revisionID.String())
+	}
+	if rev.Type == RevisionTypeDelete {
+		return nil, nil, errors.NewBadParameterError("revision", revisionID).Expected("a revision that holds the fields of the work item")
+	}
+	revType, err := r.witr.Load(ctx, rev.WorkItemTypeID)
+	if err != nil {
+		return nil, nil, errs.Wrapf(err, "failed to load work item type: %s", rev.WorkItemTypeID)
+	}
+	revWI, err := revType.ConvertWorkItemStorageToModel(WorkItemStorage{Type: rev.WorkItemTypeID, Fields: rev.WorkItemFields})
+	if err != nil {
+		return nil, nil, errors.NewConversionError(err.Error())
+	}
+	if err := r.checkReferences(ctx, revType, revWI.Fields); err != nil {
+		return nil, nil, err
+	}
+	wi.Version = version
+	wiType := revType
+	if restoreType {
+		wi.Type = rev.WorkItemTypeID
+	} else if wi.Type != rev.WorkItemTypeID {
+		wiType, err = r.witr.Load(ctx, wi.Type)
+		if err != nil {
+			return nil, nil, errs.Wrapf(err, "failed to load work item type: %s", wi.Type)
+		}
+	}
+	for fieldName, fieldDef := range wiType.Fields {
+		if fieldDef.ReadOnly {
+			continue
+		}
+		wi.Fields[fieldName] = revWI.Fields[fieldName]
+	}
+	// the type and the fields are restored in a single update, so that the
+	// revert results in a single revision.
+	return r.save(ctx, wi.SpaceID, *wi, restoreType, modifierID)
+}
+
 // CheckTypeAndSpaceShareTemplate returns true if the given workitem type (wit)
 // belongs to the same space template as the space (spaceID); otherwise false is
 // returned
@@ -786,7 +943,7 @@ func ConvertWorkItemStorageToModel(wiType *WorkItemType, wi *WorkItemStorage) (*
 
 // extracted this function from List() in order to close the rows object with "defer" for more readability
 // workaround for https://github.com/lib/pq/issues/81
-func (r *GormWorkItemRepository) listItemsFromDB(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, start *int, limit *int, sort SortWorkItemsBy) ([]WorkItemStorage, int, error) {
+func (r *GormWorkItemRepository) listItemsFromDB(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, asOf *time.Time, start *int, limit *int, sort SortWorkItemsBy) ([]WorkItemStorage, int, error) {
 	where, parameters, joins, compileErrors := Compile(criteria)
 	if compileErrors != nil {
 		log.Error(ctx, map[string]interface{}{"compile_errors": compileErrors, "expression": criteria}, "failed to compile expression")
@@ -796,17 +953,29 @@ func (r *GormWorkItemRepository) listItemsFromDB(ctx context.Context, spaceID uu
 	parameters = append(parameters, spaceID.String())
 
 	if parentExists != nil && !*parentExists {
+		linksAsOf := ""
+		if asOf != nil {
+			linksAsOf = " AND created_at <= ? AND (deleted_at IS NULL OR deleted_at > ?)"
+		}
 		where += ` AND
 			id NOT IN (
 				SELECT target_id FROM work_item_links
-				WHERE link_type_id = ?
+				WHERE link_type_id = ?` + linksAsOf + `
 			)`
 		// TODO(kwk): This ID should be replaced with
 		// link.SystemWorkItemLinkTypeParentChildID but that would cause an
 		// import cycle
 		parameters = append(parameters, uuid.FromStringOrNil("25C326A7-6D03-4F5A-B23B-86A9EE4171E9").String())
+		if asOf != nil {
+			parameters = append(parameters, *asOf, *asOf)
+		}
 	}
-	db := r.db.Model(&WorkItemStorage{}).Where(where, parameters...)
+	db := r.db.Model(&WorkItemStorage{})
+	if asOf != nil {
+		// deleted work items are handled by the table expression
+		db = r.db.Unscoped().Table(WorkItemsAsOf(*asOf))
+	}
+	db = db.Where(where, parameters...)
 
 	for _, j := range joins {
 		if err := j.Validate(db); err != nil {
@@ -885,7 +1054,20 @@ func (r *GormWorkItemRepository) listItemsFromDB(ctx context.Context, spaceID uu
 // List returns work item selected by the given criteria.Expression, starting with start (zero-based) and returning at most limit items
 func (r *GormWorkItemRepository) List(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, start *int, limit *int, sort SortWorkItemsBy) ([]WorkItem, int, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "workitem", "list"}, time.Now())
-	result, count, err := r.listItemsFromDB(ctx, spaceID, criteria, parentExists, start, limit, sort)
+	return r.list(ctx, spaceID, criteria, parentExists, nil, start, limit, sort)
+}
+
+// ListAsOf works like List but evaluates the criteria against the work items
+// as they were at the given time (see WorkItemsAsOf), including the work items
+// deleted since then.
+func (r *GormWorkItemRepository) ListAsOf(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, asOf time.Time, start *int, limit *int, sort SortWorkItemsBy) ([]WorkItem, int, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "list", "asof"}, time.Now())
+	return r.list(ctx, spaceID, criteria, parentExists, &asOf, start, limit, sort)
+}
+
+// list implements List and ListAsOf. asOf is nil for the current work items.
+func (r *GormWorkItemRepository) list(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, asOf *time.Time, start *int, limit *int, sort SortWorkItemsBy) ([]WorkItem, int, error) {
+	result, count, err := r.listItemsFromDB(ctx, spaceID, criteria, parentExists, asOf, start, limit, sort)
 	if err != nil {
 		return nil, 0, errs.WithStack(err)
 	}
@@ -1091,21 +1273,10 @@ func (r *GormWorkItemRepository) GetCountsPerIteration(ctx context.Context, spac
 func (r *GormWorkItemRepository) GetCountsForIteration(ctx context.Context, itr *iteration.Iteration) (map[string]WICountsPerIteration, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "workitem", "getCountsForIteration"}, time.Now())
 	var res WICountsPerIteration
-	// get child IDs of the iteration
-	var childIDs []uuid.UUID
-	iterationTable := iteration.Iteration{}
-	iterationTableName := iterationTable.TableName()
-	getIterationsOfSpace := fmt.Sprintf(`SELECT id FROM %s WHERE path <@ ? and space_id = ?`, iterationTableName)
-	db := r.db.Raw(getIterationsOfSpace, itr.Path.Convert(), itr.SpaceID.String())
-	db.Pluck("id", &childIDs)
-	if db.Error != nil {
-		log.Error(ctx, map[string]interface{}{
-			"path": itr.Path.Convert(),
-			"err":  db.Error,
-		}, "unable to fetch children for path")
-		return nil, errors.NewInternalError(ctx, db.Error)
+	childIDs, err := r.loadIterationAndChildIDs(ctx, itr)
+	if err != nil {
+		return nil, err
 	}
-	childIDs = append(childIDs, itr.ID)
 
 	// build where clause usig above ID list
 	idsToLookFor := []string{}
@@ -1123,7 +1294,7 @@ func (r *GormWorkItemRepository) GetCountsForIteration(ctx context.Context, itr
 					WHERE %s
 					AND wi.deleted_at IS NULL`,
 		workitemTableName, whereClause)
-	db = r.db.Raw(query)
+	db := r.db.Raw(query)
 	db.Scan(&res)
 	if db.Error != nil {
 		log.Error(ctx, map[string]interface{}{
@@ -1141,6 +1312,192 @@ func (r *GormWorkItemRepository) GetCountsForIteration(ctx context.Context, itr
 	return countsMap, nil
 }
 
+// loadIterationAndChildIDs returns the IDs of the child iterations of the
+// given iteration and the ID of the iteration itself
+func (r *GormWorkItemRepository) loadIterationAndChildIDs(ctx context.Context, itr *iteration.Iteration) ([]uuid.UUID, error) {
+	var childIDs []uuid.UUID
+	iterationTable := iteration.Iteration{}
+	iterationTableName := iterationTable.TableName()
+	getIterationsOfSpace := fmt.Sprintf(`SELECT id FROM %s WHERE path <@ ? and space_id = ?`, iterationTableName)
+	db := r.db.Raw(getIterationsOfSpace, itr.Path.Convert(), itr.SpaceID.String())
+	db.Pluck("id", &childIDs)
+	if db.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"path": itr.Path.Convert(),
+			"err":  db.Error,
+		}, "unable to fetch children for path")
+		return nil, errors.NewInternalError(ctx, db.Error)
+	}
+	return append(childIDs, itr.ID), nil
+}
+
+// burndownRevision is a revision of a work item reduced to what the burndown
+// of an iteration needs
+type burndownRevision struct {
+	workItemID uuid.UUID
+	typeID     uuid.UUID
+	time       time.Time
+	deleted    bool
+	state      string
+	iteration  string
+	value      string
+}
+
+// GetBurndownForIteration returns the work items of the given iteration and
+// its child iterations at the start of the iteration and at the end of each
+// day until the end of the iteration or until now. The work items are taken
+// from their revisions, so that work items that were moved to another
+// iteration or deleted since are counted as well. The values of the given
+// numeric field are summed up unless the field is empty.
+func (r *GormWorkItemRepository) GetBurndownForIteration(ctx context.Context, itr *iteration.Iteration, field string) ([]BurndownPoint, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "getBurndownForIteration"}, time.Now())
+	if itr.StartAt == nil || itr.EndAt == nil {
+		return nil, errors.NewBadParameterError("iteration", itr.ID).Expected("an iteration with a start and an end date")
+	}
+	end := *itr.EndAt
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+	times := DailyTimes(*itr.StartAt, end)
+	if len(times) == 0 {
+		return []BurndownPoint{}, nil
+	}
+	childIDs, err := r.loadIterationAndChildIDs(ctx, itr)
+	if err != nil {
+		return nil, err
+	}
+	iterationIDs := make([]string, len(childIDs))
+	inIteration := map[string]bool{}
+	for i, id := range childIDs {
+		iterationIDs[i] = id.String()
+		inIteration[id.String()] = true
+	}
+	revisions, err := r.listIterationRevisions(ctx, itr, iterationIDs, field, times[0], times[len(times)-1])
+	if err != nil {
+		return nil, err
+	}
+	points := make([]BurndownPoint, len(times))
+	for i, t := range times {
+		points[i].Time = t
+	}
+	mappings := map[uuid.UUID]MetaStateMapping{}
+	for from := 0; from < len(revisions); {
+		to := from + 1
+		for to < len(revisions) && revisions[to].workItemID == revisions[from].workItemID {
+			to++
+		}
+		workItemRevisions := revisions[from:to]
+		from = to
+
+		// the latest revision of the work item at each point in time
+		j := -1
+		wasIn := false
+		for i, t := range times {
+			for j+1 < len(workItemRevisions) && !workItemRevisions[j+1].time.After(t) {
+				j++
+			}
+			isIn := j >= 0 && !workItemRevisions[j].deleted && inIteration[workItemRevisions[j].iteration]
+			if i > 0 && isIn && !wasIn {
+				points[i].Added++
+			}
+			if i > 0 && !isIn && wasIn {
+				points[i].Removed++
+			}
+			wasIn = isIn
+			if !isIn {
+				continue
+			}
+			rev := workItemRevisions[j]
+			mapping, err := r.metaStateMapping(ctx, mappings, rev.typeID)
+			if err != nil {
+				return nil, err
+			}
+			// non-numeric values are ignored
+			v, _ := strconv.ParseFloat(rev.value, 64)
+			if mapping.IsClosed(rev.state) {
+				points[i].Closed++
+				points[i].Completed += v
+			} else {
+				points[i].Open++
+				points[i].Remaining += v
+			}
+		}
+	}
+	return points, nil
+}
+
+// listIterationRevisions returns the revisions of the work items of the space
+// of the given iteration that are in one of the given iterations at some point
+// until end, ordered by work item and time. Of the revisions until start,
+// only the latest one of every work item is returned.
+func (r *GormWorkItemRepository) listIterationRevisions(ctx context.Context, itr *iteration.Iteration, iterationIDs []string, field string, start, end time.Time) ([]burndownRevision, error) {
+	workItemIDs := fmt.Sprintf(`SELECT work_item_id FROM %s WHERE work_item_fields->>'%s' IN (?) AND revision_time <= ?`,
+		revisionTableName, SystemIteration)
+	query := fmt.Sprintf(`SELECT work_item_id, work_item_type_id, revision_time, revision_type, state, iteration, value FROM (
+			(SELECT DISTINCT ON (r.work_item_id) %[1]s
+			FROM %[2]s r JOIN %[3]s wi ON wi.id = r.work_item_id
+			WHERE wi.space_id = ? AND r.revision_time <= ? AND r.work_item_id IN (%[4]s)
+			ORDER BY r.work_item_id, r.revision_time DESC)
+		UNION ALL
+			(SELECT %[1]s
+			FROM %[2]s r JOIN %[3]s wi ON wi.id = r.work_item_id
+			WHERE wi.space_id = ? AND r.revision_time > ? AND r.revision_time <= ? AND r.work_item_id IN (%[4]s))
+		) AS revisions
+		ORDER BY work_item_id, revision_time`,
+		fmt.Sprintf(`r.work_item_id, r.work_item_type_id, r.revision_time, r.revision_type, r.work_item_fields->>'%s' AS state, r.work_item_fields->>'%s' AS iteration, r.work_item_fields->>? AS value`, SystemState, SystemIteration),
+		revisionTableName, workitemTableName, workItemIDs)
+	rows, err := r.db.Raw(query,
+		field, itr.SpaceID, start, iterationIDs, end,
+		field, itr.SpaceID, start, end, iterationIDs, end).Rows()
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"iteration_id": itr.ID,
+			"err":          err,
+		}, "unable to fetch the revisions of the work items of the iteration")
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to fetch the revisions of the work items of iteration %s", itr.ID))
+	}
+	defer closeable.Close(ctx, rows)
+	res := []burndownRevision{}
+	for rows.Next() {
+		var rev burndownRevision
+		var revType RevisionType
+		var state, iterationID, value sql.NullString
+		if err := rows.Scan(&rev.workItemID, &rev.typeID, &rev.time, &revType, &state, &iterationID, &value); err != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to scan the revisions of the work items of the iteration"))
+		}
+		rev.deleted = revType == RevisionTypeDelete
+		rev.state, rev.iteration, rev.value = state.String, iterationID.String, value.String
+		res = append(res, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to fetch the revisions of the work items of the iteration"))
+	}
+	return res, nil
+}
+
+// metaStateMapping returns the meta-state mapping of the work item type with
+// the given ID from the given cache or loads it into the cache. The mapping of
+// a type without one is empty, so that none of its states counts as closed.
+func (r *GormWorkItemRepository) metaStateMapping(ctx context.Context, cache map[uuid.UUID]MetaStateMapping, typeID uuid.UUID) (MetaStateMapping, error) {
+	if mapping, ok := cache[typeID]; ok {
+		return mapping, nil
+	}
+	wit, err := r.witr.Load(ctx, typeID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load work item type %s", typeID)
+	}
+	mapping, err := wit.MetaStateMapping()
+	if err != nil {
+		log.Info(ctx, map[string]interface{}{
+			"wit_id": typeID,
+			"err":    err,
+		}, "work item type has no meta-state mapping")
+		mapping = MetaStateMapping{}
+	}
+	cache[typeID] = mapping
+	return mapping, nil
+}
+
 // LoadByIteration returns the list of work items belongs to given iteration
 func (r *GormWorkItemRepository) LoadByIteration(ctx context.Context, iterationID uuid.UUID) ([]*WorkItem, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "workitem", "loadByIteration"}, time.Now())
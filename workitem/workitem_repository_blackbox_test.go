@@ -11,8 +11,10 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/fabric8-services/fabric8-common/id"
 	"github.com/fabric8-services/fabric8-wit/codebase"
+	"github.com/fabric8-services/fabric8-wit/criteria"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/iteration"
 	"github.com/fabric8-services/fabric8-wit/ptr"
 	query "github.com/fabric8-services/fabric8-wit/query/simple"
 	"github.com/fabric8-services/fabric8-wit/rendering"
@@ -488,6 +490,163 @@ func (s *workItemRepoBlackBoxTest) TestGetCountsPerIteration() {
 	})
 }
 
+func (s *workItemRepoBlackBoxTest) TestGetBurndownForIteration() {
+	// the field name is specific to this test so that no other work item type
+	// in the database defines it with another type.
+	const pointsField = "burndown_test_points"
+	now := time.Now().UTC()
+	start := now.Add(-72 * time.Hour)
+	end := now.Add(24 * time.Hour)
+	// the days end at midnight
+	t1 := start.Truncate(24 * time.Hour).Add(24 * time.Hour)
+	t2 := t1.Add(24 * time.Hour)
+	t3 := t2.Add(24 * time.Hour)
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.Iterations(3, func(fxt *tf.TestFixture, idx int) error {
+			if idx == 0 {
+				fxt.Iterations[idx].StartAt = &start
+				fxt.Iterations[idx].EndAt = &end
+			}
+			return nil
+		}),
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Fields[pointsField] = workitem.FieldDefinition{
+				Label: "Points",
+				Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+			}
+			return nil
+		}),
+		tf.WorkItems(4, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+			fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateNew
+			fxt.WorkItems[idx].Fields[pointsField] = float64(idx + 1)
+			return nil
+		}),
+	)
+	// setRevisionTime moves the latest revision of the given work item to the
+	// given time
+	setRevisionTime := func(id uuid.UUID, t time.Time) {
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, id)
+		require.NoError(s.T(), err)
+		require.NoError(s.T(), s.DB.Exec("UPDATE work_item_revisions SET revision_time = ? WHERE id = ?", t, revisions[len(revisions)-1].ID).Error)
+	}
+	// the first three work items exist before the iteration starts and the
+	// fourth one is created on the third day
+	for i := 0; i < 3; i++ {
+		setRevisionTime(fxt.WorkItems[i].ID, start.Add(-time.Hour))
+	}
+	setRevisionTime(fxt.WorkItems[3].ID, t2.Add(time.Hour))
+	// the first one is closed on the first day
+	fxt.WorkItems[0].Fields[workitem.SystemState] = workitem.SystemStateClosed
+	_, _, err := s.repo.Save(s.Ctx, fxt.Spaces[0].ID, *fxt.WorkItems[0], fxt.Identities[0].ID)
+	require.NoError(s.T(), err)
+	setRevisionTime(fxt.WorkItems[0].ID, start.Add(t1.Sub(start)/2))
+	// the second one is moved to another iteration on the second day
+	fxt.WorkItems[1].Fields[workitem.SystemIteration] = fxt.Iterations[1].ID.String()
+	_, _, err = s.repo.Save(s.Ctx, fxt.Spaces[0].ID, *fxt.WorkItems[1], fxt.Identities[0].ID)
+	require.NoError(s.T(), err)
+	setRevisionTime(fxt.WorkItems[1].ID, t1.Add(time.Hour))
+
+	s.T().Run("ok", func(t *testing.T) {
+		// when
+		points, err := s.repo.GetBurndownForIteration(s.Ctx, fxt.Iterations[0], pointsField)
+		// then
+		require.NoError(t, err)
+		require.Len(t, points, 5)
+		expected := []workitem.BurndownPoint{
+			{Time: start, Open: 3, Closed: 0, Remaining: 6, Completed: 0},
+			{Time: t1, Open: 2, Closed: 1, Remaining: 5, Completed: 1},
+			{Time: t2, Open: 1, Closed: 1, Remaining: 3, Completed: 1, Removed: 1},
+			{Time: t3, Open: 2, Closed: 1, Remaining: 7, Completed: 1, Added: 1},
+		}
+		for i, e := range expected {
+			assert.True(t, e.Time.Equal(points[i].Time), "point %d: expected %s, got %s", i, e.Time, points[i].Time)
+			points[i].Time = e.Time
+			assert.Equal(t, e, points[i], "point %d", i)
+		}
+		assert.Equal(t, 2, points[4].Open)
+		assert.Equal(t, 1, points[4].Closed)
+	})
+
+	s.T().Run("without field", func(t *testing.T) {
+		// when
+		points, err := s.repo.GetBurndownForIteration(s.Ctx, fxt.Iterations[0], "")
+		// then
+		require.NoError(t, err)
+		require.Len(t, points, 5)
+		assert.Equal(t, 3, points[0].Open)
+		assert.Equal(t, 0.0, points[0].Remaining)
+	})
+
+	s.T().Run("closed state of another name", func(t *testing.T) {
+		// given a type whose closed meta-state is called done and two work
+		// items of which one is done
+		enum := func(values ...interface{}) workitem.FieldDefinition {
+			return workitem.FieldDefinition{
+				Label: "State",
+				Type: workitem.EnumType{
+					SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
+					BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+					Values:     values,
+				},
+			}
+		}
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.Iterations(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.Iterations[idx].StartAt = &start
+				fxt.Iterations[idx].EndAt = &end
+				return nil
+			}),
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemTypes[idx].Extends = uuid.Nil
+				fxt.WorkItemTypes[idx].Fields = workitem.FieldDefinitions{
+					workitem.SystemIteration: {Label: "Iteration", Type: workitem.SimpleType{Kind: workitem.KindIteration}},
+					workitem.SystemState:     enum(workitem.SystemStateNew, "done"),
+					workitem.SystemMetaState: enum(workitem.SystemMetaStateNew, workitem.SystemMetaStateClosed),
+				}
+				return nil
+			}),
+			tf.WorkItems(2, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+				fxt.WorkItems[idx].Fields[workitem.SystemState] = []string{workitem.SystemStateNew, "done"}[idx]
+				return nil
+			}),
+		)
+		for _, wi := range fxt.WorkItems {
+			revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, wi.ID)
+			require.NoError(t, err)
+			require.NoError(t, s.DB.Exec("UPDATE work_item_revisions SET revision_time = ? WHERE id = ?", start.Add(-time.Hour), revisions[0].ID).Error)
+		}
+		// when
+		points, err := s.repo.GetBurndownForIteration(s.Ctx, fxt.Iterations[0], "")
+		// then
+		require.NoError(t, err)
+		require.Len(t, points, 5)
+		assert.Equal(t, 1, points[0].Open)
+		assert.Equal(t, 1, points[0].Closed)
+	})
+
+	s.T().Run("iteration without dates", func(t *testing.T) {
+		// when
+		_, err := s.repo.GetBurndownForIteration(s.Ctx, fxt.Iterations[2], pointsField)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("iteration not started yet", func(t *testing.T) {
+		// given
+		future := *fxt.Iterations[2]
+		startAt, endAt := now.Add(24*time.Hour), now.Add(48*time.Hour)
+		future.StartAt, future.EndAt = &startAt, &endAt
+		// when
+		points, err := s.repo.GetBurndownForIteration(s.Ctx, &future, pointsField)
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, points)
+	})
+}
+
 func (s *workItemRepoBlackBoxTest) TestLookupIDByNamedSpaceAndNumber() {
 	s.T().Run("ok", func(t *testing.T) {
 		// given
@@ -655,7 +814,7 @@ func (s *workItemRepoBlackBoxTest) TestList() {
 		t.Run("by created descending", func(t *testing.T) {
 			// when
 			exp, _ := query.Parse(ptr.String(`{"system.state": "open"}`))
-			sort, _ := workitem.ParseSortWorkItemsBy(ptr.String("-created"))
+			sort, _ := workitem.ParseSortWorkItemsBy(ptr.String("-created"), nil)
 			res, count, err := s.repo.List(context.Background(), fxt.Spaces[0].ID, exp, nil, nil, nil, sort)
 			// then
 			require.NoError(t, err)
@@ -682,7 +841,7 @@ func (s *workItemRepoBlackBoxTest) TestList() {
 		t.Run("by created ascending", func(t *testing.T) {
 			// when
 			exp, _ := query.Parse(ptr.String(`{"system.state": "open"}`))
-			sort, _ := workitem.ParseSortWorkItemsBy(ptr.String("created"))
+			sort, _ := workitem.ParseSortWorkItemsBy(ptr.String("created"), nil)
 			res, count, err := s.repo.List(context.Background(), fxt.Spaces[0].ID, exp, nil, nil, nil, sort)
 			// then
 			require.NoError(t, err)
@@ -712,7 +871,7 @@ func (s *workItemRepoBlackBoxTest) TestList() {
 				s.repo.Save(context.Background(), fxt.WorkItems[v].SpaceID, *fxt.WorkItems[v], fxt.Identities[0].ID)
 			}
 			exp, _ := query.Parse(ptr.String(`{"system.state": "open"}`))
-			sort, _ := workitem.ParseSortWorkItemsBy(ptr.String("-updated"))
+			sort, _ := workitem.ParseSortWorkItemsBy(ptr.String("-updated"), nil)
 			res, count, err := s.repo.List(context.Background(), fxt.Spaces[0].ID, exp, nil, nil, nil, sort)
 			// then
 			require.NoError(t, err)
@@ -742,7 +901,7 @@ func (s *workItemRepoBlackBoxTest) TestList() {
 				s.repo.Save(context.Background(), fxt.WorkItems[v].SpaceID, *fxt.WorkItems[v], fxt.Identities[0].ID)
 			}
 			exp, _ := query.Parse(ptr.String(`{"system.state": "open"}`))
-			sort, _ := workitem.ParseSortWorkItemsBy(ptr.String("updated"))
+			sort, _ := workitem.ParseSortWorkItemsBy(ptr.String("updated"), nil)
 			res, count, err := s.repo.List(context.Background(), fxt.Spaces[0].ID, exp, nil, nil, nil, sort)
 			// then
 			require.NoError(t, err)
@@ -768,6 +927,213 @@ func (s *workItemRepoBlackBoxTest) TestList() {
 		})
 
 	})
+
+	s.T().Run("list ordered by field", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemTypes[idx].Fields["effort"] = workitem.FieldDefinition{
+					Label: "Effort",
+					Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+				}
+				return nil
+			}),
+			tf.WorkItems(4, func(fxt *tf.TestFixture, idx int) error {
+				switch idx {
+				case 0:
+					fxt.WorkItems[idx].Fields["effort"] = 3.0
+				case 1:
+					fxt.WorkItems[idx].Fields["effort"] = 1.5
+				case 3:
+					fxt.WorkItems[idx].Fields["effort"] = 2.0
+				}
+				return nil
+			}),
+		)
+		t.Run("ascending", func(t *testing.T) {
+			// when
+			sort, err := workitem.ParseSortWorkItemsBy(ptr.String("effort"), []workitem.WorkItemType{*fxt.WorkItemTypes[0]})
+			require.NoError(t, err)
+			res, count, err := s.repo.List(context.Background(), fxt.Spaces[0].ID, criteria.Literal(true), nil, nil, nil, sort)
+			// then
+			require.NoError(t, err)
+			require.Equal(t, 4, count)
+			// work items without effort come last
+			for i, v := range []int{1, 3, 0, 2} {
+				require.Equal(t, fxt.WorkItems[v].ID, res[i].ID)
+			}
+		})
+		t.Run("descending", func(t *testing.T) {
+			// when
+			sort, err := workitem.ParseSortWorkItemsBy(ptr.String("-effort"), []workitem.WorkItemType{*fxt.WorkItemTypes[0]})
+			require.NoError(t, err)
+			res, count, err := s.repo.List(context.Background(), fxt.Spaces[0].ID, criteria.Literal(true), nil, nil, nil, sort)
+			// then
+			require.NoError(t, err)
+			require.Equal(t, 4, count)
+			for i, v := range []int{0, 3, 1, 2} {
+				require.Equal(t, fxt.WorkItems[v].ID, res[i].ID)
+			}
+		})
+		t.Run("invalid field name", func(t *testing.T) {
+			_, err := workitem.ParseSortWorkItemsBy(ptr.String("effort' DESC; --"), []workitem.WorkItemType{*fxt.WorkItemTypes[0]})
+			require.Error(t, err)
+			require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		})
+		t.Run("unknown field", func(t *testing.T) {
+			_, err := workitem.ParseSortWorkItemsBy(ptr.String("-priority"), []workitem.WorkItemType{*fxt.WorkItemTypes[0]})
+			require.Error(t, err)
+			require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		})
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestListAsOf() {
+	// given three open work items of which the first one is renamed, the second
+	// one is deleted and the third one is closed after the point in time
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(3, func(fxt *tf.TestFixture, idx int) error {
+		fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateOpen
+		return nil
+	}))
+	asOf := time.Now()
+	oldTitle := fxt.WorkItems[0].Fields[workitem.SystemTitle]
+	fxt.WorkItems[0].Fields[workitem.SystemTitle] = "renamed"
+	_, _, err := s.repo.Save(s.Ctx, fxt.Spaces[0].ID, *fxt.WorkItems[0], fxt.Identities[0].ID)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.repo.Delete(s.Ctx, fxt.WorkItems[1].ID, fxt.Identities[0].ID))
+	fxt.WorkItems[2].Fields[workitem.SystemState] = workitem.SystemStateClosed
+	_, _, err = s.repo.Save(s.Ctx, fxt.Spaces[0].ID, *fxt.WorkItems[2], fxt.Identities[0].ID)
+	require.NoError(s.T(), err)
+	openState := criteria.Equals(criteria.Field(workitem.SystemState), criteria.Literal(workitem.SystemStateOpen))
+
+	s.T().Run("as of the past", func(t *testing.T) {
+		// when
+		res, count, err := s.repo.ListAsOf(s.Ctx, fxt.Spaces[0].ID, openState, nil, asOf, nil, nil, workitem.SortWorkItemsByCreatedAtAsc)
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+		require.Len(t, res, 3)
+		for i, wi := range res {
+			assert.Equal(t, fxt.WorkItems[i].ID, wi.ID)
+		}
+		assert.Equal(t, oldTitle, res[0].Fields[workitem.SystemTitle])
+		assert.Equal(t, fxt.WorkItems[0].Number, res[0].Number)
+	})
+
+	s.T().Run("as of now", func(t *testing.T) {
+		// when
+		now := time.Now()
+		res, count, err := s.repo.ListAsOf(s.Ctx, fxt.Spaces[0].ID, openState, nil, now, nil, nil, workitem.SortWorkItemsByCreatedAtAsc)
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		assert.Equal(t, fxt.WorkItems[0].ID, res[0].ID)
+		assert.Equal(t, "renamed", res[0].Fields[workitem.SystemTitle])
+	})
+
+	s.T().Run("before the work items existed", func(t *testing.T) {
+		// when
+		before := fxt.WorkItems[0].Fields[workitem.SystemCreatedAt].(time.Time).Add(-time.Minute)
+		_, count, err := s.repo.ListAsOf(s.Ctx, fxt.Spaces[0].ID, criteria.Literal(true), nil, before, nil, nil, workitem.SortWorkItemsByDefault)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestRevert() {
+	// given a work item whose title and iteration are changed after its
+	// creation
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Iterations(2), tf.WorkItems(2, func(fxt *tf.TestFixture, idx int) error {
+		fxt.WorkItems[idx].Fields[workitem.SystemTitle] = "original"
+		fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+		return nil
+	}))
+	wi := *fxt.WorkItems[0]
+	wi.Fields[workitem.SystemTitle] = "changed"
+	wi.Fields[workitem.SystemIteration] = fxt.Iterations[1].ID.String()
+	changed, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+	require.NoError(s.T(), err)
+	revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, wi.ID)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), revisions, 2)
+
+	s.T().Run("ok", func(t *testing.T) {
+		// when
+		reverted, rev, err := s.repo.Revert(s.Ctx, wi.ID, revisions[0].ID, changed.Version, false, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "original", reverted.Fields[workitem.SystemTitle])
+		assert.Equal(t, fxt.Iterations[0].ID.String(), reverted.Fields[workitem.SystemIteration])
+		assert.Equal(t, changed.Version+1, reverted.Version)
+		assert.Equal(t, wi.Number, reverted.Number)
+		require.NotNil(t, rev)
+		assert.Equal(t, workitem.RevisionTypeUpdate, rev.Type)
+		assert.Equal(t, reverted.Version, rev.WorkItemVersion)
+		changed = reverted
+	})
+
+	s.T().Run("version conflict", func(t *testing.T) {
+		// when
+		_, _, err := s.repo.Revert(s.Ctx, wi.ID, revisions[1].ID, changed.Version-1, false, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.VersionConflictError{}, errs.Cause(err))
+	})
+
+	s.T().Run("revision of another work item", func(t *testing.T) {
+		// when
+		otherRevisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, fxt.WorkItems[1].ID)
+		require.NoError(t, err)
+		_, _, err = s.repo.Revert(s.Ctx, wi.ID, otherRevisions[0].ID, changed.Version, false, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+
+	s.T().Run("unknown revision", func(t *testing.T) {
+		// when
+		_, _, err := s.repo.Revert(s.Ctx, wi.ID, uuid.NewV4(), changed.Version, false, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+
+	s.T().Run("deleted iteration", func(t *testing.T) {
+		// given
+		require.NoError(t, iteration.NewIterationRepository(s.DB).Delete(s.Ctx, fxt.Iterations[1].ID))
+		// when
+		_, _, err := s.repo.Revert(s.Ctx, wi.ID, revisions[1].ID, changed.Version, false, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		loaded, err := s.repo.LoadByID(s.Ctx, wi.ID)
+		require.NoError(t, err)
+		assert.Equal(t, changed.Version, loaded.Version)
+	})
+
+	s.T().Run("restore type", func(t *testing.T) {
+		// given a work item whose type is changed after its creation
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(2), tf.WorkItems(1))
+		wi := *fxt.WorkItems[0]
+		wi.Type = fxt.WorkItemTypes[1].ID
+		changed, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, wi.ID)
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		// when
+		reverted, rev, err := s.repo.Revert(s.Ctx, wi.ID, revisions[0].ID, changed.Version, true, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, reverted.Type)
+		assert.Equal(t, fxt.WorkItems[0].Fields[workitem.SystemTitle], reverted.Fields[workitem.SystemTitle])
+		assert.Equal(t, changed.Version+1, reverted.Version)
+		require.NotNil(t, rev)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, rev.WorkItemTypeID)
+		revisions, err = workitem.NewRevisionRepository(s.DB).List(s.Ctx, wi.ID)
+		require.NoError(t, err)
+		assert.Len(t, revisions, 3)
+	})
 }
 
 func (s *workItemRepoBlackBoxTest) TestDeleteWorkitem() {
@@ -1,6 +1,7 @@
 package workitem
 
 import (
+	"fmt"
 	"time"
 
 	uuid "github.com/satori/go.uuid"
@@ -47,3 +48,38 @@ const (
 func (w Revision) TableName() string {
 	return revisionTableName
 }
+
+// WorkItemsAsOf returns a table expression that can replace the work_items
+// table in a query. It holds the work items as they were at the given time
+// according to their latest revision at or before that time, so work items
+// deleted since then are included while work items created afterwards or
+// deleted before are not. The number, order and space of a work item are not
+// part of its revisions and are always the current ones.
+func WorkItemsAsOf(asOf time.Time) string {
+	return fmt.Sprintf(`(SELECT wi.id, wi.number, wi.space_id, wi.execution_order, wi.relationships_changed_at,
+			wi.created_at, rev.revision_time AS updated_at, NULL::timestamp with time zone AS deleted_at,
+			rev.work_item_type_id AS type, rev.work_item_version AS version, rev.work_item_fields AS fields
+		FROM %[1]s wi
+		JOIN LATERAL (
+			SELECT * FROM %[2]s r
+			WHERE r.work_item_id = wi.id AND r.revision_time <= '%[3]s'
+			ORDER BY r.revision_time DESC
+			LIMIT 1
+		) rev ON rev.revision_type <> %[4]d) AS %[1]s`,
+		workitemTableName, revisionTableName, asOf.UTC().Format(time.RFC3339Nano), RevisionTypeDelete)
+}
+
+// DailyTimes returns the given start, the midnights (UTC) between start and
+// end and the given end, i.e. the points in time of a daily chart. There are
+// no points in time unless start is before end.
+func DailyTimes(start, end time.Time) []time.Time {
+	start, end = start.UTC(), end.UTC()
+	if !start.Before(end) {
+		return []time.Time{}
+	}
+	times := []time.Time{start}
+	for t := start.Truncate(24 * time.Hour).Add(24 * time.Hour); t.Before(end); t = t.Add(24 * time.Hour) {
+		times = append(times, t)
+	}
+	return append(times, end)
+}
@@ -18,6 +18,8 @@ type RevisionRepository interface {
 	Create(ctx context.Context, modifierID uuid.UUID, revisionType RevisionType, workitem WorkItemStorage) (Revision, error)
 	// List retrieves all revisions for a given work item
 	List(ctx context.Context, workitemID uuid.UUID) ([]Revision, error)
+	// Load retrieves the revision with the given ID
+	Load(ctx context.Context, id uuid.UUID) (*Revision, error)
 }
 
 // NewRevisionRepository creates a GormRevisionRepository
@@ -67,3 +69,17 @@ func (r *GormRevisionRepository) List(ctx context.Context, workitemID uuid.UUID)
 	}
 	return revisions, nil
 }
+
+// Load retrieves the revision with the given ID
+func (r *GormRevisionRepository) Load(ctx context.Context, id uuid.UUID) (*Revision, error) {
+	log.Debug(nil, map[string]interface{}{"rev_id": id}, "Loading work item revision")
+	var revision Revision
+	tx := r.db.Where("id = ?", id).First(&revision)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("work item revision", id.String())
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(tx.Error, "failed to load work item revision %s", id))
+	}
+	return &revision, nil
+}
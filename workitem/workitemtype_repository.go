@@ -143,7 +143,7 @@ func (r *GormWorkItemTypeRepository) CreateFromModel(ctx context.Context, model
 	// now process new fields, checking whether they are already there.
 	for field, definition := range model.Fields {
 		existing, exists := allFields[field]
-		if exists && !compatibleFields(existing, definition) {
+		if exists && !compatibleFields(field, existing, definition) {
 			return nil, errs.Errorf("incompatible change for field %s", field)
 		}
 		allFields[field] = definition